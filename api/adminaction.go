@@ -0,0 +1,304 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/chain"
+	"github.com/lixvyang/chestnut/handlers"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// owner action of a group owned by admins, the item is built like the owner APIs build it
+type ProposeAdminActionParam struct {
+	GroupId        string `from:"group_id"        json:"group_id"        validate:"required"`
+	KeyName        string `from:"key_name"        json:"key_name"`
+	Type           string `from:"type"            json:"type"            validate:"required,oneof=producer schema deny"`
+	Action         string `from:"action"          json:"action"          validate:"required,oneof=add remove"`
+	ProducerPubkey string `from:"producer_pubkey" json:"producer_pubkey" validate:"required_if=Type producer"`
+	SchemaType     string `from:"schema_type"     json:"schema_type"     validate:"required_if=Type schema"`
+	SchemaRule     string `from:"schema_rule"     json:"schema_rule"     validate:"required_if=Type schema"`
+	PeerId         string `from:"peer_id"         json:"peer_id"         validate:"required_if=Type deny"`
+	Memo           string `from:"memo"            json:"memo"`
+}
+
+type AdminSignInfo struct {
+	Pubkey string `json:"pubkey" validate:"required"`
+	Sign   string `json:"sign"   validate:"required"`
+}
+
+// an admin action is passed to the other admins as is, content is the decoded item for review
+type AdminActionInfo struct {
+	ActionId  string           `json:"action_id"  validate:"required"`
+	GroupId   string           `json:"group_id"   validate:"required"`
+	Type      string           `json:"type"       validate:"required,oneof=AUTH PRODUCER SCHEMA CONFIG"`
+	Data      []byte           `json:"data"       validate:"required"`
+	Signs     []*AdminSignInfo `json:"signs"      validate:"dive"`
+	Proposer  string           `json:"proposer"`
+	TimeStamp int64            `json:"timestamp"`
+	Signed    int              `json:"signed"`
+	Threshold int32            `json:"threshold"`
+	Content   interface{}      `json:"content,omitempty"`
+}
+
+type CosignAdminActionParam struct {
+	KeyName string           `from:"key_name" json:"key_name"`
+	Action  *AdminActionInfo `from:"action"   json:"action"   validate:"required"`
+}
+
+type SubmitAdminActionParam struct {
+	GroupId  string `from:"group_id"  json:"group_id"  validate:"required"`
+	ActionId string `from:"action_id" json:"action_id" validate:"required"`
+}
+
+type SubmitAdminActionResult struct {
+	GroupId  string `json:"group_id"`
+	ActionId string `json:"action_id"`
+	TrxId    string `json:"trx_id"`
+}
+
+type AdminActionListResult struct {
+	Actions []*AdminActionInfo `json:"actions"`
+}
+
+func adminActionContent(action *chestnutpb.AdminAction) interface{} {
+	var item proto.Message
+	switch action.Type {
+	case chestnutpb.TrxType_AUTH:
+		item = &chestnutpb.DenyUserItem{}
+	case chestnutpb.TrxType_PRODUCER:
+		item = &chestnutpb.ProducerItem{}
+	case chestnutpb.TrxType_SCHEMA:
+		item = &chestnutpb.SchemaItem{}
+	case chestnutpb.TrxType_CONFIG:
+		item = &chestnutpb.ConfigItem{}
+	default:
+		return nil
+	}
+	if err := proto.Unmarshal(action.Data, item); err != nil {
+		return nil
+	}
+	return item
+}
+
+func toAdminActionInfo(action *chestnutpb.AdminAction, admins *chestnutpb.AdminSet) *AdminActionInfo {
+	info := &AdminActionInfo{
+		ActionId:  action.ActionId,
+		GroupId:   action.GroupId,
+		Type:      action.Type.String(),
+		Data:      action.Data,
+		Signs:     []*AdminSignInfo{},
+		Proposer:  action.Proposer,
+		TimeStamp: action.TimeStamp,
+		Signed:    len(action.Signs),
+		Threshold: admins.GetThreshold(),
+		Content:   adminActionContent(action),
+	}
+	for _, sign := range action.Signs {
+		info.Signs = append(info.Signs, &AdminSignInfo{Pubkey: sign.Pubkey, Sign: hex.EncodeToString(sign.Sign)})
+	}
+	return info
+}
+
+func fromAdminActionInfo(info *AdminActionInfo) (*chestnutpb.AdminAction, error) {
+	action := &chestnutpb.AdminAction{
+		ActionId:  info.ActionId,
+		GroupId:   info.GroupId,
+		Type:      chestnutpb.TrxType(chestnutpb.TrxType_value[info.Type]),
+		Data:      info.Data,
+		Proposer:  info.Proposer,
+		TimeStamp: info.TimeStamp,
+	}
+	for _, sign := range info.Signs {
+		signature, err := hex.DecodeString(sign.Sign)
+		if err != nil {
+			return nil, fmt.Errorf("signature of %s can't be decoded, err:%s", sign.Pubkey, err)
+		}
+		action.Signs = append(action.Signs, &chestnutpb.AdminSign{Pubkey: sign.Pubkey, Sign: signature})
+	}
+	return action, nil
+}
+
+// the owner trx of the proposed action
+func adminActionTrx(group *chain.Group, params *ProposeAdminActionParam) (chestnutpb.TrxType, []byte, error) {
+	actionType := chestnutpb.ActionType_ADD
+	if params.Action == "remove" {
+		actionType = chestnutpb.ActionType_REMOVE
+	}
+
+	var trxType chestnutpb.TrxType
+	var item proto.Message
+	switch params.Type {
+	case "producer":
+		isAnnounced, err := group.IsProducerAnnounced(params.ProducerPubkey)
+		if err != nil {
+			return trxType, nil, err
+		}
+		if !isAnnounced {
+			return trxType, nil, fmt.Errorf("Producer is not announced")
+		}
+		trxType = chestnutpb.TrxType_PRODUCER
+		item = &chestnutpb.ProducerItem{
+			GroupId:          group.Item.GroupId,
+			ProducerPubkey:   params.ProducerPubkey,
+			GroupOwnerPubkey: group.Item.OwnerPubKey,
+			Action:           actionType,
+			Memo:             params.Memo,
+			TimeStamp:        time.Now().UnixNano(),
+		}
+	case "schema":
+		trxType = chestnutpb.TrxType_SCHEMA
+		item = &chestnutpb.SchemaItem{
+			GroupId:          group.Item.GroupId,
+			GroupOwnerPubkey: group.Item.OwnerPubKey,
+			Type:             params.SchemaType,
+			Rule:             params.SchemaRule,
+			Action:           actionType,
+			TimeStamp:        time.Now().UnixNano(),
+		}
+	case "deny":
+		//deny list actions are add and del
+		action := "add"
+		if actionType == chestnutpb.ActionType_REMOVE {
+			action = "del"
+		}
+		trxType = chestnutpb.TrxType_AUTH
+		item = &chestnutpb.DenyUserItem{
+			GroupId:          group.Item.GroupId,
+			PeerId:           params.PeerId,
+			GroupOwnerPubkey: group.Item.OwnerPubKey,
+			Action:           action,
+			Memo:             params.Memo,
+			TimeStamp:        time.Now().UnixNano(),
+		}
+	default:
+		return trxType, nil, fmt.Errorf("Unknown type")
+	}
+
+	data, err := proto.Marshal(item)
+	return trxType, data, err
+}
+
+func (h *Handler) ProposeAdminAction(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(ProposeAdminActionParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = "Can not find group"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	trxType, data, err := adminActionTrx(group, params)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	action, err := handlers.ProposeAdminAction(group, h.tenant(c).Keystore, params.KeyName, trxType, data)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, toAdminActionInfo(action, group.Item.Admins))
+}
+
+// sign an action received from another admin, the signed action is saved for submit
+func (h *Handler) CosignAdminAction(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(CosignAdminActionParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.Action.GroupId)
+	if !ok {
+		output[ERROR_INFO] = "Can not find group"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	action, err := fromAdminActionInfo(params.Action)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	action, err = handlers.CosignAdminAction(group, h.tenant(c).Keystore, params.KeyName, action)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, toAdminActionInfo(action, group.Item.Admins))
+}
+
+func (h *Handler) SubmitAdminAction(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(SubmitAdminActionParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = "Can not find group"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	trxId, err := handlers.SubmitAdminAction(group, params.ActionId)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &SubmitAdminActionResult{GroupId: params.GroupId, ActionId: params.ActionId, TrxId: trxId})
+}
+
+func (h *Handler) GetAdminActions(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(groupid)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	actions, err := group.GetAdminActions()
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	result := &AdminActionListResult{Actions: []*AdminActionInfo{}}
+	for _, action := range actions {
+		result.Actions = append(result.Actions, toAdminActionInfo(action, group.Item.Admins))
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/lixvyang/chestnut/chain"
 	localcrypto "github.com/lixvyang/chestnut/crypto"
-	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 )
 
@@ -50,8 +49,8 @@ func (h *Handler) Announce(c echo.Context) (err error) {
 	 item := &chestnutpb.AnnounceItem{}
 	 item.GroupId = params.GroupId
 
-	 groupmgr := chain.GetGroupMgr()
-	 if group, ok := groupmgr.Groups[params.GroupId]; !ok {
+	 groupmgr := h.groupMgr(c)
+	 if group, ok := groupmgr.Get(params.GroupId); !ok {
 			output[ERROR_INFO] = "Can not find group"
 			return c.JSON(http.StatusBadRequest, output)
 	 } else {
@@ -76,7 +75,7 @@ func (h *Handler) Announce(c echo.Context) (err error) {
 			item.SignPubkey = group.Item.UserSignPubkey
 
 			if item.Type == chestnutpb.AnnounceType_AS_USER {
-				item.EncryptPubkey, err = nodectx.GetNodeCtx().Keystore.GetEncodedPubkey(params.GroupId, localcrypto.Encrypt)
+				item.EncryptPubkey, err = h.tenant(c).Keystore.GetEncodedPubkey(params.GroupId, localcrypto.Encrypt)
 			}
 
 			if err != nil {
@@ -94,7 +93,7 @@ func (h *Handler) Announce(c echo.Context) (err error) {
 			buffer.Write([]byte(item.EncryptPubkey))
 			buffer.Write([]byte(item.Type.String()))
 			hash := chain.Hash(buffer.Bytes())
-			signature, err := nodectx.GetNodeCtx().Keystore.SignByKeyName(item.GroupId, hash)
+			signature, err := h.tenant(c).Keystore.SignByKeyName(item.GroupId, hash)
 			if err != nil {
 				output[ERROR_INFO] = err.Error()
 				return c.JSON(http.StatusBadRequest, output)
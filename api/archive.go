@@ -0,0 +1,60 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ImportBlocksResult struct {
+	GroupId string `json:"group_id"`
+	Blocks  int64  `json:"blocks"`
+}
+
+// export all blocks of the group as a protobuf-delimited block archive
+func (h *Handler) ExportGroupBlocks(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMEOctetStream)
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%s.blocks", groupid))
+		c.Response().WriteHeader(http.StatusOK)
+		_, err := group.ExportBlocks(c.Response())
+		return err
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
+
+// import a block archive (request body) exported by ExportGroupBlocks
+func (h *Handler) ImportGroupBlocks(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		defer c.Request().Body.Close()
+		count, err := group.ImportBlocks(c.Request().Body)
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		return c.JSON(http.StatusOK, &ImportBlocksResult{GroupId: groupid, Blocks: count})
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
@@ -0,0 +1,101 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/storage"
+)
+
+const BLOB_FETCH_TIMEOUT = 5 * time.Minute
+
+type BlobResult struct {
+	BlobId    string   `json:"blob_id"`
+	Url       string   `json:"url"`
+	Name      string   `json:"name"`
+	MediaType string   `json:"media_type"`
+	Size      int64    `json:"size"`
+	Chunks    []string `json:"chunks"`
+}
+
+// upload an attachment (multipart form field "file") to the group
+func (h *Handler) UploadBlob(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		file, err := c.FormFile("file")
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		if file.Size > storage.BLOB_SIZE_LIMIT {
+			output[ERROR_INFO] = fmt.Sprintf("file size exceeds limit %d", storage.BLOB_SIZE_LIMIT)
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		src, err := file.Open()
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		defer src.Close()
+
+		mediaType := file.Header.Get(echo.HeaderContentType)
+		if mediaType == "" {
+			mediaType = echo.MIMEOctetStream
+		}
+		manifest, err := group.AddBlob(file.Filename, mediaType, src)
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		return c.JSON(http.StatusOK, &BlobResult{BlobId: manifest.BlobId, Url: storage.BlobUrl(manifest.BlobId), Name: manifest.Name, MediaType: manifest.MediaType, Size: manifest.Size, Chunks: manifest.Chunks})
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
+
+// download an attachment of the group, fetched from group peers if it is not saved locally
+func (h *Handler) DownloadBlob(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	blobid := c.Param("blob_id")
+	if !storage.IsBlobId(blobid) {
+		output[ERROR_INFO] = "invalid blob_id."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		ctx, cancel := context.WithTimeout(c.Request().Context(), BLOB_FETCH_TIMEOUT)
+		defer cancel()
+		manifest, err := group.GetBlobManifest(ctx, blobid)
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusNotFound, output)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, manifest.MediaType)
+		c.Response().Header().Set(echo.HeaderContentLength, fmt.Sprint(manifest.Size))
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", manifest.Name))
+		c.Response().WriteHeader(http.StatusOK)
+		return group.ReadBlob(ctx, blobid, c.Response())
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
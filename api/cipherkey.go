@@ -0,0 +1,51 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+type CipherKeyParam struct {
+	GroupId   string `from:"group_id"   json:"group_id"   validate:"required"`
+	Epoch     uint32 `from:"epoch"      json:"epoch"      validate:"required"`
+	CipherKey string `from:"cipher_key" json:"cipher_key" validate:"required,hexadecimal"`
+}
+
+type CipherKeyResult struct {
+	GroupId string `json:"group_id"`
+	Epoch   uint32 `json:"epoch"`
+}
+
+// switch the group to the cipher key of a new epoch, the key is shared with the group members out of band like the seed.
+// Data sealed with the keys of earlier epochs can still be opened
+func (h *Handler) UpdCipherKey(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(CipherKeyParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", params.GroupId)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	if err := group.UpdCipherKey(params.Epoch, params.CipherKey); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &CipherKeyResult{GroupId: params.GroupId, Epoch: params.Epoch})
+}
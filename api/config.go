@@ -37,6 +37,9 @@ const (
 	Producer = "Producer"
 	Announce = "Announce"
 	App      = "App"
+	Like     = "Like"
+	Dislike  = "Dislike"
+	Delete   = "Delete"
 )
 
 const BLACK_LIST_OP_PREFIX string = "blklistop_"
\ No newline at end of file
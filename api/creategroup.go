@@ -7,7 +7,6 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/lixvyang/chestnut/handlers"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
-	"github.com/lixvyang/chestnut/utils/options"
 )
 
 type CreateGroupParam struct {
@@ -28,6 +27,7 @@ type CreateGroupResult struct {
 	CipherKey          string          `json:"cipher_key"`
 	AppKey             string          `json:"app_key"`
 	Signature          string          `json:"signature"`
+	CipherAlg          string          `json:"cipher_alg,omitempty"`
 }
 
 
@@ -40,7 +40,7 @@ func (h *Handler) CreateGroup(c echo.Context) (err error)  {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	res, err := handlers.CreateGroup(params, options.GetNodeOptions(), h.Appdb)
+	res, err := handlers.CreateGroup(params, h.tenant(c).Options, h.Appdb, h.groupMgr(c))
 	if err != nil {
 		output[ERROR_INFO] = err.Error()
 		return c.JSON(http.StatusBadRequest, output)
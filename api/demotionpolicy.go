@@ -0,0 +1,94 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/chain"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+type DemotionPolicyParam struct {
+	GroupId         string `from:"group_id"          json:"group_id"          validate:"required"`
+	MaxMissedRounds int64  `from:"max_missed_rounds" json:"max_missed_rounds" validate:"gte=0"` //0 disables
+}
+
+type DemotionPolicyResult struct {
+	GroupId         string `json:"group_id"`
+	MaxMissedRounds int64  `json:"max_missed_rounds"`
+	RoundSeconds    int64  `json:"round_seconds"`
+}
+
+// set the policy proposing removal of producers missed max_missed_rounds heartbeat rounds in a row.
+// The owner node removes them, in a group owned by admins the removal is proposed for the admins to cosign.
+func (h *Handler) UpdDemotionPolicy(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(DemotionPolicyParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", params.GroupId)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if chain.IsAdminGroup(group.Item) {
+		if !chain.IsAdmin(group.Item.Admins, group.Item.UserSignPubkey) {
+			output[ERROR_INFO] = "Only group admins can set the demotion policy"
+			return c.JSON(http.StatusBadRequest, output)
+		}
+	} else if group.Item.OwnerPubKey != group.Item.UserSignPubkey {
+		output[ERROR_INFO] = "Only group owner can set the demotion policy"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	item := &chestnutpb.DemotionPolicyItem{
+		MaxMissedRounds: params.MaxMissedRounds,
+		TimeStamp:       time.Now().UnixNano(),
+	}
+	if err := group.UpdDemotionPolicy(item); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &DemotionPolicyResult{GroupId: params.GroupId, MaxMissedRounds: item.MaxMissedRounds, RoundSeconds: int64(chain.HEARTBEAT_INTERVAL / time.Second)})
+}
+
+func (h *Handler) GetDemotionPolicy(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(groupid)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	item, err := group.GetDemotionPolicy()
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	result := &DemotionPolicyResult{GroupId: groupid, RoundSeconds: int64(chain.HEARTBEAT_INTERVAL / time.Second)}
+	if item != nil {
+		result.MaxMissedRounds = item.MaxMissedRounds
+	}
+	return c.JSON(http.StatusOK, result)
+}
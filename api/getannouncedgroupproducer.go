@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
 )
 
 type AnnouncedProducerListItem struct {
@@ -23,8 +22,8 @@ func (h *Handler) GetAnnouncedGroupProducer(c echo.Context) (err error) {
 		output[ERROR_INFO] = "group_id can't be nil."
 		return c.JSON(http.StatusBadRequest, output)
 	}
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[groupid]; ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
 		prdList, err := group.GetAnnouncedProducer()
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
 )
 
 
@@ -27,8 +26,8 @@ func (h *Handler) GetAnnouncedGroupUsers(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[groupid]; ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
 		usrList, err := group.GetAnnouncedUser()
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
@@ -0,0 +1,38 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// block_hash is the hex encoded block hash, the block id of blocks with hash ids
+func (h *Handler) GetBlockByHash(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	blockhash := c.Param("block_hash")
+	if blockhash == "" {
+		output[ERROR_INFO] = "block_hash can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		block, err := group.GetBlockByHash(blockhash)
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		return c.JSON(http.StatusOK, block)
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
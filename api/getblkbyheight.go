@@ -0,0 +1,48 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+type BlockListResult struct {
+	Blocks []*chestnutpb.Block `json:"blocks"`
+}
+
+// get blocks of the group at height, more than one block is returned if the chain is forked at height
+func (h *Handler) GetBlockByHeight(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	height, err := strconv.ParseInt(c.QueryParam("height"), 10, 64)
+	if err != nil || height < 0 {
+		output[ERROR_INFO] = "height must be a non-negative integer."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		blocks, err := group.GetBlocksByHeight(height)
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		if len(blocks) == 0 {
+			output[ERROR_INFO] = fmt.Sprintf("No block at height %d", height)
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		return c.JSON(http.StatusOK, &BlockListResult{Blocks: blocks})
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
 )
 
 
@@ -24,8 +23,8 @@ func (h *Handler) GetBlockById(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[groupid]; ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
 		block, err := group.GetBlock(blockid)
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
@@ -6,7 +6,6 @@ import (
 	"fmt"
 
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
 )
 
 type DeniedUserListItem struct {
@@ -29,8 +28,8 @@ func (h *Handler) GetDeniedUserList(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[groupid]; ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
 		blkList, err := group.GetBlockedUser()
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
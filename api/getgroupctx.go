@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/anypb"
@@ -30,8 +29,8 @@ func (h *Handler) GetGroupCtn(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[groupid]; ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
 		ctnList, err := group.GetGroupCtn(filter)
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
 )
 
 
@@ -16,6 +15,11 @@ type ProducerListItem struct {
 	OwnerSign      string
 	TimeStamp      int64
 	BlockProduced  int64
+	LastSeen       int64 //0 if not seen since the node started
+	LastHeight     int64
+	MissedRounds   int64 //heartbeat rounds missed in a row
+	TotalMissed    int64
+	Online         bool
 }
 
 
@@ -28,14 +32,15 @@ func (h *Handler) GetGroupProducers(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[groupid]; ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
 		prdList, err := group.GetProducers()
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
 			return c.JSON(http.StatusBadRequest, output)
 		}
 
+		liveness := group.ChainCtx.GetLiveness()
 		var prdResultList []*ProducerListItem
 		for _, prd := range prdList {
 			item := &ProducerListItem{}
@@ -44,6 +49,13 @@ func (h *Handler) GetGroupProducers(c echo.Context) (err error) {
 			item.OwnerSign = prd.GroupOwnerSign
 			item.TimeStamp = prd.TimeStamp
 			item.BlockProduced = prd.BlockProduced
+			if stat, ok := liveness[prd.ProducerPubkey]; ok {
+				item.LastSeen = stat.LastSeen
+				item.LastHeight = stat.LastHeight
+				item.MissedRounds = stat.MissedRounds
+				item.TotalMissed = stat.TotalMissed
+				item.Online = stat.LastSeen > 0 && stat.MissedRounds == 0
+			}
 			prdResultList = append(prdResultList, item)
 		}
 		return c.JSON(http.StatusOK, prdResultList)
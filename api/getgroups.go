@@ -7,6 +7,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/lixvyang/chestnut/chain"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
 )
 
 type groupInfo struct {
@@ -17,11 +18,15 @@ type groupInfo struct {
 	ConsensusType  string `json:"consensus_type"`
 	EncryptionType string `json:"encryption_type"`
 	CipherKey      string `json:"cipher_key"`
+	CipherAlg      string `json:"cipher_alg"`
 	AppKey         string `json:"app_key"`
 	LastUpdated    int64  `json:"last_updated"`
 	HighestHeight  int64  `json:"highest_height"`
 	HighestBlockId string `json:"highest_block_id"`
 	GroupStatus    string `json:"group_status"`
+	GroupState     string `json:"group_state"`
+	Admins         []string `json:"admins,omitempty"`
+	AdminThreshold int32    `json:"admin_threshold,omitempty"`
 }
 
 
@@ -43,24 +48,33 @@ func (s *GroupInfoList) Less(i, j int) bool {
 
 func (h *Handler) GetGroups(c echo.Context) (err error) {
 	var groups []*groupInfo
-	groupmgr := chain.GetGroupMgr()
-	for _, value := range groupmgr.Groups {
+	groupmgr := h.groupMgr(c)
+	for _, value := range groupmgr.List() {
 		group := &groupInfo{}
+		item := value.ItemSnapshot()
 
-		group.OwnerPubKey = value.Item.OwnerPubKey
-		group.GroupId = value.Item.GroupId
-		group.GroupName = value.Item.GroupName
-		group.OwnerPubKey = value.Item.OwnerPubKey
-		group.UserPubkey = value.Item.UserSignPubkey
-		group.ConsensusType = value.Item.ConsenseType.String()
-		group.EncryptionType = value.Item.EncryptType.String()
-		group.CipherKey = value.Item.CipherKey
-		group.AppKey = value.Item.AppKey
-		group.LastUpdated = value.Item.LastUpdate
-		group.HighestHeight = value.Item.HighestHeight
-		group.HighestBlockId = value.Item.HighestBlockId
+		group.OwnerPubKey = item.OwnerPubKey
+		group.GroupId = item.GroupId
+		group.GroupName = item.GroupName
+		group.OwnerPubKey = item.OwnerPubKey
+		group.UserPubkey = item.UserSignPubkey
+		group.ConsensusType = item.ConsenseType.String()
+		group.EncryptionType = item.EncryptType.String()
+		group.CipherKey = item.CipherKey
+		group.CipherAlg = item.CipherAlg
+		if group.CipherAlg == "" {
+			group.CipherAlg = localcrypto.CIPHER_ALG_LEGACY.String()
+		}
+		group.AppKey = item.AppKey
+		group.LastUpdated = item.LastUpdate
+		group.HighestHeight = item.HighestHeight
+		group.HighestBlockId = item.HighestBlockId
+		if chain.IsAdminGroup(item) {
+			group.Admins = item.Admins.Pubkeys
+			group.AdminThreshold = item.Admins.Threshold
+		}
 
-		switch value.ChainCtx.Syncer.Status {
+		switch value.ChainCtx.Syncer.GetStatus() {
 			case chain.SYNCING_BACKWARD:
 				group.GroupStatus = "SYNCING"
 			case chain.SYNCING_FORWARD:
@@ -70,6 +84,9 @@ func (h *Handler) GetGroups(c echo.Context) (err error) {
 			case chain.IDLE:
 				group.GroupStatus = "IDLE"
 		}
+		if state, ok := groupmgr.GetState(item.GroupId); ok {
+			group.GroupState = state.String()
+		}
 		groups = append(groups, group)
 	}
 	ret := GroupInfoList{groups}
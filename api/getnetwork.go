@@ -7,7 +7,6 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/libp2p/go-libp2p-core/host"
 	"github.com/libp2p/go-libp2p-core/peer"
-	"github.com/lixvyang/chestnut/chain"
 	"github.com/lixvyang/chestnut/nodectx"
 	"github.com/lixvyang/chestnut/p2p"
 	"github.com/lixvyang/chestnut/utils/options"
@@ -34,8 +33,8 @@ func (h *Handler) GetNetwork(nodehost *host.Host, nodeinfo *p2p.NodeInfo, nodeop
 		result := &NetworkInfo{}	
 		node := make(map[string]interface{})
 		groupnetworklist := []*groupNetworkInfo{}
-		groupmgr := chain.GetGroupMgr()
-		for _, group := range groupmgr.Groups {
+		groupmgr := h.groupMgr(c)
+		for _, group := range groupmgr.List() {
 			groupnetwork := &groupNetworkInfo{}
 			groupnetwork.GroupId = group.Item.GroupId
 			groupnetwork.GroupName = group.Item.GroupName
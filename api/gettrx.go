@@ -6,7 +6,6 @@ import (
 	"net/http"
 
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
 	_ "github.com/lixvyang/chestnut/pb"
 )
 
@@ -28,8 +27,8 @@ func (h *Handler) GetTrx(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[groupid]; ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
 		trx, err := group.GetTrx(trxid)
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
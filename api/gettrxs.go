@@ -0,0 +1,49 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+type TrxListResult struct {
+	Trxs []*chestnutpb.Trx `json:"trxs"`
+}
+
+// get trxs of the group by timestamp, filtered by sender if the sender param is set
+func (h *Handler) GetGroupTrxs(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	sender := c.QueryParam("sender")
+	num := 20
+	if c.QueryParam("num") != "" {
+		num, err = strconv.Atoi(c.QueryParam("num"))
+		if err != nil || num <= 0 {
+			output[ERROR_INFO] = "num must be a positive integer."
+			return c.JSON(http.StatusBadRequest, output)
+		}
+	}
+	reverse := c.QueryParam("reverse") == "true"
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		trxs, err := group.GetTrxs(sender, num, reverse)
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		return c.JSON(http.StatusOK, &TrxListResult{Trxs: trxs})
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
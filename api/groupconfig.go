@@ -0,0 +1,133 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/handlers"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+type GroupConfigParam struct {
+	GroupId         string `from:"group_id"          json:"group_id"          validate:"required"`
+	ProduceTimer    int64  `from:"produce_timer"     json:"produce_timer"     validate:"gte=0"` //ms, 0 keeps the current value
+	MergeTimer      int64  `from:"merge_timer"       json:"merge_timer"       validate:"gte=0"` //ms
+	TrxsTotalSize   int64  `from:"trxs_total_size"   json:"trxs_total_size"   validate:"gte=0"`
+	ObjectSizeLimit int64  `from:"object_size_limit" json:"object_size_limit" validate:"gte=0"`
+	TrxExpire       int64  `from:"trx_expire"        json:"trx_expire"        validate:"gte=0"` //s
+	EffectiveHeight int64  `from:"effective_height"  json:"effective_height"  validate:"gte=0"` //0 for a few blocks after the current height
+	Memo            string `from:"memo"              json:"memo"`
+}
+
+type GroupConfigInfo struct {
+	ProduceTimer    int64  `json:"produce_timer"`
+	MergeTimer      int64  `json:"merge_timer"`
+	TrxsTotalSize   int64  `json:"trxs_total_size"`
+	ObjectSizeLimit int64  `json:"object_size_limit"`
+	TrxExpire       int64  `json:"trx_expire"`
+	EffectiveHeight int64  `json:"effective_height"`
+	Memo            string `json:"memo,omitempty"`
+}
+
+type GroupConfigResult struct {
+	GroupId string           `json:"group_id"`
+	Config  *GroupConfigInfo `json:"config"`
+	TrxId   string           `json:"trx_id,omitempty"`
+	Action  *AdminActionInfo `json:"action,omitempty"` //proposed in a group owned by admins
+}
+
+type GroupConfigListResult struct {
+	GroupId string             `json:"group_id"`
+	Height  int64              `json:"height"`
+	Current *GroupConfigInfo   `json:"current"`
+	Changes []*GroupConfigInfo `json:"changes"`
+}
+
+func groupConfigInfo(config *chestnutpb.GroupConfig, effectiveHeight int64, memo string) *GroupConfigInfo {
+	return &GroupConfigInfo{
+		ProduceTimer:    config.ProduceTimer,
+		MergeTimer:      config.MergeTimer,
+		TrxsTotalSize:   config.TrxsTotalSize,
+		ObjectSizeLimit: config.ObjectSizeLimit,
+		TrxExpire:       config.TrxExpire,
+		EffectiveHeight: effectiveHeight,
+		Memo:            memo,
+	}
+}
+
+// change the block production config of the group, every node applies it at the effective height
+func (h *Handler) UpdGroupConfig(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(GroupConfigParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = "Can not find group"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	config := &chestnutpb.GroupConfig{
+		ProduceTimer:    params.ProduceTimer,
+		MergeTimer:      params.MergeTimer,
+		TrxsTotalSize:   params.TrxsTotalSize,
+		ObjectSizeLimit: params.ObjectSizeLimit,
+		TrxExpire:       params.TrxExpire,
+	}
+	item, trxId, action, err := handlers.UpdGroupConfig(group, h.tenant(c).Keystore, config, params.EffectiveHeight, params.Memo)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	result := &GroupConfigResult{GroupId: params.GroupId, Config: groupConfigInfo(item.Config, item.EffectiveHeight, item.Memo), TrxId: trxId}
+	if action != nil {
+		result.Action = toAdminActionInfo(action, group.Item.Admins)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// config at the current height and the changes applied, including the ones not effective yet
+func (h *Handler) GetGroupConfig(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(groupid)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	changes, err := group.GetConfigChanges()
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	height, _ := group.ChainHead()
+	result := &GroupConfigListResult{
+		GroupId: groupid,
+		Height:  height,
+		Current: groupConfigInfo(group.GetConfig(), 0, ""),
+		Changes: []*GroupConfigInfo{},
+	}
+	for _, item := range changes {
+		result.Changes = append(result.Changes, groupConfigInfo(item.Config, item.EffectiveHeight, item.Memo))
+	}
+	return c.JSON(http.StatusOK, result)
+}
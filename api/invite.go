@@ -0,0 +1,151 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/handlers"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+type InviteParam struct {
+	GroupId       string `from:"group_id"       json:"group_id"       validate:"required"`
+	ExpireHours   int64  `from:"expire_hours"   json:"expire_hours"   validate:"gte=0"` //0 for never
+	MaxUse        int32  `from:"max_use"        json:"max_use"        validate:"gte=0"` //0 for no limit
+	InviteePubkey string `from:"invitee_pubkey" json:"invitee_pubkey"`                  //sign pubkey of the only invitee
+}
+
+type RevokeInviteParam struct {
+	GroupId  string `from:"group_id"  json:"group_id"  validate:"required"`
+	InviteId string `from:"invite_id" json:"invite_id" validate:"required"`
+}
+
+type InviteInfo struct {
+	InviteId      string `json:"invite_id"`
+	GroupId       string `json:"group_id"`
+	Expire        int64  `json:"expire"`
+	MaxUse        int32  `json:"max_use"`
+	InviteePubkey string `json:"invitee_pubkey"`
+	Used          int32  `json:"used"`
+	Revoked       bool   `json:"revoked"`
+	TimeStamp     int64  `json:"timestamp"`
+}
+
+type InviteResult struct {
+	*InviteInfo
+	Token string `json:"token"`
+	TrxId string `json:"trx_id"`
+}
+
+type RevokeInviteResult struct {
+	GroupId  string `json:"group_id"`
+	InviteId string `json:"invite_id"`
+	TrxId    string `json:"trx_id"`
+}
+
+type InviteListResult struct {
+	Invites []*InviteInfo `json:"invites"`
+}
+
+func inviteInfo(item *chestnutpb.InviteItem) *InviteInfo {
+	return &InviteInfo{
+		InviteId:      item.InviteId,
+		GroupId:       item.GroupId,
+		Expire:        item.Expire,
+		MaxUse:        item.MaxUse,
+		InviteePubkey: item.InviteePubkey,
+		Used:          item.Used,
+		Revoked:       item.Revoked,
+		TimeStamp:     item.TimeStamp,
+	}
+}
+
+// mint an invitation, the token is passed to the joiner as the seed
+func (h *Handler) CreateInvite(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(InviteParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = "Can not find group"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	var expire int64
+	if params.ExpireHours > 0 {
+		expire = time.Now().Add(time.Duration(params.ExpireHours) * time.Hour).UnixNano()
+	}
+	item, token, trxId, err := handlers.CreateInvite(group, h.tenant(c).Keystore, h.Appdb, expire, params.MaxUse, params.InviteePubkey)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &InviteResult{InviteInfo: inviteInfo(item), Token: token, TrxId: trxId})
+}
+
+func (h *Handler) RevokeInvite(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(RevokeInviteParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = "Can not find group"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	trxId, err := handlers.RevokeInvite(group, h.tenant(c).Keystore, params.InviteId)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &RevokeInviteResult{GroupId: params.GroupId, InviteId: params.InviteId, TrxId: trxId})
+}
+
+// invitations on chain, used and revoked as applied by the node
+func (h *Handler) GetInvites(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(groupid)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	invites, err := group.GetInvites()
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	result := &InviteListResult{Invites: []*InviteInfo{}}
+	for _, item := range invites {
+		result.Invites = append(result.Invites, inviteInfo(item))
+	}
+	return c.JSON(http.StatusOK, result)
+}
@@ -14,10 +14,9 @@ import (
 	"github.com/labstack/echo/v4"
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/lixvyang/chestnut/chain"
+	"github.com/lixvyang/chestnut/handlers"
 	localcrypto "github.com/lixvyang/chestnut/crypto"
-	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
-	"github.com/lixvyang/chestnut/utils/options"
 )
 
 type JoinGroupParam struct {
@@ -30,6 +29,9 @@ type JoinGroupParam struct {
 	CipherKey      string            `from:"cipher_key" json:"cipher_key" validate:"required"`
 	AppKey         string            `from:"app_key" json:"app_key" validate:"required"`
 	Signature      string            `from:"signature" json:"signature" validate:"required"`
+	CipherAlg      string            `from:"cipher_alg" json:"cipher_alg" validate:"omitempty,oneof=legacy aes-gcm xchacha20-poly1305"`
+	Invite         string            `from:"invite" json:"invite"`     //invitation token, the seed fields are taken from it
+	KeyName        string            `from:"key_name" json:"key_name"` //invitee key of a bound invitation
 }
 
 type JoinGroupResult struct {
@@ -43,6 +45,7 @@ type JoinGroupResult struct {
 	CipherKey         string `json:"cipher_key"`
 	AppKey            string `json:"app_key"`
 	Signature         string `json:"signature"`
+	RedeemTrxId       string `json:"redeem_trx_id,omitempty"`
 }
 
 func (h *Handler) JoinGroup(c echo.Context) (err error) {
@@ -54,6 +57,30 @@ func (h *Handler) JoinGroup(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
+	var invite *chestnutpb.InviteItem
+	if params.Invite != "" {
+		var seed *handlers.GroupSeed
+		invite, seed, err = handlers.OpenInvite(params.Invite)
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		if err = handlers.CheckInvitee(invite, h.tenant(c).Keystore, params.KeyName); err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		params.GenesisBlock = seed.GenesisBlock
+		params.GroupId = seed.GroupId
+		params.GroupName = seed.GroupName
+		params.OwnerPubKey = seed.OwnerPubkey
+		params.ConsensusType = seed.ConsensusType
+		params.EncryptionType = seed.EncryptionType
+		params.CipherKey = seed.CipherKey
+		params.AppKey = seed.AppKey
+		params.Signature = seed.Signature
+		params.CipherAlg = seed.CipherAlg
+	}
+
 	if err = validate.Struct(params); err != nil {
 		output[ERROR_INFO] = "unmarshal genesis block failed with msg:" + err.Error()
 		return c.JSON(http.StatusBadRequest, output)
@@ -64,35 +91,29 @@ func (h *Handler) JoinGroup(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	nodeoptions := options.GetNodeOptions()
+	nodeoptions := h.tenant(c).Options
 	var groupSignPubkey []byte
-	ks := nodectx.GetNodeCtx().Keystore
-	dirks, ok := ks.(*localcrypto.DirKeyStore)
-	if ok {
-		hexkey, err := dirks.GetEncodedPubkey(params.GroupId, localcrypto.Sign)
-		if err != nil && strings.HasPrefix(err.Error(), "key not exist ")  {
-			newsignaddr, err := dirks.NewKeyWithDefaultPassword(params.GroupId, localcrypto.Sign)
-			if err == nil && newsignaddr != "" {
-				err = nodeoptions.SetSignKeyMap(params.GroupId, newsignaddr)
-				if err != nil {
-					output[ERROR_INFO] = fmt.Sprintf("save key map %s err: %s", newsignaddr, err.Error())
-					return c.JSON(http.StatusBadRequest, output)
-				}
-				hexkey, err = dirks.GetEncodedPubkey(params.GroupId, localcrypto.Sign)
-			} else {
-				output[ERROR_INFO] = "create new group key err:" + err.Error()
+	ks := h.tenant(c).Keystore
+	hexkey, err := ks.GetEncodedPubkey(params.GroupId, localcrypto.Sign)
+	if err != nil && strings.HasPrefix(err.Error(), "key not exist ")  {
+		newsignaddr, err := ks.NewKeyWithDefaultPassword(params.GroupId, localcrypto.Sign)
+		if err == nil && newsignaddr != "" {
+			err = nodeoptions.SetSignKeyMap(params.GroupId, newsignaddr)
+			if err != nil {
+				output[ERROR_INFO] = fmt.Sprintf("save key map %s err: %s", newsignaddr, err.Error())
 				return c.JSON(http.StatusBadRequest, output)
 			}
-		}
-		pubkeybytes, err := hex.DecodeString(hexkey)
-		p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
-		groupSignPubkey, err = p2pcrypto.MarshalPublicKey(p2ppubkey)
-		if err != nil {
-			output[ERROR_INFO] = "group key can't be decoded, err:" + err.Error()
+			hexkey, err = ks.GetEncodedPubkey(params.GroupId, localcrypto.Sign)
+		} else {
+			output[ERROR_INFO] = "create new group key err:" + err.Error()
 			return c.JSON(http.StatusBadRequest, output)
 		}
-	} else {
-		output[ERROR_INFO] = fmt.Sprintf("unknown keystore type  %v:", ks)
+	}
+	pubkeybytes, err := hex.DecodeString(hexkey)
+	p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
+	groupSignPubkey, err = p2pcrypto.MarshalPublicKey(p2ppubkey)
+	if err != nil {
+		output[ERROR_INFO] = "group key can't be decoded, err:" + err.Error()
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
@@ -118,10 +139,10 @@ func (h *Handler) JoinGroup(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupEncryptkey, err := dirks.GetEncodedPubkey(params.GroupId, localcrypto.Encrypt)
+	groupEncryptkey, err := ks.GetEncodedPubkey(params.GroupId, localcrypto.Encrypt)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "key not exist ") {
-			groupEncryptkey, err = dirks.NewKeyWithDefaultPassword(params.GroupId, localcrypto.Encrypt)
+			groupEncryptkey, err = ks.NewKeyWithDefaultPassword(params.GroupId, localcrypto.Encrypt)
 			if err != nil {
 				output[ERROR_INFO] = "Create key pair failed with msg:" + err.Error()
 				return c.JSON(http.StatusBadRequest, output)
@@ -140,7 +161,8 @@ func (h *Handler) JoinGroup(c echo.Context) (err error) {
 	buffer.Write([]byte(params.ConsensusType))
 	buffer.Write([]byte(params.EncryptionType))
 	buffer.Write([]byte(params.AppKey))
-	buffer.Write(cipherKey)	
+	buffer.Write(cipherKey)
+	buffer.Write([]byte(params.CipherAlg))
 
 	hash := localcrypto.Hash(buffer.Bytes())
 	verifiy, err := ownerPubkey.Verify(hash, decodedSignature)
@@ -161,14 +183,23 @@ func (h *Handler) JoinGroup(c echo.Context) (err error) {
 	item.GroupName = params.GroupName
 	item.OwnerPubKey = p2pcrypto.ConfigEncodeKey(ownerPubkeyBytes)
 	item.CipherKey = params.CipherKey
+	item.CipherAlg = params.CipherAlg
+	//admin set is signed with the genesis block
+	item.Admins = params.GenesisBlock.Admins
+	if chain.IsAdminGroup(item) {
+		if err := chain.CheckAdminSet(item.Admins); err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+	}
 	item.AppKey = params.AppKey
 	item.ConsenseType = chestnutpb.GroupConsenseType_POA
 	item.UserSignPubkey = p2pcrypto.ConfigEncodeKey(groupSignPubkey)
 
-	userEncryptKey, err := dirks.GetEncodedPubkey(params.GroupId, localcrypto.Encrypt)
+	userEncryptKey, err := ks.GetEncodedPubkey(params.GroupId, localcrypto.Encrypt)
 	if err != nil {
 		if strings.HasPrefix(err.Error(), "key not exist ") {
-			userEncryptKey, err = dirks.NewKeyWithDefaultPassword(params.GroupId, localcrypto.Encrypt)
+			userEncryptKey, err = ks.NewKeyWithDefaultPassword(params.GroupId, localcrypto.Encrypt)
 			if err != nil {
 				output[ERROR_INFO] = "Create key pair failed with msg:" + err.Error()
 				return c.JSON(http.StatusBadRequest, output)
@@ -192,26 +223,23 @@ func (h *Handler) JoinGroup(c echo.Context) (err error) {
 	item.LastUpdate = time.Now().UnixNano()
 	item.GenesisBlock = params.GenesisBlock
 
-	// create the group
-	var group *chain.Group
-	group = &chain.Group{}
-	err = group.CreateGrp(item)
+	// create the group and start sync
+	groupmgr := h.groupMgr(c)
+	group, err := groupmgr.Join(item)
 	if err != nil {
 		output[ERROR_INFO] = err.Error()
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	// start sync 
-	err = group.StartSync()
-	if err != nil {
-		output[ERROR_INFO] = err.Error()
-		return c.JSON(http.StatusBadRequest, output)
+	redeemTrxId := ""
+	if invite != nil {
+		redeemTrxId, err = handlers.RedeemInvite(group, ks, params.KeyName, invite)
+		if err != nil {
+			output[ERROR_INFO] = "group joined, redeem invitation failed with msg:" + err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
 	}
 
-	// add group to content
-	groupmgr := chain.GetGroupMgr()
-	groupmgr.Groups[group.Item.GroupId] = group
-
 	var bufferResult bytes.Buffer
 	bufferResult.Write(genesisBlockBytes)
 	bufferResult.Write([]byte(item.GroupId))
@@ -238,6 +266,7 @@ func (h *Handler) JoinGroup(c echo.Context) (err error) {
 		CipherKey: item.CipherKey,
 		AppKey: item.AppKey,
 		Signature: encodedSign,
+		RedeemTrxId: redeemTrxId,
 	}
 	return c.JSON(http.StatusOK, joinGrpResult)
 }
@@ -0,0 +1,255 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/handlers"
+	"github.com/lixvyang/chestnut/nodectx"
+)
+
+type KeyInfo struct {
+	KeyName string `json:"key_name"`
+	KeyType string `json:"key_type"`
+	Addr    string `json:"addr,omitempty"`
+	Pubkey  string `json:"pubkey,omitempty"`
+}
+
+type KeyListResult struct {
+	Keys []*KeyInfo `json:"keys"`
+}
+
+type KeyParam struct {
+	KeyName string `from:"key_name" json:"key_name" validate:"required"`
+	KeyType string `from:"key_type" json:"key_type" validate:"required,oneof=sign encrypt"`
+}
+
+type ExportKeyParam struct {
+	KeyParam
+	Passphrase string `from:"passphrase" json:"passphrase" validate:"required"`
+}
+
+type ExportKeyResult struct {
+	KeyName string `json:"key_name"`
+	KeyType string `json:"key_type"`
+	Key     string `json:"key"`
+}
+
+type ImportKeyParam struct {
+	KeyParam
+	Key        string `from:"key" json:"key" validate:"required"`
+	Passphrase string `from:"passphrase" json:"passphrase" validate:"required"`
+}
+
+type RenameKeyParam struct {
+	KeyParam
+	NewName string `from:"new_name" json:"new_name" validate:"required"`
+}
+
+type KeyOrphansResult struct {
+	OrphanKeys   []*KeyInfo `json:"orphan_keys"`
+	MissingKeys  []string   `json:"missing_keys"`
+	UnmappedKeys []string   `json:"unmapped_keys"`
+}
+
+func keyInfo(key *localcrypto.KeyItem) *KeyInfo {
+	return &KeyInfo{KeyName: key.KeyName, KeyType: key.KeyType.String(), Addr: key.Addr}
+}
+
+// key management works on the keys saved in the keystore dir of the tenant
+func (h *Handler) dirKeystore(c echo.Context) (*localcrypto.DirKeyStore, bool) {
+	ks, ok := h.tenant(c).Keystore.(*localcrypto.DirKeyStore)
+	return ks, ok
+}
+
+// bind and validate the params, key type is returned parsed
+func bindKeyParam(c echo.Context, params interface{}, keyparam *KeyParam) (localcrypto.KeyType, error) {
+	if err := c.Bind(params); err != nil {
+		return 0, err
+	}
+	if err := validator.New().Struct(params); err != nil {
+		return 0, err
+	}
+	return localcrypto.ParseKeyType(keyparam.KeyType)
+}
+
+// groups saved by the tenant, including the groups not loaded
+func (h *Handler) savedGroupIds(c echo.Context) ([]string, error) {
+	tenant := h.tenant(c)
+	if tenant.IsDefault {
+		return handlers.SavedGroupIds(nodectx.GetDbMgr(), "")
+	}
+	return handlers.SavedGroupIds(nodectx.GetDbMgr(), tenant.Name)
+}
+
+func (h *Handler) ListKeys(c echo.Context) (err error) {
+	output := make(map[string]string)
+	ks, ok := h.dirKeystore(c)
+	if !ok {
+		output[ERROR_INFO] = "keys of the keystore can't be managed by the node"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	keys, err := ks.ListKeys()
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	result := &KeyListResult{Keys: []*KeyInfo{}}
+	for _, key := range keys {
+		info := keyInfo(key)
+		//sign pubkey of the key, used as an admin pubkey of a group
+		if key.KeyType == localcrypto.Sign {
+			info.Pubkey, _ = handlers.GetSignPubkey(key.KeyName, ks)
+		}
+		result.Keys = append(result.Keys, info)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+func (h *Handler) ExportKey(c echo.Context) (err error) {
+	output := make(map[string]string)
+	params := new(ExportKeyParam)
+	keytype, err := bindKeyParam(c, params, &params.KeyParam)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	ks, ok := h.dirKeystore(c)
+	if !ok {
+		output[ERROR_INFO] = "keys of the keystore can't be managed by the node"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	key, err := ks.ExportKey(params.KeyName, keytype, params.Passphrase)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &ExportKeyResult{KeyName: params.KeyName, KeyType: params.KeyType, Key: key})
+}
+
+func (h *Handler) ImportKey(c echo.Context) (err error) {
+	output := make(map[string]string)
+	params := new(ImportKeyParam)
+	keytype, err := bindKeyParam(c, params, &params.KeyParam)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	ks, ok := h.dirKeystore(c)
+	if !ok {
+		output[ERROR_INFO] = "keys of the keystore can't be managed by the node"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	addr, err := handlers.ImportKey(ks, h.tenant(c).Options, params.KeyName, keytype, params.Key, params.Passphrase)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &KeyInfo{KeyName: params.KeyName, KeyType: params.KeyType, Addr: addr})
+}
+
+func (h *Handler) RenameKey(c echo.Context) (err error) {
+	output := make(map[string]string)
+	params := new(RenameKeyParam)
+	keytype, err := bindKeyParam(c, params, &params.KeyParam)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	ks, ok := h.dirKeystore(c)
+	if !ok {
+		output[ERROR_INFO] = "keys of the keystore can't be managed by the node"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err := handlers.RenameKey(ks, h.tenant(c).Options, params.KeyName, params.NewName, keytype); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &KeyInfo{KeyName: params.NewName, KeyType: params.KeyType})
+}
+
+func (h *Handler) DeleteKey(c echo.Context) (err error) {
+	output := make(map[string]string)
+	params := new(KeyParam)
+	keytype, err := bindKeyParam(c, params, params)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	ks, ok := h.dirKeystore(c)
+	if !ok {
+		output[ERROR_INFO] = "keys of the keystore can't be managed by the node"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	groupIds, err := h.savedGroupIds(c)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err := handlers.DeleteKey(ks, h.tenant(c).Options, params.KeyName, keytype, groupIds); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &KeyInfo{KeyName: params.KeyName, KeyType: params.KeyType})
+}
+
+// keys of groups not hosted any more, and sign key map entries out of sync with the key files of the tenant
+func (h *Handler) GetKeyOrphans(c echo.Context) (err error) {
+	output := make(map[string]string)
+	ks, ok := h.dirKeystore(c)
+	if !ok {
+		output[ERROR_INFO] = "keys of the keystore can't be managed by the node"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	groupIds, err := h.savedGroupIds(c)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	orphans, err := handlers.CheckKeyOrphans([]*localcrypto.DirKeyStore{ks}, h.tenant(c).Options.SignKeyMap, groupIds)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	result := &KeyOrphansResult{OrphanKeys: []*KeyInfo{}, MissingKeys: orphans.MissingKeys, UnmappedKeys: orphans.UnmappedKeys}
+	for _, key := range orphans.OrphanKeys {
+		result.OrphanKeys = append(result.OrphanKeys, keyInfo(key))
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+type ChangePasswordParam struct {
+	OldPassword string `from:"old_password" json:"old_password" validate:"required"`
+	NewPassword string `from:"new_password" json:"new_password" validate:"required,min=8"`
+}
+
+// re-encrypt the keys of the tenant under the new password, every tenant has its own password.
+// The password file of the tenant is replaced with the new password in the same change.
+func (h *Handler) ChangeKeystorePassword(c echo.Context) (err error) {
+	output := make(map[string]string)
+	params := new(ChangePasswordParam)
+	if err := c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err := validator.New().Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	ks, ok := h.dirKeystore(c)
+	if !ok {
+		output[ERROR_INFO] = "keys of the keystore can't be managed by the node"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	if err := ks.ChangePassword(params.OldPassword, params.NewPassword, h.tenant(c).PasswordFile); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	output["status"] = "ok"
+	return c.JSON(http.StatusOK, output)
+}
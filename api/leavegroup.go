@@ -38,34 +38,24 @@ func (h *Handler) LeaveGroup(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[params.GroupId]; ok {
-		err := group.LeaveGrp()
-
+	groupmgr := h.groupMgr(c)
+	if _, ok := groupmgr.Get(params.GroupId); ok {
+		err := groupmgr.Leave(params.GroupId)
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
 			return c.JSON(http.StatusBadRequest, output)
 		}
 
-		delete(groupmgr.Groups, params.GroupId)
+		var groupSignPubkey []byte
+		ks := h.tenant(c).Keystore
+		hexkey, err := ks.GetEncodedPubkey("default", localcrypto.Sign)
+		pubkeybytes, err := hex.DecodeString(hexkey)
+		p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
+		groupSignPubkey, err = p2pcrypto.MarshalPublicKey(p2ppubkey)
 		if err != nil {
-			output[ERROR_INFO] = err.Error()
+			output[ERROR_INFO] = "group key can't be decoded, err:" + err.Error()
 			return c.JSON(http.StatusBadRequest, output)
 		}
-
-		var groupSignPubkey []byte
-		ks := localcrypto.GetKeystore()
-		dirks, ok := ks.(*localcrypto.DirKeyStore)
-		if ok {
-			hexkey, err := dirks.GetEncodedPubkey("default", localcrypto.Sign)
-			pubkeybytes, err := hex.DecodeString(hexkey)
-			p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
-			groupSignPubkey, err = p2pcrypto.MarshalPublicKey(p2ppubkey)
-			if err != nil {
-				output[ERROR_INFO] = "group key can't be decoded, err:" + err.Error()
-				return c.JSON(http.StatusBadRequest, output)
-			}
-		}
 		var buffer bytes.Buffer
 		buffer.Write(groupSignPubkey)
 		buffer.Write([]byte(params.GroupId))
@@ -0,0 +1,130 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+type LightModeParam struct {
+	GroupId string   `from:"group_id" json:"group_id" validate:"required"`
+	Light   bool     `from:"light"    json:"light"`
+	Senders []string `from:"senders"  json:"senders"` //followed senders, post data of other senders is not saved
+}
+
+type LightModeResult struct {
+	GroupId     string   `json:"group_id"`
+	Light       bool     `json:"light"`
+	Senders     []string `json:"senders"`
+	MissingTrxs int64    `json:"missing_trxs"` //trxs saved without data
+}
+
+type FetchTrxParam struct {
+	GroupId string `from:"group_id" json:"group_id" validate:"required"`
+	TrxId   string `from:"trx_id"   json:"trx_id"   validate:"required"`
+}
+
+// set the light mode of the group, blocks synced later are saved without the post data of the senders not followed
+func (h *Handler) UpdLightMode(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(LightModeParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", params.GroupId)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	if params.Light {
+		err = group.UpdLightMode(&chestnutpb.LightModeItem{Senders: params.Senders})
+	} else {
+		err = group.RmLightMode()
+	}
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return h.lightModeResult(c, params.GroupId)
+}
+
+func (h *Handler) GetLightMode(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return h.lightModeResult(c, groupid)
+}
+
+func (h *Handler) lightModeResult(c echo.Context, groupid string) error {
+	output := make(map[string]string)
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(groupid)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	item, err := group.GetLightMode()
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	missing, err := group.CountLightTrxs()
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	result := &LightModeResult{GroupId: groupid, Senders: []string{}, MissingTrxs: missing}
+	if item != nil {
+		result.Light = true
+		result.Senders = append(result.Senders, item.Senders...)
+	}
+	return c.JSON(http.StatusOK, result)
+}
+
+// request the data of a trx saved without data, the trx is verified by the trx root of its block
+// and the post is saved when a producer responds
+func (h *Handler) FetchTrx(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(FetchTrxParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	group, ok := groupmgr.Get(params.GroupId)
+	if !ok {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", params.GroupId)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	if err := group.FetchTrx(params.TrxId); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, params)
+}
@@ -12,7 +12,6 @@ import (
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/lixvyang/chestnut/chain"
 	localcrypto "github.com/lixvyang/chestnut/crypto"
-	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 )
 
@@ -49,18 +48,15 @@ func (h *Handler) MgrGrpBlkList(c echo.Context) (err error) {
 	}
 
 	var groupSignPubkey []byte
-	ks := nodectx.GetNodeCtx().Keystore
-	dirks, ok := ks.(*localcrypto.DirKeyStore)
-	if ok {
-		hexkey, err := dirks.GetEncodedPubkey(params.GroupId, localcrypto.Sign)
-		pubkeybytes, err := hex.DecodeString(hexkey)
-		p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
-		groupSignPubkey, err = p2pcrypto.MarshalPublicKey(p2ppubkey)
-		if err != nil {
-			output[ERROR_INFO] = "group key can't be decoded, err:" + err.Error()
-			return c.JSON(http.StatusBadRequest, output)
-		}	
-	}
+	ks := h.tenant(c).Keystore
+	hexkey, err := ks.GetEncodedPubkey(params.GroupId, localcrypto.Sign)
+	pubkeybytes, err := hex.DecodeString(hexkey)
+	p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
+	groupSignPubkey, err = p2pcrypto.MarshalPublicKey(p2ppubkey)
+	if err != nil {
+		output[ERROR_INFO] = "group key can't be decoded, err:" + err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}	
 
 	item := &chestnutpb.DenyUserItem{}
 	item.GroupId = params.GroupId
@@ -70,10 +66,13 @@ func (h *Handler) MgrGrpBlkList(c echo.Context) (err error) {
 	item.Action = params.Action
 	item.Memo = params.Memo
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[item.GroupId]; !ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(item.GroupId); !ok {
 		output[ERROR_INFO] = "Can not find group"
 		return c.JSON(http.StatusBadRequest, output)
+	} else if chain.IsAdminGroup(group.Item) {
+		output[ERROR_INFO] = "Group is owned by admins, propose the action with /api/v1/group/admin/propose"
+		return c.JSON(http.StatusBadRequest, output)
 	} else if group.Item.OwnerPubKey != group.Item.UserSignPubkey {
 		output[ERROR_INFO] = "Only group owner can add or remove user to blocklist"
 		return c.JSON(http.StatusBadRequest, output)
@@ -0,0 +1,48 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PauseGroupResult struct {
+	GroupId    string `json:"group_id"`
+	GroupState string `json:"group_state"`
+}
+
+// stop participating in a group without leaving it, the paused state is kept after restart
+func (h *Handler) PauseGroup(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if err := groupmgr.Pause(groupid); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	state, _ := groupmgr.GetState(groupid)
+	return c.JSON(http.StatusOK, &PauseGroupResult{GroupId: groupid, GroupState: state.String()})
+}
+
+func (h *Handler) ResumeGroup(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if err := groupmgr.Resume(groupid); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	state, _ := groupmgr.GetState(groupid)
+	return c.JSON(http.StatusOK, &PauseGroupResult{GroupId: groupid, GroupState: state.String()})
+}
@@ -7,8 +7,8 @@ import (
 
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
 )
 
 type CustomValidatorPost struct {
@@ -23,17 +23,41 @@ func (cv *CustomValidatorPost) Validate(i interface{}) error {
 	switch i.(type) {
 		case *chestnutpb.Activity:
 			inputobj := i.(*chestnutpb.Activity)
-			if inputobj.Type == Add {
-				if inputobj.Object != nil && inputobj.Target != nil {
-					if inputobj.Target.Type == Group && inputobj.Target.Id != "" {
-						if inputobj.Object.Type == Note && inputobj.Object.Content != "" {
-							return nil 
-						}
-						return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("unsupported object type: %s", inputobj.Object.Type))
+			if inputobj.Object == nil || inputobj.Target == nil {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("Object and Target Object must not be nil"))
+			}
+			if inputobj.Target.Type != Group || inputobj.Target.Id == "" {
+				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("Target Group must not be nil"))
+			}
+			switch inputobj.Type {
+			case Add:
+				//a post, or a reply if inreplyto is set
+				if inputobj.Object.Type != Note || !hasNoteContent(inputobj.Object) {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("unsupported object type: %s", inputobj.Object.Type))
+				}
+				if reply := inputobj.Object.Inreplyto; reply != nil {
+					if reply.Trxid == "" {
+						return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("inreplyto trxid must not be nil"))
+					}
+					if reply.Groupid != "" && reply.Groupid != inputobj.Target.Id {
+						return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("can not reply to a post of another group"))
 					}
-					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("Target Group must not be nil"))
 				}
-				return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("Object and Target Object must not be nil"))
+				return nil
+			case Like, Dislike, Delete:
+				//object id is the trx id of the post
+				if inputobj.Object.Id == "" {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("Object id must not be nil"))
+				}
+				return nil
+			case Update:
+				if inputobj.Object.Id == "" {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("Object id must not be nil"))
+				}
+				if inputobj.Object.Type != Note || !hasNoteContent(inputobj.Object) {
+					return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("unsupported object type: %s", inputobj.Object.Type))
+				}
+				return nil
 			}
 			return echo.NewHTTPError(http.StatusInternalServerError, fmt.Errorf("unknown type of Actitity: %s", inputobj.Type))
 		default:
@@ -45,6 +69,11 @@ func (cv *CustomValidatorPost) Validate(i interface{}) error {
 	return nil 
 }
 
+// a note has text content or attachments
+func hasNoteContent(obj *chestnutpb.Object) bool {
+	return obj.Content != "" || len(obj.Attachments) > 0 || len(obj.Image) > 0
+}
+
 func (h *Handler) PostToGroup(c echo.Context) (err error) {
 	output := make(map[string]string)
 	paramspb := new(chestnutpb.Activity)
@@ -59,9 +88,32 @@ func (h *Handler) PostToGroup(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[paramspb.Target.Id]; ok {
-		trxId, err := group.PostToGroup(paramspb.Object)
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(paramspb.Target.Id); ok {
+		//attachments must be uploaded to the group before posting
+		if err := group.CheckAttachments(paramspb.Object); err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+
+		var content proto.Message
+		switch paramspb.Type {
+		case Add:
+			content = paramspb.Object
+		default:
+			//reactions, edits and deletes refer to a post, the activity is posted so the verb is kept
+			trx, err := group.GetTrx(paramspb.Object.Id)
+			if err != nil || trx.GroupId != group.Item.GroupId {
+				output[ERROR_INFO] = fmt.Sprintf("Post %s not exist", paramspb.Object.Id)
+				return c.JSON(http.StatusBadRequest, output)
+			}
+			if (paramspb.Type == Update || paramspb.Type == Delete) && trx.SenderPubkey != group.Item.UserSignPubkey {
+				output[ERROR_INFO] = "only the author can edit or delete the post"
+				return c.JSON(http.StatusBadRequest, output)
+			}
+			content = paramspb
+		}
+		trxId, err := group.PostToGroup(content)
 
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
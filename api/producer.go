@@ -10,7 +10,6 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/lixvyang/chestnut/chain"
-	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 )
 
@@ -46,10 +45,13 @@ func (h *Handler) GroupProducer(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[params.GroupId]; !ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(params.GroupId); !ok {
 		output[ERROR_INFO] = "Can not find group"
 		return c.JSON(http.StatusBadRequest, output)
+	} else if chain.IsAdminGroup(group.Item) {
+		output[ERROR_INFO] = "Group is owned by admins, propose the action with /api/v1/group/admin/propose"
+		return c.JSON(http.StatusBadRequest, output)
 	} else if group.Item.OwnerPubKey != group.Item.UserSignPubkey {
 		output[ERROR_INFO] = "Only group owner can add or remove producer"
 		return c.JSON(http.StatusBadRequest, output)
@@ -76,7 +78,7 @@ func (h *Handler) GroupProducer(c echo.Context) (err error) {
 
 		hash := chain.Hash(buffer.Bytes())
 
-		ks := nodectx.GetNodeCtx().Keystore
+		ks := h.tenant(c).Keystore
 		signature, err := ks.SignByKeyName(item.GroupId, hash)
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
@@ -0,0 +1,96 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/chain"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+type RetentionParam struct {
+	GroupId     string `from:"group_id"     json:"group_id"     validate:"required"`
+	KeepDays    int64  `from:"keep_days"    json:"keep_days"    validate:"gte=0"`
+	KeepBlocks  int64  `from:"keep_blocks"  json:"keep_blocks"  validate:"gte=0"`
+	HeadersOnly bool   `from:"headers_only" json:"headers_only"`
+}
+
+type RetentionResult struct {
+	GroupId     string             `json:"group_id"`
+	KeepDays    int64              `json:"keep_days"`
+	KeepBlocks  int64              `json:"keep_blocks"`
+	HeadersOnly bool               `json:"headers_only"`
+	LastPrune   *chain.PruneResult `json:"last_prune"`
+	GCBytes     int64              `json:"gc_bytes"`
+}
+
+// set the local retention policy of group, keep_days and keep_blocks 0 means no limit
+func (h *Handler) UpdRetention(c echo.Context) (err error) {
+	output := make(map[string]string)
+	validate := validator.New()
+	params := new(RetentionParam)
+	if err = c.Bind(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	if err = validate.Struct(params); err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(params.GroupId); ok {
+		item := &chestnutpb.RetentionItem{
+			KeepDays:    params.KeepDays,
+			KeepBlocks:  params.KeepBlocks,
+			HeadersOnly: params.HeadersOnly,
+			TimeStamp:   time.Now().UnixNano(),
+		}
+		if err := group.UpdRetention(item); err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+		return c.JSON(http.StatusOK, params)
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", params.GroupId)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
+
+func (h *Handler) GetRetention(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	if groupid == "" {
+		output[ERROR_INFO] = "group_id can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		item, err := group.GetRetention()
+		if err != nil {
+			output[ERROR_INFO] = err.Error()
+			return c.JSON(http.StatusBadRequest, output)
+		}
+
+		result := &RetentionResult{GroupId: groupid}
+		if item != nil {
+			result.KeepDays = item.KeepDays
+			result.KeepBlocks = item.KeepBlocks
+			result.HeadersOnly = item.HeadersOnly
+		}
+		if pruner := chain.GetPruner(); pruner != nil {
+			result.LastPrune = pruner.GetResult(groupid)
+			result.GCBytes = pruner.GetGCBytes()
+		}
+		return c.JSON(http.StatusOK, result)
+	} else {
+		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", groupid)
+		return c.JSON(http.StatusBadRequest, output)
+	}
+}
@@ -12,7 +12,6 @@ import (
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/lixvyang/chestnut/chain"
 	localcrypto "github.com/lixvyang/chestnut/crypto"
-	"github.com/lixvyang/chestnut/nodectx"
 )
 
 
@@ -41,42 +40,28 @@ func (h *Handler) RmGroup(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest,output)
 	}
 
-	shouldRemove := false
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[params.GroupId]; ok {
-		err := group.DelGrp()
-
+	groupmgr := h.groupMgr(c)
+	if _, ok := groupmgr.Get(params.GroupId); ok {
+		err := groupmgr.Delete(params.GroupId)
 		if err != nil {
 			output[ERROR_INFO] = err.Error()
 			return c.JSON(http.StatusBadRequest,output)
 		}
-
-		shouldRemove = true
 	} else {
 		output[ERROR_INFO] = fmt.Sprintf("Group %s not exist", params.GroupId)
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	if shouldRemove {
-		delete(groupmgr.Groups, params.GroupId)
-	}
-
 	var groupSignPubkey []byte
-	ks := nodectx.GetNodeCtx().Keystore
-	dirks, ok := ks.(*localcrypto.DirKeyStore)
-	if !ok {
-		//use "default" key for all groups
-		//TODO: user can create new sign keys for each groups
-		hexkey, err := dirks.GetEncodedPubkey(params.GroupId, localcrypto.Sign)
-		pubkeybytes, err := hex.DecodeString(hexkey)
-		p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
-		groupSignPubkey, err = p2pcrypto.MarshalPublicKey(p2ppubkey)
-		if err != nil {
-			output[ERROR_INFO] = "group key can't be decoded, err:" + err.Error()
-			return c.JSON(http.StatusBadRequest, output)
-		}
-	} else {
-		output[ERROR_INFO] = fmt.Sprintf("unknown keystore type  %v:", ks)
+	ks := h.tenant(c).Keystore
+	//use "default" key for all groups
+	//TODO: user can create new sign keys for each groups
+	hexkey, err := ks.GetEncodedPubkey(params.GroupId, localcrypto.Sign)
+	pubkeybytes, err := hex.DecodeString(hexkey)
+	p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
+	groupSignPubkey, err = p2pcrypto.MarshalPublicKey(p2ppubkey)
+	if err != nil {
+		output[ERROR_INFO] = "group key can't be decoded, err:" + err.Error()
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
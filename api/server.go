@@ -4,6 +4,7 @@ package api
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"syscall"
 
@@ -25,7 +26,9 @@ func StartAPIServer(config cli.Config, signalch chan os.Signal,h *Handler, apph
 	e := echo.New()
 	e.Binder = new(CustomBinder)
 	r := e.Group("api")
+	r.Use(TenantMiddleware(nodeopt.TenantTokens))
 	a := e.Group("app/api")
+	a.Use(TenantMiddleware(nodeopt.TenantTokens))
 	s := e.Group("sd")
 	r.GET("/quit", quitapp)
 	// Check sd info.
@@ -50,9 +53,23 @@ func StartAPIServer(config cli.Config, signalch chan os.Signal,h *Handler, apph
 		r.POST("v1/group/announce", h.Announce)
 		r.POST("/v1/group/schema", h.Schema)
 		r.POST("/v1/group/:group_id/startsync", h.StartSync)
+		r.POST("/v1/group/:group_id/pause", h.PauseGroup)
+		r.POST("/v1/group/:group_id/resume", h.ResumeGroup)
+		r.POST("/v1/group/retention", h.UpdRetention)
+		r.POST("/v1/group/admin/propose", h.ProposeAdminAction)
+		r.POST("/v1/group/admin/cosign", h.CosignAdminAction)
+		r.POST("/v1/group/admin/submit", h.SubmitAdminAction)
+		r.POST("/v1/group/invite", h.CreateInvite)
+		r.POST("/v1/group/invite/revoke", h.RevokeInvite)
+		r.POST("/v1/group/producer/policy", h.UpdDemotionPolicy)
+		r.POST("/v1/group/config", h.UpdGroupConfig)
+		r.POST("/v1/group/light", h.UpdLightMode)
+		r.POST("/v1/group/cipherkey", h.UpdCipherKey)
+		r.POST("/v1/group/light/fetch", h.FetchTrx)
 		r.GET("v1/network", h.GetNetwork(&node.Host, node.Info, nodeopt, ethaddr))
 		r.POST("/v1/psping", h.PSPingPeer(node))
 		r.GET("/v1/block/:group_id/:block_id", h.GetBlockById)
+		r.GET("/v1/block/:group_id/hash/:block_hash", h.GetBlockByHash)
 		r.GET("/v1/trx/:group_id/:trx_id", h.GetTrx)
 		r.GET("/v1/groups", h.GetGroups)
 		r.GET("/v1/group/:group_id/content", h.GetGroupCtn)
@@ -61,10 +78,34 @@ func StartAPIServer(config cli.Config, signalch chan os.Signal,h *Handler, apph
 		r.GET("/v1/group/:group_id/announced/users", h.GetAnnouncedGroupUsers)
 		r.GET("/v1/group/:group_id/announced/producers", h.GetAnnouncedGroupProducer)
 		r.GET("/v1/group/:group_id/app/schema", h.GetGroupAppSchema)
+		r.GET("/v1/group/:group_id/block", h.GetBlockByHeight)
+		r.GET("/v1/group/:group_id/trxs", h.GetGroupTrxs)
+		r.GET("/v1/group/:group_id/retention", h.GetRetention)
+		r.GET("/v1/group/:group_id/admin/actions", h.GetAdminActions)
+		r.GET("/v1/group/:group_id/invites", h.GetInvites)
+		r.GET("/v1/group/:group_id/producer/policy", h.GetDemotionPolicy)
+		r.GET("/v1/group/:group_id/config", h.GetGroupConfig)
+		r.GET("/v1/group/:group_id/light", h.GetLightMode)
+		r.GET("/v1/group/:group_id/export", h.ExportGroupBlocks)
+		r.POST("/v1/group/:group_id/import", h.ImportGroupBlocks)
+		r.POST("/v1/group/:group_id/blob", h.UploadBlob)
+		r.GET("/v1/group/:group_id/blob/:blob_id", h.DownloadBlob)
+		r.GET("/v1/keys", h.ListKeys)
+		r.GET("/v1/keys/orphans", h.GetKeyOrphans)
+		r.POST("/v1/keys/export", h.ExportKey)
+		r.POST("/v1/keys/import", h.ImportKey)
+		r.POST("/v1/keys/rename", h.RenameKey)
+		r.POST("/v1/keys/passwd", h.ChangeKeystorePassword)
+		r.DELETE("/v1/keys", h.DeleteKey)
 
 		
 
 		a.POST("/v1/group/:group_id/content", apph.ContentByPeers)
+		a.GET("/v1/group/:group_id/content/:trx_id", apph.ContentThread)
+		a.GET("/v1/group/:group_id/search", apph.SearchContent)
+		a.POST("/v1/group/:group_id/search/rebuild", apph.RebuildSearchIndex)
+		a.GET("/v1/group/:group_id/sync", apph.GetGroupSyncStatus)
+		a.GET("/v1/sync", apph.GetSyncStatus)
 		// a.POST("/v1/token/apply", apph.ApplyToken)
 		// a.POST("/v1/token/refresh", apph.RefreshToken)
 
@@ -92,7 +133,13 @@ func (cb *CustomBinder) Bind(i interface{}, c echo.Context) (err error) {
 	}
 }
 
+// only the node itself can stop the node, tenants are refused
 func quitapp(c echo.Context) (err error) {
+	if !requestTenant(c).IsDefault {
+		output := make(map[string]string)
+		output[ERROR_INFO] = "only the node can quit"
+		return c.JSON(http.StatusForbidden, output)
+	}
 	fmt.Println("/api/quit has been called, send Signal SIGNERM...")
 	quitch <- syscall.SIGTERM
 	return nil
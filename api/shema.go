@@ -10,7 +10,6 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 	"github.com/lixvyang/chestnut/chain"
-	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 )
 
@@ -59,10 +58,13 @@ func (h *Handler) Schema(c echo.Context) (err error) {
 	item.Type = params.Type
 	item.Rule = params.Rule
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[item.GroupId]; !ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(item.GroupId); !ok {
 		output[ERROR_INFO] = "Can not find group"
 		return c.JSON(http.StatusBadRequest, output)
+	} else if chain.IsAdminGroup(group.Item) {
+		output[ERROR_INFO] = "Group is owned by admins, propose the action with /api/v1/group/admin/propose"
+		return c.JSON(http.StatusBadRequest, output)
 	} else if group.Item.OwnerPubKey != group.Item.UserSignPubkey {
 		output[ERROR_INFO] = "Only group owner can add or remove schema to group"
 		return c.JSON(http.StatusBadRequest, output)
@@ -85,7 +87,7 @@ func (h *Handler) Schema(c echo.Context) (err error) {
 		buffer.Write([]byte(item.GroupOwnerPubkey))
 		hash := chain.Hash(buffer.Bytes())
 
-		ks := nodectx.GetNodeCtx().Keystore
+		ks := h.tenant(c).Keystore
 		signature, err := ks.SignByKeyName(item.GroupId, hash)
 
 		if err != nil {
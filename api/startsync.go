@@ -21,14 +21,14 @@ func (h *Handler) StartSync(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[groupid]; ok {
-		if group.ChainCtx.Syncer.Status == chain.SYNCING_BACKWARD || group.ChainCtx.Syncer.Status == chain.SYNCING_FORWARD {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(groupid); ok {
+		if group.ChainCtx.Syncer.GetStatus() == chain.SYNCING_BACKWARD || group.ChainCtx.Syncer.GetStatus() == chain.SYNCING_FORWARD {
 			error_info := "GROUP_ALREADY_IN_SYNCING"
 			startSyncResult := &StartSyncResult{GroupId: group.Item.GroupId, Error: error_info}
 			return c.JSON(http.StatusBadRequest, startSyncResult)
 		} else {
-			err := group.StartSync()
+			err := groupmgr.StartSync(group.Item.GroupId)
 			if err == nil {
 				startSyncResult := &StartSyncResult{GroupId: group.Item.GroupId, Error: ""}
 				return c.JSON(http.StatusOK, startSyncResult)
@@ -0,0 +1,66 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/chain"
+	"github.com/lixvyang/chestnut/nodectx"
+)
+
+// Select the tenant of a request by the bearer token, tokens maps tenant names to tokens.
+// Requests without a token are served by the default tenant only if the node hosts no tenants,
+// otherwise the node itself has a token too and every request must carry one.
+func TenantMiddleware(tokens map[string]string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			output := make(map[string]string)
+			auth := c.Request().Header.Get(echo.HeaderAuthorization)
+			if auth == "" {
+				if len(nodectx.GetNodeCtx().ListTenants()) > 0 {
+					output[ERROR_INFO] = "token is required"
+					return c.JSON(http.StatusUnauthorized, output)
+				}
+				return next(c)
+			}
+			token := strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+			for name, t := range tokens {
+				if t != "" && subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+					if _, ok := nodectx.GetNodeCtx().GetTenant(name); !ok {
+						// the token of a tenant not hosted by this run must not fall back to the default tenant
+						break
+					}
+					c.Set(nodectx.TENANT_CONTEXT_KEY, name)
+					return next(c)
+				}
+			}
+			output[ERROR_INFO] = "invalid token"
+			return c.JSON(http.StatusUnauthorized, output)
+		}
+	}
+}
+
+// the tenant selected by the request token
+func (h *Handler) tenant(c echo.Context) *nodectx.Tenant {
+	return requestTenant(c)
+}
+
+func requestTenant(c echo.Context) *nodectx.Tenant {
+	name, _ := c.Get(nodectx.TENANT_CONTEXT_KEY).(string)
+	if tenant, ok := nodectx.GetNodeCtx().GetTenant(name); ok {
+		return tenant
+	}
+	return nodectx.GetNodeCtx().DefaultTenant()
+}
+
+// the GroupMgr of the tenant selected by the request token
+func (h *Handler) groupMgr(c echo.Context) *chain.GroupMgr {
+	name, _ := c.Get(nodectx.TENANT_CONTEXT_KEY).(string)
+	if groupmgr, ok := chain.GetTenantGroupMgr(name); ok {
+		return groupmgr
+	}
+	return chain.GetGroupMgr()
+}
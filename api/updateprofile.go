@@ -11,7 +11,6 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/labstack/echo/v4"
 
-	"github.com/lixvyang/chestnut/chain"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 )
 type CustomValidatorProfile struct {
@@ -58,8 +57,8 @@ func (h *Handler) UpdateProfile(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	if group, ok := groupmgr.Groups[paramspb.Target.Id]; ok {
+	groupmgr := h.groupMgr(c)
+	if group, ok := groupmgr.Get(paramspb.Target.Id); ok {
 		if paramspb.Person.Image != nil {
 			_, formatname, err := image.Decode(bytes.NewReader(paramspb.Person.Image.Content))
 			if err != nil {
@@ -2,22 +2,23 @@
 package appdata
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"sync"
 
-	"github.com/dgraph-io/badger/v3"
 	"github.com/google/orderedcode"
 	logging "github.com/ipfs/go-log/v2"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
 )
 
 type AppDb struct {
 	Db storage.ChestnutStorage
 	seq map[string]storage.Sequence
 	DataPath string
+	mu sync.Mutex //serialize index updates by AppSync, rebuilds and pruning
 }
 
 var appdatalog = logging.Logger("appdata")
@@ -39,6 +40,11 @@ func NewAppDb() *AppDb {
 	return &app
 }
 
+// Run pending schema migrations of AppDb
+func (appdb *AppDb) TryMigration() error {
+	return storage.Migrate(storage.APP_DB, appdb.Db)
+}
+
 func (appdb *AppDb) GetGroupStatus(groupid, name string) (string, error) {
 	key := fmt.Sprintf("%s%s_%s", STATUS_PREFIX, groupid, name)
 	exist, err := appdb.Db.IsExist([]byte(key))
@@ -63,10 +69,6 @@ func (appdb *AppDb) GetSeqId(seqkey string) (uint64, error) {
 	return appdb.seq[seqkey].Next()
 }
 
-func (appdb *AppDb) Rebuild(vertag string, chainDb *badger.DB) error {
-	return nil
-}
-
 func (appdb *AppDb) GetGroupContentBySenders(groupid string, senders []string, starttrx string, num int, reverse bool) ([]string, error) {
 	prefix := fmt.Sprintf("%s%s-%s", CNT_PREFIX, GRP_PREFIX, groupid)
 	sendermap := make(map[string]bool)
@@ -90,10 +92,11 @@ func (appdb *AppDb) GetGroupContentBySenders(groupid string, senders []string, s
 			return err
 		}
 
-		dataidx := bytes.LastIndexByte(k, byte('_'))
-		trxid := string(k[len(k)-37-1 : len(k)-1-1])
+		sender, trxid, ok := parseContentKey(k)
+		if !ok {
+			return nil
+		}
 		if runcollector == true {
-			sender := string(k[dataidx+1+2 : len(k)-37-2]) //+2/-2 for remove the term, len(term)=2
 			if len(senders) == 0 || sendermap[sender] == true {
 				trxids = append(trxids, trxid)
 			}
@@ -119,14 +122,40 @@ func getKey(prefix string, seqid uint64, tailing string) ([]byte, error) {
 	return orderedcode.Append(nil, prefix, "-", orderedcode.Infinity, uint64(seqid), "_", tailing)
 }
 
-func (appdb *AppDb) AddMetaByTrx(blockId string, groupid string, trxs []*chestnutpb.Trx) error {
+// Index trxs of a block, contents are the decoded trx data keyed by trxid.
+// Activities refer to a post (reactions, edits and deletes) are not listed as group content.
+func (appdb *AppDb) AddMetaByTrx(blockId string, groupid string, trxs []*chestnutpb.Trx, contents map[string]proto.Message) error {
+	appdb.mu.Lock()
+	defer appdb.mu.Unlock()
+	return appdb.addMetaByTrx(blockId, groupid, trxs, contents)
+}
+
+// must be called with mu held
+func (appdb *AppDb) addMetaByTrx(blockId string, groupid string, trxs []*chestnutpb.Trx, contents map[string]proto.Message) error {
 	var err error
 
 	seqkey := SEQ_PREFIX + CNT_PREFIX + GRP_PREFIX + groupid
 
-	keylist := [][]byte{}
+	txn, err := appdb.Db.BeginTxn()
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	keys := [][]byte{}
+	values := [][]byte{}
 	for _, trx := range trxs {
 		if trx.Type == chestnutpb.TrxType_POST {
+			content, ok := contents[trx.TrxId]
+			if ok {
+				if err := appdb.addSocialByTrx(txn, groupid, trx, content); err != nil {
+					return err
+				}
+				if _, isActivity := content.(*chestnutpb.Activity); isActivity {
+					continue
+				}
+			}
+
 			seqid, err := appdb.GetSeqId(seqkey)
 			if err != nil {
 				return err
@@ -136,26 +165,21 @@ func (appdb *AppDb) AddMetaByTrx(blockId string, groupid string, trxs []*chestnu
 			if err != nil {
 				return err
 			}
-			keylist = append(keylist, key)
+			keys = append(keys, key)
+			values = append(values, nil)
 		}
 	}
 
-	keys := [][]byte{}
-	values := [][]byte{}
-
-	for _, key := range keylist {
-		keys = append(keys, key)
-		values = append(values, nil)
-	}
-
 	valuename := "HighestBlockId"
 	groupLastestBlockidkey := fmt.Sprintf("%s%s_%s", STATUS_PREFIX, groupid, valuename)
 	keys = append(keys, []byte(groupLastestBlockidkey))
 	values = append(values, []byte(blockId))
 
-	err = appdb.Db.BatchWrite(keys, values)
+	if err = txn.BatchWrite(keys, values); err != nil {
+		return err
+	}
 
-	return err
+	return txn.Commit()
 }
 
 func (appdb *AppDb) Release() error {
@@ -183,6 +207,19 @@ func (appdb *AppDb) SetGroupSeed(seed *chestnutpb.GroupSeed) error {
 	return appdb.Db.Set(key, value)
 }
 
+// seed of a group created by the node
+func (appdb *AppDb) GetGroupSeed(groupID string) (*chestnutpb.GroupSeed, error) {
+	value, err := appdb.Db.Get(groupSeedKey(groupID))
+	if err != nil {
+		return nil, err
+	}
+	seed := &chestnutpb.GroupSeed{}
+	if err := json.Unmarshal(value, seed); err != nil {
+		return nil, err
+	}
+	return seed, nil
+}
+
 func groupSeedKey(groupID string) []byte {
 	return []byte(fmt.Sprintf("%s%s", SED_PREFIX, groupID))
 }
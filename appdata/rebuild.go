@@ -0,0 +1,172 @@
+// Package appdata provides storage for chestnut.
+package appdata
+
+import (
+	"fmt"
+	"strings"
+
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+// Version tag of the app indexes, bump it when the indexes change and
+// the app db is rebuilt from the chain store on the next start.
+const INDEX_VERSION_TAG = "3"
+
+const VER_PREFIX string = "ver_"
+
+const indexVersionKey = VER_PREFIX + "index"
+
+func (appdb *AppDb) GetIndexVersion() (string, error) {
+	value, err := appdb.Db.Get([]byte(indexVersionKey))
+	if err == storage.ErrKeyNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// Rebuild the app db if the index version tag saved differs from INDEX_VERSION_TAG.
+// groups are keyed by the node name their chain data is saved under and must contain
+// every group saved in the db, loaded or not, since the app keys of all groups are removed.
+// keystores are keyed by the same node names, posts of private groups are decrypted by them.
+func (appdb *AppDb) TryRebuild(dbmgr *storage.DbMgr, groups map[string][]*chestnutpb.GroupItem, keystores map[string]localcrypto.Keystore) error {
+	vertag, err := appdb.GetIndexVersion()
+	if err != nil {
+		return err
+	}
+	if vertag == INDEX_VERSION_TAG {
+		return nil
+	}
+	appdatalog.Infof("app db index version %q, expect %q, rebuild", vertag, INDEX_VERSION_TAG)
+	return appdb.Rebuild(INDEX_VERSION_TAG, dbmgr, groups, keystores)
+}
+
+// Rebuild all app indexes from the chain store: content and sender keys, thread, reaction,
+// overlay and search indexes, and the HighestBlockId status of each group.
+// Only blocks on the canonical branch (from the GenesisBlock to the HighestBlockId) are indexed,
+// the branch is walked back from the HighestBlockId by PrevBlockId (see CanonicalBlockIds), not
+// forward from the GenesisBlock by GetSubBlock, which can't tell forks from the canonical branch.
+// Group seeds, sequences and schema versions are kept. vertag is saved after all groups are rebuilt.
+func (appdb *AppDb) Rebuild(vertag string, dbmgr *storage.DbMgr, groups map[string][]*chestnutpb.GroupItem, keystores map[string]localcrypto.Keystore) error {
+	appdb.mu.Lock()
+	defer appdb.mu.Unlock()
+
+	keys := [][]byte{}
+	err := appdb.Db.PrefixForeachKey([]byte{}, []byte{}, false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		key := string(k)
+		if strings.HasPrefix(key, SED_PREFIX) || strings.HasPrefix(key, SEQ_PREFIX) || storage.IsDataVersionKey(k) {
+			return nil
+		}
+		keys = append(keys, k)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	appdatalog.Infof("Rebuild: remove %d key(s)", len(keys))
+	for _, k := range keys {
+		if err := appdb.Db.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	for nodename, items := range groups {
+		for _, groupitem := range items {
+			if err := appdb.rebuildGroup(dbmgr, groupitem, nodename, keystores[nodename]); err != nil {
+				return err
+			}
+		}
+	}
+
+	return appdb.Db.Set([]byte(indexVersionKey), []byte(vertag))
+}
+
+// Rebuild indexes of a group up to the head block, used when the indexed branch is no longer canonical
+func (appdb *AppDb) RebuildGroup(dbmgr *storage.DbMgr, groupitem *chestnutpb.GroupItem, headBlockId string, nodename string, ks localcrypto.Keystore) error {
+	appdb.mu.Lock()
+	defer appdb.mu.Unlock()
+
+	groupid := groupitem.GroupId
+	prefixes := []string{
+		fmt.Sprintf("%s%s-%s", CNT_PREFIX, GRP_PREFIX, groupid),
+		fmt.Sprintf("%s%s_", STATUS_PREFIX, groupid),
+		fmt.Sprintf("%s%s_", PST_PREFIX, groupid),
+		fmt.Sprintf("%s%s_", RPL_PREFIX, groupid),
+		fmt.Sprintf("%s%s_", RCT_PREFIX, groupid),
+		fmt.Sprintf("%s%s_", RCS_PREFIX, groupid),
+		fmt.Sprintf("%s%s_", OVL_PREFIX, groupid),
+		fmt.Sprintf("%s%s_", IDX_PREFIX, groupid),
+		fmt.Sprintf("%s%s_", IDD_PREFIX, groupid),
+		fmt.Sprintf("%s%s", IST_PREFIX, groupid),
+	}
+	for _, prefix := range prefixes {
+		if err := appdb.removePrefix(prefix); err != nil {
+			return err
+		}
+	}
+	item := proto.Clone(groupitem).(*chestnutpb.GroupItem)
+	item.HighestBlockId = headBlockId
+	return appdb.rebuildGroup(dbmgr, item, nodename, ks)
+}
+
+// index blocks of the canonical branch from the GenesisBlock to the HighestBlockId of group,
+// in chain order as collected by CanonicalBlockIds
+func (appdb *AppDb) rebuildGroup(dbmgr *storage.DbMgr, groupitem *chestnutpb.GroupItem, nodename string, ks localcrypto.Keystore) error {
+	groupid := groupitem.GroupId
+	if groupitem.GenesisBlock == nil {
+		appdatalog.Warningf("<%s> no genesis block, skip rebuild", groupid)
+		return nil
+	}
+
+	head := groupitem.HighestBlockId
+	if head == "" {
+		head = groupitem.GenesisBlock.BlockId
+	}
+	blockIds, _, err := CanonicalBlockIds(dbmgr, head, groupitem.GenesisBlock.BlockId, groupitem.GenesisBlock.BlockId, nodename)
+	if err != nil {
+		return err
+	}
+	for _, blockId := range blockIds {
+		blk, err := dbmgr.GetBlock(blockId, false, nodename)
+		if err != nil {
+			return err
+		}
+		if err := appdb.addMetaByTrx(blk.BlockId, groupid, blk.Trxs, DecodeTrxContents(groupitem, blk.Trxs, ks)); err != nil {
+			return err
+		}
+	}
+	appdatalog.Infof("<%s> rebuild %d block(s)", groupid, len(blockIds))
+	return nil
+}
+
+// Ids of the blocks after stop on the branch from genesis to head, in chain order.
+// Blocks are walked back from head by the parent block, since a block may have more
+// than one sub block before the chain is trimmed. found is false if stop is not an
+// ancestor of head (stop is on a fork), the ids are the whole branch after genesis then.
+func CanonicalBlockIds(dbmgr *storage.DbMgr, head string, stop string, genesis string, nodename string) ([]string, bool, error) {
+	blockIds := []string{}
+	blockId := head
+	for blockId != stop && blockId != genesis {
+		blockIds = append(blockIds, blockId)
+		blk, err := dbmgr.GetBlock(blockId, false, nodename)
+		if err != nil {
+			return nil, false, err
+		}
+		if blk.PrevBlockId == "" {
+			return nil, false, fmt.Errorf("block %s has no parent", blockId)
+		}
+		blockId = blk.PrevBlockId
+	}
+
+	for i, j := 0, len(blockIds)-1; i < j; i, j = i+1, j-1 {
+		blockIds[i], blockIds[j] = blockIds[j], blockIds[i]
+	}
+	return blockIds, blockId == stop, nil
+}
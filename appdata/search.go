@@ -0,0 +1,450 @@
+// Package appdata provides storage for chestnut.
+package appdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+)
+
+const (
+	IDX_PREFIX string = "idx_" //inverted index, idx_<groupid>_<term>_<trxid> -> weighted term frequency
+	IDD_PREFIX string = "idd_" //indexed doc, idd_<groupid>_<trxid> -> searchDoc
+	IST_PREFIX string = "ist_" //index stats, ist_<groupid> -> searchStats
+)
+
+const maxTermLength = 64
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// fields of Object are indexed with weights
+var searchFieldWeights = map[string]int{
+	"name":    3,
+	"summary": 2,
+	"tag":     2,
+	"content": 1,
+}
+
+// terms of an indexed post, kept to update the index on edit or delete
+type searchDoc struct {
+	Fields    map[string]map[string]int //term counts of each field
+	TimeStamp int64
+}
+
+type searchStats struct {
+	Docs     int64
+	TotalLen int64
+}
+
+type SearchResult struct {
+	TrxId     string
+	Score     float64
+	TimeStamp int64
+}
+
+// Split text to lower case terms. Letters and digits are split on other characters,
+// runs of CJK characters are split to bigrams since they are not separated by spaces.
+func tokenize(text string) []string {
+	terms := []string{}
+	word := []rune{}
+	cjk := []rune{}
+	flushWord := func() {
+		if len(word) > 0 && len(string(word)) <= maxTermLength {
+			terms = append(terms, string(word))
+		}
+		word = word[:0]
+	}
+	flushCjk := func() {
+		if len(cjk) == 1 {
+			terms = append(terms, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			terms = append(terms, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case isCJK(r):
+			flushWord()
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushCjk()
+			word = append(word, r)
+		default:
+			flushWord()
+			flushCjk()
+		}
+	}
+	flushWord()
+	flushCjk()
+	return terms
+}
+
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul)
+}
+
+func countTerms(text string) map[string]int {
+	counts := make(map[string]int)
+	for _, term := range tokenize(text) {
+		counts[term]++
+	}
+	return counts
+}
+
+// searchable text of an object by field
+func objectFields(obj *chestnutpb.Object) map[string]string {
+	tags := []string{}
+	for _, tag := range obj.Tag {
+		tags = append(tags, tag.Name, tag.Content)
+	}
+	return map[string]string{
+		"name":    obj.Name,
+		"summary": obj.Summary,
+		"tag":     strings.Join(tags, " "),
+		"content": obj.Content,
+	}
+}
+
+func (doc *searchDoc) weighted() map[string]int {
+	result := make(map[string]int)
+	if doc == nil {
+		return result
+	}
+	for field, counts := range doc.Fields {
+		for term, count := range counts {
+			result[term] += count * searchFieldWeights[field]
+		}
+	}
+	return result
+}
+
+func (doc *searchDoc) length() int64 {
+	var length int64
+	if doc == nil {
+		return 0
+	}
+	for _, counts := range doc.Fields {
+		for _, count := range counts {
+			length += int64(count)
+		}
+	}
+	return length
+}
+
+func (appdb *AppDb) getSearchDoc(db storage.ChestnutStorage, groupid string, trxid string) (*searchDoc, error) {
+	key := fmt.Sprintf("%s%s_%s", IDD_PREFIX, groupid, trxid)
+	value, err := db.Get([]byte(key))
+	if err == storage.ErrKeyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	doc := &searchDoc{}
+	if err := json.Unmarshal(value, doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (appdb *AppDb) getSearchStats(db storage.ChestnutStorage, groupid string) (*searchStats, error) {
+	key := fmt.Sprintf("%s%s", IST_PREFIX, groupid)
+	stats := &searchStats{}
+	value, err := db.Get([]byte(key))
+	if err == storage.ErrKeyNotFound {
+		return stats, nil
+	} else if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(value, stats)
+	return stats, err
+}
+
+// Index fields of a post, fields not given are kept from the indexed doc (e.g. on edit).
+func (appdb *AppDb) indexSearchDoc(db storage.ChestnutStorage, groupid string, trxid string, timestamp int64, fields map[string]string) error {
+	old, err := appdb.getSearchDoc(db, groupid, trxid)
+	if err != nil {
+		return err
+	}
+
+	doc := &searchDoc{Fields: make(map[string]map[string]int), TimeStamp: timestamp}
+	if old != nil {
+		doc.TimeStamp = old.TimeStamp
+		for field, counts := range old.Fields {
+			doc.Fields[field] = counts
+		}
+	}
+	for field, text := range fields {
+		counts := countTerms(text)
+		if len(counts) == 0 {
+			delete(doc.Fields, field)
+		} else {
+			doc.Fields[field] = counts
+		}
+	}
+	return appdb.putSearchDoc(db, groupid, trxid, old, doc)
+}
+
+func (appdb *AppDb) removeSearchDoc(db storage.ChestnutStorage, groupid string, trxid string) error {
+	old, err := appdb.getSearchDoc(db, groupid, trxid)
+	if err != nil || old == nil {
+		return err
+	}
+	return appdb.putSearchDoc(db, groupid, trxid, old, nil)
+}
+
+// replace the postings of old doc by the postings of doc, doc is removed if nil
+func (appdb *AppDb) putSearchDoc(db storage.ChestnutStorage, groupid string, trxid string, old *searchDoc, doc *searchDoc) error {
+	oldTerms := old.weighted()
+	newTerms := doc.weighted()
+	for term := range oldTerms {
+		if _, ok := newTerms[term]; !ok {
+			key := fmt.Sprintf("%s%s_%s_%s", IDX_PREFIX, groupid, term, trxid)
+			if err := db.Delete([]byte(key)); err != nil {
+				return err
+			}
+		}
+	}
+	for term, tf := range newTerms {
+		if oldTerms[term] == tf {
+			continue
+		}
+		key := fmt.Sprintf("%s%s_%s_%s", IDX_PREFIX, groupid, term, trxid)
+		if err := db.Set([]byte(key), []byte(fmt.Sprint(tf))); err != nil {
+			return err
+		}
+	}
+
+	stats, err := appdb.getSearchStats(db, groupid)
+	if err != nil {
+		return err
+	}
+	stats.TotalLen += doc.length() - old.length()
+	docKey := fmt.Sprintf("%s%s_%s", IDD_PREFIX, groupid, trxid)
+	if doc == nil || len(doc.Fields) == 0 {
+		if old != nil {
+			stats.Docs--
+		}
+		if err := db.Delete([]byte(docKey)); err != nil {
+			return err
+		}
+	} else {
+		if old == nil {
+			stats.Docs++
+		}
+		value, err := json.Marshal(doc)
+		if err != nil {
+			return err
+		}
+		if err := db.Set([]byte(docKey), value); err != nil {
+			return err
+		}
+	}
+
+	value, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return db.Set([]byte(fmt.Sprintf("%s%s", IST_PREFIX, groupid)), value)
+}
+
+// Search posts of group, results are ranked by BM25 and paged by offset and num.
+// Returns the page of results and the total number of matched posts.
+func (appdb *AppDb) Search(groupid string, query string, offset int, num int) ([]*SearchResult, int, error) {
+	terms := []string{}
+	seen := make(map[string]bool)
+	for _, term := range tokenize(query) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	if len(terms) == 0 {
+		return nil, 0, errors.New("empty query")
+	}
+
+	stats, err := appdb.getSearchStats(appdb.Db, groupid)
+	if err != nil {
+		return nil, 0, err
+	}
+	if stats.Docs == 0 {
+		return []*SearchResult{}, 0, nil
+	}
+	avgLen := float64(stats.TotalLen) / float64(stats.Docs)
+
+	scores := make(map[string]*SearchResult)
+	docs := make(map[string]*searchDoc)
+	for _, term := range terms {
+		prefix := fmt.Sprintf("%s%s_%s_", IDX_PREFIX, groupid, term)
+		postings := make(map[string]int)
+		err := appdb.Db.PrefixForeach([]byte(prefix), func(k []byte, v []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			tf, err := strconv.Atoi(string(v))
+			if err != nil {
+				return err
+			}
+			postings[string(k[len(prefix):])] = tf
+			return nil
+		})
+		if err != nil {
+			return nil, 0, err
+		}
+
+		df := float64(len(postings))
+		idf := math.Log(1 + (float64(stats.Docs)-df+0.5)/(df+0.5))
+		for trxid, tf := range postings {
+			doc, ok := docs[trxid]
+			if !ok {
+				doc, err = appdb.getSearchDoc(appdb.Db, groupid, trxid)
+				if err != nil {
+					return nil, 0, err
+				}
+				docs[trxid] = doc
+			}
+			if doc == nil {
+				continue
+			}
+			result, ok := scores[trxid]
+			if !ok {
+				result = &SearchResult{TrxId: trxid, TimeStamp: doc.TimeStamp}
+				scores[trxid] = result
+			}
+			norm := 1 - bm25B + bm25B*float64(doc.length())/avgLen
+			result.Score += idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + bm25K1*norm)
+		}
+	}
+
+	results := make([]*SearchResult, 0, len(scores))
+	for _, result := range scores {
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].TimeStamp > results[j].TimeStamp
+	})
+
+	total := len(results)
+	if offset >= total {
+		return []*SearchResult{}, total, nil
+	}
+	end := total
+	if num > 0 && offset+num < total {
+		end = offset + num
+	}
+	return results[offset:end], total, nil
+}
+
+// Rebuild the search index of group from the trxs in the chain store.
+// Edits and deletes are applied from the post overlays, posts of a private group are decrypted by ks.
+// Returns the number of indexed posts.
+func (appdb *AppDb) RebuildSearchIndex(groupitem *chestnutpb.GroupItem, dbmgr *storage.DbMgr, nodename string, ks localcrypto.Keystore) (int64, error) {
+	appdb.mu.Lock()
+	defer appdb.mu.Unlock()
+
+	groupid := groupitem.GroupId
+	appdatalog.Infof("<%s> RebuildSearchIndex called", groupid)
+	for _, prefix := range []string{IDX_PREFIX + groupid + "_", IDD_PREFIX + groupid + "_", IST_PREFIX + groupid} {
+		if err := appdb.removePrefix(prefix); err != nil {
+			return 0, err
+		}
+	}
+
+	trxids, err := dbmgr.GetTrxIdsByGroup(groupid, "", 0, false, nodename)
+	if err != nil {
+		return 0, err
+	}
+	for _, trxid := range trxids {
+		trx, err := dbmgr.GetTrx(trxid, nodename)
+		if err != nil {
+			return 0, err
+		}
+		if trx.Type != chestnutpb.TrxType_POST || len(trx.Data) == 0 {
+			continue
+		}
+		content, _, err := DecodeTrxContent(groupitem, trx, ks)
+		if err != nil {
+			appdatalog.Warningf("<%s> decode trx %s err: %s", groupid, trxid, err)
+			continue
+		}
+		if obj, ok := content.(*chestnutpb.Object); ok {
+			if err := appdb.indexSearchDoc(appdb.Db, groupid, trxid, trx.TimeStamp, objectFields(obj)); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	//apply edits and deletes
+	prefix := fmt.Sprintf("%s%s_", OVL_PREFIX, groupid)
+	overlays := make(map[string]*PostOverlay)
+	err = appdb.Db.PrefixForeach([]byte(prefix), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		overlay := &PostOverlay{}
+		if err := json.Unmarshal(v, overlay); err != nil {
+			return err
+		}
+		overlays[string(k[len(prefix):])] = overlay
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for trxid, overlay := range overlays {
+		if err := appdb.applySearchOverlay(appdb.Db, groupid, trxid, overlay); err != nil {
+			return 0, err
+		}
+	}
+
+	stats, err := appdb.getSearchStats(appdb.Db, groupid)
+	if err != nil {
+		return 0, err
+	}
+	return stats.Docs, nil
+}
+
+func (appdb *AppDb) applySearchOverlay(db storage.ChestnutStorage, groupid string, trxid string, overlay *PostOverlay) error {
+	if overlay.Deleted {
+		return appdb.removeSearchDoc(db, groupid, trxid)
+	}
+	return appdb.indexSearchDoc(db, groupid, trxid, overlay.TimeStamp, map[string]string{"name": overlay.Name, "content": overlay.Content})
+}
+
+func (appdb *AppDb) removePrefix(prefix string) error {
+	keys := [][]byte{}
+	err := appdb.Db.PrefixForeachKey([]byte(prefix), []byte(prefix), false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := appdb.Db.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
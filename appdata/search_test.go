@@ -0,0 +1,106 @@
+package appdata
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"testing"
+
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+)
+
+const testNodeName = "test"
+
+func newTestDb(t *testing.T) *storage.CSMemory {
+	db := &storage.CSMemory{}
+	if err := db.Init(""); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// a public group with posts saved in the chain store, one post per block
+func newTestPosts(t *testing.T, dbmgr *storage.DbMgr, count int) (*chestnutpb.GroupItem, [][]*chestnutpb.Trx) {
+	key := bytes.Repeat([]byte{0x01}, 32)
+	item := &chestnutpb.GroupItem{GroupId: "group", CipherKey: hex.EncodeToString(key)}
+	blocks := [][]*chestnutpb.Trx{}
+	for i := 0; i < count; i++ {
+		content, err := chestnutpb.ContentToBytes(&chestnutpb.Object{Name: fmt.Sprintf("post %d", i), Content: "hello world"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := localcrypto.SealEnvelope(content, key, localcrypto.CIPHER_ALG_AES_GCM, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		trx := &chestnutpb.Trx{TrxId: fmt.Sprintf("trx%03d", i), GroupId: item.GroupId, Type: chestnutpb.TrxType_POST, SenderPubkey: "sender", Data: data, TimeStamp: int64(i + 1)}
+		if err := dbmgr.AddTrx(trx, testNodeName); err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, []*chestnutpb.Trx{trx})
+	}
+	return item, blocks
+}
+
+// posts indexed by AppSync while the search index is rebuilt are counted once, run with -race
+func TestSearchIndexConcurrentRebuild(t *testing.T) {
+	const postCount = 50
+	chaindb := newTestDb(t)
+	dbmgr := &storage.DbMgr{GroupInfoDb: chaindb, Db: chaindb}
+	appdb := NewAppDb()
+	appdb.Db = newTestDb(t)
+	item, blocks := newTestPosts(t, dbmgr, postCount)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	synced := make(chan struct{})
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(synced)
+		for i, trxs := range blocks {
+			if err := appdb.AddMetaByTrx(fmt.Sprintf("block%d", i), item.GroupId, trxs, DecodeTrxContents(item, trxs, nil)); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			if _, err := appdb.RebuildSearchIndex(item, dbmgr, testNodeName, nil); err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case <-synced:
+				return
+			default:
+			}
+		}
+	}()
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	results, total, err := appdb.Search(item.GroupId, "hello", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != postCount || len(results) != postCount {
+		t.Fatalf("%d posts found, want %d", total, postCount)
+	}
+	stats, err := appdb.getSearchStats(appdb.Db, item.GroupId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Docs != postCount {
+		t.Fatalf("%d docs indexed, want %d", stats.Docs, postCount)
+	}
+}
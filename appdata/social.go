@@ -0,0 +1,391 @@
+// Package appdata provides storage for chestnut.
+package appdata
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/lixvyang/chestnut/chain"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	PST_PREFIX string = "pst_" //post author, pst_<groupid>_<trxid> -> sender
+	RPL_PREFIX string = "rpl_" //thread index, rpl_<groupid>_<parent trxid>_<timestamp>_<trxid> -> sender
+	RCT_PREFIX string = "rct_" //reaction counter, rct_<groupid>_<trxid>_<type> -> count
+	RCS_PREFIX string = "rcs_" //reaction of a sender, rcs_<groupid>_<trxid>_<sender> -> type
+	OVL_PREFIX string = "ovl_" //edit/delete overlay, ovl_<groupid>_<trxid> -> PostOverlay
+)
+
+// activity types of trxs refer to a post, same as the api
+const (
+	LIKE    = "Like"
+	DISLIKE = "Dislike"
+	UPDATE  = "Update"
+	DELETE  = "Delete"
+)
+
+// edits and deletes by the author, applied on the original post when read
+type PostOverlay struct {
+	TrxId     string //trx of the last edit or delete
+	Name      string
+	Content   string
+	Deleted   bool
+	TimeStamp int64
+}
+
+// decrypt and decode trx data of a POST trx, returns the content and its type url.
+// ks is the keystore of the tenant hosting the group, private posts are decrypted by its encrypt key
+func DecodeTrxContent(groupitem *chestnutpb.GroupItem, trx *chestnutpb.Trx, ks localcrypto.Keystore) (proto.Message, string, error) {
+	var data []byte
+	var err error
+	if groupitem.EncryptType == chestnutpb.GroupEncryptType_PRIVATE {
+		//for post, private group, encrypted by age for all announced group user
+		if ks == nil {
+			return nil, "", fmt.Errorf("no keystore to decrypt trx %s of private group %s", trx.TrxId, groupitem.GroupId)
+		}
+		data, err = ks.Decrypt(groupitem.UserEncryptPubkey, trx.Data)
+	} else {
+		data, err = chain.DecryptGroupData(groupitem, trx.Data)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return chestnutpb.BytesToMessage(trx.TrxId, data)
+}
+
+// decode data of POST trxs keyed by trxid, trxs can not be decrypted or pruned are skipped
+func DecodeTrxContents(groupitem *chestnutpb.GroupItem, trxs []*chestnutpb.Trx, ks localcrypto.Keystore) map[string]proto.Message {
+	contents := make(map[string]proto.Message)
+	for _, trx := range trxs {
+		if trx.Type != chestnutpb.TrxType_POST || len(trx.Data) == 0 {
+			continue
+		}
+		content, _, err := DecodeTrxContent(groupitem, trx, ks)
+		if err != nil {
+			appdatalog.Warningf("<%s> decode trx %s err: %s", groupitem.GroupId, trx.TrxId, err)
+			continue
+		}
+		contents[trx.TrxId] = content
+	}
+	return contents
+}
+
+// index a post, a reply or an activity refer to a post, db should be a transaction
+func (appdb *AppDb) addSocialByTrx(db storage.ChestnutStorage, groupid string, trx *chestnutpb.Trx, content proto.Message) error {
+	switch obj := content.(type) {
+	case *chestnutpb.Object:
+		key := fmt.Sprintf("%s%s_%s", PST_PREFIX, groupid, trx.TrxId)
+		if err := db.Set([]byte(key), []byte(trx.SenderPubkey)); err != nil {
+			return err
+		}
+		if err := appdb.indexSearchDoc(db, groupid, trx.TrxId, trx.TimeStamp, objectFields(obj)); err != nil {
+			return err
+		}
+		if obj.Inreplyto != nil && obj.Inreplyto.Trxid != "" {
+			if obj.Inreplyto.Groupid != "" && obj.Inreplyto.Groupid != groupid {
+				appdatalog.Warningf("<%s> reply %s to post of another group, ignored", groupid, trx.TrxId)
+				return nil
+			}
+			key := fmt.Sprintf("%s%s_%s_%019d_%s", RPL_PREFIX, groupid, obj.Inreplyto.Trxid, trx.TimeStamp, trx.TrxId)
+			return db.Set([]byte(key), []byte(trx.SenderPubkey))
+		}
+		return nil
+	case *chestnutpb.Activity:
+		if obj.Object == nil || obj.Object.Id == "" {
+			return nil
+		}
+		author, err := appdb.getPostSender(db, groupid, obj.Object.Id)
+		if err != nil {
+			return err
+		}
+		if author == "" {
+			appdatalog.Warningf("<%s> %s refers to unknown post %s, ignored", groupid, obj.Type, obj.Object.Id)
+			return nil
+		}
+
+		switch obj.Type {
+		case LIKE, DISLIKE:
+			return appdb.addReaction(db, groupid, obj.Object.Id, trx.SenderPubkey, obj.Type)
+		case UPDATE, DELETE:
+			if author != trx.SenderPubkey {
+				appdatalog.Warningf("<%s> %s of post %s not by the author, ignored", groupid, obj.Type, obj.Object.Id)
+				return nil
+			}
+			return appdb.updOverlay(db, groupid, trx, obj)
+		}
+		appdatalog.Warningf("<%s> unsupported activity %s in trx %s", groupid, obj.Type, trx.TrxId)
+	}
+	return nil
+}
+
+// one reaction per sender on a post, a new reaction replaces the old one
+func (appdb *AppDb) addReaction(db storage.ChestnutStorage, groupid string, trxid string, sender string, reaction string) error {
+	senderKey := fmt.Sprintf("%s%s_%s_%s", RCS_PREFIX, groupid, trxid, sender)
+	old, err := db.Get([]byte(senderKey))
+	if err != nil && err != storage.ErrKeyNotFound {
+		return err
+	}
+	if string(old) == reaction {
+		return nil
+	}
+	if len(old) > 0 {
+		if err := appdb.incReaction(db, groupid, trxid, string(old), -1); err != nil {
+			return err
+		}
+	}
+	if err := appdb.incReaction(db, groupid, trxid, reaction, 1); err != nil {
+		return err
+	}
+	return db.Set([]byte(senderKey), []byte(reaction))
+}
+
+func (appdb *AppDb) incReaction(db storage.ChestnutStorage, groupid string, trxid string, reaction string, delta int64) error {
+	key := fmt.Sprintf("%s%s_%s_%s", RCT_PREFIX, groupid, trxid, reaction)
+	var count int64
+	value, err := db.Get([]byte(key))
+	if err == nil {
+		count, err = strconv.ParseInt(string(value), 10, 64)
+	}
+	if err != nil && err != storage.ErrKeyNotFound {
+		return err
+	}
+	count += delta
+	if count <= 0 {
+		return db.Delete([]byte(key))
+	}
+	return db.Set([]byte(key), []byte(fmt.Sprint(count)))
+}
+
+// the latest edit wins, a deleted post can not be edited
+func (appdb *AppDb) updOverlay(db storage.ChestnutStorage, groupid string, trx *chestnutpb.Trx, activity *chestnutpb.Activity) error {
+	key := fmt.Sprintf("%s%s_%s", OVL_PREFIX, groupid, activity.Object.Id)
+	overlay, err := appdb.getOverlay(db, key)
+	if err != nil {
+		return err
+	}
+	if overlay == nil {
+		overlay = &PostOverlay{}
+	}
+	if overlay.Deleted || overlay.TimeStamp > trx.TimeStamp {
+		return nil
+	}
+
+	overlay.TrxId = trx.TrxId
+	overlay.TimeStamp = trx.TimeStamp
+	if activity.Type == DELETE {
+		overlay.Deleted = true
+		overlay.Name = ""
+		overlay.Content = ""
+	} else {
+		overlay.Name = activity.Object.Name
+		overlay.Content = activity.Object.Content
+	}
+
+	value, err := json.Marshal(overlay)
+	if err != nil {
+		return err
+	}
+	if err := db.Set([]byte(key), value); err != nil {
+		return err
+	}
+	return appdb.applySearchOverlay(db, groupid, activity.Object.Id, overlay)
+}
+
+func (appdb *AppDb) getOverlay(db storage.ChestnutStorage, key string) (*PostOverlay, error) {
+	value, err := db.Get([]byte(key))
+	if err == storage.ErrKeyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	overlay := &PostOverlay{}
+	if err := json.Unmarshal(value, overlay); err != nil {
+		return nil, err
+	}
+	return overlay, nil
+}
+
+// sender of a post, empty if the post is unknown.
+// posts synced before the author index existed are found from the content index.
+func (appdb *AppDb) getPostSender(db storage.ChestnutStorage, groupid string, trxid string) (string, error) {
+	key := fmt.Sprintf("%s%s_%s", PST_PREFIX, groupid, trxid)
+	value, err := db.Get([]byte(key))
+	if err == nil {
+		return string(value), nil
+	} else if err != storage.ErrKeyNotFound {
+		return "", err
+	}
+
+	sender := ""
+	prefix := fmt.Sprintf("%s%s-%s", CNT_PREFIX, GRP_PREFIX, groupid)
+	err = db.PrefixForeachKey([]byte(prefix), []byte(prefix), false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		s, t, ok := parseContentKey(k)
+		if ok && t == trxid {
+			sender = s
+			return errors.New("OK")
+		}
+		return nil
+	})
+	if err != nil && err.Error() != "OK" {
+		return "", err
+	}
+	return sender, nil
+}
+
+// get sender and trxid from a content key, see getKey
+func parseContentKey(k []byte) (string, string, bool) {
+	if !strings.HasSuffix(string(k), term) {
+		return "", "", false
+	}
+	tail := strings.TrimSuffix(string(k), term)
+	sep := strings.LastIndex(tail, "_"+term)
+	if sep < 0 {
+		return "", "", false
+	}
+	tail = tail[sep+1+len(term):]
+	idx := strings.LastIndex(tail, ":")
+	if idx < 0 {
+		return "", "", false
+	}
+	return tail[:idx], tail[idx+1:], true
+}
+
+func (appdb *AppDb) GetPostSender(groupid string, trxid string) (string, error) {
+	return appdb.getPostSender(appdb.Db, groupid, trxid)
+}
+
+// trxids of the replies to a post, ordered by time
+func (appdb *AppDb) GetReplies(groupid string, trxid string, starttrx string, num int, reverse bool) ([]string, error) {
+	prefix := fmt.Sprintf("%s%s_%s_", RPL_PREFIX, groupid, trxid)
+	p := []byte(prefix)
+	if reverse {
+		p = append(p, 0xff)
+	}
+
+	trxids := []string{}
+	runcollector := starttrx == ""
+	err := appdb.Db.PrefixForeachKey(p, []byte(prefix), reverse, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		replyid := string(k[len(prefix)+20:])
+		if runcollector {
+			trxids = append(trxids, replyid)
+		}
+		if replyid == starttrx {
+			runcollector = true
+		}
+		if num > 0 && len(trxids) == num {
+			return errors.New("OK")
+		}
+		return nil
+	})
+	if err != nil && err.Error() == "OK" {
+		err = nil
+	}
+	return trxids, err
+}
+
+func (appdb *AppDb) GetReplyCount(groupid string, trxid string) (int64, error) {
+	prefix := fmt.Sprintf("%s%s_%s_", RPL_PREFIX, groupid, trxid)
+	var count int64
+	err := appdb.Db.PrefixForeachKey([]byte(prefix), []byte(prefix), false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
+
+// reaction counters of a post, keyed by reaction type
+func (appdb *AppDb) GetReactions(groupid string, trxid string) (map[string]int64, error) {
+	prefix := fmt.Sprintf("%s%s_%s_", RCT_PREFIX, groupid, trxid)
+	reactions := make(map[string]int64)
+	err := appdb.Db.PrefixForeach([]byte(prefix), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		count, err := strconv.ParseInt(string(v), 10, 64)
+		if err != nil {
+			return err
+		}
+		reactions[string(k[len(prefix):])] = count
+		return nil
+	})
+	return reactions, err
+}
+
+// edit/delete overlay of a post, nil if the post is not edited
+func (appdb *AppDb) GetPostOverlay(groupid string, trxid string) (*PostOverlay, error) {
+	return appdb.getOverlay(appdb.Db, fmt.Sprintf("%s%s_%s", OVL_PREFIX, groupid, trxid))
+}
+
+// Remove the posts pruned by the retention policy from the search and social indexes.
+// Replies to a pruned post are kept in its thread index, they are not pruned yet.
+func (appdb *AppDb) RmPrunedPosts(groupid string, trxids []string) error {
+	appdb.mu.Lock()
+	defer appdb.mu.Unlock()
+
+	txn, err := appdb.Db.BeginTxn()
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	pruned := make(map[string]bool)
+	keys := [][]byte{}
+	collect := func(prefix string, match func(k []byte) bool) error {
+		return txn.PrefixForeachKey([]byte(prefix), []byte(prefix), false, func(k []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			if match(k) {
+				keys = append(keys, append([]byte{}, k...))
+			}
+			return nil
+		})
+	}
+	all := func(k []byte) bool { return true }
+
+	for _, trxid := range trxids {
+		pruned[trxid] = true
+		if err := appdb.removeSearchDoc(txn, groupid, trxid); err != nil {
+			return err
+		}
+		keys = append(keys, []byte(fmt.Sprintf("%s%s_%s", PST_PREFIX, groupid, trxid)), []byte(fmt.Sprintf("%s%s_%s", OVL_PREFIX, groupid, trxid)))
+		for _, prefix := range []string{RCT_PREFIX, RCS_PREFIX} {
+			if err := collect(fmt.Sprintf("%s%s_%s_", prefix, groupid, trxid), all); err != nil {
+				return err
+			}
+		}
+	}
+
+	//a pruned reply is removed from the thread of its parent
+	err = collect(fmt.Sprintf("%s%s_", RPL_PREFIX, groupid), func(k []byte) bool {
+		key := string(k)
+		return pruned[key[strings.LastIndex(key, "_")+1:]]
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if err := txn.Delete(k); err != nil {
+			return err
+		}
+	}
+	return txn.Commit()
+}
@@ -2,89 +2,286 @@
 package appdata
 
 import (
+	"context"
+	"sync"
 	"time"
 
 	logging "github.com/ipfs/go-log/v2"
 	"github.com/lixvyang/chestnut/chain"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
 )
 
 var appsynclog = logging.Logger("appsync")
 
+// retry a failed group sync after
+const SYNC_RETRY_INTERVAL = 30 * time.Second
+
 type AppSync struct {
-	appdb *AppDb
-	dbmgr *storage.DbMgr
+	appdb    *AppDb
+	dbmgr    *storage.DbMgr
 	groupmgr *chain.GroupMgr
-	apiroot string
+	apiroot  string
 	nodename string
+
+	mu      sync.Mutex
+	workers map[string]*groupSyncWorker
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
 }
 
-func NewAppSyncAgent(apiroot string, nodename string, appdb *AppDb, dbmgr *storage.DbMgr) *AppSync {
-	groupmgr := chain.GetGroupMgr()
-	appsync := &AppSync{appdb, dbmgr, groupmgr, apiroot, nodename}
+// sync status of a group, Lag is the number of canonical blocks not indexed yet
+type GroupSyncStatus struct {
+	GroupId        string
+	HighestHeight  int64
+	HighestBlockId string
+	IndexedBlockId string
+	Lag            int64
+	LastSync       int64
+	Error          string `json:",omitempty"`
+}
+
+// index blocks of a group when woken by block-accepted notifications
+type groupSyncWorker struct {
+	appsync *AppSync
+	item    *chestnutpb.GroupItem //decrypt keys and genesis block, not changed after the group is created
+	wake    chan struct{}
+
+	mu     sync.Mutex
+	status GroupSyncStatus
+}
+
+// index the groups of groupmgr, every tenant has its own GroupMgr and AppSync
+func NewAppSyncAgent(apiroot string, nodename string, appdb *AppDb, dbmgr *storage.DbMgr, groupmgr *chain.GroupMgr) *AppSync {
+	appsync := &AppSync{appdb: appdb, dbmgr: dbmgr, groupmgr: groupmgr, apiroot: apiroot, nodename: nodename}
+	appsync.workers = make(map[string]*groupSyncWorker)
 	return appsync
 }
 
 func (appsync *AppSync) GetGroups() []*chestnutpb.GroupItem {
-	var items []*chestnutpb.GroupItem
-	for _, grp := range appsync.groupmgr.Groups {
-		items = append(items, grp.Item)
+	return appsync.groupmgr.GetGroupItems()
+}
+
+// Start a worker for each group and listen to new blocks until ctx is done or Stop is called
+func (appsync *AppSync) Start(ctx context.Context) {
+	appsync.mu.Lock()
+	appsync.ctx, appsync.cancel = context.WithCancel(ctx)
+	appsync.mu.Unlock()
+
+	for _, item := range appsync.GetGroups() {
+		appsync.notify(item, item.HighestBlockId, item.HighestHeight)
 	}
-	return items
+	appsync.groupmgr.AddBlockListener(func(groupId string, blockId string, height int64) {
+		item, err := appsync.groupmgr.GetGroupItem(groupId)
+		if err != nil {
+			return
+		}
+		appsync.notify(item, blockId, height)
+	})
+	appsync.groupmgr.AddPruneListener(func(groupId string, trxIds []string) {
+		if err := appsync.appdb.RmPrunedPosts(groupId, trxIds); err != nil {
+			appsynclog.Warningf("<%s> remove %d pruned post(s) from the indexes err: %s", groupId, len(trxIds), err)
+		}
+	})
 }
 
-func (appsync *AppSync) ParseBlockTrx(groupid string, block *chestnutpb.Block) ([]*chestnutpb.Block, error) {
-	appsynclog.Infof("ParseBlockTrxs %d trx(s) on group %s", len(block.Trxs), groupid)
-	if err := appsync.appdb.AddMetaByTrx(block.BlockId, groupid, block.Trxs); err != nil {
-		appsynclog.Errorf("ParseBlockTrxs on group %s err: ", groupid, err)
-	}
-	return appsync.dbmgr.GetSubBlock(block.BlockId, appsync.nodename)
-}
-
-func (appsync *AppSync) RunSync(groupid string, lastBlockedId string, newBlockId string)  {
-	var blocks []*chestnutpb.Block
-	subblocks, err := appsync.dbmgr.GetSubBlock(lastBlockedId, appsync.nodename)
-	if err == nil {
-		blocks = append(blocks, subblocks...)
-		for {
-			if len(blocks) == 0 {
-				appsynclog.Infof("no new blocks, skip sync")
-				break
-			}
-			var blk *chestnutpb.Block
-			blk, blocks = blocks[0], blocks[1:]
-			newsubblocks, err := appsync.ParseBlockTrx(groupid, blk)
-			if err == nil {
-				blocks = append(blocks, newsubblocks...)
-			} else {
-				appsynclog.Errorf("ParseBlockTrxs error %s", err)
-			}
+// Stop all workers and wait for them to exit
+func (appsync *AppSync) Stop() {
+	appsync.mu.Lock()
+	if appsync.cancel != nil {
+		appsync.cancel()
+	}
+	appsync.mu.Unlock()
+	appsync.wg.Wait()
+}
+
+// wake up the worker of group, the worker is started if not running
+func (appsync *AppSync) notify(item *chestnutpb.GroupItem, blockId string, height int64) {
+	appsync.mu.Lock()
+	defer appsync.mu.Unlock()
+	if appsync.ctx == nil || appsync.ctx.Err() != nil {
+		return
+	}
+
+	worker, ok := appsync.workers[item.GroupId]
+	if !ok {
+		worker = &groupSyncWorker{appsync: appsync, item: proto.Clone(item).(*chestnutpb.GroupItem), wake: make(chan struct{}, 1)}
+		worker.status.GroupId = item.GroupId
+		appsync.workers[item.GroupId] = worker
+		appsync.wg.Add(1)
+		go worker.run(appsync.ctx)
+	}
+
+	worker.mu.Lock()
+	if blockId != "" && height >= worker.status.HighestHeight {
+		worker.status.HighestHeight = height
+		worker.status.HighestBlockId = blockId
+	}
+	worker.mu.Unlock()
+
+	select {
+	case worker.wake <- struct{}{}:
+	default:
+	}
+}
+
+// sync status of all groups
+func (appsync *AppSync) GetSyncStatus() []*GroupSyncStatus {
+	appsync.mu.Lock()
+	defer appsync.mu.Unlock()
+	result := []*GroupSyncStatus{}
+	for _, worker := range appsync.workers {
+		result = append(result, worker.getStatus())
+	}
+	return result
+}
+
+func (appsync *AppSync) GetGroupSyncStatus(groupid string) (*GroupSyncStatus, bool) {
+	appsync.mu.Lock()
+	defer appsync.mu.Unlock()
+	worker, ok := appsync.workers[groupid]
+	if !ok {
+		return nil, false
+	}
+	return worker.getStatus(), true
+}
+
+func (appsync *AppSync) removeWorker(groupid string) {
+	appsync.mu.Lock()
+	defer appsync.mu.Unlock()
+	delete(appsync.workers, groupid)
+}
+
+func (worker *groupSyncWorker) getStatus() *GroupSyncStatus {
+	worker.mu.Lock()
+	defer worker.mu.Unlock()
+	status := worker.status
+	return &status
+}
+
+func (worker *groupSyncWorker) run(ctx context.Context) {
+	defer worker.appsync.wg.Done()
+	groupid := worker.item.GroupId
+	appsynclog.Debugf("<%s> sync worker started", groupid)
+
+	retry := time.NewTimer(SYNC_RETRY_INTERVAL)
+	retry.Stop()
+	defer retry.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			appsynclog.Debugf("<%s> sync worker stopped", groupid)
+			return
+		case <-worker.wake:
+		case <-retry.C:
 		}
-	} else {
-		appsynclog.Errorf("db read err: %s", err)
-	}
-}
-
-func (appsync *AppSync) Start(interval int)  {
-	go func() {
-		for {
-			groups := appsync.GetGroups()
-			for _, groupitem := range groups {
-				lastBlockId, err := appsync.appdb.GetGroupStatus(groupitem.GroupId, "HighestBlockId")
-				if err == nil {
-					if lastBlockId == "" {
-						lastBlockId = groupitem.GenesisBlock.BlockId
-					}
-					if lastBlockId != groupitem.HighestBlockId {
-						appsync.RunSync(groupitem.GroupId, lastBlockId, groupitem.HighestBlockId)
-					}
-				} else {
-					appsynclog.Errorf("sync group: %s Get HeightBlockId err %s", groupitem.GroupId, err)
-				}
-			}
-			time.Sleep(time.Duration(interval) * time.Second)
+
+		if _, err := worker.appsync.groupmgr.GetGroupItem(groupid); err != nil {
+			appsynclog.Infof("<%s> group removed, sync worker exit", groupid)
+			worker.appsync.removeWorker(groupid)
+			return
 		}
-	}()
+
+		err := worker.sync(ctx)
+		worker.mu.Lock()
+		worker.status.LastSync = time.Now().UnixNano()
+		if err != nil {
+			worker.status.Error = err.Error()
+		} else {
+			worker.status.Error = ""
+		}
+		worker.mu.Unlock()
+		if err != nil && ctx.Err() == nil {
+			appsynclog.Errorf("<%s> sync err: %s, retry in %s", groupid, err, SYNC_RETRY_INTERVAL)
+			retry.Reset(SYNC_RETRY_INTERVAL)
+		}
+	}
 }
 
+// index the canonical blocks from the last indexed block to the highest block, stop on the first error
+func (worker *groupSyncWorker) sync(ctx context.Context) error {
+	appsync := worker.appsync
+	groupid := worker.item.GroupId
+	if worker.item.GenesisBlock == nil {
+		return nil
+	}
+	genesis := worker.item.GenesisBlock.BlockId
+
+	worker.mu.Lock()
+	head := worker.status.HighestBlockId
+	worker.mu.Unlock()
+	if head == "" {
+		head = genesis
+	}
+
+	indexed, err := appsync.appdb.GetGroupStatus(groupid, "HighestBlockId")
+	if err != nil {
+		return err
+	}
+	if indexed == "" {
+		indexed = genesis
+	}
+	worker.setIndexed(indexed, -1)
+	if indexed == head {
+		worker.setIndexed(indexed, 0)
+		return nil
+	}
+
+	blockIds, found, err := CanonicalBlockIds(appsync.dbmgr, head, indexed, genesis, appsync.nodename)
+	if err != nil {
+		return err
+	}
+	if !found {
+		//the indexed block is on a fork trimmed from the chain
+		appsynclog.Warningf("<%s> indexed block %s is not on the canonical branch, rebuild group", groupid, indexed)
+		worker.setIndexed("", int64(len(blockIds)))
+		if err := appsync.appdb.RebuildGroup(appsync.dbmgr, worker.item, head, appsync.nodename, appsync.groupmgr.Tenant().Keystore); err != nil {
+			return err
+		}
+		worker.setIndexed(head, 0)
+		return nil
+	}
+
+	appsynclog.Infof("<%s> sync %d block(s)", groupid, len(blockIds))
+	for i, blockId := range blockIds {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		worker.setIndexed(indexed, int64(len(blockIds)-i))
+		blk, err := appsync.dbmgr.GetBlock(blockId, false, appsync.nodename)
+		if err != nil {
+			return err
+		}
+		if err := appsync.ParseBlockTrx(groupid, blk); err != nil {
+			return err
+		}
+		indexed = blockId
+	}
+	worker.setIndexed(indexed, 0)
+	return nil
+}
+
+// lag < 0 keeps the current lag
+func (worker *groupSyncWorker) setIndexed(blockId string, lag int64) {
+	worker.mu.Lock()
+	defer worker.mu.Unlock()
+	worker.status.IndexedBlockId = blockId
+	if lag >= 0 {
+		worker.status.Lag = lag
+	}
+}
+
+func (appsync *AppSync) ParseBlockTrx(groupid string, block *chestnutpb.Block) error {
+	appsynclog.Infof("ParseBlockTrxs %d trx(s) on group %s", len(block.Trxs), groupid)
+	contents := make(map[string]proto.Message)
+	if groupitem, err := appsync.groupmgr.GetGroupItem(groupid); err == nil {
+		contents = DecodeTrxContents(groupitem, block.Trxs, appsync.groupmgr.Tenant().Keystore)
+	}
+	if err := appsync.appdb.AddMetaByTrx(block.BlockId, groupid, block.Trxs, contents); err != nil {
+		appsynclog.Errorf("ParseBlockTrxs on group %s err: %s", groupid, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	logging "github.com/ipfs/go-log/v2"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+var admin_log = logging.Logger("admin")
+
+// group owned by an admin set, owner trxs need the signatures of the admins instead of the owner key
+func IsAdminGroup(item *chestnutpb.GroupItem) bool {
+	return item.Admins != nil && item.Admins.Threshold > 0
+}
+
+// trxs authorized by the group owner
+func IsOwnerTrx(trxType chestnutpb.TrxType) bool {
+	switch trxType {
+	case chestnutpb.TrxType_AUTH, chestnutpb.TrxType_PRODUCER, chestnutpb.TrxType_SCHEMA, chestnutpb.TrxType_CONFIG:
+		return true
+	}
+	return false
+}
+
+func IsAdmin(admins *chestnutpb.AdminSet, pubkey string) bool {
+	if admins == nil {
+		return false
+	}
+	for _, admin := range admins.Pubkeys {
+		if admin == pubkey {
+			return true
+		}
+	}
+	return false
+}
+
+// check the admin set of a new group, pubkeys are encoded like the group owner pubkey
+func CheckAdminSet(admins *chestnutpb.AdminSet) error {
+	if len(admins.Pubkeys) == 0 {
+		return errors.New("admin set is empty")
+	}
+	if admins.Threshold < 1 || int(admins.Threshold) > len(admins.Pubkeys) {
+		return fmt.Errorf("admin threshold must be between 1 and %d", len(admins.Pubkeys))
+	}
+	seen := make(map[string]bool)
+	for _, pubkey := range admins.Pubkeys {
+		if seen[pubkey] {
+			return fmt.Errorf("duplicated admin %s", pubkey)
+		}
+		seen[pubkey] = true
+		serializedpub, err := p2pcrypto.ConfigDecodeKey(pubkey)
+		if err != nil {
+			return fmt.Errorf("admin %s can't be decoded, err:%s", pubkey, err)
+		}
+		if _, err := p2pcrypto.UnmarshalPublicKey(serializedpub); err != nil {
+			return fmt.Errorf("admin %s can't be decoded, err:%s", pubkey, err)
+		}
+	}
+	return nil
+}
+
+// hash signed by the admins, the action id becomes the trx id so an action can't be applied twice.
+// Each field is prefixed by its length, so the bytes of a field can't be moved to the next one
+func AdminActionHash(action *chestnutpb.AdminAction) []byte {
+	var buffer bytes.Buffer
+	sizebuf := make([]byte, binary.MaxVarintLen64)
+	for _, field := range [][]byte{[]byte(action.ActionId), []byte(action.GroupId), []byte(action.Type.String()), action.Data} {
+		n := binary.PutUvarint(sizebuf, uint64(len(field)))
+		buffer.Write(sizebuf[:n])
+		buffer.Write(field)
+	}
+	return Hash(buffer.Bytes())
+}
+
+func VerifyAdminSign(admins *chestnutpb.AdminSet, action *chestnutpb.AdminAction, sign *chestnutpb.AdminSign) error {
+	if !IsAdmin(admins, sign.Pubkey) {
+		return fmt.Errorf("%s is not an admin of the group", sign.Pubkey)
+	}
+	return verifySign(sign.Pubkey, AdminActionHash(action), sign.Sign)
+}
+
+// all signatures must be valid, and signed by at least Threshold different admins
+func VerifyAdminSigns(admins *chestnutpb.AdminSet, action *chestnutpb.AdminAction) error {
+	signed := make(map[string]bool)
+	for _, sign := range action.Signs {
+		if err := VerifyAdminSign(admins, action, sign); err != nil {
+			return err
+		}
+		signed[sign.Pubkey] = true
+	}
+	if len(signed) < int(admins.Threshold) {
+		return fmt.Errorf("signed by %d admins, %d required", len(signed), admins.Threshold)
+	}
+	return nil
+}
+
+// owner trxs of an admin group are applied only with enough admin signatures, trx data is decrypted
+func checkAdminTrx(item *chestnutpb.GroupItem, trx *chestnutpb.Trx) error {
+	if !IsAdminGroup(item) || !IsOwnerTrx(trx.Type) {
+		return nil
+	}
+	action := &chestnutpb.AdminAction{ActionId: trx.TrxId, GroupId: trx.GroupId, Type: trx.Type, Data: trx.Data, Signs: trx.AdminSigns}
+	if err := VerifyAdminSigns(item.Admins, action); err != nil {
+		admin_log.Warningf("<%s> owner trx <%s> rejected: %s", trx.GroupId, trx.TrxId, err)
+		return err
+	}
+	return nil
+}
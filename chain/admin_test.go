@@ -0,0 +1,28 @@
+package chain
+
+import (
+	"bytes"
+	"testing"
+
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+// actions with the same bytes split into other fields are signed by different hashes
+func TestAdminActionHashFields(t *testing.T) {
+	actions := []*chestnutpb.AdminAction{
+		{ActionId: "action", GroupId: "group", Type: chestnutpb.TrxType_AUTH, Data: []byte("data")},
+		{ActionId: "actiong", GroupId: "roup", Type: chestnutpb.TrxType_AUTH, Data: []byte("data")},
+		{ActionId: "", GroupId: "actiongroup", Type: chestnutpb.TrxType_AUTH, Data: []byte("data")},
+	}
+
+	var hashes [][]byte
+	for _, action := range actions {
+		hash := AdminActionHash(action)
+		for i, other := range hashes {
+			if bytes.Equal(hash, other) {
+				t.Fatalf("action %+v signed by the hash of action %d", action, i)
+			}
+		}
+		hashes = append(hashes, hash)
+	}
+}
@@ -0,0 +1,240 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	logging "github.com/ipfs/go-log/v2"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/nodectx"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var archive_log = logging.Logger("archive")
+
+// max size of a single block in archive, same as the pubsub message limit
+const ARCHIVE_BLOCK_SIZE_LIMIT = 1 << 20
+
+// save progress after every N imported blocks
+const IMPORT_PROGRESS_INTERVAL = 100
+
+// Export all blocks of the group as varint length-delimited protobuf blocks.
+// Blocks are written from genesis, parents always before their children.
+func (grp *Group) ExportBlocks(w io.Writer) (int64, error) {
+	group_log.Debugf("<%s> ExportBlocks called", grp.Item.GroupId)
+	bw := bufio.NewWriter(w)
+
+	var count int64
+	blocks := []*chestnutpb.Block{grp.Item.GenesisBlock}
+	for len(blocks) > 0 {
+		var block *chestnutpb.Block
+		block, blocks = blocks[0], blocks[1:]
+
+		pruned, err := nodectx.GetDbMgr().IsBlockPruned(block.BlockId, grp.ChainCtx.nodename)
+		if err != nil {
+			return count, err
+		}
+		if pruned {
+			return count, fmt.Errorf("block <%s> is pruned by retention policy", block.BlockId)
+		}
+		if IsBlockStripped(block) {
+			return count, fmt.Errorf("block <%s> is saved without trx data in light mode", block.BlockId)
+		}
+
+		if err := writeDelimitedBlock(bw, block); err != nil {
+			return count, err
+		}
+		count++
+
+		subBlocks, err := nodectx.GetDbMgr().GetSubBlock(block.BlockId, grp.ChainCtx.nodename)
+		if err != nil {
+			return count, err
+		}
+		blocks = append(blocks, subBlocks...)
+	}
+
+	archive_log.Infof("<%s> exported <%d> blocks", grp.Item.GroupId, count)
+	return count, bw.Flush()
+}
+
+// Import blocks exported by ExportBlocks (from this node or another node).
+// Every block and trx signature is verified, blocks are added by the consensus
+// the same way as synced blocks, so all trxs go through applyTrxs.
+// The number of imported blocks is saved with the id of the archive, an interrupted import
+// can be resumed by importing the same archive again.
+func (grp *Group) ImportBlocks(r io.Reader) (int64, error) {
+	group_log.Debugf("<%s> ImportBlocks called", grp.Item.GroupId)
+	dbMgr := nodectx.GetDbMgr()
+	nodename := grp.ChainCtx.nodename
+
+	if grp.ChainCtx.IsSyncerReady() {
+		return 0, errors.New("group is in syncing or sync failed, stop sync before import")
+	}
+
+	br := bufio.NewReader(r)
+	var archiveId string
+	var imported, index int64
+	for {
+		block, err := readDelimitedBlock(br)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return index, err
+		}
+		index++
+
+		if index == 1 {
+			archiveId = ArchiveId(grp.Item.GroupId, block)
+			imported, err = dbMgr.GetImportProgress(grp.Item.GroupId, archiveId, nodename)
+			if err != nil {
+				return 0, err
+			}
+			if imported > 0 {
+				archive_log.Infof("<%s> resume import, skip <%d> blocks", grp.Item.GroupId, imported)
+			}
+		}
+
+		//archives of the group start from the same genesis block, a block is skipped only
+		//if it is saved, the blocks after a block not saved are imported again
+		if index <= imported {
+			isSaved, err := dbMgr.IsBlockExist(block.BlockId, false, nodename)
+			if err != nil {
+				return index - 1, err
+			}
+			if isSaved {
+				continue
+			}
+			archive_log.Warningf("<%s> block <%s> before the resume point not saved, import the rest", grp.Item.GroupId, block.BlockId)
+			imported = 0
+		}
+
+		if err := grp.importBlock(block); err != nil {
+			if perr := dbMgr.UpdImportProgress(grp.Item.GroupId, archiveId, index-1, nodename); perr != nil {
+				archive_log.Warningf("<%s> save import progress failed: %s", grp.Item.GroupId, perr)
+			}
+			return index - 1, fmt.Errorf("import block <%s> failed: %s", block.BlockId, err)
+		}
+
+		if index%IMPORT_PROGRESS_INTERVAL == 0 {
+			if err := dbMgr.UpdImportProgress(grp.Item.GroupId, archiveId, index, nodename); err != nil {
+				return index, err
+			}
+		}
+	}
+
+	archive_log.Infof("<%s> import done, <%d> blocks in archive", grp.Item.GroupId, index)
+	return index, dbMgr.RmImportProgress(grp.Item.GroupId, nodename)
+}
+
+// id of an archive of the group, by the group id and the hash of the first block in the archive
+func ArchiveId(groupId string, first *chestnutpb.Block) string {
+	var data []byte
+	sizebuf := make([]byte, binary.MaxVarintLen64)
+	for _, field := range [][]byte{[]byte(groupId), []byte(first.BlockId), first.Hash} {
+		n := binary.PutUvarint(sizebuf, uint64(len(field)))
+		data = append(data, sizebuf[:n]...)
+		data = append(data, field...)
+	}
+	return hex.EncodeToString(localcrypto.Hash(data))
+}
+
+func (grp *Group) importBlock(block *chestnutpb.Block) error {
+	if block.GroupId != grp.Item.GroupId {
+		return fmt.Errorf("block belongs to group <%s>", block.GroupId)
+	}
+
+	//genesis block is saved when the group is created or joined
+	if block.PrevBlockId == "" {
+		if block.BlockId != grp.Item.GenesisBlock.BlockId {
+			return errors.New("genesis block mismatch")
+		}
+		return nil
+	}
+
+	if _, ok := grp.ChainCtx.ProducerPool[block.ProducerPubKey]; !ok {
+		return fmt.Errorf("block producer <%s> not registed", block.ProducerPubKey)
+	}
+	if err := CheckBlockId(block, grp.Item.GenesisBlock); err != nil {
+		return err
+	}
+
+	valid, err := IsBlockSignValid(block)
+	if !valid {
+		if err == nil {
+			err = errors.New("invalid block signature")
+		}
+		return err
+	}
+
+	trxMgr := grp.ChainCtx.GetProducerTrxMgr()
+	for _, trx := range block.Trxs {
+		valid, err := trxMgr.VerifyTrx(trx)
+		if !valid {
+			if err == nil {
+				err = errors.New("invalid trx signature")
+			}
+			return fmt.Errorf("trx <%s>: %s", trx.TrxId, err)
+		}
+	}
+
+	isSaved, err := nodectx.GetDbMgr().IsBlockExist(block.BlockId, false, grp.ChainCtx.nodename)
+	if err != nil {
+		return err
+	}
+	if isSaved {
+		archive_log.Debugf("<%s> block <%s> already saved, skip", grp.Item.GroupId, block.BlockId)
+		return nil
+	}
+
+	//imported blocks are saved by DbMgr.AddBlock like synced blocks, with the height and hash indexes
+	if grp.ChainCtx.Consensus.Producer() != nil {
+		return grp.ChainCtx.Consensus.Producer().AddBlock(block)
+	}
+	return grp.ChainCtx.Consensus.User().AddBlock(block)
+}
+
+func writeDelimitedBlock(w io.Writer, block *chestnutpb.Block) error {
+	bbytes, err := proto.Marshal(block)
+	if err != nil {
+		return err
+	}
+
+	sizebuf := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(sizebuf, uint64(len(bbytes)))
+	if _, err := w.Write(sizebuf[:n]); err != nil {
+		return err
+	}
+	_, err = w.Write(bbytes)
+	return err
+}
+
+func readDelimitedBlock(r *bufio.Reader) (*chestnutpb.Block, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > ARCHIVE_BLOCK_SIZE_LIMIT {
+		return nil, fmt.Errorf("block size <%d> over limit", size)
+	}
+
+	bbytes := make([]byte, size)
+	if _, err := io.ReadFull(r, bbytes); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	block := &chestnutpb.Block{}
+	if err := proto.Unmarshal(bbytes, block); err != nil {
+		return nil, err
+	}
+	return block, nil
+}
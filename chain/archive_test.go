@@ -0,0 +1,161 @@
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"testing"
+
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	"github.com/lixvyang/chestnut/nodectx"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// group created by the tenant with blocks of posts produced by the owner, the item is cloned before
+// the group is created so the group can be created by another tenant
+func newTestArchiveGroup(t *testing.T, groupmgr *GroupMgr, ks *testKeystore, groupId string, count int) (*Group, *chestnutpb.GroupItem) {
+	item := newTestGroupItem(t, ks, ks, groupId)
+	item.CipherKey = "0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f20"
+	clone := proto.Clone(item).(*chestnutpb.GroupItem)
+	group, err := groupmgr.Create(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubkey, err := p2pcrypto.MarshalPublicKey(ks.key(t, groupId).GetPublic())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent := item.GenesisBlock
+	for i := 1; i <= count; i++ {
+		trx, err := group.ChainCtx.GetProducerTrxMgr().CreateTrx(chestnutpb.TrxType_POST, []byte(fmt.Sprintf("post %d", i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		block, err := CreateBlock(parent, []*chestnutpb.Trx{trx}, pubkey, ks)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := group.ChainCtx.Consensus.Producer().AddBlock(block); err != nil {
+			t.Fatal(err)
+		}
+		parent = block
+	}
+	checkChainHead(t, group, int64(count))
+	return group, clone
+}
+
+func checkChainHead(t *testing.T, group *Group, height int64) {
+	if got, _ := group.ChainHead(); got != height {
+		t.Fatalf("chain head at %d, want %d", got, height)
+	}
+}
+
+func readArchive(t *testing.T, archive []byte) []*chestnutpb.Block {
+	var blocks []*chestnutpb.Block
+	r := bufio.NewReader(bytes.NewReader(archive))
+	for {
+		block, err := readDelimitedBlock(r)
+		if err == io.EOF {
+			return blocks
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, block)
+	}
+}
+
+func writeArchive(t *testing.T, blocks []*chestnutpb.Block) []byte {
+	var buf bytes.Buffer
+	for _, block := range blocks {
+		if err := writeDelimitedBlock(&buf, block); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// group manager of a new tenant hosting the group of the archive, with the key of the group
+func newTestImportGroup(t *testing.T, ks *testKeystore, item *chestnutpb.GroupItem) (*GroupMgr, *Group) {
+	groupmgr, tenantKs := newTestGroupMgr(t)
+	tenantKs.keys[item.GroupId] = ks.key(t, item.GroupId)
+	group, err := groupmgr.Create(proto.Clone(item).(*chestnutpb.GroupItem))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return groupmgr, group
+}
+
+// blocks exported by a group are imported into the group hosted by another tenant, an import
+// stopped by a tampered block is resumed by importing the archive again
+func TestExportImportBlocks(t *testing.T) {
+	const blockCount = 10
+	groupmgr, ks := newTestGroupMgr(t)
+	groupId := groupmgr.Tenant().Name + "-archive"
+	group, item := newTestArchiveGroup(t, groupmgr, ks, groupId, blockCount)
+
+	var buf bytes.Buffer
+	count, err := group.ExportBlocks(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != blockCount+1 {
+		t.Fatalf("exported %d blocks, want %d", count, blockCount+1)
+	}
+	archive := buf.Bytes()
+	if err := groupmgr.Delete(groupId); err != nil {
+		t.Fatal(err)
+	}
+
+	// the data of a trx is changed, the trx signature is invalid
+	blocks := readArchive(t, archive)
+	blocks[6].Trxs[0].Data = append([]byte{}, blocks[6].Trxs[0].Data...)
+	blocks[6].Trxs[0].Data[0] ^= 0xff
+	tampered := writeArchive(t, blocks)
+
+	groupmgr, group = newTestImportGroup(t, ks, item)
+	count, err = group.ImportBlocks(bytes.NewReader(tampered))
+	if err == nil {
+		t.Fatal("tampered block imported")
+	}
+	if count != 6 {
+		t.Fatalf("%d blocks imported before the tampered block, want 6", count)
+	}
+	checkChainHead(t, group, 5)
+	archiveId := ArchiveId(groupId, blocks[0])
+	imported, err := nodectx.GetDbMgr().GetImportProgress(groupId, archiveId, groupmgr.Tenant().Name)
+	if err != nil || imported != 6 {
+		t.Fatalf("import progress %d %v, want 6", imported, err)
+	}
+	if imported, _ := nodectx.GetDbMgr().GetImportProgress(groupId, ArchiveId("other", blocks[0]), groupmgr.Tenant().Name); imported != 0 {
+		t.Fatalf("import progress %d of another archive", imported)
+	}
+
+	count, err = group.ImportBlocks(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != blockCount+1 {
+		t.Fatalf("imported %d blocks, want %d", count, blockCount+1)
+	}
+	checkChainHead(t, group, blockCount)
+	if imported, _ := nodectx.GetDbMgr().GetImportProgress(groupId, archiveId, groupmgr.Tenant().Name); imported != 0 {
+		t.Fatalf("import progress %d left after import", imported)
+	}
+	if err := groupmgr.Delete(groupId); err != nil {
+		t.Fatal(err)
+	}
+
+	// the blocks before a resume point not matching the chain are imported
+	groupmgr, group = newTestImportGroup(t, ks, item)
+	if err := nodectx.GetDbMgr().UpdImportProgress(groupId, archiveId, blockCount, groupmgr.Tenant().Name); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := group.ImportBlocks(bytes.NewReader(archive)); err != nil {
+		t.Fatal(err)
+	}
+	checkChainHead(t, group, blockCount)
+}
@@ -0,0 +1,112 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/libp2p/go-libp2p-core/peer"
+	"github.com/lixvyang/chestnut/nodectx"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+)
+
+// save an attachment of the group, the returned manifest has the blob id referenced by posts
+func (grp *Group) AddBlob(name string, mediaType string, r io.Reader) (*chestnutpb.BlobManifest, error) {
+	group_log.Debugf("<%s> AddBlob called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().AddBlob(grp.Item.GroupId, name, mediaType, r, grp.ChainCtx.nodename)
+}
+
+// get the manifest of a blob, the blob is fetched from group peers if it is not saved locally
+func (grp *Group) GetBlobManifest(ctx context.Context, blobId string) (*chestnutpb.BlobManifest, error) {
+	group_log.Debugf("<%s> GetBlobManifest called", grp.Item.GroupId)
+	if !storage.IsBlobId(blobId) {
+		return nil, errors.New("invalid blob id")
+	}
+
+	dbMgr := nodectx.GetDbMgr()
+	ref, err := dbMgr.IsBlobRef(grp.Item.GroupId, blobId, grp.ChainCtx.nodename)
+	if err != nil {
+		return nil, err
+	}
+	if ref {
+		return dbMgr.GetBlobManifest(blobId, grp.ChainCtx.nodename)
+	}
+
+	node := nodectx.GetNodeCtx().Node
+	if node == nil || node.Blob == nil {
+		return nil, errors.New("blob service is not available")
+	}
+	peers := nodectx.GetNodeCtx().ListGroupPeers(grp.Item.GroupId)
+	return node.Blob.Fetch(ctx, grp.Item.GroupId, blobId, peers)
+}
+
+// write the content of a blob to w, fetch it from group peers first if needed
+func (grp *Group) ReadBlob(ctx context.Context, blobId string, w io.Writer) error {
+	if _, err := grp.GetBlobManifest(ctx, blobId); err != nil {
+		return err
+	}
+	return nodectx.GetDbMgr().ReadBlob(blobId, w, grp.ChainCtx.nodename)
+}
+
+// check all blobs referenced by the object are saved in the group
+func (grp *Group) CheckAttachments(obj *chestnutpb.Object) error {
+	dbMgr := nodectx.GetDbMgr()
+	for _, blobId := range AttachedBlobs(obj) {
+		if err := dbMgr.CheckBlob(grp.Item.GroupId, blobId, grp.ChainCtx.nodename); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// blob ids referenced by Object.attachments and Image.url of the object
+func AttachedBlobs(obj *chestnutpb.Object) []string {
+	blobIds := []string{}
+	if obj == nil {
+		return blobIds
+	}
+	for _, image := range obj.Image {
+		if blobId := storage.ParseBlobUrl(image.Url); blobId != "" {
+			blobIds = append(blobIds, blobId)
+		}
+	}
+	for _, attachment := range obj.Attachments {
+		if blobId := storage.ParseBlobUrl(attachment.Id); blobId != "" {
+			blobIds = append(blobIds, blobId)
+		}
+		for _, link := range attachment.Url {
+			if blobId := storage.ParseBlobUrl(link.Href); blobId != "" {
+				blobIds = append(blobIds, blobId)
+			}
+		}
+		for _, image := range attachment.Image {
+			if blobId := storage.ParseBlobUrl(image.Url); blobId != "" {
+				blobIds = append(blobIds, blobId)
+			}
+		}
+	}
+	return blobIds
+}
+
+// node name prefix of the data of a group, the name of the tenant hosting the group
+func GroupNodeName(groupId string) string {
+	if group, _, ok := FindGroup(groupId); ok {
+		return group.ChainCtx.nodename
+	}
+	return nodectx.GetNodeCtx().Name
+}
+
+// blobs of a group are only served to peers of the group user channel
+func IsGroupPeer(groupId string, p peer.ID) bool {
+	if _, _, ok := FindGroup(groupId); !ok {
+		return false
+	}
+	for _, groupPeer := range nodectx.GetNodeCtx().ListGroupPeers(groupId) {
+		if groupPeer == p {
+			return true
+		}
+	}
+	return false
+}
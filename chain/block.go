@@ -3,22 +3,26 @@ package chain
 
 import (
 	"bytes"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	guuid "github.com/google/uuid"
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
-	"github.com/lixvyang/chestnut/nodectx"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 	"google.golang.org/protobuf/proto"
 )
 
 
-func CreateBlock(oldBlock *chestnutpb.Block, trxs []*chestnutpb.Trx, groupPublicKey []byte, opts ...string) (*chestnutpb.Block, error) {
+// block id of a new block follows the id of the parent, a chain with a legacy uuid genesis
+// block keeps producing uuid ids so nodes not knowing hash ids can still verify the blocks
+func CreateBlock(oldBlock *chestnutpb.Block, trxs []*chestnutpb.Trx, groupPublicKey []byte, ks localcrypto.Keystore, opts ...string) (*chestnutpb.Block, error) {
 	var newBlock chestnutpb.Block
-	blockId := guuid.New()
-
-	newBlock.BlockId = blockId.String()
+	hashId := IsHashBlockId(oldBlock)
+	if !hashId {
+		newBlock.BlockId = guuid.New().String()
+	}
 	newBlock.GroupId = oldBlock.GroupId
 	newBlock.PrevBlockId = oldBlock.BlockId
 	newBlock.PreviousHash = oldBlock.Hash
@@ -35,16 +39,25 @@ func CreateBlock(oldBlock *chestnutpb.Block, trxs []*chestnutpb.Trx, groupPublic
 	}
 	newBlock.ProducerPubKey = p2pcrypto.ConfigEncodeKey(groupPublicKey)
 	newBlock.TimeStamp = time.Now().UnixNano()
+	//a chain started with a trx root keeps committing to the trxs by the root
+	if len(oldBlock.TrxRoot) > 0 {
+		root, _, err := TrxRoot(newBlock.Trxs)
+		if err != nil {
+			return nil, err
+		}
+		newBlock.TrxRoot = root
+	}
 
-	bbytes, err := proto.Marshal(&newBlock)
+	hash, err := blockHash(&newBlock)
 	if err != nil {
 		return nil, err
 	}
-
-	hash := Hash(bbytes)
 	newBlock.Hash = hash
+	if hashId {
+		newBlock.BlockId = BlockIdFromHash(hash)
+	}
 
-	signature, err := nodectx.GetNodeCtx().Keystore.SignByKeyName(newBlock.GroupId,hash,opts...)
+	signature, err := ks.SignByKeyName(newBlock.GroupId,hash,opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -54,14 +67,18 @@ func CreateBlock(oldBlock *chestnutpb.Block, trxs []*chestnutpb.Trx, groupPublic
 }
 
 
-func CreateGeneisBlock(groupId string, groupPublicKey p2pcrypto.PubKey) (*chestnutpb.Block, error)  {
+// admins is nil for a group owned by the group key only, legacyId creates a chain of uuid block ids
+// without trx roots
+func CreateGeneisBlock(groupId string, groupPublicKey p2pcrypto.PubKey, admins *chestnutpb.AdminSet, config *chestnutpb.GroupConfig, legacyId bool, ks localcrypto.Keystore) (*chestnutpb.Block, error)  {
 	encodedgroupPubkey, err := p2pcrypto.MarshalPublicKey(groupPublicKey)
 	if err != nil {
 		return nil, err
 	}
 
 	var genesisBlock chestnutpb.Block
-	genesisBlock.BlockId = guuid.New().String()
+	if legacyId {
+		genesisBlock.BlockId = guuid.New().String()
+	}
 	genesisBlock.GroupId = groupId
 	genesisBlock.PrevBlockId = ""
 	genesisBlock.PreviousHash = nil
@@ -69,16 +86,22 @@ func CreateGeneisBlock(groupId string, groupPublicKey p2pcrypto.PubKey) (*chestn
 
 	genesisBlock.ProducerPubKey = p2pcrypto.ConfigEncodeKey(encodedgroupPubkey)
 	genesisBlock.Trxs = nil
+	genesisBlock.Admins = admins
+	genesisBlock.Config = config
+	if !legacyId {
+		genesisBlock.TrxRoot = MerkleRoot(nil)
+	}
 	
-	bbytes, err := proto.Marshal(&genesisBlock)
+	hash, err := blockHash(&genesisBlock)
 	if err != nil {
 		return nil, err
 	}
-
-	hash := Hash(bbytes)
 	genesisBlock.Hash = hash
+	if !legacyId {
+		genesisBlock.BlockId = BlockIdFromHash(hash)
+	}
 
-	signature, err := nodectx.GetNodeCtx().Keystore.SignByKeyName(genesisBlock.GroupId, hash)
+	signature, err := ks.SignByKeyName(genesisBlock.GroupId, hash)
 	if err != nil {
 		return nil, err
 	}
@@ -88,32 +111,37 @@ func CreateGeneisBlock(groupId string, groupPublicKey p2pcrypto.PubKey) (*chestn
 }
 
 
-func IsBlockValid(newBlock, oldBlock *chestnutpb.Block) (bool, error) {
-	// deep copy newBlock by the protobuf. chestnutpb.Block is a protopbbuf defined struct.
-	clonedblockbuff, err := proto.Marshal(newBlock)
-	if err != nil {
-		return false, err
-	}
+// the id of a block is the hex encoded hash, the hash is calculated without the id
+func BlockIdFromHash(hash []byte) string {
+	return hex.EncodeToString(hash)
+}
 
-	var blockWithoutHash *chestnutpb.Block
-	blockWithoutHash = &chestnutpb.Block{}
+// false for the uuid ids of blocks created before ids were derived from the hash
+func IsHashBlockId(block *chestnutpb.Block) bool {
+	return len(block.Hash) > 0 && block.BlockId == BlockIdFromHash(block.Hash)
+}
 
-	err = proto.Unmarshal(clonedblockbuff,blockWithoutHash)
-	if err != nil {
-		return false, err
+// blocks of a chain with hash ids must have hash ids, chainBlock is the parent or the genesis block.
+// Chains started with uuid ids accept both.
+func CheckBlockId(block, chainBlock *chestnutpb.Block) error {
+	if IsHashBlockId(chainBlock) && !IsHashBlockId(block) {
+		return errors.New("Block id is not derived from the block hash")
 	}
+	return nil
+}
 
-	// set hash to ""
-	blockWithoutHash.Hash = nil
-	blockWithoutHash = &chestnutpb.Block{}
+func IsBlockValid(newBlock, oldBlock *chestnutpb.Block) (bool, error) {
+	return isBlockValid(newBlock, oldBlock, false)
+}
 
-	bbytes, err := proto.Marshal(blockWithoutHash)
-	if err != nil {
-		return  false, err
-	}
-	hash := Hash(bbytes)
-	if res := bytes.Compare(hash, newBlock.Hash); res != 0 {
-		return false, errors.New("Hash for new block is invalid")
+// a light node accepts blocks with trx data stripped, the trxs with data are checked by the leaf hashes
+func IsLightBlockValid(newBlock, oldBlock *chestnutpb.Block) (bool, error) {
+	return isBlockValid(newBlock, oldBlock, true)
+}
+
+func isBlockValid(newBlock, oldBlock *chestnutpb.Block, light bool) (bool, error) {
+	if err := CheckBlockId(newBlock, oldBlock); err != nil {
+		return false, err
 	}
 
 	if res := bytes.Compare(newBlock.PreviousHash, oldBlock.Hash); res != 0 {
@@ -124,8 +152,32 @@ func IsBlockValid(newBlock, oldBlock *chestnutpb.Block) (bool, error) {
 		return false, errors.New("Previous BlockId mismatch")
 	}
 
+	return isBlockSignValid(newBlock, light)
+}
+
+//verify block hash, trxs and producer signature, without checking the parent block
+func IsBlockSignValid(block *chestnutpb.Block) (bool, error) {
+	return isBlockSignValid(block, false)
+}
+
+func isBlockSignValid(block *chestnutpb.Block, light bool) (bool, error) {
+	if !light && IsBlockStripped(block) {
+		return false, errors.New("Trx data of block is stripped")
+	}
+	if err := CheckTrxRoot(block); err != nil {
+		return false, err
+	}
+
+	hash, err := blockHash(block)
+	if err != nil {
+		return false, err
+	}
+	if res := bytes.Compare(hash, block.Hash); res != 0 {
+		return false, errors.New("Hash for new block is invalid")
+	}
+
 	// create pubkey
-	serializedpub, err := p2pcrypto.ConfigDecodeKey(newBlock.ProducerPubKey)
+	serializedpub, err := p2pcrypto.ConfigDecodeKey(block.ProducerPubKey)
 	if err != nil {
 		return false, err
 	}
@@ -135,8 +187,41 @@ func IsBlockValid(newBlock, oldBlock *chestnutpb.Block) (bool, error) {
 		return false, err
 	}
 
-	verify, err := pubkey.Verify(newBlock.Hash,newBlock.Signature)
+	verify, err := pubkey.Verify(block.Hash, block.Signature)
 	return verify, err
-
 }
 
+// hash of the block content. Hash and signature are calculated after the content is filled, so is a hash id.
+// A block with a trx root commits to the trxs by the root, the hash covers the header only so a light node
+// can verify the block without the trx data
+func blockHash(block *chestnutpb.Block) ([]byte, error) {
+	// deep copy block by the protobuf. chestnutpb.Block is a protopbbuf defined struct.
+	clonedblockbuff, err := proto.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockWithoutHash *chestnutpb.Block
+	blockWithoutHash = &chestnutpb.Block{}
+
+	err = proto.Unmarshal(clonedblockbuff, blockWithoutHash)
+	if err != nil {
+		return nil, err
+	}
+
+	blockWithoutHash.Hash = nil
+	blockWithoutHash.Signature = nil
+	if IsHashBlockId(block) {
+		blockWithoutHash.BlockId = ""
+	}
+	if len(block.TrxRoot) > 0 {
+		blockWithoutHash.Trxs = nil
+		blockWithoutHash.TrxHashes = nil
+	}
+
+	bbytes, err := proto.Marshal(blockWithoutHash)
+	if err != nil {
+		return nil, err
+	}
+	return Hash(bbytes), nil
+}
@@ -2,13 +2,13 @@
 package chain
 
 import (
-	"encoding/hex"
 	"errors"
 	"sync"
 	"time"
 
 	"github.com/lixvyang/chestnut/nodectx"
 	"github.com/lixvyang/chestnut/pubsubconn"
+	"github.com/lixvyang/chestnut/storage"
 
 	logging "github.com/ipfs/go-log/v2"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
@@ -28,49 +28,93 @@ type Chain struct {
 	userChannelId     string
 	producerChannelId string
 	trxMgrs           map[string]*TrxMgr
+	producerPsconn    pubsubconn.PubSubConn
+	userPsconn        pubsubconn.PubSubConn
 	ProducerPool      map[string]*chestnutpb.ProducerItem
 
 	Syncer    *Syncer
 	Consensus Consensus
 	statusmu  sync.RWMutex
 	groupId   string
+
+	livemu     sync.RWMutex
+	liveness   map[string]*ProducerLiveness
+	roundStart int64
+
+	configmu     sync.Mutex
+	config       *chestnutpb.GroupConfig
+	configHeight int64
 }
 
 func (chain *Chain) Init(group *Group) error {
 	chain_log.Debugf("<%s> Init called", group.Item.GroupId)
 	chain.group = group
+	chain.groupId = group.Item.GroupId
 	chain.trxMgrs = make(map[string]*TrxMgr)
-	chain.nodename = nodectx.GetNodeCtx().Name
+	chain.nodename = group.tenant.Name
 
 	// create user channel
 	chain.userChannelId = USER_CHANNEL_PREFIX + group.Item.GroupId
 	chain.producerChannelId = PRODUCER_CHANNEL_PREFIX + group.Item.GroupId
 
-	producerPsconn := pubsubconn.InitP2pPubSubConn(nodectx.GetNodeCtx().Ctx, nodectx.GetNodeCtx().Node.Pubsub, nodectx.GetNodeCtx().Name)
-	producerPsconn.JoinChannel(chain.producerChannelId, chain)
+	producerPsconn := pubsubconn.InitP2pPubSubConn(nodectx.GetNodeCtx().Ctx, nodectx.GetNodeCtx().Node.Pubsub, chain.nodename)
+	userPsconn := pubsubconn.InitP2pPubSubConn(nodectx.GetNodeCtx().Ctx, nodectx.GetNodeCtx().Node.Pubsub, chain.nodename)
+	chain.producerPsconn = producerPsconn
+	chain.userPsconn = userPsconn
 
-	userPsconn := pubsubconn.InitP2pPubSubConn(nodectx.GetNodeCtx().Ctx, nodectx.GetNodeCtx().Node.Pubsub, nodectx.GetNodeCtx().Name)
-	userPsconn.JoinChannel(chain.userChannelId, chain)
+	// a paused group joins the channels when resumed
+	if !group.paused {
+		chain.JoinChannels()
+	}
 
 	// create user trx manager
 	var userTrxMgr *TrxMgr
 	userTrxMgr = &TrxMgr{}
-	userTrxMgr.Init(chain.group.Item, userPsconn)
+	userTrxMgr.Init(chain.group.Item, userPsconn, group.tenant.Keystore)
+	userTrxMgr.SetNodeName(chain.nodename)
+	userTrxMgr.SetConfig(chain.GetConfig)
+	userTrxMgr.SetGroupItem(chain.GetGroupItem)
 	chain.trxMgrs[chain.producerChannelId] = userTrxMgr
 
 	var producerTrxMgr *TrxMgr
 	producerTrxMgr = &TrxMgr{}
-	producerTrxMgr.Init(chain.group.Item, producerPsconn)
+	producerTrxMgr.Init(chain.group.Item, producerPsconn, group.tenant.Keystore)
+	producerTrxMgr.SetNodeName(chain.nodename)
+	producerTrxMgr.SetConfig(chain.GetConfig)
+	producerTrxMgr.SetGroupItem(chain.GetGroupItem)
 	chain.trxMgrs[chain.producerChannelId] = producerTrxMgr
 	
 	chain.Syncer = &Syncer{nodeName: chain.nodename}
 	chain.Syncer.Init(chain.group, producerTrxMgr)
 
-	chain.groupId = group.Item.GroupId
 	chain_log.Infof("<%s> chainct initialed", chain.groupId)
 	return nil
 }
 
+func (chain *Chain) JoinChannels() error {
+	chain_log.Debugf("<%s> JoinChannels called", chain.groupId)
+	if err := chain.producerPsconn.JoinChannel(chain.producerChannelId, chain); err != nil {
+		return err
+	}
+	return chain.userPsconn.JoinChannel(chain.userChannelId, chain)
+}
+
+func (chain *Chain) LeaveChannels() error {
+	chain_log.Debugf("<%s> LeaveChannels called", chain.groupId)
+	if err := chain.producerPsconn.LeaveChannel(); err != nil {
+		return err
+	}
+	return chain.userPsconn.LeaveChannel()
+}
+
+// stop the producer of group, a new producer is created by CreateConsensus
+func (chain *Chain) StopProducer() {
+	chain_log.Debugf("<%s> StopProducer called", chain.groupId)
+	if chain.Consensus != nil && chain.Consensus.Producer() != nil {
+		chain.Consensus.Producer().Stop()
+	}
+}
+
 func (chain *Chain) StartInitialSync(block *chestnutpb.Block) error {
 	chain_log.Debugf("<%s> StartInitialSync called", chain.groupId)
 	if chain.Syncer != nil {
@@ -87,6 +131,20 @@ func (chain *Chain) StopSync() error {
 	return nil
 }
 
+// copy of the group item, producers and users read the chain head and cipher key from it
+func (chain *Chain) GetGroupItem() *chestnutpb.GroupItem {
+	return chain.group.ItemSnapshot()
+}
+
+func (chain *Chain) GetChainHead() (int64, string) {
+	return chain.group.ChainHead()
+}
+
+// keystore of the tenant hosting the group
+func (chain *Chain) GetKeystore() localcrypto.Keystore {
+	return chain.group.tenant.Keystore
+}
+
 func (chain *Chain) GetProducerTrxMgr() *TrxMgr {
 	chain_log.Debugf("<%s> GetProducerTrxMgr called", chain.groupId)
 	return chain.trxMgrs[chain.producerChannelId]
@@ -97,13 +155,29 @@ func (chain *Chain) GetUserTrxMgr() *TrxMgr {
 	return chain.trxMgrs[chain.userChannelId]
 }
 
-func (chain *Chain) UpdChainInfo(height int64, blockId string) error {
+// save chain info with dbMgr, the group item in memory is updated after dbMgr committed
+func (chain *Chain) UpdChainInfo(height int64, blockId string, dbMgr *storage.DbMgr) error {
 	chain_log.Debugf("<%s> UpdChainInfo called", chain.groupId)
-	chain.group.Item.HighestHeight = height
-	chain.group.Item.HighestBlockId = blockId
-	chain.group.Item.LastUpdate = time.Now().UnixNano()
-	chain_log.Infof("<%s> Chain Info updated %d, %v", chain.group.Item.GroupId, height, blockId)
-	return nodectx.GetDbMgr().UpdGroup(chain.group.Item)
+	item := chain.group.ItemSnapshot()
+	item.HighestHeight = height
+	item.HighestBlockId = blockId
+	item.LastUpdate = time.Now().UnixNano()
+	if err := dbMgr.UpdGroup(item, chain.group.groupPrefix()...); err != nil {
+		return err
+	}
+
+	dbMgr.OnCommit(func() {
+		chain.group.itemmu.Lock()
+		chain.group.Item.HighestHeight = item.HighestHeight
+		chain.group.Item.HighestBlockId = item.HighestBlockId
+		chain.group.Item.LastUpdate = item.LastUpdate
+		chain.group.itemmu.Unlock()
+		chain_log.Infof("<%s> Chain Info updated %d, %v", chain.group.Item.GroupId, height, blockId)
+		if groupMgr != nil {
+			groupMgr.notifyBlockAccepted(chain.groupId, blockId, height)
+		}
+	})
+	return nil
 }
 
 func (chain *Chain) HandleTrx(trx *chestnutpb.Trx) error {
@@ -123,6 +197,12 @@ func (chain *Chain) HandleTrx(trx *chestnutpb.Trx) error {
 		chain.producerAddTrx(trx)
 	case chestnutpb.TrxType_SCHEMA:
 		chain.producerAddTrx(trx)
+	case chestnutpb.TrxType_INVITE:
+		chain.producerAddTrx(trx)
+	case chestnutpb.TrxType_REDEEM:
+		chain.producerAddTrx(trx)
+	case chestnutpb.TrxType_CONFIG:
+		chain.producerAddTrx(trx)
 	case chestnutpb.TrxType_REQ_BLOCK_FORWARD:
 		if trx.SenderPubkey == chain.group.Item.UserSignPubkey {
 			return nil
@@ -138,9 +218,21 @@ func (chain *Chain) HandleTrx(trx *chestnutpb.Trx) error {
 			return nil
 		}
 		chain.handleReqBlockResp(trx)
+	case chestnutpb.TrxType_REQ_TRX:
+		if trx.SenderPubkey == chain.group.Item.UserSignPubkey {
+			return nil
+		}
+		chain.handleReqTrx(trx)
+	case chestnutpb.TrxType_REQ_TRX_RESP:
+		if trx.SenderPubkey == chain.group.Item.UserSignPubkey {
+			return nil
+		}
+		chain.handleReqTrxResp(trx)
 	case chestnutpb.TrxType_BLOCK_PRODUCED:
 		chain.handleBlockProduced(trx)
 		return nil
+	case chestnutpb.TrxType_HEARTBEAT:
+		chain.handleHeartbeat(trx)
 	default:
 		chain_log.Warningf("<%s> unsupported msg type", chain.group.Item.GroupId)
 		err := errors.New("unsupported msg type")
@@ -155,6 +247,7 @@ func (chain *Chain) HandleBlock(block *chestnutpb.Block) error {
 
 	var shouldAccept bool
 
+	chain.markSeen(block.ProducerPubKey, 0)
 	if chain.Consensus.Producer() != nil {
 		//if I am a producer, no need to addBlock since block just produced is already saved
 		shouldAccept = false
@@ -167,6 +260,12 @@ func (chain *Chain) HandleBlock(block *chestnutpb.Block) error {
 		chain_log.Warningf("<%s> received block <%s> from unregisted producer <%s>, reject it", chain.group.Item.GroupId, block.BlockId, block.ProducerPubKey)
 	}
 
+	if shouldAccept && chain.GetLightMode() != nil && !IsBlockStripped(block) {
+		//a light node doesn't save full blocks, sync the stripped blocks from the producers
+		shouldAccept = false
+		chain.syncLightBlocks()
+	}
+
 	if shouldAccept {
 		err := chain.Consensus.User().AddBlock(block)
 		if err != nil {
@@ -207,12 +306,7 @@ func (chain *Chain) handleReqBlockBackward(trx *chestnutpb.Trx) error {
 }
 
 func (chain *Chain) handleReqBlockResp(trx *chestnutpb.Trx) error {
-	ciperKey, err := hex.DecodeString(chain.group.Item.CipherKey)
-	if err != nil {
-		return err
-	}
-
-	decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+	decryptData, err := DecryptGroupData(chain.GetGroupItem(), trx.Data)
 	if err != nil {
 		return err
 	}
@@ -253,6 +347,7 @@ func (chain *Chain) handleReqBlockResp(trx *chestnutpb.Trx) error {
 }
 
 func (chain *Chain) handleBlockProduced(trx *chestnutpb.Trx) error {
+	chain.markSeen(trx.SenderPubkey, 0)
 	if chain.Consensus.Producer() == nil {
 		return nil
 	}
@@ -305,10 +400,11 @@ func (chain *Chain) CreateConsensus() {
 
 func (chain *Chain) IsSyncerReady() bool {
 	chain_log.Debugf("<%s> IsSyncerReady called", chain.groupId)
-	if chain.Syncer.Status == SYNCING_BACKWARD ||
-		chain.Syncer.Status == SYNCING_FORWARD ||
-		chain.Syncer.Status == SYNC_FAILED {
-		chain_log.Debugf("<%s> syncer is busy, status: <%d>", chain.groupId, chain.Syncer.Status)
+	status := chain.Syncer.GetStatus()
+	if status == SYNCING_BACKWARD ||
+		status == SYNCING_FORWARD ||
+		status == SYNC_FAILED {
+		chain_log.Debugf("<%s> syncer is busy, status: <%d>", chain.groupId, status)
 		return true
 	}
 	chain_log.Debugf("<%s> syncer is IDLE", chain.groupId)
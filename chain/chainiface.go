@@ -2,15 +2,24 @@
 package chain
 
 import (
+	localcrypto "github.com/lixvyang/chestnut/crypto"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
 )
 
 type ChainMolassesIface interface {
 	GetUserTrxMgr() *TrxMgr
 	GetProducerTrxMgr() *TrxMgr
-	UpdChainInfo(height int64, blockId string) error
+	UpdChainInfo(height int64, blockId string, dbMgr *storage.DbMgr) error
 	UpdProducerList()
 	CreateConsensus()
 	IsSyncerReady() bool
 	SyncBackward(block *chestnutpb.Block) error
+	GetKeystore() localcrypto.Keystore
+	GetConfig() *chestnutpb.GroupConfig
+	GetConfigAt(height int64) *chestnutpb.GroupConfig
+	UpdConfig()
+	GetLightMode() *chestnutpb.LightModeItem
+	GetGroupItem() *chestnutpb.GroupItem
+	GetChainHead() (int64, string)
 }
\ No newline at end of file
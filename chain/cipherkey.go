@@ -0,0 +1,70 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/nodectx"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// keyring of the group: CipherKey for CipherKeyEpoch, the keys of earlier epochs from EpochCipherKeys
+func GroupKeyring(item *chestnutpb.GroupItem) localcrypto.CipherKeyring {
+	return func(epoch uint32) ([]byte, error) {
+		if epoch == item.CipherKeyEpoch {
+			return hex.DecodeString(item.CipherKey)
+		}
+		key, ok := item.EpochCipherKeys[epoch]
+		if !ok {
+			return nil, localcrypto.ErrUnknownKeyEpoch
+		}
+		return hex.DecodeString(key)
+	}
+}
+
+// decrypt data sealed with the group cipher key, with the key of the epoch in the envelope
+func DecryptGroupData(item *chestnutpb.GroupItem, data []byte) ([]byte, error) {
+	return localcrypto.OpenEnvelope(data, GroupKeyring(item))
+}
+
+// switch the group to the cipher key of a new epoch, data is sealed with the new key from now on.
+// The current key is kept to open data sealed before. Legacy ciphertext has no epoch, the key of
+// a group with the legacy cipher alg can't be changed
+func (grp *Group) UpdCipherKey(epoch uint32, cipherKey string) error {
+	group_log.Debugf("<%s> UpdCipherKey called", grp.Item.GroupId)
+	alg, err := localcrypto.ParseCipherAlg(grp.Item.CipherAlg)
+	if err != nil {
+		return err
+	}
+	if alg == localcrypto.CIPHER_ALG_LEGACY {
+		return errors.New("cipher key of a group with the legacy cipher alg can't be changed")
+	}
+	if _, err := hex.DecodeString(cipherKey); err != nil {
+		return err
+	}
+
+	grp.itemmu.Lock()
+	defer grp.itemmu.Unlock()
+	if epoch <= grp.Item.CipherKeyEpoch {
+		return fmt.Errorf("cipher key epoch %d must be greater than the current epoch %d", epoch, grp.Item.CipherKeyEpoch)
+	}
+	item := proto.Clone(grp.Item).(*chestnutpb.GroupItem)
+	if item.EpochCipherKeys == nil {
+		item.EpochCipherKeys = make(map[uint32]string)
+	}
+	item.EpochCipherKeys[item.CipherKeyEpoch] = item.CipherKey
+	item.CipherKey = cipherKey
+	item.CipherKeyEpoch = epoch
+	if err := nodectx.GetDbMgr().UpdGroup(item, grp.groupPrefix()...); err != nil {
+		return err
+	}
+	grp.Item.EpochCipherKeys = item.EpochCipherKeys
+	grp.Item.CipherKey = item.CipherKey
+	grp.Item.CipherKeyEpoch = item.CipherKeyEpoch
+	group_log.Infof("<%s> cipher key epoch %d", grp.Item.GroupId, epoch)
+	return nil
+}
@@ -0,0 +1,203 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	logging "github.com/ipfs/go-log/v2"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/lixvyang/chestnut/nodectx"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+var config_log = logging.Logger("config")
+
+// config of groups created without one
+const (
+	DEFAULT_PRODUCE_TIMER     int64 = 5000 //ms
+	DEFAULT_MERGE_TIMER       int64 = 5000 //ms
+	DEFAULT_TRXS_TOTAL_SIZE   int64 = 900 * 1024
+	DEFAULT_OBJECT_SIZE_LIMIT int64 = 200 * 1024
+	DEFAULT_TRX_EXPIRE        int64 = 5 * 60 //s
+)
+
+// room left in a pubsub message for the block header, the trx and package wrapping the block and
+// the encryption of the trx data
+const BLOCK_MESSAGE_OVERHEAD int64 = 64 * 1024
+
+// bounds of a group config, a produced block is sent in one pubsub message of at most
+// pubsub.DefaultMaxMessageSize, so the trxs of a block must fit in it with the overhead
+const (
+	MIN_TIMER           int64 = 100            //ms
+	MAX_TIMER           int64 = 10 * 60 * 1000 //ms
+	MAX_TRXS_TOTAL_SIZE int64 = pubsub.DefaultMaxMessageSize - BLOCK_MESSAGE_OVERHEAD
+	MIN_OBJECT_SIZE     int64 = 1024
+	MIN_TRX_EXPIRE      int64 = 10           //s
+	MAX_TRX_EXPIRE      int64 = 24 * 60 * 60 //s
+)
+
+// a config change takes effect this many blocks after the height it is sent at, if not given
+const CONFIG_DELAY_BLOCKS int64 = 10
+
+// fill the fields not set with the defaults
+func WithDefaults(config *chestnutpb.GroupConfig) *chestnutpb.GroupConfig {
+	result := &chestnutpb.GroupConfig{}
+	if config != nil {
+		result = proto.Clone(config).(*chestnutpb.GroupConfig)
+	}
+	if result.ProduceTimer == 0 {
+		result.ProduceTimer = DEFAULT_PRODUCE_TIMER
+	}
+	if result.MergeTimer == 0 {
+		result.MergeTimer = DEFAULT_MERGE_TIMER
+	}
+	if result.TrxsTotalSize == 0 {
+		result.TrxsTotalSize = DEFAULT_TRXS_TOTAL_SIZE
+	}
+	if result.ObjectSizeLimit == 0 {
+		result.ObjectSizeLimit = DEFAULT_OBJECT_SIZE_LIMIT
+	}
+	if result.TrxExpire == 0 {
+		result.TrxExpire = DEFAULT_TRX_EXPIRE
+	}
+	return result
+}
+
+// check a config with defaults filled
+func CheckGroupConfig(config *chestnutpb.GroupConfig) error {
+	if config.ProduceTimer < MIN_TIMER || config.ProduceTimer > MAX_TIMER {
+		return fmt.Errorf("produce timer must be between %d and %d ms", MIN_TIMER, MAX_TIMER)
+	}
+	if config.MergeTimer < MIN_TIMER || config.MergeTimer > MAX_TIMER {
+		return fmt.Errorf("merge timer must be between %d and %d ms", MIN_TIMER, MAX_TIMER)
+	}
+	if config.TrxsTotalSize > MAX_TRXS_TOTAL_SIZE {
+		return fmt.Errorf("trxs total size must be at most %d bytes", MAX_TRXS_TOTAL_SIZE)
+	}
+	if config.ObjectSizeLimit < MIN_OBJECT_SIZE || config.ObjectSizeLimit > config.TrxsTotalSize {
+		return fmt.Errorf("object size limit must be between %d bytes and the trxs total size", MIN_OBJECT_SIZE)
+	}
+	if config.TrxExpire < MIN_TRX_EXPIRE || config.TrxExpire > MAX_TRX_EXPIRE {
+		return fmt.Errorf("trx expire must be between %d and %d s", MIN_TRX_EXPIRE, MAX_TRX_EXPIRE)
+	}
+	return nil
+}
+
+// hash signed by the owner
+func ConfigHash(item *chestnutpb.ConfigItem) []byte {
+	config := WithDefaults(item.Config)
+	var buffer bytes.Buffer
+	buffer.Write([]byte(item.GroupId))
+	binary.Write(&buffer, binary.BigEndian, config.ProduceTimer)
+	binary.Write(&buffer, binary.BigEndian, config.MergeTimer)
+	binary.Write(&buffer, binary.BigEndian, config.TrxsTotalSize)
+	binary.Write(&buffer, binary.BigEndian, config.ObjectSizeLimit)
+	binary.Write(&buffer, binary.BigEndian, config.TrxExpire)
+	binary.Write(&buffer, binary.BigEndian, item.EffectiveHeight)
+	buffer.Write([]byte(item.GroupOwnerPubkey))
+	return Hash(buffer.Bytes())
+}
+
+// config of the block at the height, the genesis config if no change is effective
+func GetGroupConfig(dbMgr *storage.DbMgr, item *chestnutpb.GroupItem, height int64, nodename string) (*chestnutpb.GroupConfig, error) {
+	var config *chestnutpb.GroupConfig
+	if item.GenesisBlock != nil {
+		config = item.GenesisBlock.Config
+	}
+	changed, err := dbMgr.GetGroupConfig(item.GroupId, height, nodename)
+	if err != nil {
+		return nil, err
+	}
+	if changed != nil {
+		config = changed.Config
+	}
+	return WithDefaults(config), nil
+}
+
+// check the trxs of a block against the config of the block height, as packed by produceBlock
+func checkBlockConfig(block *chestnutpb.Block, config *chestnutpb.GroupConfig) error {
+	totalSizeBytes := 0
+	for _, trx := range block.Trxs {
+		encodedcontent, err := chestnutpb.ContentToBytes(trx)
+		if err != nil {
+			return err
+		}
+		totalSizeBytes += binary.Size(encodedcontent)
+	}
+	if int64(totalSizeBytes) >= config.TrxsTotalSize {
+		return fmt.Errorf("trxs size %d of block <%s> exceeds the trxs total size %d", totalSizeBytes, block.BlockId, config.TrxsTotalSize)
+	}
+	return nil
+}
+
+// apply a config trx packed in the block at blockHeight, a config can not change the blocks already produced
+func applyConfigTrx(dbMgr *storage.DbMgr, grpItem *chestnutpb.GroupItem, trx *chestnutpb.Trx, blockHeight int64, nodename string) error {
+	item := &chestnutpb.ConfigItem{}
+	if err := proto.Unmarshal(trx.Data, item); err != nil {
+		return err
+	}
+	if item.GroupId != grpItem.GroupId {
+		return errors.New("config of another group")
+	}
+	//owner trxs of an admin group are checked by the admin signatures
+	if !IsAdminGroup(grpItem) {
+		if item.GroupOwnerPubkey != grpItem.OwnerPubKey {
+			return errors.New("config is not signed by the group owner")
+		}
+		sign, err := hex.DecodeString(item.GroupOwnerSign)
+		if err != nil {
+			return err
+		}
+		if err := verifySign(grpItem.OwnerPubKey, ConfigHash(item), sign); err != nil {
+			config_log.Warningf("<%s> config <%s> rejected: %s", grpItem.GroupId, trx.TrxId, err)
+			return err
+		}
+	}
+	if item.EffectiveHeight <= blockHeight {
+		config_log.Warningf("<%s> config <%s> rejected: effective height <%d> not above block height <%d>", grpItem.GroupId, trx.TrxId, item.EffectiveHeight, blockHeight)
+		return fmt.Errorf("effective height %d must be above the block height %d", item.EffectiveHeight, blockHeight)
+	}
+	item.Config = WithDefaults(item.Config)
+	if err := CheckGroupConfig(item.Config); err != nil {
+		config_log.Warningf("<%s> config <%s> rejected: %s", grpItem.GroupId, trx.TrxId, err)
+		return err
+	}
+	config_log.Infof("<%s> config changed at height <%d>", grpItem.GroupId, item.EffectiveHeight)
+	return dbMgr.SaveGroupConfig(item, nodename)
+}
+
+// config of the next block to produce
+func (chain *Chain) GetConfig() *chestnutpb.GroupConfig {
+	height, _ := chain.group.ChainHead()
+	return chain.GetConfigAt(height + 1)
+}
+
+// config of the block at the height, cached until the height or config changes
+func (chain *Chain) GetConfigAt(height int64) *chestnutpb.GroupConfig {
+	chain.configmu.Lock()
+	defer chain.configmu.Unlock()
+	if chain.config != nil && chain.configHeight == height {
+		return chain.config
+	}
+	config, err := GetGroupConfig(nodectx.GetDbMgr(), chain.group.Item, height, chain.nodename)
+	if err != nil {
+		config_log.Warningf("<%s> get config failed: %s", chain.groupId, err.Error())
+		return WithDefaults(nil)
+	}
+	chain.config = config
+	chain.configHeight = height
+	return config
+}
+
+// drop the cached config, called after a config trx is applied
+func (chain *Chain) UpdConfig() {
+	chain.configmu.Lock()
+	defer chain.configmu.Unlock()
+	chain.config = nil
+}
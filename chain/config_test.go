@@ -0,0 +1,82 @@
+package chain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/pubsubconn"
+)
+
+// pubsub conn keeping the size of the last message published
+type sizeConn struct {
+	pubsubconn.PubSubConn
+	size int
+}
+
+func (conn *sizeConn) Publish(data []byte) error {
+	conn.size = len(data)
+	return nil
+}
+
+// a block with trxs of MAX_TRXS_TOTAL_SIZE fits in one pubsub message when produced or
+// sent to a syncing node
+func TestMaxTrxsTotalSizeFitsMessage(t *testing.T) {
+	initTestNode(t)
+	ks := newTestKeystore()
+	item := newTestGroupItem(t, ks, ks, "sizegroup")
+	item.CipherKey = hex.EncodeToString(make([]byte, 32))
+	item.CipherAlg = "aes-gcm"
+	conn := &sizeConn{}
+	trxMgr := &TrxMgr{}
+	trxMgr.Init(item, conn, ks)
+
+	for _, dataSize := range []int{64, 1024, 100 * 1024} {
+		t.Run(fmt.Sprint(dataSize), func(t *testing.T) {
+			block := &chestnutpb.Block{GroupId: item.GroupId, PrevBlockId: item.GenesisBlock.BlockId, PreviousHash: item.GenesisBlock.Hash, ProducerPubKey: item.OwnerPubKey}
+			total := 0
+			for {
+				trx, err := trxMgr.CreateTrx(chestnutpb.TrxType_POST, make([]byte, dataSize))
+				if err != nil {
+					t.Fatal(err)
+				}
+				trxBytes, err := chestnutpb.ContentToBytes(trx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if int64(total+len(trxBytes)) >= MAX_TRXS_TOTAL_SIZE {
+					break
+				}
+				total += len(trxBytes)
+				block.Trxs = append(block.Trxs, trx)
+			}
+			root, leaves, err := TrxRoot(block.Trxs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			block.TrxRoot = root
+			block.TrxHashes = leaves
+			hashBlock(t, block, false)
+			block.Signature = make([]byte, 72)
+			if err := checkBlockConfig(block, &chestnutpb.GroupConfig{TrxsTotalSize: MAX_TRXS_TOTAL_SIZE}); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := trxMgr.SendBlockProduced(block); err != nil {
+				t.Fatal(err)
+			}
+			if conn.size > pubsub.DefaultMaxMessageSize {
+				t.Fatalf("produced block message of %d bytes", conn.size)
+			}
+			req := &chestnutpb.ReqBlock{GroupId: item.GroupId, BlockId: block.PrevBlockId, UserId: item.UserSignPubkey}
+			if err := trxMgr.SendReqBlockResp(req, block, chestnutpb.ReqBlkResult_BLOCK_IN_TRX); err != nil {
+				t.Fatal(err)
+			}
+			if conn.size > pubsub.DefaultMaxMessageSize {
+				t.Fatalf("block response message of %d bytes", conn.size)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"errors"
+	"sync"
 	"time"
 
 	logging "github.com/ipfs/go-log/v2"
@@ -24,10 +25,19 @@ type Group struct {
 	// Group Item
 	Item *chestnutpb.GroupItem
 	ChainCtx *Chain
+	paused bool
+	tenant *nodectx.Tenant
+
+	// guards the fields of Item changed while the group runs: the chain head and the cipher key
+	itemmu sync.RWMutex
 }
 
-func (grp *Group) Init(item *chestnutpb.GroupItem)  {
+func (grp *Group) Init(item *chestnutpb.GroupItem) {
 	groupMgr_log.Debugf("<%s> Init called", item.GroupId)
+	grp.Item = item
+	if grp.tenant == nil {
+		grp.tenant = nodectx.GetNodeCtx().DefaultTenant()
+	}
 	grp.ChainCtx = &Chain{}
 	grp.ChainCtx.Init(grp)
 
@@ -36,10 +46,37 @@ func (grp *Group) Init(item *chestnutpb.GroupItem)  {
 	group_log.Infof("Group <%s> initialed", grp.Item.GroupId)
 }
 
+// copy of the group item, safe to read while blocks are committed
+func (grp *Group) ItemSnapshot() *chestnutpb.GroupItem {
+	grp.itemmu.RLock()
+	defer grp.itemmu.RUnlock()
+	return proto.Clone(grp.Item).(*chestnutpb.GroupItem)
+}
+
+// highest height and block id of the group
+func (grp *Group) ChainHead() (int64, string) {
+	grp.itemmu.RLock()
+	defer grp.itemmu.RUnlock()
+	return grp.Item.HighestHeight, grp.Item.HighestBlockId
+}
+
+// the tenant hosting the group
+func (grp *Group) Tenant() *nodectx.Tenant {
+	return grp.tenant
+}
+
+// group items of tenants are saved with the tenant name prefix, groups of the default tenant without prefix
+func (grp *Group) groupPrefix() []string {
+	if grp.tenant.IsDefault {
+		return nil
+	}
+	return []string{grp.tenant.Name}
+}
+
 // teardown group
 func (grp *Group) TearDown() {
 	groupMgr_log.Debugf("<%s> TearDown called", grp.Item.GroupId)
-	if grp.ChainCtx.Syncer.Status == SYNCING_BACKWARD || grp.ChainCtx.Syncer.Status == SYNCING_FORWARD {
+	if grp.ChainCtx.Syncer.GetStatus() == SYNCING_BACKWARD || grp.ChainCtx.Syncer.GetStatus() == SYNCING_FORWARD {
 		grp.ChainCtx.Syncer.stopWaitBlock()
 	}
 
@@ -69,7 +106,7 @@ func (grp *Group) CreateGrp(item *chestnutpb.GroupItem) error {
 	buffer.Write([]byte(pItem.GroupOwnerPubkey))
 	hash := Hash(buffer.Bytes())
 
-	ks := nodectx.GetNodeCtx().Keystore
+	ks := grp.tenant.Keystore
 	signature, err := ks.SignByKeyName(item.GroupId, hash)
 	if err != nil {
 		return err
@@ -89,7 +126,7 @@ func (grp *Group) CreateGrp(item *chestnutpb.GroupItem) error {
 	grp.ChainCtx.CreateConsensus()
 
 	group_log.Infof("Group <%s> created", grp.Item.GroupId)
-	return nodectx.GetDbMgr().AddGroup(grp.Item)
+	return nodectx.GetDbMgr().AddGroup(grp.Item, grp.groupPrefix()...)
 }
 
 
@@ -106,7 +143,7 @@ func (grp *Group) DelGrp() error {
 	}
 
 	group_log.Infof("Group <%s> deleted", grp.Item.GroupId)
-	return nodectx.GetDbMgr().RmGroup(grp.Item)
+	return nodectx.GetDbMgr().RmGroup(grp.Item, grp.groupPrefix()...)
 }
 
 func (grp *Group) LeaveGrp() error {
@@ -123,7 +160,7 @@ func (grp *Group) LeaveGrp() error {
 
 	group_log.Infof("Group <%s> leaved", grp.Item.GroupId)
 
-	return nodectx.GetDbMgr().RmGroup(grp.Item)
+	return nodectx.GetDbMgr().RmGroup(grp.Item, grp.groupPrefix()...)
 }
 
 
@@ -152,11 +189,11 @@ func (grp *Group) clearGroup() error {
 
 func (grp *Group) StartSync() error {
 	group_log.Debugf("<%s> StartSync called", grp.Item.GroupId)
-	if grp.ChainCtx.Syncer.Status == SYNCING_BACKWARD || grp.ChainCtx.Syncer.Status == SYNCING_FORWARD {
+	if grp.ChainCtx.Syncer.GetStatus() == SYNCING_BACKWARD || grp.ChainCtx.Syncer.GetStatus() == SYNCING_FORWARD {
 		return errors.New("Group is syncing, don't start again")
 	}
 
-	higestBId := grp.ChainCtx.group.Item.HighestBlockId
+	_, higestBId := grp.ChainHead()
 	topBlock, err := nodectx.GetDbMgr().GetBlock(higestBId, false, grp.ChainCtx.nodename)
 	if err != nil {
 		group_log.Warningf("Get top block error, blockId <%s> at <%s>, <%s>", higestBId, grp.ChainCtx.nodename, err.Error())
@@ -168,7 +205,7 @@ func (grp *Group) StartSync() error {
 
 func (grp *Group) StopSync() error {
 	group_log.Debugf("<%s> StopSync called", grp.Item.GroupId)
-	if grp.ChainCtx.Syncer.Status == SYNCING_BACKWARD || grp.ChainCtx.Syncer.Status == SYNCING_FORWARD {
+	if grp.ChainCtx.Syncer.GetStatus() == SYNCING_BACKWARD || grp.ChainCtx.Syncer.GetStatus() == SYNCING_FORWARD {
 		grp.ChainCtx.StopSync()
 	}
 
@@ -177,6 +214,38 @@ func (grp *Group) StopSync() error {
 }
 
 
+// stop syncing and producing, and leave the group channels
+func (grp *Group) Pause() error {
+	group_log.Debugf("<%s> Pause called", grp.Item.GroupId)
+	if grp.paused {
+		return errors.New("Group is paused")
+	}
+	grp.StopSync()
+	grp.ChainCtx.StopProducer()
+	if err := grp.ChainCtx.LeaveChannels(); err != nil {
+		return err
+	}
+	grp.paused = true
+	group_log.Infof("Group <%s> paused", grp.Item.GroupId)
+	return nil
+}
+
+// join the group channels again, recreate the producer and start syncing
+func (grp *Group) Resume() error {
+	group_log.Debugf("<%s> Resume called", grp.Item.GroupId)
+	if !grp.paused {
+		return errors.New("Group is not paused")
+	}
+	if err := grp.ChainCtx.JoinChannels(); err != nil {
+		return err
+	}
+	grp.ChainCtx.UpdProducerList()
+	grp.ChainCtx.CreateConsensus()
+	grp.paused = false
+	group_log.Infof("Group <%s> resumed", grp.Item.GroupId)
+	return grp.StartSync()
+}
+
 func (grp *Group) GetGroupCtn(filter string) ([]*chestnutpb.PostItem, error) {
 	group_log.Debugf("<%s> GetGroupCtn called", grp.Item.GroupId)
 	return nodectx.GetDbMgr().GetGrpCtnt(grp.Item.GroupId, filter, grp.ChainCtx.nodename)
@@ -187,11 +256,48 @@ func (grp *Group) GetBlock(blockId string) (*chestnutpb.Block, error) {
 	return nodectx.GetDbMgr().GetBlock(blockId, false, grp.ChainCtx.nodename)
 }
 
+func (grp *Group) GetBlockByHash(hash string) (*chestnutpb.Block, error) {
+	group_log.Debugf("<%s> GetBlockByHash called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetBlockByHash(grp.Item.GroupId, hash, grp.ChainCtx.nodename)
+}
+
 func (grp *Group) GetTrx(trxId string) (*chestnutpb.Trx, error) {
 	group_log.Debugf("<%s> GetTrx called", grp.Item.GroupId)
 	return nodectx.GetDbMgr().GetTrx(trxId, grp.ChainCtx.nodename)
 }
 
+func (grp *Group) GetBlocksByHeight(height int64) ([]*chestnutpb.Block, error) {
+	group_log.Debugf("<%s> GetBlocksByHeight called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetBlocksByHeight(grp.Item.GroupId, height, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) GetTrxs(sender string, num int, reverse bool) ([]*chestnutpb.Trx, error) {
+	group_log.Debugf("<%s> GetTrxs called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetTrxsByGroup(grp.Item.GroupId, sender, num, reverse, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) GetRetention() (*chestnutpb.RetentionItem, error) {
+	group_log.Debugf("<%s> GetRetention called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetRetention(grp.Item.GroupId, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) UpdRetention(item *chestnutpb.RetentionItem) error {
+	group_log.Debugf("<%s> UpdRetention called", grp.Item.GroupId)
+	item.GroupId = grp.Item.GroupId
+	return nodectx.GetDbMgr().UpdRetention(item, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) GetDemotionPolicy() (*chestnutpb.DemotionPolicyItem, error) {
+	group_log.Debugf("<%s> GetDemotionPolicy called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetDemotionPolicy(grp.Item.GroupId, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) UpdDemotionPolicy(item *chestnutpb.DemotionPolicyItem) error {
+	group_log.Debugf("<%s> UpdDemotionPolicy called", grp.Item.GroupId)
+	item.GroupId = grp.Item.GroupId
+	return nodectx.GetDbMgr().UpdDemotionPolicy(item, grp.ChainCtx.nodename)
+}
+
 func (grp *Group) GetBlockedUser() ([]*chestnutpb.DenyUserItem, error) {
 	group_log.Debugf("<%s> GetBlockedUser called", grp.Item.GroupId)
 	return nodectx.GetDbMgr().GetBlkedUsers(grp.ChainCtx.nodename)
@@ -237,6 +343,74 @@ func (grp *Group) UpdSchema(item *chestnutpb.SchemaItem) (string, error) {
 	return grp.ChainCtx.Consensus.User().UpdSchema(item)
 }
 
+func (grp *Group) SubmitAdminAction(action *chestnutpb.AdminAction) (string, error) {
+	group_log.Debugf("<%s> SubmitAdminAction called", grp.Item.GroupId)
+	if !IsAdminGroup(grp.Item) {
+		return "", errors.New("group is not owned by an admin set")
+	}
+	if err := VerifyAdminSigns(grp.Item.Admins, action); err != nil {
+		return "", err
+	}
+	return grp.ChainCtx.Consensus.User().SubmitAdminAction(action)
+}
+
+func (grp *Group) UpdInvite(item *chestnutpb.InviteItem) (string, error) {
+	group_log.Debugf("<%s> UpdInvite called", grp.Item.GroupId)
+	return grp.ChainCtx.Consensus.User().UpdInvite(item)
+}
+
+func (grp *Group) RedeemInvite(item *chestnutpb.RedeemItem) (string, error) {
+	group_log.Debugf("<%s> RedeemInvite called", grp.Item.GroupId)
+	return grp.ChainCtx.Consensus.User().RedeemInvite(item)
+}
+
+// invitations on chain with the redemptions applied by the node
+func (grp *Group) GetInvites() ([]*chestnutpb.InviteItem, error) {
+	group_log.Debugf("<%s> GetInvites called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetInvites(grp.Item.GroupId, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) GetInvite(inviteId string) (*chestnutpb.InviteItem, error) {
+	group_log.Debugf("<%s> GetInvite called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetInvite(grp.Item.GroupId, inviteId, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) GetConfig() *chestnutpb.GroupConfig {
+	group_log.Debugf("<%s> GetConfig called", grp.Item.GroupId)
+	return grp.ChainCtx.GetConfig()
+}
+
+func (grp *Group) GetConfigChanges() ([]*chestnutpb.ConfigItem, error) {
+	group_log.Debugf("<%s> GetConfigChanges called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetGroupConfigs(grp.Item.GroupId, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) UpdConfig(item *chestnutpb.ConfigItem) (string, error) {
+	group_log.Debugf("<%s> UpdConfig called", grp.Item.GroupId)
+	return grp.ChainCtx.Consensus.User().UpdConfig(item)
+}
+
+// pending admin actions are kept by the node until they are submitted
+func (grp *Group) SaveAdminAction(action *chestnutpb.AdminAction) error {
+	group_log.Debugf("<%s> SaveAdminAction called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().SaveAdminAction(action, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) GetAdminAction(actionId string) (*chestnutpb.AdminAction, error) {
+	group_log.Debugf("<%s> GetAdminAction called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetAdminAction(grp.Item.GroupId, actionId, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) GetAdminActions() ([]*chestnutpb.AdminAction, error) {
+	group_log.Debugf("<%s> GetAdminActions called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetAdminActions(grp.Item.GroupId, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) RmAdminAction(actionId string) error {
+	group_log.Debugf("<%s> RmAdminAction called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().RmAdminAction(grp.Item.GroupId, actionId, grp.ChainCtx.nodename)
+}
+
 func (grp *Group) IsProducerAnnounced(producerSignPubkey string) (bool, error) {
 	group_log.Debugf("<%s> IsProducerAnnounced called", grp.Item.GroupId)
 	return nodectx.GetDbMgr().IsProducerAnnounced(grp.Item.GroupId, producerSignPubkey, grp.ChainCtx.nodename)
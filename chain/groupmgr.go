@@ -3,82 +3,520 @@ package chain
 
 import (
 	"fmt"
+	"strings"
+	"sync"
 
-	"github.com/golang/protobuf/proto"
 	logging "github.com/ipfs/go-log/v2"
+	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
 )
+
+type GroupState int
+
+const (
+	GROUP_INITIALIZING GroupState = iota
+	GROUP_SYNCING
+	GROUP_READY
+	GROUP_STOPPED
+	GROUP_PAUSED
+	GROUP_SYNC_FAILED
+)
+
+func (s GroupState) String() string {
+	switch s {
+	case GROUP_INITIALIZING:
+		return "INITIALIZING"
+	case GROUP_SYNCING:
+		return "SYNCING"
+	case GROUP_READY:
+		return "READY"
+	case GROUP_STOPPED:
+		return "STOPPED"
+	case GROUP_PAUSED:
+		return "PAUSED"
+	case GROUP_SYNC_FAILED:
+		return "SYNC_FAILED"
+	}
+	return "UNKNOWN"
+}
+
+// group is nil while initializing
+type groupEntry struct {
+	group *Group
+	state GroupState
+}
+
+// GroupMgr owns the lifecycle of all groups, groups must be accessed by the methods of GroupMgr
 type GroupMgr struct {
-	dbMgr *storage.DbMgr
-	Groups map[string]*Group
+	dbMgr  *storage.DbMgr
+	tenant *nodectx.Tenant
+
+	mu     sync.RWMutex
+	groups map[string]*groupEntry
+
+	// serialize pause and resume
+	pausemu sync.Mutex
+
+	listenermu     sync.RWMutex
+	blockListeners []BlockListener
+	pruneListeners []PruneListener
 }
 
+// called after a new highest block of a group is committed
+type BlockListener func(groupId string, blockId string, height int64)
+
+// called after the data of POST trxs of a group is pruned
+type PruneListener func(groupId string, trxIds []string)
+
 var groupMgr *GroupMgr
+var groupMgrOnce sync.Once
 var groupMgr_log = logging.Logger("groupmgr")
 
+// group managers of the tenants hosted by the node, except the default tenant
+var (
+	tenantGroupMgrs = make(map[string]*GroupMgr)
+	tenantmgrmu     sync.RWMutex
+)
+
+// serialize reserving group ids across the group managers of all tenants
+var reservemu sync.Mutex
+
+// the GroupMgr of the default tenant
 func GetGroupMgr() *GroupMgr {
-	return groupMgr 
+	return groupMgr
 }
 
-// TODO:singlaton
+// init the GroupMgr singleton of the default tenant, later calls return the same GroupMgr
 func InitGroupMgr(dbMgr *storage.DbMgr) *GroupMgr {
-	groupMgr_log.Debug("InitGroupMgr called")
-	groupMgr = &GroupMgr{dbMgr: dbMgr}
-	groupMgr.Groups = make(map[string]*Group)
+	groupMgrOnce.Do(func() {
+		groupMgr_log.Debug("InitGroupMgr called")
+		groupMgr = &GroupMgr{dbMgr: dbMgr, tenant: nodectx.GetNodeCtx().DefaultTenant()}
+		groupMgr.groups = make(map[string]*groupEntry)
+	})
 	return groupMgr
 }
 
+// init the GroupMgr of a tenant, groups and data of the tenant are saved with the tenant name prefix
+func InitTenantGroupMgr(dbMgr *storage.DbMgr, tenant *nodectx.Tenant) (*GroupMgr, error) {
+	groupMgr_log.Debugf("<%s> InitTenantGroupMgr called", tenant.Name)
+	tenantmgrmu.Lock()
+	defer tenantmgrmu.Unlock()
+	if _, ok := tenantGroupMgrs[tenant.Name]; ok {
+		return nil, fmt.Errorf("group manager of tenant %s already exist", tenant.Name)
+	}
+	groupmgr := &GroupMgr{dbMgr: dbMgr, tenant: tenant}
+	groupmgr.groups = make(map[string]*groupEntry)
+	tenantGroupMgrs[tenant.Name] = groupmgr
+	return groupmgr, nil
+}
+
+// the GroupMgr of a tenant, the default tenant if name is empty or the node name
+func GetTenantGroupMgr(name string) (*GroupMgr, bool) {
+	if groupMgr != nil && (name == "" || name == groupMgr.tenant.Name) {
+		return groupMgr, true
+	}
+	tenantmgrmu.RLock()
+	defer tenantmgrmu.RUnlock()
+	groupmgr, ok := tenantGroupMgrs[name]
+	return groupmgr, ok
+}
+
+// group managers of all tenants, the default tenant first
+func ListGroupMgrs() []*GroupMgr {
+	groupmgrs := []*GroupMgr{}
+	if groupMgr != nil {
+		groupmgrs = append(groupmgrs, groupMgr)
+	}
+	tenantmgrmu.RLock()
+	defer tenantmgrmu.RUnlock()
+	for _, groupmgr := range tenantGroupMgrs {
+		groupmgrs = append(groupmgrs, groupmgr)
+	}
+	return groupmgrs
+}
+
+// find the group in the group managers of all tenants
+func FindGroup(groupId string) (*Group, *GroupMgr, bool) {
+	for _, groupmgr := range ListGroupMgrs() {
+		if group, ok := groupmgr.Get(groupId); ok {
+			return group, groupmgr, true
+		}
+	}
+	return nil, nil, false
+}
+
+func (groupmgr *GroupMgr) Tenant() *nodectx.Tenant {
+	return groupmgr.tenant
+}
+
+// group items of tenants are saved with the tenant name prefix, groups of the default tenant without prefix
+func (groupmgr *GroupMgr) groupPrefix() []string {
+	if groupmgr.tenant.IsDefault {
+		return nil
+	}
+	return []string{groupmgr.tenant.Name}
+}
+
+func (groupmgr *GroupMgr) has(groupId string) bool {
+	groupmgr.mu.RLock()
+	defer groupmgr.mu.RUnlock()
+	_, ok := groupmgr.groups[groupId]
+	return ok
+}
+
+// Reserve groupId for a group being initialized. The group channels are joined by
+// the node, so a group can be hosted by only one tenant of the node.
+func (groupmgr *GroupMgr) reserve(groupId string) error {
+	if groupId == "" || strings.Contains(groupId, "_") {
+		return fmt.Errorf("invalid group id: %s", groupId)
+	}
+	reservemu.Lock()
+	defer reservemu.Unlock()
+	for _, other := range ListGroupMgrs() {
+		if other != groupmgr && other.has(groupId) {
+			return fmt.Errorf("group %s is hosted by tenant %s of this node", groupId, other.tenant.Name)
+		}
+	}
+
+	groupmgr.mu.Lock()
+	defer groupmgr.mu.Unlock()
+	if _, ok := groupmgr.groups[groupId]; ok {
+		return fmt.Errorf("group already exist: %s", groupId)
+	}
+	groupmgr.groups[groupId] = &groupEntry{state: GROUP_INITIALIZING}
+	return nil
+}
+
+func (groupmgr *GroupMgr) unreserve(groupId string) {
+	groupmgr.mu.Lock()
+	defer groupmgr.mu.Unlock()
+	if entry, ok := groupmgr.groups[groupId]; ok && entry.group == nil {
+		delete(groupmgr.groups, groupId)
+	}
+}
+
+func (groupmgr *GroupMgr) setGroup(group *Group, state GroupState) {
+	groupmgr.mu.Lock()
+	defer groupmgr.mu.Unlock()
+	groupmgr.groups[group.Item.GroupId] = &groupEntry{group: group, state: state}
+}
+
+func (groupmgr *GroupMgr) setState(groupId string, state GroupState) {
+	groupmgr.mu.Lock()
+	defer groupmgr.mu.Unlock()
+	if entry, ok := groupmgr.groups[groupId]; ok {
+		entry.state = state
+	}
+}
+
+// remove a group after it is left or deleted
+func (groupmgr *GroupMgr) remove(groupId string) {
+	groupmgr.mu.Lock()
+	defer groupmgr.mu.Unlock()
+	delete(groupmgr.groups, groupId)
+}
 
-// load and group add start syncing
-func (groupmgr *GroupMgr) SyncAllGroup() error {
-	groupMgr_log.Debug("SyncAllGroup called")
+// Load groups saved and start syncing, all groups are loaded if groupIds is empty.
+// Paused groups are loaded without joining the group channels.
+func (groupmgr *GroupMgr) Start(groupIds ...string) error {
+	groupMgr_log.Debug("Start called")
 
-	// open all groups
-	groupItemsBytes, err := groupmgr.dbMgr.GetGroupsBytes()
+	groupItemsBytes, err := groupmgr.dbMgr.GetGroupsBytes(groupmgr.groupPrefix()...)
 	if err != nil {
 		return err
 	}
 
-	for _, b := range groupItemsBytes {
-		var group *Group
-		group = &Group{}
+	only := make(map[string]bool)
+	for _, groupId := range groupIds {
+		only[groupId] = true
+	}
 
-		var item *chestnutpb.GroupItem
-		item = &chestnutpb.GroupItem{}
+	for _, b := range groupItemsBytes {
+		item := &chestnutpb.GroupItem{}
+		if err := proto.Unmarshal(b, item); err != nil {
+			groupMgr_log.Errorf("can't load group: %s", err)
+			continue
+		}
+		if len(only) > 0 && !only[item.GroupId] {
+			groupMgr_log.Debugf("<%s> not in the groups to load, skip", item.GroupId)
+			continue
+		}
+		paused, err := groupmgr.dbMgr.IsGroupPaused(item.GroupId, groupmgr.tenant.Name)
+		if err != nil {
+			return err
+		}
+		if err := groupmgr.reserve(item.GroupId); err != nil {
+			groupMgr_log.Warningf("<%s> %s", item.GroupId, err)
+			continue
+		}
 
-		proto.Unmarshal(b, item)
+		group := &Group{paused: paused, tenant: groupmgr.tenant}
 		group.Init(item)
-		if err == nil {
-			groupMgr_log.Debugf("Start sync group: %s", item.GroupId)
-			go group.StopSync()
-			groupmgr.Groups[item.GroupId] = group
-		} else {
-			groupMgr_log.Fatalf("can't sync group: %s", item.GroupId)
-			groupMgr_log.Fatalf(err.Error())
+		if paused {
+			groupMgr_log.Infof("<%s> group paused, don't sync", item.GroupId)
+			groupmgr.setGroup(group, GROUP_PAUSED)
+			continue
 		}
+		groupmgr.setGroup(group, GROUP_SYNCING)
+
+		groupMgr_log.Debugf("<%s> start sync group", item.GroupId)
+		go func(group *Group) {
+			if err := group.StartSync(); err != nil {
+				groupMgr_log.Warningf("<%s> start sync failed: %s", group.Item.GroupId, err)
+			}
+		}(group)
 	}
 	return nil
 }
 
-func (groupmgr *GroupMgr) StopSyncAllGroup() error {
-	groupMgr_log.Debug("StopSyncAllGroup called")
-	return nil
+// Stop syncing and teardown all groups
+func (groupmgr *GroupMgr) Stop() {
+	groupMgr_log.Debug("Stop called")
+	for _, group := range groupmgr.List() {
+		groupMgr_log.Debugf("<%s> teardown", group.Item.GroupId)
+		group.TearDown()
+		groupmgr.setState(group.Item.GroupId, GROUP_STOPPED)
+	}
 }
 
-func (groupmgr *GroupMgr) Release()  {
+func (groupmgr *GroupMgr) Release() {
 	groupMgr_log.Debug("Release called")
-	for groupId, group := range groupMgr.Groups {
-		groupMgr_log.Debugf("group: <%s> teardown", groupId)
-		group.TearDown()
-	}
+	groupmgr.Stop()
 	// close ctx db
 	groupmgr.dbMgr.CloseDb()
 }
 
+// Create a group owned by this node
+func (groupmgr *GroupMgr) Create(item *chestnutpb.GroupItem) (*Group, error) {
+	groupMgr_log.Debugf("<%s> Create called", item.GroupId)
+	if err := groupmgr.reserve(item.GroupId); err != nil {
+		return nil, err
+	}
+
+	group := &Group{tenant: groupmgr.tenant}
+	if err := group.CreateGrp(item); err != nil {
+		groupmgr.unreserve(item.GroupId)
+		return nil, err
+	}
+	groupmgr.setGroup(group, GROUP_READY)
+	return group, nil
+}
+
+// Join a group created by others and start syncing
+func (groupmgr *GroupMgr) Join(item *chestnutpb.GroupItem) (*Group, error) {
+	groupMgr_log.Debugf("<%s> Join called", item.GroupId)
+	if err := groupmgr.reserve(item.GroupId); err != nil {
+		return nil, err
+	}
+
+	group := &Group{tenant: groupmgr.tenant}
+	if err := group.CreateGrp(item); err != nil {
+		groupmgr.unreserve(item.GroupId)
+		return nil, err
+	}
+	groupmgr.setGroup(group, GROUP_SYNCING)
+
+	//the group is saved, keep it registered in SYNC_FAILED state to start syncing again
+	if err := group.StartSync(); err != nil {
+		groupmgr.setState(item.GroupId, GROUP_SYNC_FAILED)
+		return group, err
+	}
+	return group, nil
+}
+
+// start syncing a group again, a group in SYNC_FAILED state is syncing once started
+func (groupmgr *GroupMgr) StartSync(groupId string) error {
+	groupMgr_log.Debugf("<%s> StartSync called", groupId)
+	group, ok := groupmgr.Get(groupId)
+	if !ok {
+		return fmt.Errorf("group not exist: %s", groupId)
+	}
+	if err := group.StartSync(); err != nil {
+		return err
+	}
+	groupmgr.setState(groupId, GROUP_SYNCING)
+	return nil
+}
+
+// Leave a group created by others
+func (groupmgr *GroupMgr) Leave(groupId string) error {
+	groupMgr_log.Debugf("<%s> Leave called", groupId)
+	group, ok := groupmgr.Get(groupId)
+	if !ok {
+		return fmt.Errorf("group not exist: %s", groupId)
+	}
+	if err := group.LeaveGrp(); err != nil {
+		return err
+	}
+	groupmgr.teardown(group)
+	return nil
+}
+
+// Delete a group owned by this node
+func (groupmgr *GroupMgr) Delete(groupId string) error {
+	groupMgr_log.Debugf("<%s> Delete called", groupId)
+	group, ok := groupmgr.Get(groupId)
+	if !ok {
+		return fmt.Errorf("group not exist: %s", groupId)
+	}
+	if err := group.DelGrp(); err != nil {
+		return err
+	}
+	groupmgr.teardown(group)
+	return nil
+}
+
+// teardown and remove a group left or deleted
+func (groupmgr *GroupMgr) teardown(group *Group) {
+	groupId := group.Item.GroupId
+	groupmgr.pausemu.Lock()
+	defer groupmgr.pausemu.Unlock()
+	group.TearDown()
+	if !group.paused {
+		group.ChainCtx.StopProducer()
+		if err := group.ChainCtx.LeaveChannels(); err != nil {
+			groupMgr_log.Warningf("<%s> leave channels failed: %s", groupId, err)
+		}
+	}
+	if err := groupmgr.dbMgr.SetGroupPaused(groupId, false, groupmgr.tenant.Name); err != nil {
+		groupMgr_log.Warningf("<%s> clear paused state failed: %s", groupId, err)
+	}
+	groupmgr.remove(groupId)
+}
+
+// Pause a group: stop syncing and producing, leave the group channels and persist the paused state
+func (groupmgr *GroupMgr) Pause(groupId string) error {
+	groupMgr_log.Debugf("<%s> Pause called", groupId)
+	groupmgr.pausemu.Lock()
+	defer groupmgr.pausemu.Unlock()
+
+	group, ok := groupmgr.Get(groupId)
+	if !ok {
+		return fmt.Errorf("group not exist: %s", groupId)
+	}
+	if err := group.Pause(); err != nil {
+		return err
+	}
+	groupmgr.setState(groupId, GROUP_PAUSED)
+	return groupmgr.dbMgr.SetGroupPaused(groupId, true, groupmgr.tenant.Name)
+}
+
+// Resume a paused group: join the group channels and start syncing
+func (groupmgr *GroupMgr) Resume(groupId string) error {
+	groupMgr_log.Debugf("<%s> Resume called", groupId)
+	groupmgr.pausemu.Lock()
+	defer groupmgr.pausemu.Unlock()
+
+	group, ok := groupmgr.Get(groupId)
+	if !ok {
+		return fmt.Errorf("group not exist: %s", groupId)
+	}
+	err := group.Resume()
+	if group.paused {
+		return err
+	}
+	groupmgr.setState(groupId, GROUP_SYNCING)
+	if dberr := groupmgr.dbMgr.SetGroupPaused(groupId, false, groupmgr.tenant.Name); dberr != nil {
+		return dberr
+	}
+	return err
+}
+
+// get a group, groups being initialized are not returned
+func (groupmgr *GroupMgr) Get(groupId string) (*Group, bool) {
+	groupmgr.mu.RLock()
+	defer groupmgr.mu.RUnlock()
+	entry, ok := groupmgr.groups[groupId]
+	if !ok || entry.group == nil {
+		return nil, false
+	}
+	return entry.group, true
+}
+
+func (groupmgr *GroupMgr) List() []*Group {
+	groupmgr.mu.RLock()
+	defer groupmgr.mu.RUnlock()
+	groups := []*Group{}
+	for _, entry := range groupmgr.groups {
+		if entry.group != nil {
+			groups = append(groups, entry.group)
+		}
+	}
+	return groups
+}
+
+// state of a group, a running group is syncing until the syncer is idle
+func (groupmgr *GroupMgr) GetState(groupId string) (GroupState, bool) {
+	groupmgr.mu.RLock()
+	entry, ok := groupmgr.groups[groupId]
+	var state GroupState
+	var group *Group
+	if ok {
+		state, group = entry.state, entry.group
+	}
+	groupmgr.mu.RUnlock()
+	if !ok {
+		return GROUP_STOPPED, false
+	}
+
+	if group == nil || state == GROUP_STOPPED || state == GROUP_PAUSED {
+		return state, true
+	}
+	if syncer := group.ChainCtx.Syncer; syncer != nil {
+		if status := syncer.GetStatus(); status == SYNCING_FORWARD || status == SYNCING_BACKWARD {
+			return GROUP_SYNCING, true
+		}
+	}
+	if state == GROUP_SYNC_FAILED {
+		return state, true
+	}
+	return GROUP_READY, true
+}
+
+// copies of the group items
+func (groupmgr *GroupMgr) GetGroupItems() []*chestnutpb.GroupItem {
+	var items []*chestnutpb.GroupItem
+	for _, grp := range groupmgr.List() {
+		items = append(items, grp.ItemSnapshot())
+	}
+	return items
+}
+
 func (groupmgr *GroupMgr) GetGroupItem(groupId string) (*chestnutpb.GroupItem, error) {
-	if grp, ok := groupmgr.Groups[groupId]; ok {
-		return grp.Item, nil
+	if grp, ok := groupmgr.Get(groupId); ok {
+		return grp.ItemSnapshot(), nil
 	}
 	return nil, fmt.Errorf("group not exist: %s", groupId)
-}
\ No newline at end of file
+}
+
+func (groupmgr *GroupMgr) AddBlockListener(fn BlockListener) {
+	groupmgr.listenermu.Lock()
+	defer groupmgr.listenermu.Unlock()
+	groupmgr.blockListeners = append(groupmgr.blockListeners, fn)
+}
+
+// listeners are called in the goroutine accepting the block and must not block
+func (groupmgr *GroupMgr) notifyBlockAccepted(groupId string, blockId string, height int64) {
+	groupmgr.listenermu.RLock()
+	defer groupmgr.listenermu.RUnlock()
+	for _, fn := range groupmgr.blockListeners {
+		fn(groupId, blockId, height)
+	}
+}
+
+func (groupmgr *GroupMgr) AddPruneListener(fn PruneListener) {
+	groupmgr.listenermu.Lock()
+	defer groupmgr.listenermu.Unlock()
+	groupmgr.pruneListeners = append(groupmgr.pruneListeners, fn)
+}
+
+// listeners are called in the pruner goroutine
+func (groupmgr *GroupMgr) notifyPruned(groupId string, trxIds []string) {
+	groupmgr.listenermu.RLock()
+	defer groupmgr.listenermu.RUnlock()
+	for _, fn := range groupmgr.pruneListeners {
+		fn(groupId, trxIds)
+	}
+}
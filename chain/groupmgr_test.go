@@ -0,0 +1,279 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/libp2p/go-libp2p"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/nodectx"
+	"github.com/lixvyang/chestnut/p2p"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+// keystore with the sign keys in memory, keys are created when first used
+type testKeystore struct {
+	localcrypto.Keystore
+	mu   sync.Mutex
+	keys map[string]p2pcrypto.PrivKey
+}
+
+func (ks *testKeystore) key(t *testing.T, keyname string) p2pcrypto.PrivKey {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if key, ok := ks.keys[keyname]; ok {
+		return key
+	}
+	key, _, err := p2pcrypto.GenerateSecp256k1Key(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks.keys[keyname] = key
+	return key
+}
+
+func (ks *testKeystore) SignByKeyName(keyname string, data []byte, opts ...string) ([]byte, error) {
+	ks.mu.Lock()
+	key, ok := ks.keys[keyname]
+	ks.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("key not exist :%s", keyname)
+	}
+	return key.Sign(data)
+}
+
+var (
+	testNodeOnce sync.Once
+	testTenants  int
+)
+
+// node context with a local pubsub, the chain data is saved in memory
+func initTestNode(t *testing.T) {
+	testNodeOnce.Do(func() {
+		ctx := context.Background()
+		host, err := libp2p.New(libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ps, err := pubsub.NewGossipSub(ctx, host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		db := &storage.CSMemory{}
+		if err := db.Init(""); err != nil {
+			t.Fatal(err)
+		}
+		node := &p2p.Node{PeerID: host.ID(), Host: host, Pubsub: ps}
+		nodectx.InitCtx(ctx, "default", node, &storage.DbMgr{GroupInfoDb: db, Db: db}, "pubsub", "")
+	})
+}
+
+func newTestKeystore() *testKeystore {
+	return &testKeystore{keys: make(map[string]p2pcrypto.PrivKey)}
+}
+
+// group manager of a new tenant
+func newTestGroupMgr(t *testing.T) (*GroupMgr, *testKeystore) {
+	initTestNode(t)
+	ks := newTestKeystore()
+	testTenants++
+	name := fmt.Sprintf("tenant%d", testTenants)
+	groupmgr, err := InitTenantGroupMgr(nodectx.GetDbMgr(), &nodectx.Tenant{Name: name, Keystore: ks})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return groupmgr, ks
+}
+
+// item of a group created by the owner keystore, the node signs with its key of the group id
+func newTestGroupItem(t *testing.T, ks *testKeystore, owner *testKeystore, groupId string) *chestnutpb.GroupItem {
+	encodedPubkey := func(ks *testKeystore) string {
+		pubkey, err := p2pcrypto.MarshalPublicKey(ks.key(t, groupId).GetPublic())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p2pcrypto.ConfigEncodeKey(pubkey)
+	}
+	userPubkey := encodedPubkey(ks)
+	ownerPubkey := encodedPubkey(owner)
+
+	genesis, err := CreateGeneisBlock(groupId, owner.key(t, groupId).GetPublic(), nil, WithDefaults(nil), false, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &chestnutpb.GroupItem{
+		GroupId:        groupId,
+		GroupName:      groupId,
+		OwnerPubKey:    ownerPubkey,
+		UserSignPubkey: userPubkey,
+		CipherKey:      "cipherkey",
+		HighestBlockId: genesis.BlockId,
+		GenesisBlock:   genesis,
+	}
+}
+
+// chain heads of groups created by the node are committed while the groups are read and other
+// groups are created, deleted, joined and left, run with -race
+func TestGroupMgrConcurrentGroups(t *testing.T) {
+	const groupCount = 4
+	const blockCount = 50
+	const joinCount = 10
+	groupmgr, ks := newTestGroupMgr(t)
+
+	var groups []*Group
+	for i := 0; i < groupCount; i++ {
+		groupId := fmt.Sprintf("%s-group%d", groupmgr.Tenant().Name, i)
+		group, err := groupmgr.Create(newTestGroupItem(t, ks, ks, groupId))
+		if err != nil {
+			t.Fatal(err)
+		}
+		groups = append(groups, group)
+	}
+	owner := newTestKeystore()
+	var joinItems, createItems []*chestnutpb.GroupItem
+	for i := 0; i < joinCount; i++ {
+		joinItems = append(joinItems, newTestGroupItem(t, ks, owner, fmt.Sprintf("%s-joined%d", groupmgr.Tenant().Name, i)))
+		createItems = append(createItems, newTestGroupItem(t, ks, ks, fmt.Sprintf("%s-created%d", groupmgr.Tenant().Name, i)))
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, groupCount*2+3)
+	for _, group := range groups {
+		wg.Add(1)
+		go func(group *Group) {
+			defer wg.Done()
+			for height := int64(1); height <= blockCount; height++ {
+				txn, err := groupmgr.dbMgr.BeginTxn()
+				if err != nil {
+					errs <- err
+					return
+				}
+				blockId := fmt.Sprintf("%s_%d", group.Item.GroupId, height)
+				if err := group.ChainCtx.UpdChainInfo(height, blockId, txn); err != nil {
+					txn.Rollback()
+					errs <- err
+					return
+				}
+				if err := txn.Commit(); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}(group)
+
+		wg.Add(1)
+		go func(group *Group) {
+			defer wg.Done()
+			for i := 0; i < blockCount; i++ {
+				item := group.ItemSnapshot()
+				if item.HighestHeight > 0 && item.HighestBlockId != fmt.Sprintf("%s_%d", item.GroupId, item.HighestHeight) {
+					errs <- fmt.Errorf("snapshot %d %s mismatch", item.HighestHeight, item.HighestBlockId)
+					return
+				}
+			}
+		}(group)
+	}
+
+	// the states are read until the groups joined and created are removed
+	done := make(chan struct{})
+	var readers sync.WaitGroup
+	readers.Add(1)
+	go func() {
+		defer readers.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for _, item := range groupmgr.GetGroupItems() {
+				groupmgr.GetState(item.GroupId)
+				if _, err := proto.Marshal(item); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}
+	}()
+
+	// groups joined and left while the others commit blocks, a joined group is syncing
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, item := range joinItems {
+			if _, err := groupmgr.Join(item); err != nil {
+				errs <- err
+				return
+			}
+			if state, ok := groupmgr.GetState(item.GroupId); !ok || state != GROUP_SYNCING {
+				errs <- fmt.Errorf("joined group %s state %s, want %s", item.GroupId, state, GROUP_SYNCING)
+				return
+			}
+			if err := groupmgr.Leave(item.GroupId); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for _, item := range createItems {
+			if _, err := groupmgr.Create(item); err != nil {
+				errs <- err
+				return
+			}
+			if state, ok := groupmgr.GetState(item.GroupId); !ok || state != GROUP_READY {
+				errs <- fmt.Errorf("created group %s state %s, want %s", item.GroupId, state, GROUP_READY)
+				return
+			}
+			if err := groupmgr.Delete(item.GroupId); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+	close(done)
+	readers.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if n := len(groupmgr.List()); n != groupCount {
+		t.Fatalf("%d groups registered, want %d", n, groupCount)
+	}
+	saved, err := groupmgr.dbMgr.GetGroupsBytes(groupmgr.Tenant().Name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(saved) != groupCount {
+		t.Fatalf("%d groups saved, want %d", len(saved), groupCount)
+	}
+	for _, group := range groups {
+		height, blockId := group.ChainHead()
+		if height != blockCount || blockId != fmt.Sprintf("%s_%d", group.Item.GroupId, blockCount) {
+			t.Errorf("group %s head %d %s", group.Item.GroupId, height, blockId)
+		}
+		value, err := groupmgr.dbMgr.GetGroupBytes(group.Item.GroupId, groupmgr.Tenant().Name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		item := &chestnutpb.GroupItem{}
+		if err := proto.Unmarshal(value, item); err != nil {
+			t.Fatal(err)
+		}
+		if item.HighestHeight != blockCount {
+			t.Errorf("group %s saved at height %d", group.Item.GroupId, item.HighestHeight)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	logging "github.com/ipfs/go-log/v2"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+var invite_log = logging.Logger("invite")
+
+// verify a signature of an encoded pubkey
+func verifySign(encodedPubkey string, hash, sign []byte) error {
+	serializedpub, err := p2pcrypto.ConfigDecodeKey(encodedPubkey)
+	if err != nil {
+		return err
+	}
+	pubkey, err := p2pcrypto.UnmarshalPublicKey(serializedpub)
+	if err != nil {
+		return err
+	}
+	ok, err := pubkey.Verify(hash, sign)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid signature of %s", encodedPubkey)
+	}
+	return nil
+}
+
+// hash signed by the owner, the redemption count and revoked state are kept by each node
+func InviteHash(item *chestnutpb.InviteItem) []byte {
+	var buffer bytes.Buffer
+	buffer.Write([]byte(item.InviteId))
+	buffer.Write([]byte(item.GroupId))
+	binary.Write(&buffer, binary.BigEndian, item.Expire)
+	binary.Write(&buffer, binary.BigEndian, item.MaxUse)
+	buffer.Write([]byte(item.InviteePubkey))
+	buffer.Write([]byte(item.OwnerPubkey))
+	buffer.Write([]byte(item.Action.String()))
+	return Hash(buffer.Bytes())
+}
+
+// hash signed by the invitee key of a bound invitation
+func RedeemHash(item *chestnutpb.RedeemItem) []byte {
+	var buffer bytes.Buffer
+	buffer.Write([]byte(item.InviteId))
+	buffer.Write([]byte(item.GroupId))
+	buffer.Write([]byte(item.RedeemerPubkey))
+	return Hash(buffer.Bytes())
+}
+
+func VerifyInvite(item *chestnutpb.InviteItem, ownerPubkey string) error {
+	if item.OwnerPubkey != ownerPubkey {
+		return errors.New("invitation is not signed by the group owner")
+	}
+	sign, err := hex.DecodeString(item.OwnerSign)
+	if err != nil {
+		return err
+	}
+	return verifySign(ownerPubkey, InviteHash(item), sign)
+}
+
+// check the invitation can be redeemed at the time, timestamp is the redeem trx time on chain
+func CheckInvite(item *chestnutpb.InviteItem, timestamp int64) error {
+	if item.Revoked {
+		return errors.New("invitation is revoked")
+	}
+	if item.Expire > 0 && timestamp > item.Expire {
+		return errors.New("invitation is expired")
+	}
+	if item.MaxUse > 0 && item.Used >= item.MaxUse {
+		return errors.New("invitation is used up")
+	}
+	return nil
+}
+
+// private groups with invitations on chain only accept announces of the users redeemed an invitation
+func isInviteOnly(dbMgr *storage.DbMgr, item *chestnutpb.GroupItem, nodename string) (bool, error) {
+	if item.EncryptType != chestnutpb.GroupEncryptType_PRIVATE {
+		return false, nil
+	}
+	invites, err := dbMgr.GetInvites(item.GroupId, nodename)
+	if err != nil {
+		return false, err
+	}
+	return len(invites) > 0, nil
+}
+
+func applyInviteTrx(dbMgr *storage.DbMgr, grpItem *chestnutpb.GroupItem, trx *chestnutpb.Trx, nodename string) error {
+	item := &chestnutpb.InviteItem{}
+	if err := proto.Unmarshal(trx.Data, item); err != nil {
+		return err
+	}
+	if item.GroupId != grpItem.GroupId {
+		return errors.New("invitation of another group")
+	}
+	if err := VerifyInvite(item, grpItem.OwnerPubKey); err != nil {
+		invite_log.Warningf("<%s> invitation <%s> rejected: %s", grpItem.GroupId, item.InviteId, err)
+		return err
+	}
+
+	saved, err := dbMgr.GetInvite(item.GroupId, item.InviteId, nodename)
+	if err != nil {
+		return err
+	}
+	if item.Action == chestnutpb.ActionType_REMOVE {
+		if saved == nil {
+			return errors.New("Invitation Not Found")
+		}
+		saved.Revoked = true
+		return dbMgr.SaveInvite(saved, nodename)
+	}
+	if saved != nil {
+		return nil
+	}
+	item.Used = 0
+	item.Revoked = false
+	return dbMgr.SaveInvite(item, nodename)
+}
+
+func applyRedeemTrx(dbMgr *storage.DbMgr, grpItem *chestnutpb.GroupItem, trx *chestnutpb.Trx, nodename string) error {
+	item := &chestnutpb.RedeemItem{}
+	if err := proto.Unmarshal(trx.Data, item); err != nil {
+		return err
+	}
+	if item.GroupId != grpItem.GroupId || item.RedeemerPubkey != trx.SenderPubkey {
+		return errors.New("invalid redemption")
+	}
+
+	invite, err := dbMgr.GetInvite(item.GroupId, item.InviteId, nodename)
+	if err != nil {
+		return err
+	}
+	if invite == nil {
+		invite_log.Warningf("<%s> redemption of unknown invitation <%s>", grpItem.GroupId, item.InviteId)
+		return errors.New("Invitation Not Found")
+	}
+	redeemed, err := dbMgr.IsRedeemed(item.GroupId, item.RedeemerPubkey, nodename)
+	if err != nil || redeemed {
+		return err
+	}
+	if err := CheckInvite(invite, trx.TimeStamp); err != nil {
+		invite_log.Warningf("<%s> redemption of <%s> rejected: %s", grpItem.GroupId, item.RedeemerPubkey, err)
+		return err
+	}
+	if invite.InviteePubkey != "" {
+		sign, err := hex.DecodeString(item.InviteeSign)
+		if err != nil {
+			return err
+		}
+		if err := verifySign(invite.InviteePubkey, RedeemHash(item), sign); err != nil {
+			invite_log.Warningf("<%s> redemption of <%s> rejected: %s", grpItem.GroupId, item.RedeemerPubkey, err)
+			return err
+		}
+	}
+
+	if err := dbMgr.SaveRedeem(item, nodename); err != nil {
+		return err
+	}
+	invite.Used += 1
+	if err := dbMgr.SaveInvite(invite, nodename); err != nil {
+		return err
+	}
+
+	//announce applied before the redemption
+	result, exist, err := dbMgr.GetAnnounceResult(item.GroupId, chestnutpb.AnnounceType_AS_USER, item.RedeemerPubkey, nodename)
+	if err != nil || !exist || result != chestnutpb.ApproveType_REJECTED {
+		return err
+	}
+	return dbMgr.UpdateAnnounceResult(item.GroupId, chestnutpb.AnnounceType_AS_USER, item.RedeemerPubkey, chestnutpb.ApproveType_ANNOUNCED, nodename)
+}
+
+// reject the user announce of a joiner without an invitation, the announce is applied already
+func applyAnnounceResult(dbMgr *storage.DbMgr, grpItem *chestnutpb.GroupItem, trx *chestnutpb.Trx, nodename string) error {
+	item := &chestnutpb.AnnounceItem{}
+	if err := proto.Unmarshal(trx.Data, item); err != nil {
+		return err
+	}
+	if item.Type != chestnutpb.AnnounceType_AS_USER || item.SignPubkey == grpItem.OwnerPubKey || IsAdmin(grpItem.Admins, item.SignPubkey) {
+		return nil
+	}
+	inviteOnly, err := isInviteOnly(dbMgr, grpItem, nodename)
+	if err != nil || !inviteOnly {
+		return err
+	}
+	redeemed, err := dbMgr.IsRedeemed(grpItem.GroupId, item.SignPubkey, nodename)
+	if err != nil || redeemed {
+		return err
+	}
+	invite_log.Infof("<%s> announce of <%s> rejected, no invitation redeemed", grpItem.GroupId, item.SignPubkey)
+	return dbMgr.UpdateAnnounceResult(grpItem.GroupId, chestnutpb.AnnounceType_AS_USER, item.SignPubkey, chestnutpb.ApproveType_REJECTED, nodename)
+}
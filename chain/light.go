@@ -0,0 +1,311 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"github.com/lixvyang/chestnut/nodectx"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+	"google.golang.org/protobuf/proto"
+)
+
+var light_log = logging.Logger("light")
+
+// A light node syncs stripped blocks: the block headers and trx leaf hashes, with the data of POST
+// trxs removed. Other trxs are always kept, they change the group state.
+func KeepTrxData(trx *chestnutpb.Trx) bool {
+	return trx.Type != chestnutpb.TrxType_POST
+}
+
+// The post data of the light node itself and of the senders it follows is fetched from the producers
+// after the block is saved, other posts are fetched when opened. Fetched trxs are verified by the trx root.
+func isTrxFollowed(trx *chestnutpb.Trx, userPubkey string, senders []string) bool {
+	if trx.SenderPubkey == userPubkey {
+		return true
+	}
+	for _, sender := range senders {
+		if trx.SenderPubkey == sender {
+			return true
+		}
+	}
+	return false
+}
+
+// copy of the block with the data of trxs not kept removed, the leaf hashes are added to verify
+// the trxs left. Blocks without a trx root are returned as is, the hash covers all trx data
+func StripBlock(block *chestnutpb.Block, keep func(trx *chestnutpb.Trx) bool) (*chestnutpb.Block, error) {
+	if len(block.TrxRoot) == 0 {
+		return block, nil
+	}
+
+	stripped := proto.Clone(block).(*chestnutpb.Block)
+	if !IsBlockStripped(block) {
+		_, leaves, err := TrxRoot(block.Trxs)
+		if err != nil {
+			return nil, err
+		}
+		stripped.TrxHashes = leaves
+	}
+
+	removed := false
+	for _, trx := range stripped.Trxs {
+		if len(trx.Data) > 0 && !keep(trx) {
+			trx.Data = nil
+			removed = true
+		}
+	}
+	if !removed && !IsBlockStripped(block) {
+		return block, nil
+	}
+	return stripped, nil
+}
+
+// a light node can only sync a chain with trx roots
+func CheckLightMode(item *chestnutpb.GroupItem) error {
+	if item.GenesisBlock == nil || len(item.GenesisBlock.TrxRoot) == 0 {
+		return errors.New("Blocks of the group have no trx root, light mode is not supported")
+	}
+	return nil
+}
+
+// light mode of the group, nil if the group is synced with full blocks
+func (chain *Chain) GetLightMode() *chestnutpb.LightModeItem {
+	item, err := nodectx.GetDbMgr().GetLightMode(chain.groupId, chain.nodename)
+	if err != nil {
+		light_log.Warningf("<%s> get light mode failed: %s", chain.groupId, err.Error())
+		return nil
+	}
+	return item
+}
+
+// strip the blocks to save by the light mode, trxs saved without data are indexed to fetch them later.
+// Trxs of the senders followed are returned to fetch them once the blocks are saved
+func stripLightBlocks(dbMgr *storage.DbMgr, blocks []*chestnutpb.Block, light *chestnutpb.LightModeItem, userPubkey string, nodename string) ([]*chestnutpb.Block, []*chestnutpb.ReqTrx, error) {
+	var result []*chestnutpb.Block
+	var fetchs []*chestnutpb.ReqTrx
+	for _, block := range blocks {
+		stripped, err := StripBlock(block, KeepTrxData)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, trx := range stripped.Trxs {
+			if len(trx.Data) == 0 {
+				if err := dbMgr.AddLightTrx(block.GroupId, trx.TrxId, block.BlockId, nodename); err != nil {
+					return nil, nil, err
+				}
+				if isTrxFollowed(trx, userPubkey, light.Senders) {
+					fetchs = append(fetchs, &chestnutpb.ReqTrx{GroupId: block.GroupId, BlockId: block.BlockId, TrxId: trx.TrxId})
+				}
+			}
+		}
+		result = append(result, stripped)
+	}
+	return result, fetchs, nil
+}
+
+// a light node takes stripped blocks only, full blocks produced are synced stripped from the producers
+func (chain *Chain) syncLightBlocks() {
+	_, highestBlockId := chain.group.ChainHead()
+	topBlock, err := nodectx.GetDbMgr().GetBlock(highestBlockId, false, chain.nodename)
+	if err != nil {
+		light_log.Warningf("<%s> get top block failed: %s", chain.groupId, err.Error())
+		return
+	}
+	if err := chain.Syncer.SyncForward(topBlock); err != nil {
+		light_log.Debugf("<%s> sync stripped blocks: %s", chain.groupId, err.Error())
+	}
+}
+
+func (chain *Chain) handleReqTrx(trx *chestnutpb.Trx) error {
+	if chain.Consensus.Producer() == nil {
+		return nil
+	}
+	light_log.Debugf("<%s> handleReqTrx called", chain.groupId)
+
+	decryptData, err := DecryptGroupData(chain.GetGroupItem(), trx.Data)
+	if err != nil {
+		return err
+	}
+
+	var reqTrxItem chestnutpb.ReqTrx
+	if err := proto.Unmarshal(decryptData, &reqTrxItem); err != nil {
+		return err
+	}
+
+	//check if requester is in group block list
+	isBlocked, err := nodectx.GetDbMgr().IsUserBlocked(trx.GroupId, trx.SenderPubkey)
+	if err != nil {
+		return err
+	}
+	if isBlocked {
+		light_log.Debugf("<%s> user <%s> is blocked", chain.groupId, trx.SenderPubkey)
+		return nil
+	}
+
+	isExist, err := nodectx.GetDbMgr().IsTrxExist(reqTrxItem.TrxId, chain.nodename)
+	if err != nil || !isExist {
+		return err
+	}
+	reqTrx, err := nodectx.GetDbMgr().GetTrx(reqTrxItem.TrxId, chain.nodename)
+	if err != nil {
+		return err
+	}
+	//trx data pruned, let other producers send it
+	if len(reqTrx.Data) == 0 || reqTrx.GroupId != chain.groupId {
+		return nil
+	}
+	return chain.GetProducerTrxMgr().SendReqTrxResp(&reqTrxItem, reqTrx)
+}
+
+func (chain *Chain) handleReqTrxResp(trx *chestnutpb.Trx) error {
+	decryptData, err := DecryptGroupData(chain.GetGroupItem(), trx.Data)
+	if err != nil {
+		return err
+	}
+
+	var reqTrxResp chestnutpb.ReqTrxResp
+	if err := proto.Unmarshal(decryptData, &reqTrxResp); err != nil {
+		return err
+	}
+
+	//if not asked by myself, ignore it
+	if reqTrxResp.RequesterPubkey != chain.group.Item.UserSignPubkey {
+		return nil
+	}
+	light_log.Debugf("<%s> handleReqTrxResp called", chain.groupId)
+
+	fetched := &chestnutpb.Trx{}
+	if err := proto.Unmarshal(reqTrxResp.Trx, fetched); err != nil {
+		return err
+	}
+	return chain.addFetchedTrx(reqTrxResp.BlockId, fetched)
+}
+
+// verify a fetched trx by the leaf hash of the saved block and apply it
+func (chain *Chain) addFetchedTrx(blockId string, trx *chestnutpb.Trx) error {
+	dbMgr := nodectx.GetDbMgr()
+	savedBlockId, err := dbMgr.GetLightTrxBlock(chain.groupId, trx.TrxId, chain.nodename)
+	if err != nil {
+		return err
+	}
+	if savedBlockId == "" {
+		//fetched already, or sent by other producers
+		return nil
+	}
+	if savedBlockId != blockId {
+		return fmt.Errorf("trx <%s> is not packed in block <%s>", trx.TrxId, blockId)
+	}
+
+	block, err := dbMgr.GetBlock(blockId, false, chain.nodename)
+	if err != nil {
+		return err
+	}
+	index := -1
+	for i, blockTrx := range block.Trxs {
+		if blockTrx.TrxId == trx.TrxId {
+			index = i
+			break
+		}
+	}
+	if index < 0 || index >= len(block.TrxHashes) {
+		return fmt.Errorf("trx <%s> is not packed in block <%s>", trx.TrxId, blockId)
+	}
+
+	leaf, err := TrxHash(trx)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(leaf, block.TrxHashes[index]) {
+		light_log.Warningf("<%s> fetched trx <%s> mismatch the trx root, reject", chain.groupId, trx.TrxId)
+		return errors.New("Trx hash mismatch")
+	}
+	valid, err := chain.GetProducerTrxMgr().VerifyTrx(trx)
+	if !valid {
+		if err == nil {
+			err = errors.New("invalid trx signature")
+		}
+		return err
+	}
+
+	originalData := trx.Data
+	if chain.group.Item.EncryptType == chestnutpb.GroupEncryptType_PRIVATE {
+		decryptData, err := chain.GetKeystore().Decrypt(chain.group.Item.UserEncryptPubkey, trx.Data)
+		if err != nil {
+			return err
+		}
+		trx.Data = decryptData
+	} else {
+		decryptData, err := DecryptGroupData(chain.GetGroupItem(), trx.Data)
+		if err != nil {
+			return err
+		}
+		trx.Data = decryptData
+	}
+
+	txn, err := dbMgr.BeginTxn()
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	if err := txn.AddPost(trx, chain.nodename); err != nil {
+		return err
+	}
+	trx.Data = originalData
+	if err := txn.AddTrx(trx, chain.nodename); err != nil {
+		return err
+	}
+	if err := txn.RmLightTrx(chain.groupId, trx.TrxId, chain.nodename); err != nil {
+		return err
+	}
+	light_log.Infof("<%s> trx <%s> fetched", chain.groupId, trx.TrxId)
+	return txn.Commit()
+}
+
+func (grp *Group) GetLightMode() (*chestnutpb.LightModeItem, error) {
+	group_log.Debugf("<%s> GetLightMode called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().GetLightMode(grp.Item.GroupId, grp.ChainCtx.nodename)
+}
+
+// blocks saved after light mode is set are stripped, blocks saved before are kept
+func (grp *Group) UpdLightMode(item *chestnutpb.LightModeItem) error {
+	group_log.Debugf("<%s> UpdLightMode called", grp.Item.GroupId)
+	if err := CheckLightMode(grp.Item); err != nil {
+		return err
+	}
+	if _, ok := grp.ChainCtx.ProducerPool[grp.Item.UserSignPubkey]; ok {
+		return errors.New("Producer needs full blocks, light mode is not supported")
+	}
+	item.GroupId = grp.Item.GroupId
+	item.TimeStamp = time.Now().UnixNano()
+	return nodectx.GetDbMgr().UpdLightMode(item, grp.ChainCtx.nodename)
+}
+
+// new blocks are saved with all trx data, trx data not saved can still be fetched
+func (grp *Group) RmLightMode() error {
+	group_log.Debugf("<%s> RmLightMode called", grp.Item.GroupId)
+	return nodectx.GetDbMgr().RmLightMode(grp.Item.GroupId, grp.ChainCtx.nodename)
+}
+
+func (grp *Group) CountLightTrxs() (int64, error) {
+	return nodectx.GetDbMgr().CountLightTrxs(grp.Item.GroupId, grp.ChainCtx.nodename)
+}
+
+// request the data of a trx saved without data from the producers, the trx is applied when a
+// producer responds
+func (grp *Group) FetchTrx(trxId string) error {
+	group_log.Debugf("<%s> FetchTrx called", grp.Item.GroupId)
+	blockId, err := nodectx.GetDbMgr().GetLightTrxBlock(grp.Item.GroupId, trxId, grp.ChainCtx.nodename)
+	if err != nil {
+		return err
+	}
+	if blockId == "" {
+		return fmt.Errorf("Data of trx %s is saved or trx not exist", trxId)
+	}
+	return grp.ChainCtx.GetProducerTrxMgr().SendReqTrx(blockId, trxId)
+}
@@ -0,0 +1,289 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	guuid "github.com/google/uuid"
+	logging "github.com/ipfs/go-log/v2"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var liveness_log = logging.Logger("liveness")
+
+// a round of producer liveness, each producer sends a heartbeat every round
+const HEARTBEAT_INTERVAL = 30 * time.Second
+
+// liveness of a producer seen by the node since it started
+type ProducerLiveness struct {
+	ProducerPubkey string `json:"producer_pubkey"`
+	LastSeen       int64  `json:"last_seen"`     //0 if not seen yet
+	LastHeight     int64  `json:"last_height"`   //highest block height reported by the producer
+	MissedRounds   int64  `json:"missed_rounds"` //rounds missed in a row
+	TotalMissed    int64  `json:"total_missed"`
+	proposed       bool   //removal proposed by the demotion policy
+}
+
+// Liveness sends the heartbeats of the groups the node produces for, closes a
+// round of each group every interval and demotes producers by the demotion policy
+type Liveness struct {
+	interval time.Duration
+	stop     chan struct{}
+}
+
+var liveness *Liveness
+
+func StartLiveness(interval time.Duration) *Liveness {
+	liveness = &Liveness{interval: interval, stop: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				liveness.RunOnce()
+			case <-liveness.stop:
+				liveness_log.Info("liveness stopped")
+				return
+			}
+		}
+	}()
+	return liveness
+}
+
+func GetLiveness() *Liveness {
+	return liveness
+}
+
+func (l *Liveness) Stop() {
+	close(l.stop)
+}
+
+func (l *Liveness) RunOnce() {
+	for _, groupmgr := range ListGroupMgrs() {
+		for _, grp := range groupmgr.List() {
+			if grp.paused {
+				continue
+			}
+			grp.ChainCtx.closeRound(l.interval)
+			if grp.ChainCtx.Consensus != nil && grp.ChainCtx.Consensus.Producer() != nil {
+				if err := grp.ChainCtx.sendHeartbeat(); err != nil {
+					liveness_log.Warningf("<%s> send heartbeat failed: %s", grp.Item.GroupId, err.Error())
+				}
+			}
+			grp.demoteProducers()
+		}
+	}
+}
+
+func (chain *Chain) sendHeartbeat() error {
+	height, blockId := chain.group.ChainHead()
+	item := &chestnutpb.HeartbeatItem{
+		GroupId:        chain.groupId,
+		ProducerPubkey: chain.group.Item.UserSignPubkey,
+		HighestHeight:  height,
+		HighestBlockId: blockId,
+		TimeStamp:      time.Now().UnixNano(),
+	}
+	if err := chain.GetProducerTrxMgr().SendHeartbeat(item); err != nil {
+		return err
+	}
+	chain.markSeen(item.ProducerPubkey, item.HighestHeight)
+	return nil
+}
+
+func (chain *Chain) handleHeartbeat(trx *chestnutpb.Trx) error {
+	if _, ok := chain.ProducerPool[trx.SenderPubkey]; !ok {
+		return nil
+	}
+	verified, err := chain.GetProducerTrxMgr().VerifyTrx(trx)
+	if err != nil {
+		return err
+	}
+	if !verified {
+		chain_log.Warningf("<%s> heartbeat of <%s> with invalid signature", chain.groupId, trx.SenderPubkey)
+		return errors.New("invalid heartbeat signature")
+	}
+	//an expired heartbeat replayed doesn't prove the producer is online
+	if trx.Expired < time.Now().UnixNano() {
+		return nil
+	}
+
+	decryptData, err := DecryptGroupData(chain.GetGroupItem(), trx.Data)
+	if err != nil {
+		return err
+	}
+	item := &chestnutpb.HeartbeatItem{}
+	if err := proto.Unmarshal(decryptData, item); err != nil {
+		return err
+	}
+	if item.GroupId != chain.groupId || item.ProducerPubkey != trx.SenderPubkey {
+		return errors.New("invalid heartbeat")
+	}
+
+	chain_log.Debugf("<%s> heartbeat of <%s> at height <%d>", chain.groupId, item.ProducerPubkey, item.HighestHeight)
+	chain.markSeen(item.ProducerPubkey, item.HighestHeight)
+	return nil
+}
+
+// the producer in the pool is online in the current round
+func (chain *Chain) markSeen(pubkey string, height int64) {
+	if _, ok := chain.ProducerPool[pubkey]; !ok {
+		return
+	}
+	chain.livemu.Lock()
+	defer chain.livemu.Unlock()
+	stat := chain.producerLiveness(pubkey)
+	stat.LastSeen = time.Now().UnixNano()
+	if height > stat.LastHeight {
+		stat.LastHeight = height
+	}
+}
+
+// must be called with livemu locked
+func (chain *Chain) producerLiveness(pubkey string) *ProducerLiveness {
+	if chain.liveness == nil {
+		chain.liveness = make(map[string]*ProducerLiveness)
+	}
+	stat, ok := chain.liveness[pubkey]
+	if !ok {
+		stat = &ProducerLiveness{ProducerPubkey: pubkey}
+		chain.liveness[pubkey] = stat
+	}
+	return stat
+}
+
+// count a missed round for each producer not seen since the round started. Heartbeats
+// sent on another ticker may arrive a little early, half a round is tolerated.
+func (chain *Chain) closeRound(interval time.Duration) {
+	now := time.Now().UnixNano()
+	chain.livemu.Lock()
+	defer chain.livemu.Unlock()
+	if chain.roundStart == 0 {
+		chain.roundStart = now
+		return
+	}
+
+	since := chain.roundStart - int64(interval/2)
+	for pubkey := range chain.ProducerPool {
+		stat := chain.producerLiveness(pubkey)
+		if stat.LastSeen >= since {
+			stat.MissedRounds = 0
+			stat.proposed = false
+		} else {
+			stat.MissedRounds += 1
+			stat.TotalMissed += 1
+		}
+	}
+	for pubkey := range chain.liveness {
+		if _, ok := chain.ProducerPool[pubkey]; !ok {
+			delete(chain.liveness, pubkey)
+		}
+	}
+	chain.roundStart = now
+}
+
+// liveness of the producers in the pool
+func (chain *Chain) GetLiveness() map[string]ProducerLiveness {
+	chain.livemu.RLock()
+	defer chain.livemu.RUnlock()
+	result := make(map[string]ProducerLiveness)
+	for pubkey, stat := range chain.liveness {
+		result[pubkey] = *stat
+	}
+	return result
+}
+
+// producers missed the rounds in a row and not proposed yet, the owner is never demoted
+func (chain *Chain) producersToDemote(maxMissed int64) map[string]int64 {
+	chain.livemu.Lock()
+	defer chain.livemu.Unlock()
+	result := make(map[string]int64)
+	for pubkey, stat := range chain.liveness {
+		if pubkey == chain.group.Item.OwnerPubKey || stat.proposed || stat.MissedRounds < maxMissed {
+			continue
+		}
+		stat.proposed = true
+		result[pubkey] = stat.MissedRounds
+	}
+	return result
+}
+
+// propose removing the producers missed the rounds of the demotion policy. The owner node
+// removes them, an admin node of an admin group saves a removal action for the admins to cosign.
+func (grp *Group) demoteProducers() {
+	if IsAdminGroup(grp.Item) {
+		if !IsAdmin(grp.Item.Admins, grp.Item.UserSignPubkey) {
+			return
+		}
+	} else if grp.Item.OwnerPubKey != grp.Item.UserSignPubkey {
+		return
+	}
+
+	policy, err := grp.GetDemotionPolicy()
+	if err != nil {
+		liveness_log.Warningf("<%s> get demotion policy failed: %s", grp.Item.GroupId, err.Error())
+		return
+	}
+	if policy == nil || policy.MaxMissedRounds <= 0 {
+		return
+	}
+
+	for pubkey, missed := range grp.ChainCtx.producersToDemote(policy.MaxMissedRounds) {
+		if err := grp.proposeDemotion(pubkey, missed); err != nil {
+			liveness_log.Warningf("<%s> demote producer <%s> failed: %s", grp.Item.GroupId, pubkey, err.Error())
+			continue
+		}
+		liveness_log.Infof("<%s> producer <%s> missed <%d> rounds, removal proposed", grp.Item.GroupId, pubkey, missed)
+	}
+}
+
+func (grp *Group) proposeDemotion(pubkey string, missed int64) error {
+	item := &chestnutpb.ProducerItem{
+		GroupId:          grp.Item.GroupId,
+		ProducerPubkey:   pubkey,
+		GroupOwnerPubkey: grp.Item.OwnerPubKey,
+		Action:           chestnutpb.ActionType_REMOVE,
+		Memo:             fmt.Sprintf("missed %d rounds", missed),
+		TimeStamp:        time.Now().UnixNano(),
+	}
+	ks := grp.ChainCtx.GetKeystore()
+
+	if IsAdminGroup(grp.Item) {
+		data, err := proto.Marshal(item)
+		if err != nil {
+			return err
+		}
+		action := &chestnutpb.AdminAction{
+			ActionId:  guuid.New().String(),
+			GroupId:   grp.Item.GroupId,
+			Type:      chestnutpb.TrxType_PRODUCER,
+			Data:      data,
+			Proposer:  grp.Item.UserSignPubkey,
+			TimeStamp: time.Now().UnixNano(),
+		}
+		signature, err := ks.SignByKeyName(grp.Item.GroupId, AdminActionHash(action))
+		if err != nil {
+			return err
+		}
+		action.Signs = []*chestnutpb.AdminSign{{Pubkey: grp.Item.UserSignPubkey, Sign: signature}}
+		return grp.SaveAdminAction(action)
+	}
+
+	var buffer bytes.Buffer
+	buffer.Write([]byte(item.GroupId))
+	buffer.Write([]byte(item.ProducerPubkey))
+	buffer.Write([]byte(item.GroupOwnerPubkey))
+	signature, err := ks.SignByKeyName(item.GroupId, Hash(buffer.Bytes()))
+	if err != nil {
+		return err
+	}
+	item.GroupOwnerSign = hex.EncodeToString(signature)
+	_, err = grp.UpdProducer(item)
+	return err
+}
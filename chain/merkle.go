@@ -0,0 +1,110 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"bytes"
+	"errors"
+
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// leaf and inner nodes are hashed with different prefixes, so a leaf can't be taken as an inner node
+const (
+	merkleLeafPrefix  byte = 0
+	merkleInnerPrefix byte = 1
+)
+
+// leaf hash of a trx in the trx root. The resend count is changed when a trx is resent, it is not covered
+func TrxHash(trx *chestnutpb.Trx) ([]byte, error) {
+	leaf := proto.Clone(trx).(*chestnutpb.Trx)
+	leaf.ResendCount = 0
+	tbytes, err := proto.MarshalOptions{Deterministic: true}.Marshal(leaf)
+	if err != nil {
+		return nil, err
+	}
+	return Hash(append([]byte{merkleLeafPrefix}, tbytes...)), nil
+}
+
+// root of the binary Merkle tree of the leaves, the last node of a level with odd nodes is moved up
+func MerkleRoot(leaves [][]byte) []byte {
+	if len(leaves) == 0 {
+		return Hash(nil)
+	}
+	level := leaves
+	for len(level) > 1 {
+		var next [][]byte
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+				continue
+			}
+			node := append([]byte{merkleInnerPrefix}, level[i]...)
+			next = append(next, Hash(append(node, level[i+1]...)))
+		}
+		level = next
+	}
+	return level[0]
+}
+
+// trx root and leaf hashes of the trxs
+func TrxRoot(trxs []*chestnutpb.Trx) ([]byte, [][]byte, error) {
+	var leaves [][]byte
+	for _, trx := range trxs {
+		leaf, err := TrxHash(trx)
+		if err != nil {
+			return nil, nil, err
+		}
+		leaves = append(leaves, leaf)
+	}
+	return MerkleRoot(leaves), leaves, nil
+}
+
+// verify the trxs of a block by the trx root. Trxs of a stripped block are checked by the leaf
+// hashes, trxs without data are left to be checked when the data is fetched
+func CheckTrxRoot(block *chestnutpb.Block) error {
+	if len(block.TrxRoot) == 0 {
+		//the block hash covers the trxs
+		if len(block.TrxHashes) > 0 {
+			return errors.New("Trx hashes of a block without trx root")
+		}
+		return nil
+	}
+
+	leaves := block.TrxHashes
+	if len(leaves) == 0 {
+		root, _, err := TrxRoot(block.Trxs)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(root, block.TrxRoot) {
+			return errors.New("Trx root mismatch")
+		}
+		return nil
+	}
+
+	if len(leaves) != len(block.Trxs) {
+		return errors.New("Trx hashes mismatch")
+	}
+	for i, trx := range block.Trxs {
+		if len(trx.Data) == 0 {
+			continue
+		}
+		leaf, err := TrxHash(trx)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(leaf, leaves[i]) {
+			return errors.New("Trx hash mismatch")
+		}
+	}
+	if !bytes.Equal(MerkleRoot(leaves), block.TrxRoot) {
+		return errors.New("Trx root mismatch")
+	}
+	return nil
+}
+
+// trx data of a stripped block is not saved
+func IsBlockStripped(block *chestnutpb.Block) bool {
+	return len(block.TrxHashes) > 0
+}
@@ -5,27 +5,21 @@ import (
 	"bytes"
 	"crypto/sha256"
 	"encoding/binary"
-	"encoding/hex"
 	"errors"
 	"sync"
 	"time"
 
-	localcrypto "github.com/lixvyang/chestnut/crypto"
 	guuid "github.com/google/uuid"
 	logging "github.com/ipfs/go-log/v2"
 	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
 	"google.golang.org/protobuf/proto"
 )
 
 var molaproducer_log = logging.Logger("producer")
 
-const PRODUCE_TIMER time.Duration = 5
-const MERGE_TIMER time.Duration = 5
-
-const TRXS_TOTAL_SIZE int = 900 * 1024
-
 type ProducerStatus int
 
 const (
@@ -46,6 +40,8 @@ type MolassesProducer struct {
 	nodename string
 	cIface ChainMolassesIface
 	groupId string
+	stopped chan struct{}
+	stoponce sync.Once
 }
 
 func (producer *MolassesProducer) Init(item *chestnutpb.GroupItem, nodename string, iface ChainMolassesIface)  {
@@ -57,14 +53,32 @@ func (producer *MolassesProducer) Init(item *chestnutpb.GroupItem, nodename stri
 	producer.status = StatusIdle
 	producer.nodename = nodename
 	producer.groupId = item.GroupId
+	producer.stopped = make(chan struct{})
 
 	molaproducer_log.Infof("<%s> producer created", producer.groupId)
 }
 
+// stop producing and merging blocks, a stopped producer can't be restarted
+func (producer *MolassesProducer) Stop() {
+	molaproducer_log.Debugf("<%s> Stop called", producer.groupId)
+	producer.stoponce.Do(func() {
+		close(producer.stopped)
+	})
+}
+
+func (producer *MolassesProducer) isStopped() bool {
+	select {
+	case <-producer.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
 // add trx to trx pool
 func (producer *MolassesProducer) AddTrx(trx *chestnutpb.Trx)  {
 	molaproducer_log.Debugf("<%s> AddTrx called", producer.groupId)
-	if producer.cIface.IsSyncerReady() {
+	if producer.cIface.IsSyncerReady() || producer.isStopped() {
 		return
 	}
 
@@ -78,7 +92,7 @@ func (producer *MolassesProducer) AddTrx(trx *chestnutpb.Trx)  {
 
 func (producer *MolassesProducer) startProduceBlock()  {
 	molaproducer_log.Debugf("<%s> startProduceBlock called", producer.groupId)
-	producer.ProduceTimer = time.NewTimer(PRODUCE_TIMER * time.Second)
+	producer.ProduceTimer = time.NewTimer(time.Duration(producer.cIface.GetConfig().ProduceTimer) * time.Millisecond)
 	producer.statusmu.Lock()
 	producer.status = StatusProducing
 	molaproducer_log.Debugf("<%s> set StatusProducing", producer.groupId)
@@ -90,15 +104,20 @@ func (producer *MolassesProducer) startProduceBlock()  {
 		producer.statusmu.Unlock()
 	}()
 
-	t := <- producer.ProduceTimer.C
-	molaproducer_log.Debugf("<%s> producer wait done at <%s>",producer.groupId, t.UTC().String())
-	producer.produceBlock()
+	select {
+	case t := <- producer.ProduceTimer.C:
+		molaproducer_log.Debugf("<%s> producer wait done at <%s>",producer.groupId, t.UTC().String())
+		producer.produceBlock()
+	case <-producer.stopped:
+		molaproducer_log.Debugf("<%s> producer stopped", producer.groupId)
+	}
 }
 
 
 func (producer *MolassesProducer) produceBlock() {
 	molaproducer_log.Debugf("<%s> produceBlock called", producer.groupId)
-	topBlock, err := nodectx.GetDbMgr().GetBlock(producer.grpItem.HighestBlockId, false, producer.nodename)
+	highestHeight, highestBlockId := producer.cIface.GetChainHead()
+	topBlock, err := nodectx.GetDbMgr().GetBlock(highestBlockId, false, producer.nodename)
 	if err != nil {
 		molaproducer_log.Info(err.Error())
 		return
@@ -112,12 +131,13 @@ func (producer *MolassesProducer) produceBlock() {
 
 	totalSizeBytes := 0
 	totalTrx := 0
+	trxsTotalSize := int(producer.cIface.GetConfigAt(highestHeight + 1).TrxsTotalSize)
 
 	for key, value := range producer.trxPool {
 		encodedcontent, _ := chestnutpb.ContentToBytes(value)
 		totalSizeBytes += binary.Size(encodedcontent)
 
-		if totalSizeBytes < TRXS_TOTAL_SIZE {
+		if totalSizeBytes < trxsTotalSize {
 			trxs = append(trxs, value)
 			//remove trx from pool
 			delete(producer.trxPool, key)
@@ -134,7 +154,7 @@ func (producer *MolassesProducer) produceBlock() {
 		return
 	}
 
-	newBlock, err := CreateBlock(topBlock, trxs, pubkeyBytes, producer.nodename)
+	newBlock, err := CreateBlock(topBlock, trxs, pubkeyBytes, producer.cIface.GetKeystore(), producer.nodename)
 	if err != nil {
 		molaproducer_log.Errorf("<%s> create block error", producer.groupId)
 		molaproducer_log.Errorf(err.Error())
@@ -162,12 +182,7 @@ func (producer *MolassesProducer) AddProducedBlock(trx *chestnutpb.Trx) error {
 		return nil
 	}
 
-	ciperKey, err := hex.DecodeString(producer.grpItem.CipherKey)
-	if err != nil {
-		return err
-	}
-
-	decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+	decryptData, err := DecryptGroupData(producer.cIface.GetGroupItem(), trx.Data)
 	if err != nil {
 		return err
 	}
@@ -201,15 +216,23 @@ func (producer *MolassesProducer) startMergeBlock() error {
 		producer.status = StatusIdle
 		producer.statusmu.Unlock()
 
-		if len(producer.trxPool) != 0 {
+		if len(producer.trxPool) != 0 && !producer.isStopped() {
 			molaproducer_log.Debugf("<%s> start produce block", producer.groupId)
 			producer.startProduceBlock()
 		}
 	}()
-	molaproducer_log.Debugf("<%s> set merge timer to <%d>s", producer.groupId, MERGE_TIMER)
-	mergeTimer := time.NewTimer(MERGE_TIMER * time.Second)
-	t := <-mergeTimer.C
-	molaproducer_log.Debugf("<%s> merge timer ticker...<%s>", producer.groupId, t.UTC().String())
+	mergeTimerMs := producer.cIface.GetConfig().MergeTimer
+	molaproducer_log.Debugf("<%s> set merge timer to <%d>ms", producer.groupId, mergeTimerMs)
+	mergeTimer := time.NewTimer(time.Duration(mergeTimerMs) * time.Millisecond)
+	defer mergeTimer.Stop()
+	select {
+	case t := <-mergeTimer.C:
+		molaproducer_log.Debugf("<%s> merge timer ticker...<%s>", producer.groupId, t.UTC().String())
+	case <-producer.stopped:
+		molaproducer_log.Debugf("<%s> producer stopped, drop <%d> block(s) to merge", producer.groupId, len(producer.blockPool))
+		producer.blockPool = make(map[string]*chestnutpb.Block)
+		return nil
+	}
 
 	candidateBlkid := ""
 	var oHash []byte
@@ -263,12 +286,7 @@ func (producer *MolassesProducer) GetBlockForward(trx *chestnutpb.Trx) error {
 	molaproducer_log.Debugf("<%s> GetBlockForward called", producer.groupId)
 
 	var reqBlockItem chestnutpb.ReqBlock
-	ciperKey, err := hex.DecodeString(producer.grpItem.CipherKey)
-	if err != nil {
-		return err
-	}
-
-	decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+	decryptData, err := DecryptGroupData(producer.cIface.GetGroupItem(), trx.Data)
 	if err != nil {
 		return err
 	}
@@ -293,8 +311,18 @@ func (producer *MolassesProducer) GetBlockForward(trx *chestnutpb.Trx) error {
 
 	if len(subBlocks) != 0 {
 		for _, block := range subBlocks {
+			//trx data of pruned block removed, let other producers send it
+			if pruned, _ := nodectx.GetDbMgr().IsBlockPruned(block.BlockId, producer.nodename); pruned {
+				molaproducer_log.Debugf("<%s> block <%s> pruned, skip", producer.groupId, block.BlockId)
+				continue
+			}
+			block, err := producer.lightBlock(&reqBlockItem, block)
+			if err != nil {
+				molaproducer_log.Warnf(err.Error())
+				continue
+			}
 			molaproducer_log.Debugf("<%s> send REQ_NEXT_BLOCK_RESP (BLOCK_IN_TRX)", producer.groupId)
-			err := producer.cIface.GetProducerTrxMgr().SendReqBlockResp(&reqBlockItem, block, chestnutpb.ReqBlkResult_BLOCK_IN_TRX)
+			err = producer.cIface.GetProducerTrxMgr().SendReqBlockResp(&reqBlockItem, block, chestnutpb.ReqBlkResult_BLOCK_IN_TRX)
 			if err != nil {
 				molaproducer_log.Warnf(err.Error())
 			}
@@ -316,12 +344,7 @@ func (producer *MolassesProducer) GetBlockBackward(trx *chestnutpb.Trx) error {
 
 	var reqBlockItem chestnutpb.ReqBlock
 
-	ciperKey, err := hex.DecodeString(producer.grpItem.CipherKey)
-	if err != nil {
-		return err
-	}
-
-	decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+	decryptData, err := DecryptGroupData(producer.cIface.GetGroupItem(), trx.Data)
 	if err != nil {
 		return err
 	}
@@ -361,6 +384,14 @@ func (producer *MolassesProducer) GetBlockBackward(trx *chestnutpb.Trx) error {
 		if err != nil {
 			return err
 		}
+		if pruned, _ := nodectx.GetDbMgr().IsBlockPruned(parentBlock.BlockId, producer.nodename); pruned {
+			molaproducer_log.Debugf("<%s> block <%s> pruned, skip", producer.groupId, parentBlock.BlockId)
+			return nil
+		}
+		parentBlock, err = producer.lightBlock(&reqBlockItem, parentBlock)
+		if err != nil {
+			return err
+		}
 		return producer.cIface.GetProducerTrxMgr().SendReqBlockResp(&reqBlockItem, parentBlock, chestnutpb.ReqBlkResult_BLOCK_IN_TRX)
 	} else {
 		var emptyBlock *chestnutpb.Block
@@ -372,6 +403,14 @@ func (producer *MolassesProducer) GetBlockBackward(trx *chestnutpb.Trx) error {
 	}
 }
 
+//a light node requester gets the stripped block, post data is fetched by REQ_TRX
+func (producer *MolassesProducer) lightBlock(req *chestnutpb.ReqBlock, block *chestnutpb.Block) (*chestnutpb.Block, error) {
+	if !req.Light {
+		return block, nil
+	}
+	return StripBlock(block, KeepTrxData)
+}
+
 //addBlock for producer
 func (producer *MolassesProducer) AddBlock(block *chestnutpb.Block) error {
 	molaproducer_log.Debugf("<%s> AddBlock called", producer.groupId)
@@ -425,21 +464,25 @@ func (producer *MolassesProducer) AddBlock(block *chestnutpb.Block) error {
 		return nodectx.GetDbMgr().RmBlock(block.BlockId, true, producer.nodename)
 	}
 
-	//search cache, gather all blocks can be connected with this block
-	blocks, err := nodectx.GetDbMgr().GatherBlocksFromCache(block, true, producer.nodename)
+	//trxs of the block packed with the config of the block height
+	parentHeight, err := nodectx.GetDbMgr().GetBlockHeight(parentBlock.BlockId, producer.nodename)
 	if err != nil {
 		return err
 	}
+	if err := checkBlockConfig(block, producer.cIface.GetConfigAt(parentHeight+1)); err != nil {
+		molaproducer_log.Warningf("<%s> invalid block <%s>", producer.groupId, err.Error())
+		return nodectx.GetDbMgr().RmBlock(block.BlockId, true, producer.nodename)
+	}
 
-	//get all trxs in those new blocks
-	var trxs []*chestnutpb.Trx
-	trxs, err = GetAllTrxs(blocks)
+	//apply blocks, trxs and chain info in one transaction
+	txn, err := nodectx.GetDbMgr().BeginTxn()
 	if err != nil {
 		return err
 	}
+	defer txn.Rollback()
 
-	//apply those trxs
-	err = producer.applyTrxs(trxs)
+	//search cache, gather all blocks can be connected with this block
+	blocks, err := txn.GatherBlocksFromCache(block, true, producer.nodename)
 	if err != nil {
 		return err
 	}
@@ -447,43 +490,62 @@ func (producer *MolassesProducer) AddBlock(block *chestnutpb.Block) error {
 	//move blocks from cache to normal
 	for _, block := range blocks {
 		molaproducer_log.Debugf("<%s> move block <%s> from cache to chain", producer.groupId, block.BlockId)
-		err := nodectx.GetDbMgr().AddBlock(block, false, producer.nodename)
+		err := txn.AddBlock(block, false, producer.nodename)
 		if err != nil {
 			return err
 		}
 
-		err = nodectx.GetDbMgr().RmBlock(block.BlockId, true, producer.nodename)
+		err = txn.RmBlock(block.BlockId, true, producer.nodename)
 		if err != nil {
 			return err
 		}
 	}
 
+	//apply the trxs of those blocks, block by block with the block height
 	for _, block := range blocks {
-		err := nodectx.GetDbMgr().AddProducedBlockCount(producer.groupId, block.ProducerPubKey, producer.nodename)
+		blockHeight, err := txn.GetBlockHeight(block.BlockId, producer.nodename)
 		if err != nil {
 			return err
 		}
+		if err := producer.applyTrxs(txn, block.Trxs, blockHeight); err != nil {
+			return err
+		}
 	}
 
-	molaproducer_log.Debugf("<%s> chain height before recal: <%d>", producer.groupId, producer.grpItem.HighestHeight)
-	topBlock, err := nodectx.GetDbMgr().GetBlock(producer.grpItem.HighestBlockId, false, producer.nodename)
+	for _, block := range blocks {
+		err := txn.AddProducedBlockCount(producer.groupId, block.ProducerPubKey, producer.nodename)
+		if err != nil {
+			return err
+		}
+	}
+
+	highestHeight, highestBlockId := producer.cIface.GetChainHead()
+	molaproducer_log.Debugf("<%s> chain height before recal: <%d>", producer.groupId, highestHeight)
+	topBlock, err := txn.GetBlock(highestBlockId, false, producer.nodename)
 	if err != nil {
 		return err
 	}
-	newHeight, newHighestBlockId, err := RecalChainHeight(blocks, producer.grpItem.HighestHeight, topBlock, producer.nodename)
+	newHeight, newHighestBlockId, err := RecalChainHeight(txn, blocks, highestHeight, topBlock, producer.nodename)
 	if err != nil {
 		return err
 	}
 	molaproducer_log.Debugf("<%s> new height <%d>, new highest blockId %v", producer.groupId, newHeight, newHighestBlockId)
 
-	return producer.cIface.UpdChainInfo(newHeight, newHighestBlockId)
+	err = producer.cIface.UpdChainInfo(newHeight, newHighestBlockId, txn)
+	if err != nil {
+		return err
+	}
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+	return AddCachedChildBlocks(blocks, producer.nodename, producer.AddBlock)
 }
 
-func (producer *MolassesProducer) applyTrxs(trxs []*chestnutpb.Trx) error {
+func (producer *MolassesProducer) applyTrxs(dbMgr *storage.DbMgr, trxs []*chestnutpb.Trx, blockHeight int64) error {
 	molaproducer_log.Debugf("<%s> applyTrxs called", producer.groupId)
 	for _, trx := range trxs {
 		//check if trx already applied
-		isExist, err := nodectx.GetDbMgr().IsTrxExist(trx.TrxId, producer.nodename)
+		isExist, err := dbMgr.IsTrxExist(trx.TrxId, producer.nodename)
 		if err != nil {
 			molaproducer_log.Debugf("<%s> %s", producer.groupId, err.Error())
 			continue
@@ -491,7 +553,7 @@ func (producer *MolassesProducer) applyTrxs(trxs []*chestnutpb.Trx) error {
 
 		if isExist {
 			molaproducer_log.Debugf("<%s> trx <%s> existed, update trx", producer.groupId, trx.TrxId)
-			nodectx.GetDbMgr().AddTrx(trx)
+			dbMgr.AddTrx(trx, producer.nodename)
 			continue
 		}
 
@@ -504,7 +566,7 @@ func (producer *MolassesProducer) applyTrxs(trxs []*chestnutpb.Trx) error {
 			//since owner also needs to show POST data, and all announced user will encrypt for owner pubkey
 			//owner can actually decrypt POST
 			//for other producer, they can not decrpyt POST
-			ks := localcrypto.GetKeystore()
+			ks := producer.cIface.GetKeystore()
 			decryptData, err := ks.Decrypt(producer.grpItem.UserEncryptPubkey, trx.Data)
 			if err == nil {
 				//set trx.Data to decrypted []byte
@@ -512,12 +574,7 @@ func (producer *MolassesProducer) applyTrxs(trxs []*chestnutpb.Trx) error {
 			}
 		} else {
 			//decode trx data
-			ciperKey, err := hex.DecodeString(producer.grpItem.CipherKey)
-			if err != nil {
-				return err
-			}
-
-			decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+			decryptData, err := DecryptGroupData(producer.cIface.GetGroupItem(), trx.Data)
 			if err != nil {
 				return err
 			}
@@ -526,26 +583,49 @@ func (producer *MolassesProducer) applyTrxs(trxs []*chestnutpb.Trx) error {
 			trx.Data = decryptData
 		}
 
+		//owner trxs of an admin group without enough admin signatures are saved but not applied
+		if err := checkAdminTrx(producer.grpItem, trx); err != nil {
+			trx.Data = originalData
+			dbMgr.AddTrx(trx, producer.nodename)
+			continue
+		}
+
 		molaproducer_log.Debugf("<%s> apply trx <%s>", producer.groupId, trx.TrxId)
 		//apply trx content
 		switch trx.Type {
 		case chestnutpb.TrxType_POST:
 			molaproducer_log.Debugf("<%s> apply POST trx", producer.groupId)
-			nodectx.GetDbMgr().AddPost(trx, producer.nodename)
+			dbMgr.AddPost(trx, producer.nodename)
 		case chestnutpb.TrxType_AUTH:
 			molaproducer_log.Debugf("<%s> apply AUTH trx", producer.groupId)
-			nodectx.GetDbMgr().UpdateBlkListItem(trx, producer.nodename)
+			dbMgr.UpdateBlkListItem(trx, producer.nodename)
 		case chestnutpb.TrxType_PRODUCER:
 			molaproducer_log.Debugf("<%s> apply PRODUCER trx", producer.groupId)
-			nodectx.GetDbMgr().UpdateProducer(trx, producer.nodename)
-			producer.cIface.UpdProducerList()
-			producer.cIface.CreateConsensus()
+			dbMgr.UpdateProducer(trx, producer.nodename)
+			//producer list is loaded from db, reload it after the trx committed
+			dbMgr.OnCommit(func() {
+				producer.cIface.UpdProducerList()
+				producer.cIface.CreateConsensus()
+			})
 		case chestnutpb.TrxType_ANNOUNCE:
 			molaproducer_log.Debugf("<%s> apply ANNOUNCE trx", producer.groupId)
-			nodectx.GetDbMgr().UpdateAnnounce(trx, producer.nodename)
+			dbMgr.UpdateAnnounce(trx, producer.nodename)
+			applyAnnounceResult(dbMgr, producer.grpItem, trx, producer.nodename)
 		case chestnutpb.TrxType_SCHEMA:
 			molaproducer_log.Debugf("<%s> apply SCHEMA trx", producer.groupId)
-			nodectx.GetDbMgr().UpdateSchema(trx, producer.nodename)
+			dbMgr.UpdateSchema(trx, producer.nodename)
+		case chestnutpb.TrxType_INVITE:
+			molaproducer_log.Debugf("<%s> apply INVITE trx", producer.groupId)
+			applyInviteTrx(dbMgr, producer.grpItem, trx, producer.nodename)
+		case chestnutpb.TrxType_REDEEM:
+			molaproducer_log.Debugf("<%s> apply REDEEM trx", producer.groupId)
+			applyRedeemTrx(dbMgr, producer.grpItem, trx, producer.nodename)
+		case chestnutpb.TrxType_CONFIG:
+			molaproducer_log.Debugf("<%s> apply CONFIG trx", producer.groupId)
+			applyConfigTrx(dbMgr, producer.grpItem, trx, blockHeight, producer.nodename)
+			dbMgr.OnCommit(func() {
+				producer.cIface.UpdConfig()
+			})
 		default:
 			molaproducer_log.Warningf("<%s> unsupported msgType <%s>", producer.groupId, trx.Type)
 		}
@@ -554,7 +634,7 @@ func (producer *MolassesProducer) applyTrxs(trxs []*chestnutpb.Trx) error {
 		trx.Data = originalData
 
 		//save trx to db
-		nodectx.GetDbMgr().AddTrx(trx, producer.nodename)
+		dbMgr.AddTrx(trx, producer.nodename)
 	}
 
 	return nil
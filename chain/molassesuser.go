@@ -2,12 +2,11 @@
 package chain
 
 import (
-	"encoding/hex"
 	"errors"
-	localcrypto "github.com/lixvyang/chestnut/crypto"
 	logging "github.com/ipfs/go-log/v2"
 	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
 	"google.golang.org/protobuf/proto"
 )
 
@@ -48,6 +47,26 @@ func (user *MolassesUser) UpdProducer(item *chestnutpb.ProducerItem) (string, er
 	return user.cIface.GetProducerTrxMgr().SendRegProducerTrx(item)
 }
 
+func (user *MolassesUser) SubmitAdminAction(action *chestnutpb.AdminAction) (string, error) {
+	molauser_log.Debugf("<%s> SubmitAdminAction called", user.groupId)
+	return user.cIface.GetProducerTrxMgr().SendAdminTrx(action)
+}
+
+func (user *MolassesUser) UpdInvite(item *chestnutpb.InviteItem) (string, error) {
+	molauser_log.Debugf("<%s> UpdInvite called", user.groupId)
+	return user.cIface.GetProducerTrxMgr().SendInviteTrx(item)
+}
+
+func (user *MolassesUser) RedeemInvite(item *chestnutpb.RedeemItem) (string, error) {
+	molauser_log.Debugf("<%s> RedeemInvite called", user.groupId)
+	return user.cIface.GetProducerTrxMgr().SendRedeemTrx(item)
+}
+
+func (user *MolassesUser) UpdConfig(item *chestnutpb.ConfigItem) (string, error) {
+	molauser_log.Debugf("<%s> UpdConfig called", user.groupId)
+	return user.cIface.GetProducerTrxMgr().SendConfigTrx(item)
+}
+
 func (user *MolassesUser) PostToGroup(content proto.Message) (string, error) {
 	molauser_log.Debugf("<%s> PostToGroup called", user.groupId)
 	if user.cIface.IsSyncerReady() {
@@ -105,88 +124,137 @@ func (user *MolassesUser) AddBlock(block *chestnutpb.Block) error {
 		return err
 	}
 
-	//valid block with parent block
-	valid, err := IsBlockValid(block, parentBlock)
+	//valid block with parent block, a light node accepts blocks with trx data stripped
+	light := user.cIface.GetLightMode()
+	var valid bool
+	if light != nil {
+		valid, err = IsLightBlockValid(block, parentBlock)
+	} else {
+		valid, err = IsBlockValid(block, parentBlock)
+	}
 	if !valid {
 		molauser_log.Debugf("<%s> remove invalid block <%s> from cache", user.groupId, block.BlockId)
 		molauser_log.Warningf("<%s> invalid block <%s>", user.groupId, err.Error())
 		return nodectx.GetDbMgr().RmBlock(block.BlockId, true, user.nodename)
 	}
 
-	//search cache, gather all blocks can be connected with this block
-	blocks, err := nodectx.GetDbMgr().GatherBlocksFromCache(block, true, user.nodename)
+	//trxs of the block packed with the config of the block height
+	parentHeight, err := nodectx.GetDbMgr().GetBlockHeight(parentBlock.BlockId, user.nodename)
 	if err != nil {
 		return err
 	}
+	if err := checkBlockConfig(block, user.cIface.GetConfigAt(parentHeight+1)); err != nil {
+		molauser_log.Warningf("<%s> invalid block <%s>", user.groupId, err.Error())
+		return nodectx.GetDbMgr().RmBlock(block.BlockId, true, user.nodename)
+	}
 
-	//get all trxs from those blocks
-	var trxs []*chestnutpb.Trx
-	trxs, err = GetAllTrxs(blocks)
+	//apply blocks, trxs and chain info in one transaction
+	txn, err := nodectx.GetDbMgr().BeginTxn()
 	if err != nil {
 		return err
 	}
+	defer txn.Rollback()
 
-	//apply those trxs
-	err = user.applyTrxs(trxs, user.nodename)
+	//search cache, gather all blocks can be connected with this block
+	blocks, err := txn.GatherBlocksFromCache(block, true, user.nodename)
 	if err != nil {
 		return err
 	}
 
+	//a light node keeps the data of own trxs and trxs of the senders followed only
+	if light != nil {
+		var fetchs []*chestnutpb.ReqTrx
+		blocks, fetchs, err = stripLightBlocks(txn, blocks, light, user.grpItem.UserSignPubkey, user.nodename)
+		if err != nil {
+			return err
+		}
+		//fetch the post data of the senders followed after the blocks committed
+		txn.OnCommit(func() {
+			for _, req := range fetchs {
+				if err := user.cIface.GetProducerTrxMgr().SendReqTrx(req.BlockId, req.TrxId); err != nil {
+					molauser_log.Warningf("<%s> fetch trx <%s> failed: %s", user.groupId, req.TrxId, err.Error())
+				}
+			}
+		})
+	}
+
 	//move gathered blocks from cache to chain
 	for _, block := range blocks {
 		molauser_log.Debugf("<%s> move block <%s> from cache to chain", user.groupId, block.BlockId)
-		err := nodectx.GetDbMgr().AddBlock(block, false, user.nodename)
+		err := txn.AddBlock(block, false, user.nodename)
+		if err != nil {
+			return err
+		}
+
+		err = txn.RmBlock(block.BlockId, true, user.nodename)
 		if err != nil {
 			return err
 		}
+	}
 
-		err = nodectx.GetDbMgr().RmBlock(block.BlockId, true, user.nodename)
+	//apply the trxs of those blocks, block by block with the block height
+	for _, block := range blocks {
+		blockHeight, err := txn.GetBlockHeight(block.BlockId, user.nodename)
 		if err != nil {
 			return err
 		}
+		if err := user.applyTrxs(txn, block.Trxs, blockHeight, user.nodename); err != nil {
+			return err
+		}
 	}
 
 	//update block produced count
 	for _, block := range blocks {
-		err := nodectx.GetDbMgr().AddProducedBlockCount(user.groupId, block.ProducerPubKey, user.nodename)
+		err := txn.AddProducedBlockCount(user.groupId, block.ProducerPubKey, user.nodename)
 		if err != nil {
 			return err
 		}
 	}
 
 	//calculate new height
-	molauser_log.Debugf("<%s> height before recal <%d>", user.groupId, user.grpItem.HighestHeight)
-	topBlock, err := nodectx.GetDbMgr().GetBlock(user.grpItem.HighestBlockId, false, user.nodename)
+	highestHeight, highestBlockId := user.cIface.GetChainHead()
+	molauser_log.Debugf("<%s> height before recal <%d>", user.groupId, highestHeight)
+	topBlock, err := txn.GetBlock(highestBlockId, false, user.nodename)
 	if err != nil {
 		return err
 	}
-	newHeight, newHighestBlockId, err := RecalChainHeight(blocks, user.grpItem.HighestHeight, topBlock, user.nodename)
+	newHeight, newHighestBlockId, err := RecalChainHeight(txn, blocks, highestHeight, topBlock, user.nodename)
 	if err != nil {
 		return err
 	}
 	molauser_log.Debugf("<%s> new height <%d>, new highest blockId %v", user.groupId, newHeight, newHighestBlockId)
 
 	//if the new block is not highest block after recalculate, we need to "trim" the chain
-	if newHeight < user.grpItem.HighestHeight {
+	if newHeight < highestHeight {
 
 		//from parent of the new blocks, get all blocks not belong to the longest path
-		resendBlocks, err := GetTrimedBlocks(blocks, user.nodename)
+		resendBlocks, err := GetTrimedBlocks(txn, blocks, user.nodename)
 		if err != nil {
 			return err
 		}
 
 		var resendTrxs []*chestnutpb.Trx
-		resendTrxs, err = GetMyTrxs(resendBlocks, user.nodename, user.grpItem.UserSignPubkey)
+		resendTrxs, err = GetMyTrxs(txn, resendBlocks, user.nodename, user.grpItem.UserSignPubkey)
 
 		if err != nil {
 			return err
 		}
 
-		UpdateResendCount(resendTrxs)
-		err = user.resendTrx(resendTrxs)
+		//resend only after the new blocks committed
+		txn.OnCommit(func() {
+			UpdateResendCount(resendTrxs)
+			user.resendTrx(resendTrxs)
+		})
 	}
 
-	return user.cIface.UpdChainInfo(newHeight, newHighestBlockId)
+	err = user.cIface.UpdChainInfo(newHeight, newHighestBlockId, txn)
+	if err != nil {
+		return err
+	}
+	if err := txn.Commit(); err != nil {
+		return err
+	}
+	return AddCachedChildBlocks(blocks, user.nodename, user.AddBlock)
 }
 
 //resend all trx in the list
@@ -199,19 +267,30 @@ func (user *MolassesUser) resendTrx(trxs []*chestnutpb.Trx) error {
 	return nil
 }
 
-func (user *MolassesUser) applyTrxs(trxs []*chestnutpb.Trx, nodename string) error {
+func (user *MolassesUser) applyTrxs(dbMgr *storage.DbMgr, trxs []*chestnutpb.Trx, blockHeight int64, nodename string) error {
 	molauser_log.Debugf("<%s> applyTrxs called", user.groupId)
 	for _, trx := range trxs {
 		//check if trx already applied
-		isExist, err := nodectx.GetDbMgr().IsTrxExist(trx.TrxId, nodename)
+		isExist, err := dbMgr.IsTrxExist(trx.TrxId, nodename)
 		if err != nil {
 			molauser_log.Debugf("<%s> %s", user.groupId, err.Error())
 			continue
 		}
 
 		if isExist {
+			//keep the data of a trx saved, the trx may be packed again in a block stripped for a light node
+			if len(trx.Data) == 0 {
+				continue
+			}
 			molauser_log.Debugf("<%s> trx <%s> existed, update trx only", user.groupId, trx.TrxId)
-			nodectx.GetDbMgr().AddTrx(trx)
+			dbMgr.AddTrx(trx, nodename)
+			continue
+		}
+
+		//trx data not saved by a light node, saved to fetch the data later
+		if len(trx.Data) == 0 {
+			molauser_log.Debugf("<%s> trx <%s> without data, save trx only", user.groupId, trx.TrxId)
+			dbMgr.AddTrx(trx, nodename)
 			continue
 		}
 
@@ -220,7 +299,7 @@ func (user *MolassesUser) applyTrxs(trxs []*chestnutpb.Trx, nodename string) err
 		//new trx, apply it
 		if trx.Type == chestnutpb.TrxType_POST && user.grpItem.EncryptType == chestnutpb.GroupEncryptType_PRIVATE {
 			//for post, private group, encrypted by pgp for all announced group user
-			ks := localcrypto.GetKeystore()
+			ks := user.cIface.GetKeystore()
 			decryptData, err := ks.Decrypt(user.grpItem.UserEncryptPubkey, trx.Data)
 			if err != nil {
 				return err
@@ -230,12 +309,7 @@ func (user *MolassesUser) applyTrxs(trxs []*chestnutpb.Trx, nodename string) err
 			trx.Data = decryptData
 		} else {
 			//decode trx data
-			ciperKey, err := hex.DecodeString(user.grpItem.CipherKey)
-			if err != nil {
-				return err
-			}
-
-			decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
+			decryptData, err := DecryptGroupData(user.cIface.GetGroupItem(), trx.Data)
 			if err != nil {
 				return err
 			}
@@ -244,26 +318,49 @@ func (user *MolassesUser) applyTrxs(trxs []*chestnutpb.Trx, nodename string) err
 			trx.Data = decryptData
 		}
 
+		//owner trxs of an admin group without enough admin signatures are saved but not applied
+		if err := checkAdminTrx(user.grpItem, trx); err != nil {
+			trx.Data = originalData
+			dbMgr.AddTrx(trx, nodename)
+			continue
+		}
+
 		molauser_log.Debugf("<%s> try apply trx <%s>", user.groupId, trx.TrxId)
 		//apply trx content
 		switch trx.Type {
 		case chestnutpb.TrxType_POST:
 			molauser_log.Debugf("<%s> apply POST trx", user.groupId)
-			nodectx.GetDbMgr().AddPost(trx, nodename)
+			dbMgr.AddPost(trx, nodename)
 		case chestnutpb.TrxType_AUTH:
 			molauser_log.Debugf("<%s> apply AUTH trx", user.groupId)
-			nodectx.GetDbMgr().UpdateBlkListItem(trx, nodename)
+			dbMgr.UpdateBlkListItem(trx, nodename)
 		case chestnutpb.TrxType_PRODUCER:
 			molauser_log.Debugf("<%s> apply PRODUCER trx", user.groupId)
-			nodectx.GetDbMgr().UpdateProducer(trx, nodename)
-			user.cIface.UpdProducerList()
-			user.cIface.CreateConsensus()
+			dbMgr.UpdateProducer(trx, nodename)
+			//producer list is loaded from db, reload it after the trx committed
+			dbMgr.OnCommit(func() {
+				user.cIface.UpdProducerList()
+				user.cIface.CreateConsensus()
+			})
 		case chestnutpb.TrxType_ANNOUNCE:
 			molauser_log.Debugf("<%s> apply ANNOUNCE trx", user.groupId)
-			nodectx.GetDbMgr().UpdateAnnounce(trx, nodename)
+			dbMgr.UpdateAnnounce(trx, nodename)
+			applyAnnounceResult(dbMgr, user.grpItem, trx, nodename)
 		case chestnutpb.TrxType_SCHEMA:
 			molauser_log.Debugf("<%s> apply SCHEMA trx", user.groupId)
-			nodectx.GetDbMgr().UpdateSchema(trx, nodename)
+			dbMgr.UpdateSchema(trx, nodename)
+		case chestnutpb.TrxType_INVITE:
+			molauser_log.Debugf("<%s> apply INVITE trx", user.groupId)
+			applyInviteTrx(dbMgr, user.grpItem, trx, nodename)
+		case chestnutpb.TrxType_REDEEM:
+			molauser_log.Debugf("<%s> apply REDEEM trx", user.groupId)
+			applyRedeemTrx(dbMgr, user.grpItem, trx, nodename)
+		case chestnutpb.TrxType_CONFIG:
+			molauser_log.Debugf("<%s> apply CONFIG trx", user.groupId)
+			applyConfigTrx(dbMgr, user.grpItem, trx, blockHeight, nodename)
+			dbMgr.OnCommit(func() {
+				user.cIface.UpdConfig()
+			})
 		default:
 			molauser_log.Warningf("<%s> unsupported msgType <%s>", user.groupId, trx.Type)
 		}
@@ -272,7 +369,7 @@ func (user *MolassesUser) applyTrxs(trxs []*chestnutpb.Trx, nodename string) err
 		trx.Data = originalData
 
 		//save trx to db
-		nodectx.GetDbMgr().AddTrx(trx, nodename)
+		dbMgr.AddTrx(trx, nodename)
 	}
 
 	return nil
@@ -4,13 +4,13 @@ package chain
 import (
 	"bytes"
 
-	"github.com/lixvyang/chestnut/nodectx"
-
 	logging "github.com/ipfs/go-log/v2"
 
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 
 	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/nodectx"
+	"github.com/lixvyang/chestnut/storage"
 )
 
 var molautil_log = logging.Logger("util")
@@ -20,7 +20,7 @@ func Hash(data []byte) []byte {
 }
 
 //find the highest block from the block tree
-func RecalChainHeight(blocks []*chestnutpb.Block, currentHeight int64, currentHighestBlock *chestnutpb.Block, nodename string) (int64, string, error) {
+func RecalChainHeight(dbMgr *storage.DbMgr, blocks []*chestnutpb.Block, currentHeight int64, currentHighestBlock *chestnutpb.Block, nodename string) (int64, string, error) {
 	molautil_log.Debug("RecalChainHeight called")
 
 	newHighestHeight := currentHeight
@@ -28,7 +28,7 @@ func RecalChainHeight(blocks []*chestnutpb.Block, currentHeight int64, currentHi
 	newHighestBlock := currentHighestBlock
 
 	for _, block := range blocks {
-		blockHeight, err := nodectx.GetDbMgr().GetBlockHeight(block.BlockId, nodename)
+		blockHeight, err := dbMgr.GetBlockHeight(block.BlockId, nodename)
 		if err != nil {
 			return -1, "INVALID_BLOCK_ID", err
 		}
@@ -50,7 +50,7 @@ func RecalChainHeight(blocks []*chestnutpb.Block, currentHeight int64, currentHi
 }
 
 //from root of the new block tree, get all blocks trimed when not belong to longest path
-func GetTrimedBlocks(blocks []*chestnutpb.Block, nodename string) ([]string, error) {
+func GetTrimedBlocks(dbMgr *storage.DbMgr, blocks []*chestnutpb.Block, nodename string) ([]string, error) {
 	molautil_log.Debug("GetTrimedBlocks called")
 	var cache map[string]bool
 	var longestPath []string
@@ -58,7 +58,7 @@ func GetTrimedBlocks(blocks []*chestnutpb.Block, nodename string) ([]string, err
 
 	cache = make(map[string]bool)
 
-	err := dfs(blocks, cache, longestPath, nodename)
+	err := dfs(dbMgr, blocks, cache, longestPath, nodename)
 
 	for _, blockId := range longestPath {
 		if _, ok := cache[blockId]; !ok {
@@ -69,17 +69,17 @@ func GetTrimedBlocks(blocks []*chestnutpb.Block, nodename string) ([]string, err
 	return result, err
 }
 
-func dfs(blocks []*chestnutpb.Block, cache map[string]bool, result []string, nodename string) error {
+func dfs(dbMgr *storage.DbMgr, blocks []*chestnutpb.Block, cache map[string]bool, result []string, nodename string) error {
 	molautil_log.Debug("dfs called")
 	for _, block := range blocks {
 		if _, ok := cache[block.BlockId]; !ok {
 			cache[block.BlockId] = true
 			result = append(result, block.BlockId)
-			subBlocks, err := nodectx.GetDbMgr().GetSubBlock(block.BlockId, nodename)
+			subBlocks, err := dbMgr.GetSubBlock(block.BlockId, nodename)
 			if err != nil {
 				return err
 			}
-			err = dfs(subBlocks, cache, result, nodename)
+			err = dfs(dbMgr, subBlocks, cache, result, nodename)
 		}
 	}
 	return nil
@@ -87,12 +87,12 @@ func dfs(blocks []*chestnutpb.Block, cache map[string]bool, result []string, nod
 
 
 //get all trx belongs to me from the block list
-func GetMyTrxs(blockIds []string, nodename string, userSignPubkey string) ([]*chestnutpb.Trx, error) {
+func GetMyTrxs(dbMgr *storage.DbMgr, blockIds []string, nodename string, userSignPubkey string) ([]*chestnutpb.Trx, error) {
 	molautil_log.Debug("GetMyTrxs called")
 	var trxs []*chestnutpb.Trx
 
 	for _, blockId := range blockIds {
-		block, err := nodectx.GetDbMgr().GetBlock(blockId, false, nodename)
+		block, err := dbMgr.GetBlock(blockId, false, nodename)
 		if err != nil {
 			chain_log.Warnf(err.Error())
 			continue
@@ -107,6 +107,22 @@ func GetMyTrxs(blockIds []string, nodename string, userSignPubkey string) ([]*ch
 	return trxs, nil
 }
 
+// add the cached blocks left by GatherBlocksFromCache when it reaches storage.MAX_GATHER_BYTES,
+// each child is added in its own transaction with the blocks gathered from it
+func AddCachedChildBlocks(blocks []*chestnutpb.Block, nodename string, addBlock func(block *chestnutpb.Block) error) error {
+	children, err := nodectx.GetDbMgr().GetCachedChildBlocks(blocks, nodename)
+	if err != nil {
+		return err
+	}
+	for _, child := range children {
+		molautil_log.Debugf("add cached block <%s> left by the last transaction", child.BlockId)
+		if err := addBlock(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // get all trx from the block list
 func GetAllTrxs(blocks []*chestnutpb.Block) ([]*chestnutpb.Trx, error) {
 	molautil_log.Debug("GetAllTrxs called")
@@ -13,4 +13,5 @@ type Producer interface {
 	GetBlockBackward(trx *chestnutpb.Trx) error
 	AddProducedBlock(trx *chestnutpb.Trx) error
 	AddBlock(block *chestnutpb.Block) error
+	Stop()
 }
\ No newline at end of file
@@ -0,0 +1,131 @@
+// Package chain provides chain for chestnut.
+package chain
+
+import (
+	"sync"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"github.com/lixvyang/chestnut/nodectx"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+)
+
+var pruner_log = logging.Logger("pruner")
+
+const PRUNE_INTERVAL = 1 * time.Hour
+
+type PruneResult struct {
+	GroupId   string `json:"group_id"`
+	Blocks    int64  `json:"blocks"`
+	Bytes     int64  `json:"bytes"`
+	TimeStamp int64  `json:"timestamp"`
+}
+
+// Pruner removes trx data of old blocks by the retention policy of each group,
+// then runs the storage GC
+type Pruner struct {
+	mu      sync.RWMutex
+	results map[string]*PruneResult
+	gcBytes int64
+	stop    chan struct{}
+}
+
+var pruner *Pruner
+
+func StartPruner(interval time.Duration) *Pruner {
+	pruner = &Pruner{results: make(map[string]*PruneResult), stop: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				pruner.RunOnce()
+			case <-pruner.stop:
+				pruner_log.Info("pruner stopped")
+				return
+			}
+		}
+	}()
+	return pruner
+}
+
+func GetPruner() *Pruner {
+	return pruner
+}
+
+func (p *Pruner) Stop() {
+	close(p.stop)
+}
+
+func (p *Pruner) RunOnce() {
+	dbMgr := nodectx.GetDbMgr()
+	var total int64
+	for _, groupmgr := range ListGroupMgrs() {
+		for _, grp := range groupmgr.List() {
+			total += p.pruneGroup(dbMgr, groupmgr, grp)
+		}
+	}
+
+	gcBytes, err := dbMgr.RunGC()
+	if err != nil {
+		pruner_log.Warningf("storage GC failed: %s", err.Error())
+	}
+	p.mu.Lock()
+	p.gcBytes = gcBytes
+	p.mu.Unlock()
+	pruner_log.Infof("prune done, <%d> bytes removed, <%d> bytes reclaimed by GC", total, gcBytes)
+}
+
+// remove the trx data of a block pruned by the retention policy, the leaf hashes are kept to verify
+// the block by its trx root. Blocks without a trx root are kept as is, the block hash covers the trx data
+func PruneBlockData(block *chestnutpb.Block) (*chestnutpb.Block, error) {
+	return StripBlock(block, func(trx *chestnutpb.Trx) bool { return false })
+}
+
+// prune a group by its retention policy, the app indexes of the pruned posts are removed
+// by the prune listeners of the group manager. Returns the bytes removed.
+func (p *Pruner) pruneGroup(dbMgr *storage.DbMgr, groupmgr *GroupMgr, grp *Group) int64 {
+	item := grp.ItemSnapshot()
+	groupId := item.GroupId
+	policy, err := dbMgr.GetRetention(groupId, grp.ChainCtx.nodename)
+	if err != nil {
+		pruner_log.Warningf("<%s> get retention policy failed: %s", groupId, err.Error())
+		return 0
+	}
+	if policy == nil {
+		return 0
+	}
+
+	blocks, bytes, trxIds, err := dbMgr.PruneGroup(item, policy, PruneBlockData, grp.ChainCtx.nodename)
+	if err != nil {
+		pruner_log.Warningf("<%s> prune failed: %s", groupId, err.Error())
+	}
+	if blocks > 0 {
+		pruner_log.Infof("<%s> pruned <%d> blocks, <%d> bytes", groupId, blocks, bytes)
+	}
+	//blocks pruned before an error are committed, their posts are removed from the indexes too
+	if len(trxIds) > 0 {
+		groupmgr.notifyPruned(groupId, trxIds)
+	}
+
+	p.mu.Lock()
+	p.results[groupId] = &PruneResult{GroupId: groupId, Blocks: blocks, Bytes: bytes, TimeStamp: time.Now().UnixNano()}
+	p.mu.Unlock()
+	return bytes
+}
+
+// result of the last prune of group, nil if group not pruned yet
+func (p *Pruner) GetResult(groupId string) *PruneResult {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.results[groupId]
+}
+
+// bytes reclaimed by the last storage GC
+func (p *Pruner) GetGCBytes() int64 {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.gcBytes
+}
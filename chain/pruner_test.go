@@ -0,0 +1,109 @@
+package chain
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+)
+
+// a chain of blocks with one trx each, with trx roots unless legacy
+func newTestChain(t *testing.T, dbMgr *storage.DbMgr, groupId string, count int, legacy bool) *chestnutpb.GroupItem {
+	genesis := &chestnutpb.Block{GroupId: groupId, TimeStamp: 1}
+	if !legacy {
+		genesis.TrxRoot = MerkleRoot(nil)
+	}
+	hashBlock(t, genesis, legacy)
+	if err := dbMgr.AddGensisBlock(genesis, "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	parent := genesis
+	for i := 1; i <= count; i++ {
+		trx := &chestnutpb.Trx{TrxId: fmt.Sprintf("%s_trx%d", groupId, i), GroupId: groupId, Type: chestnutpb.TrxType_POST, Data: []byte("post data"), TimeStamp: int64(i)}
+		block := &chestnutpb.Block{GroupId: groupId, PrevBlockId: parent.BlockId, PreviousHash: parent.Hash, Trxs: []*chestnutpb.Trx{trx}, TimeStamp: int64(i + 1)}
+		if !legacy {
+			root, _, err := TrxRoot(block.Trxs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			block.TrxRoot = root
+		}
+		hashBlock(t, block, legacy)
+		if err := dbMgr.AddBlock(block, false, "test"); err != nil {
+			t.Fatal(err)
+		}
+		if err := dbMgr.AddTrx(trx, "test"); err != nil {
+			t.Fatal(err)
+		}
+		parent = block
+	}
+	return &chestnutpb.GroupItem{GroupId: groupId, GenesisBlock: genesis, HighestBlockId: parent.BlockId, HighestHeight: int64(count)}
+}
+
+func hashBlock(t *testing.T, block *chestnutpb.Block, legacy bool) {
+	if legacy {
+		block.BlockId = fmt.Sprintf("%s_%d", block.GroupId, block.TimeStamp)
+	}
+	hash, err := blockHash(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block.Hash = hash
+	if !legacy {
+		block.BlockId = BlockIdFromHash(hash)
+	}
+}
+
+// pruned blocks keep a valid hash and trx root, the blocks near the head are kept even if only
+// headers are kept by the policy
+func TestPruneGroupHeadersOnly(t *testing.T) {
+	const blockCount = storage.PRUNE_MIN_KEEP_BLOCKS + 20
+	for _, legacy := range []bool{false, true} {
+		t.Run(fmt.Sprintf("legacy=%v", legacy), func(t *testing.T) {
+			db := &storage.CSMemory{}
+			if err := db.Init(""); err != nil {
+				t.Fatal(err)
+			}
+			dbMgr := &storage.DbMgr{GroupInfoDb: db, Db: db}
+			item := newTestChain(t, dbMgr, "group", int(blockCount), legacy)
+
+			_, _, trxIds, err := dbMgr.PruneGroup(item, &chestnutpb.RetentionItem{GroupId: "group", HeadersOnly: true}, PruneBlockData, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(trxIds) != 20 {
+				t.Fatalf("%d posts pruned, want 20", len(trxIds))
+			}
+
+			blockId := item.HighestBlockId
+			for height := blockCount; height > 0; height-- {
+				block, err := dbMgr.GetBlock(blockId, false, "test")
+				if err != nil {
+					t.Fatal(err)
+				}
+				hash, err := blockHash(block)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !bytes.Equal(hash, block.Hash) {
+					t.Fatalf("block at height %d: hash mismatch after prune", height)
+				}
+				if err := CheckTrxRoot(block); err != nil {
+					t.Fatalf("block at height %d: %s", height, err)
+				}
+				pruned, err := dbMgr.IsBlockPruned(blockId, "test")
+				if err != nil {
+					t.Fatal(err)
+				}
+				wantPruned := !legacy && height <= blockCount-storage.PRUNE_MIN_KEEP_BLOCKS
+				if pruned != wantPruned || (len(block.Trxs[0].Data) == 0) != wantPruned {
+					t.Fatalf("block at height %d: pruned %v, data %q", height, pruned, block.Trxs[0].Data)
+				}
+				blockId = block.PrevBlockId
+			}
+		})
+	}
+}
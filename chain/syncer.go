@@ -4,6 +4,7 @@ package chain
 import (
 	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	logging "github.com/ipfs/go-log/v2"
@@ -29,7 +30,8 @@ type Syncer struct {
 	trxMgr           *TrxMgr
 	AskNextTimer     *time.Timer
 	AskNextTimeDone  chan bool
-	Status           int8
+	statusmu         sync.RWMutex
+	status           int8
 	retryCount       int8
 	statusBeforeFail int8
 	responses        map[string]*chestnutpb.ReqBlockResp
@@ -38,8 +40,7 @@ type Syncer struct {
 
 func (syncer *Syncer) Init(grp *Group, trxMgr *TrxMgr) {
 	syncer_log.Debug("Init called")
-	syncer.Status = IDLE
-	syncer.Status = IDLE
+	syncer.setStatus(IDLE)
 	syncer.group = grp
 	syncer.trxMgr = trxMgr
 	syncer.retryCount = 0
@@ -48,6 +49,19 @@ func (syncer *Syncer) Init(grp *Group, trxMgr *TrxMgr) {
 	syncer_log.Infof("<%s> syncer initialed", syncer.groupId)
 }
 
+// sync status, read by the api and the group manager while the syncer runs
+func (syncer *Syncer) GetStatus() int8 {
+	syncer.statusmu.RLock()
+	defer syncer.statusmu.RUnlock()
+	return syncer.status
+}
+
+func (syncer *Syncer) setStatus(status int8) {
+	syncer.statusmu.Lock()
+	defer syncer.statusmu.Unlock()
+	syncer.status = status
+}
+
 // sync block "forward"
 func (syncer *Syncer) SyncForward(block *chestnutpb.Block) error {
 	syncer_log.Debugf("<%s> SyncForward called", syncer.group.Item.GroupId)
@@ -63,12 +77,12 @@ func (syncer *Syncer) SyncForward(block *chestnutpb.Block) error {
 	} else if _, ok := syncer.group.ChainCtx.ProducerPool[syncer.group.Item.UserSignPubkey]; ok {
 		syncer_log.Debugf("<%s> producer, no need to sync forward (sync backward when new block produced and found missing block(s)", syncer.groupId)
 		return errors.New("producer, no need to sync forward (sync backward when new block produced and found missing block(s)")
-	} else if syncer.Status == SYNCING_FORWARD || syncer.Status == SYNCING_BACKWARD {
+	} else if syncer.GetStatus() == SYNCING_FORWARD || syncer.GetStatus() == SYNCING_BACKWARD {
 		return errors.New("already in SYNCING")
 	}
 
 	syncer_log.Debugf("<%s> try sync forward from block <%s>", syncer.groupId, block.BlockId)
-	syncer.Status = SYNCING_FORWARD
+	syncer.setStatus(SYNCING_FORWARD)
 	syncer.askNextBlock(block)
 	syncer.waitBlock(block)
 	return nil
@@ -85,11 +99,11 @@ func (syncer *Syncer) SyncBackward(block *chestnutpb.Block) error {
 		return nil
 	}
 
-	if syncer.Status == SYNCING_FORWARD || syncer.Status == SYNCING_BACKWARD {
+	if syncer.GetStatus() == SYNCING_FORWARD || syncer.GetStatus() == SYNCING_BACKWARD {
 		return errors.New("already in SYNCING")
 	}
 
-	syncer.Status = SYNCING_BACKWARD
+	syncer.setStatus(SYNCING_BACKWARD)
 	syncer.askPreviousBlock(block)
 	syncer.waitBlock(block)
 	return nil
@@ -98,7 +112,7 @@ func (syncer *Syncer) SyncBackward(block *chestnutpb.Block) error {
 func (syncer *Syncer) StopSync() error {
 	syncer_log.Debugf("<%s> StopSync called", syncer.groupId)
 	syncer.stopWaitBlock()
-	syncer.Status = IDLE
+	syncer.setStatus(IDLE)
 	syncer_log.Debugf("<%s> sync stopped", syncer.groupId)
 	return nil
 }
@@ -107,13 +121,13 @@ func (syncer *Syncer) ContinueSync(block *chestnutpb.Block) error {
 	syncer_log.Debugf("<%s> ContinueSync called", syncer.groupId)
 	syncer.stopWaitBlock()
 
-	if syncer.Status == SYNCING_FORWARD {
+	if syncer.GetStatus() == SYNCING_FORWARD {
 		syncer.askNextBlock(block)
 		syncer.waitBlock(block)
-	} else if syncer.Status == SYNCING_BACKWARD {
+	} else if syncer.GetStatus() == SYNCING_BACKWARD {
 		syncer.askPreviousBlock(block)
 		syncer.waitBlock(block)
-	} else if syncer.Status == SYNC_FAILED {
+	} else if syncer.GetStatus() == SYNC_FAILED {
 		syncer_log.Debugf("<%s> TBD, Sync faileld, should manually start sync", syncer.groupId)
 	} else {
 		// IDLE
@@ -124,7 +138,7 @@ func (syncer *Syncer) ContinueSync(block *chestnutpb.Block) error {
 
 func (syncer *Syncer) AddBlockSynced(resp *chestnutpb.ReqBlockResp, block *chestnutpb.Block) error {
 	syncer_log.Debugf("<%s> AddBlockSynced called", syncer.groupId)
-	if !(syncer.Status == SYNCING_FORWARD || syncer.Status == SYNCING_BACKWARD) {
+	if !(syncer.GetStatus() == SYNCING_FORWARD || syncer.GetStatus() == SYNCING_BACKWARD) {
 		syncer_log.Warningf("<%s> Not in syncing, ignore block", syncer.groupId)
 		return nil
 	}
@@ -140,7 +154,7 @@ func (syncer *Syncer) AddBlockSynced(resp *chestnutpb.ReqBlockResp, block *chest
 
 	_, producer := syncer.group.ChainCtx.ProducerPool[syncer.group.Item.UserSignPubkey]
 
-	if syncer.Status == SYNCING_FORWARD {
+	if syncer.GetStatus() == SYNCING_FORWARD {
 		if producer {
 			syncer_log.Debugf("<%s> SYNCING_FORWARD, PRODUCER ADD BLOCK", syncer.groupId)
 			err := syncer.group.ChainCtx.Consensus.Producer().AddBlock(block)
@@ -182,7 +196,7 @@ func (syncer *Syncer) askNextBlock(block *chestnutpb.Block)  {
 	// reset recived response
 	syncer.responses = make(map[string]*chestnutpb.ReqBlockResp)
 	// send ask block forward msg out
-	syncer.trxMgr.SendReqBlockForward(block)
+	syncer.trxMgr.SendReqBlockForward(block, syncer.group.ChainCtx.GetLightMode())
 }
 
 func (syncer *Syncer) askPreviousBlock(block *chestnutpb.Block) {
@@ -191,7 +205,7 @@ func (syncer *Syncer) askPreviousBlock(block *chestnutpb.Block) {
 	//reset received response
 	syncer.responses = make(map[string]*chestnutpb.ReqBlockResp)
 	//send ask block backward msg out
-	syncer.trxMgr.SendReqBlockBackward(block)
+	syncer.trxMgr.SendReqBlockBackward(block, syncer.group.ChainCtx.GetLightMode())
 }
 
 func (syncer *Syncer) waitBlock(block *chestnutpb.Block) {
@@ -212,21 +226,21 @@ func (syncer *Syncer) waitBlock(block *chestnutpb.Block) {
 					if syncer.retryCount == int8(RETRY_LIMIT) {
 						syncer_log.Debugf("<%s> reach retry limit <%d>, SYNC FAILED, check network connection", syncer.groupId, RETRY_LIMIT)
 						//save syncer status
-						syncer.statusBeforeFail = syncer.Status
-						syncer.Status = SYNC_FAILED
+						syncer.statusBeforeFail = syncer.GetStatus()
+						syncer.setStatus(SYNC_FAILED)
 						return
 					}
-					if syncer.Status == SYNCING_FORWARD {
+					if syncer.GetStatus() == SYNCING_FORWARD {
 						syncer.askNextBlock(block)
 						syncer.waitBlock(block)
-					} else if syncer.Status == SYNCING_BACKWARD {
+					} else if syncer.GetStatus() == SYNCING_BACKWARD {
 						syncer.askPreviousBlock(block)
 						syncer.waitBlock(block)
 					}
 					//syncer.ShowChainStruct()
 				} else {
 					syncer_log.Debugf("<%s> received <%d> BLOCK_NOT_FOUND resp, sync done, set to IDLE", syncer.groupId, len(syncer.responses))
-					syncer.Status = IDLE
+					syncer.setStatus(IDLE)
 				}
 			}
 		}
@@ -262,7 +276,8 @@ func (syncer *Syncer) ShowChainStruct() {
 	syncer_log.Debugf("<%s> ShowChainStruct called", syncer.groupId)
 	genesisblkid := syncer.group.ChainCtx.group.Item.GenesisBlock.BlockId
 
-	chainstruct, err := syncer.GetBlockToGenesis(syncer.group.Item.HighestBlockId, genesisblkid)
+	_, highestBlockId := syncer.group.ChainHead()
+	chainstruct, err := syncer.GetBlockToGenesis(highestBlockId, genesisblkid)
 	if err != nil {
 		syncer_log.Errorf("<%s> ChainStruct genesis <%s> err <%s>", syncer.groupId, genesisblkid, err)
 	} else {
@@ -4,7 +4,6 @@ package chain
 import (
 	"encoding/binary"
 	"encoding/hex"
-	"errors"
 	"fmt"
 	"time"
 
@@ -18,14 +17,6 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-const (
-	Hours = 0
-	Mins  = 5
-	Sec   = 0
-)
-
-const OBJECT_SIZE_LIMIT = 200 * 1024 //(200Kb)
-
 var trxmgr_log = logging.Logger("trxmgr")
 
 type TrxMgr struct {
@@ -33,18 +24,47 @@ type TrxMgr struct {
 	groupItem *chestnutpb.GroupItem
 	psconn pubsubconn.PubSubConn
 	groupId string
+	keystore localcrypto.Keystore
+	config func() *chestnutpb.GroupConfig
+	item func() *chestnutpb.GroupItem
 }
 
-func (trxMgr *TrxMgr) Init(groupItem *chestnutpb.GroupItem, psconn pubsubconn.PubSubConn) {
+func (trxMgr *TrxMgr) Init(groupItem *chestnutpb.GroupItem, psconn pubsubconn.PubSubConn, ks localcrypto.Keystore) {
 	trxMgr.groupItem = groupItem
 	trxMgr.psconn = psconn
+	trxMgr.keystore = ks
 	trxMgr.groupId = groupItem.GroupId
 }
 
+// the node name the group data is saved under, the tenant name for groups of a tenant
 func (trxMgr *TrxMgr) SetNodeName(nodename string) {
 	trxMgr.nodename = nodename
 }
 
+// the group config the trx expiry and object size limit are read from
+func (trxMgr *TrxMgr) SetConfig(config func() *chestnutpb.GroupConfig) {
+	trxMgr.config = config
+}
+
+// the group item the cipher key is read from, a copy is read while blocks are committed
+func (trxMgr *TrxMgr) SetGroupItem(item func() *chestnutpb.GroupItem) {
+	trxMgr.item = item
+}
+
+func (trxMgr *TrxMgr) getGroupItem() *chestnutpb.GroupItem {
+	if trxMgr.item == nil {
+		return trxMgr.groupItem
+	}
+	return trxMgr.item()
+}
+
+func (trxMgr *TrxMgr) getConfig() *chestnutpb.GroupConfig {
+	if trxMgr.config == nil {
+		return WithDefaults(nil)
+	}
+	return trxMgr.config()
+}
+
 func (trxMgr *TrxMgr) CreateTrxWithoutSign(msgType chestnutpb.TrxType, data []byte) (*chestnutpb.Trx, []byte, error) {
 	var trx chestnutpb.Trx
 
@@ -58,8 +78,10 @@ func (trxMgr *TrxMgr) CreateTrxWithoutSign(msgType chestnutpb.TrxType, data []by
 
 	if msgType == chestnutpb.TrxType_POST && trxMgr.groupItem.EncryptType == chestnutpb.GroupEncryptType_PRIVATE {
 		//for post, private group, encrypted by age for all announced group users
-		var err error
-		announcedUser, err := nodectx.GetDbMgr().GetAnnouncedUsersByGroup(trxMgr.groupItem.GroupId)
+		announcedUser, err := nodectx.GetDbMgr().GetAnnouncedUsersByGroup(trxMgr.groupItem.GroupId, trxMgr.nodename)
+		if err != nil {
+			return &trx, []byte(""), err
+		}
 
 		var pubkeys []string
 		for _, item := range announcedUser {
@@ -68,18 +90,22 @@ func (trxMgr *TrxMgr) CreateTrxWithoutSign(msgType chestnutpb.TrxType, data []by
 			}
 		}
 
-		ks := localcrypto.GetKeystore()
-		encryptdData, err = ks.EncryptTo(pubkeys, data)
+		encryptdData, err = trxMgr.keystore.EncryptTo(pubkeys, data)
 		if err != nil {
 			return &trx, []byte(""), err
 		}
 	} else {
 		var err error
-		ciperKey, err := hex.DecodeString(trxMgr.groupItem.CipherKey)
+		groupItem := trxMgr.getGroupItem()
+		ciperKey, err := hex.DecodeString(groupItem.CipherKey)
 		if err != nil {
 			return &trx, []byte(""), err
 		}
-		encryptdData, err = localcrypto.AesEncrypt(data, ciperKey)
+		cipherAlg, err := localcrypto.ParseCipherAlg(groupItem.CipherAlg)
+		if err != nil {
+			return &trx, []byte(""), err
+		}
+		encryptdData, err = localcrypto.SealEnvelope(data, ciperKey, cipherAlg, groupItem.CipherKeyEpoch)
 		if err != nil {
 			return &trx, []byte(""), err
 		}
@@ -88,9 +114,7 @@ func (trxMgr *TrxMgr) CreateTrxWithoutSign(msgType chestnutpb.TrxType, data []by
 
 	trx.TimeStamp = time.Now().UnixNano()
 	trx.Version = nodectx.GetNodeCtx().Version
-	timein := time.Now().Local().Add(time.Hour*time.Duration(Hours) +
-		time.Minute*time.Duration(Mins) +
-		time.Second*time.Duration(Sec))
+	timein := time.Now().Local().Add(time.Second * time.Duration(trxMgr.getConfig().TrxExpire))
 	trx.Expired = timein.UnixNano()
 
 	bytes, err := proto.Marshal(&trx)
@@ -107,12 +131,7 @@ func (trxMgr *TrxMgr) CreateTrx(msgType chestnutpb.TrxType, data []byte) (*chest
 		return trx, err
 	}	
 
-	ks := nodectx.GetNodeCtx().Keystore
-	keyname := trxMgr.groupItem.GroupId
-	if trxMgr.nodename != "" {
-		keyname = fmt.Sprintf("%s_%s", trxMgr.nodename, trxMgr.groupItem.GroupId)
-	}
-	signature, err := ks.SignByKeyName(keyname, hashed)
+	signature, err := trxMgr.signTrx(hashed)
 	if err != nil {
 		return trx, err
 	}
@@ -120,6 +139,11 @@ func (trxMgr *TrxMgr) CreateTrx(msgType chestnutpb.TrxType, data []byte) (*chest
 	return trx, nil
 }
 
+// the sign key is saved by group id in the keystore of the tenant hosting the group
+func (trxMgr *TrxMgr) signTrx(hashed []byte) ([]byte, error) {
+	return trxMgr.keystore.SignByKeyName(trxMgr.groupItem.GroupId, hashed)
+}
+
 
 func (trxMgr *TrxMgr) VerifyTrx(trx *chestnutpb.Trx) (bool, error) {
 	//clone trxMsg to verify
@@ -221,6 +245,90 @@ func (trxMgr *TrxMgr) SendUpdSchemaTrx(item *chestnutpb.SchemaItem) (string, err
 }
 
 
+func (trxMgr *TrxMgr) SendInviteTrx(item *chestnutpb.InviteItem) (string, error) {
+	trxmgr_log.Debugf("<%s> SendInviteTrx called", trxMgr.groupId)
+	encodedcontent, err := proto.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+
+	trx, err := trxMgr.CreateTrx(chestnutpb.TrxType_INVITE, encodedcontent)
+	if err != nil {
+		return "INVALID_TRX", err
+	}
+	err = trxMgr.sendTrx(trx)
+	if err != nil {
+		return "INVALID_TRX", err
+	}
+
+	return trx.TrxId, nil
+}
+
+func (trxMgr *TrxMgr) SendConfigTrx(item *chestnutpb.ConfigItem) (string, error) {
+	trxmgr_log.Debugf("<%s> SendConfigTrx called", trxMgr.groupId)
+	encodedcontent, err := proto.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+
+	trx, err := trxMgr.CreateTrx(chestnutpb.TrxType_CONFIG, encodedcontent)
+	if err != nil {
+		return "INVALID_TRX", err
+	}
+	err = trxMgr.sendTrx(trx)
+	if err != nil {
+		return "INVALID_TRX", err
+	}
+
+	return trx.TrxId, nil
+}
+
+func (trxMgr *TrxMgr) SendRedeemTrx(item *chestnutpb.RedeemItem) (string, error) {
+	trxmgr_log.Debugf("<%s> SendRedeemTrx called", trxMgr.groupId)
+	encodedcontent, err := proto.Marshal(item)
+	if err != nil {
+		return "", err
+	}
+
+	trx, err := trxMgr.CreateTrx(chestnutpb.TrxType_REDEEM, encodedcontent)
+	if err != nil {
+		return "INVALID_TRX", err
+	}
+	err = trxMgr.sendTrx(trx)
+	if err != nil {
+		return "INVALID_TRX", err
+	}
+
+	return trx.TrxId, nil
+}
+
+// send an owner trx signed by the admins, the action id is used as the trx id
+func (trxMgr *TrxMgr) SendAdminTrx(action *chestnutpb.AdminAction) (string, error) {
+	trxmgr_log.Debugf("<%s> SendAdminTrx called", trxMgr.groupId)
+	trx, _, err := trxMgr.CreateTrxWithoutSign(action.Type, action.Data)
+	if err != nil {
+		return "", err
+	}
+	trx.TrxId = action.ActionId
+
+	bytes, err := proto.Marshal(trx)
+	if err != nil {
+		return "", err
+	}
+	signature, err := trxMgr.signTrx(localcrypto.Hash(bytes))
+	if err != nil {
+		return "", err
+	}
+	trx.SenderSign = signature
+	trx.AdminSigns = action.Signs
+
+	err = trxMgr.sendTrx(trx)
+	if err != nil {
+		return "INVALID_TRX", err
+	}
+	return trx.TrxId, nil
+}
+
 func (trxMgr *TrxMgr) SendReqBlockResp(req *chestnutpb.ReqBlock, block *chestnutpb.Block, result chestnutpb.ReqBlkResult) error {
 	trxmgr_log.Debugf("<%s> SendReqBlockResp called", trxMgr.groupId)
 
@@ -253,13 +361,16 @@ func (trxMgr *TrxMgr) SendReqBlockResp(req *chestnutpb.ReqBlock, block *chestnut
 }
 
 
-func (trxMgr *TrxMgr) SendReqBlockForward(block *chestnutpb.Block) error {
+func (trxMgr *TrxMgr) SendReqBlockForward(block *chestnutpb.Block, light *chestnutpb.LightModeItem) error {
 	trxmgr_log.Debugf("<%s> SendReqBlockForward called", trxMgr.groupId)
 
 	var reqBlockItem chestnutpb.ReqBlock
 	reqBlockItem.BlockId = block.BlockId
 	reqBlockItem.GroupId = block.GroupId
 	reqBlockItem.UserId = trxMgr.groupItem.UserSignPubkey
+	if light != nil {
+		reqBlockItem.Light = true
+	}
 
 	bItemBytes, err := proto.Marshal(&reqBlockItem)
 	if err != nil {
@@ -276,13 +387,16 @@ func (trxMgr *TrxMgr) SendReqBlockForward(block *chestnutpb.Block) error {
 	return trxMgr.sendTrx(trx)
 }
 
-func (trxMgr *TrxMgr) SendReqBlockBackward(block *chestnutpb.Block) error {
+func (trxMgr *TrxMgr) SendReqBlockBackward(block *chestnutpb.Block, light *chestnutpb.LightModeItem) error {
 	trxmgr_log.Debugf("<%s> SendReqBlockBackward called", trxMgr.groupId)
 
 	var reqBlockItem chestnutpb.ReqBlock
 	reqBlockItem.BlockId = block.BlockId
 	reqBlockItem.GroupId = block.GroupId
 	reqBlockItem.UserId = trxMgr.groupItem.UserSignPubkey
+	if light != nil {
+		reqBlockItem.Light = true
+	}
 
 	bItemBytes, err := proto.Marshal(&reqBlockItem)
 	if err != nil {
@@ -299,6 +413,59 @@ func (trxMgr *TrxMgr) SendReqBlockBackward(block *chestnutpb.Block) error {
 	return trxMgr.sendTrx(trx)
 }
 
+func (trxMgr *TrxMgr) SendReqTrx(blockId string, trxId string) error {
+	trxmgr_log.Debugf("<%s> SendReqTrx called", trxMgr.groupId)
+
+	var reqTrxItem chestnutpb.ReqTrx
+	reqTrxItem.GroupId = trxMgr.groupId
+	reqTrxItem.BlockId = blockId
+	reqTrxItem.TrxId = trxId
+	reqTrxItem.UserId = trxMgr.groupItem.UserSignPubkey
+
+	bItemBytes, err := proto.Marshal(&reqTrxItem)
+	if err != nil {
+		return err
+	}
+
+	trx, err := trxMgr.CreateTrx(chestnutpb.TrxType_REQ_TRX, bItemBytes)
+	if err != nil {
+		trxmgr_log.Warningf(err.Error())
+		return err
+	}
+
+	return trxMgr.sendTrx(trx)
+}
+
+func (trxMgr *TrxMgr) SendReqTrxResp(req *chestnutpb.ReqTrx, reqTrx *chestnutpb.Trx) error {
+	trxmgr_log.Debugf("<%s> SendReqTrxResp called", trxMgr.groupId)
+
+	var reqTrxRespItem chestnutpb.ReqTrxResp
+	reqTrxRespItem.GroupId = req.GroupId
+	reqTrxRespItem.BlockId = req.BlockId
+	reqTrxRespItem.TrxId = req.TrxId
+	reqTrxRespItem.ProviderPubkey = trxMgr.groupItem.UserSignPubkey
+	reqTrxRespItem.RequesterPubkey = req.UserId
+
+	pbBytesTrx, err := proto.Marshal(reqTrx)
+	if err != nil {
+		return err
+	}
+	reqTrxRespItem.Trx = pbBytesTrx
+
+	bItemBytes, err := proto.Marshal(&reqTrxRespItem)
+	if err != nil {
+		return err
+	}
+
+	trx, err := trxMgr.CreateTrx(chestnutpb.TrxType_REQ_TRX_RESP, bItemBytes)
+	if err != nil {
+		trxmgr_log.Warningf(err.Error())
+		return err
+	}
+
+	return trxMgr.sendTrx(trx)
+}
+
 func (trxMgr *TrxMgr) SendBlockProduced(blk *chestnutpb.Block) error {
 	trxmgr_log.Debugf("<%s> SendBlockProduced called", trxMgr.groupId)
 	encodedcontent, err := proto.Marshal(blk)
@@ -312,6 +479,19 @@ func (trxMgr *TrxMgr) SendBlockProduced(blk *chestnutpb.Block) error {
 	return trxMgr.sendTrx(trx)
 }
 
+func (trxMgr *TrxMgr) SendHeartbeat(item *chestnutpb.HeartbeatItem) error {
+	trxmgr_log.Debugf("<%s> SendHeartbeat called", trxMgr.groupId)
+	encodedcontent, err := proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+	trx, err := trxMgr.CreateTrx(chestnutpb.TrxType_HEARTBEAT, encodedcontent)
+	if err != nil {
+		return err
+	}
+	return trxMgr.sendTrx(trx)
+}
+
 func (trxMgr *TrxMgr) PostBytes(trxtype chestnutpb.TrxType, encodedcontent []byte) (string, error) {
 	trxmgr_log.Debugf("<%s> PostBytes called", trxMgr.groupId)
 	trx, err := trxMgr.CreateTrx(trxtype, encodedcontent)
@@ -332,8 +512,8 @@ func (trxMgr *TrxMgr) PostAny(content proto.Message) (string, error) {
 	}
 
 	trxmgr_log.Debugf("<%s> content size <%d>", trxMgr.groupId, binary.Size(encodedcontent))
-	if binary.Size(encodedcontent) > OBJECT_SIZE_LIMIT {
-		err := errors.New("Content size over 200Kb")
+	if objectSizeLimit := trxMgr.getConfig().ObjectSizeLimit; int64(binary.Size(encodedcontent)) > objectSizeLimit {
+		err := fmt.Errorf("Content size over %dKb", objectSizeLimit/1024)
 		return "", err
 	}
 
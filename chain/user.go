@@ -12,6 +12,10 @@ type User interface {
 	UpdBlkList(item *chestnutpb.DenyUserItem) (string, error)
 	UpdSchema(item *chestnutpb.SchemaItem) (string, error)
 	UpdProducer(item *chestnutpb.ProducerItem) (string, error)
+	SubmitAdminAction(action *chestnutpb.AdminAction) (string, error)
+	UpdInvite(item *chestnutpb.InviteItem) (string, error)
+	RedeemInvite(item *chestnutpb.RedeemItem) (string, error)
+	UpdConfig(item *chestnutpb.ConfigItem) (string, error)
 	PostToGroup(content proto.Message) (string, error)
 	AddBlock(block *chestnutpb.Block) error
 }
\ No newline at end of file
@@ -0,0 +1,56 @@
+// Package main provides a reference signer for the remote keystore of chestnut.
+package main
+
+import (
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	logging "github.com/ipfs/go-log/v2"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+)
+
+var signerlog = logging.Logger("signer")
+
+func main() {
+	keystoredir := flag.String("keystoredir", "./signerkeystore/", "keystore dir of the signer")
+	keystorename := flag.String("keystorename", "signerkeystore", "keystore name")
+	socketpath := flag.String("socket", "./signer.sock", "unix socket the signer listens on")
+	flag.Parse()
+	logging.SetLogLevel("signer", "info")
+
+	ks, signkeycount, err := localcrypto.InitDirKeyStore(*keystorename, *keystoredir)
+	if err != nil {
+		signerlog.Fatalf(err.Error())
+	}
+
+	password := os.Getenv("CHESTNUT_PASSWORD")
+	if password == "" {
+		if signkeycount > 0 {
+			password, err = localcrypto.PassphrasePromptForUnlock()
+		} else {
+			password, err = localcrypto.PassphrasePromptForEncryption()
+		}
+		if err != nil {
+			signerlog.Fatalf(err.Error())
+		}
+	}
+	if err := ks.Unlock(map[string]string{}, password); err != nil {
+		signerlog.Fatalf(err.Error())
+	}
+
+	l, err := localcrypto.ServeSigner(localcrypto.NewSignerService(ks, password), *socketpath)
+	if err != nil {
+		signerlog.Fatalf(err.Error())
+	}
+	signerlog.Infof("signer listening on <%s>, sign keys: %d", *socketpath, signkeycount)
+
+	signalch := make(chan os.Signal, 1)
+	signal.Notify(signalch, os.Interrupt, syscall.SIGTERM)
+	<-signalch
+	l.Close()
+	ks.Lock()
+	os.Remove(*socketpath)
+	signerlog.Infof("signer stopped")
+}
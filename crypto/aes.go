@@ -5,6 +5,7 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"errors"
 	"io"
 )
 
@@ -33,7 +34,12 @@ func AesEncrypt(data, key []byte) ([]byte, error) {
 }
 
 
+// decrypt both the legacy format and the cipher envelope, the key is used whatever the key epoch is
 func AesDecode(data, key []byte) ([]byte, error) {
+	return OpenEnvelope(data, func(uint32) ([]byte, error) { return key, nil })
+}
+
+func aesDecodeLegacy(data, key []byte) ([]byte, error) {
 	c, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, err
@@ -44,7 +50,7 @@ func AesDecode(data, key []byte) ([]byte, error) {
 	}
 	nonceSize := gcmDecrypt.NonceSize()
 	if len(data) < nonceSize {
-		return nil, err
+		return nil, errors.New("ciphertext too short")
 	}
 	nonce, encrypteddata := data[:nonceSize], data[nonceSize:]
 	plaintext, err := gcmDecrypt.Open(nil, nonce, encrypteddata, nil)
@@ -47,6 +47,15 @@ func InitDirKeyStore(name, keydir string) (*DirKeyStore, int, error) {
 		}
 	}
 
+	// an interrupted password change is finished if committed, or discarded. It is recovered before
+	// the password file is read, the password file is replaced in the same change
+	if _, err := os.Stat(filepath.Join(keydir, PASSWD_DIR)); err == nil {
+		cryptolog.Warningf("unfinished password change of keystore %s found, recover", name)
+		if err := recoverPassword(keydir); err != nil {
+			return nil, 0, err
+		}
+	}
+
 	signkeycount := 0
 	files, err := ioutil.ReadDir(keydir)
 	if err != nil {
@@ -0,0 +1,174 @@
+// Package crypto provides crypto for chestnut.
+package crypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Envelope of the group ciphertext, the header is authenticated as additional data:
+//
+//	magic(2) | version(1) | alg(1) | key epoch(4, big endian) | nonce | ciphertext with tag
+//
+// Ciphertext without the magic is the legacy format of AesEncrypt: nonce(12) | ciphertext with tag.
+type CipherAlg byte
+
+const (
+	CIPHER_ALG_LEGACY            CipherAlg = 0
+	CIPHER_ALG_AES_GCM           CipherAlg = 1
+	CIPHER_ALG_XCHACHA20POLY1305 CipherAlg = 2
+)
+
+const (
+	ENVELOPE_VERSION     = 1
+	ENVELOPE_HEADER_SIZE = 8
+)
+
+var envelopeMagic = [2]byte{0xce, 0x5e}
+
+var ErrUnknownKeyEpoch = errors.New("unknown cipher key epoch")
+
+type EnvelopeHeader struct {
+	Version byte
+	Alg     CipherAlg
+	Epoch   uint32
+}
+
+// the cipher key of an epoch
+type CipherKeyring func(epoch uint32) ([]byte, error)
+
+func (alg CipherAlg) String() string {
+	switch alg {
+	case CIPHER_ALG_LEGACY:
+		return "legacy"
+	case CIPHER_ALG_AES_GCM:
+		return "aes-gcm"
+	case CIPHER_ALG_XCHACHA20POLY1305:
+		return "xchacha20-poly1305"
+	}
+	return fmt.Sprintf("unknown(%d)", byte(alg))
+}
+
+func ParseCipherAlg(s string) (CipherAlg, error) {
+	switch s {
+	case "", "legacy":
+		return CIPHER_ALG_LEGACY, nil
+	case "aes-gcm":
+		return CIPHER_ALG_AES_GCM, nil
+	case "xchacha20-poly1305":
+		return CIPHER_ALG_XCHACHA20POLY1305, nil
+	}
+	return 0, fmt.Errorf("unknown cipher alg: %s", s)
+}
+
+// a keyring with only one key
+func SingleKeyring(key []byte, epoch uint32) CipherKeyring {
+	return func(e uint32) ([]byte, error) {
+		if e != epoch {
+			return nil, ErrUnknownKeyEpoch
+		}
+		return key, nil
+	}
+}
+
+func newAEAD(alg CipherAlg, key []byte) (cipher.AEAD, error) {
+	switch alg {
+	case CIPHER_ALG_AES_GCM:
+		c, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(c)
+	case CIPHER_ALG_XCHACHA20POLY1305:
+		return chacha20poly1305.NewX(key)
+	}
+	return nil, fmt.Errorf("unsupported cipher alg: %s", alg)
+}
+
+func IsEnvelope(data []byte) bool {
+	return len(data) >= ENVELOPE_HEADER_SIZE && data[0] == envelopeMagic[0] && data[1] == envelopeMagic[1]
+}
+
+func ParseEnvelopeHeader(data []byte) (*EnvelopeHeader, error) {
+	if !IsEnvelope(data) {
+		return nil, errors.New("not a cipher envelope")
+	}
+	header := &EnvelopeHeader{Version: data[2], Alg: CipherAlg(data[3]), Epoch: binary.BigEndian.Uint32(data[4:8])}
+	if header.Version != ENVELOPE_VERSION {
+		return nil, fmt.Errorf("unsupported cipher envelope version: %d", header.Version)
+	}
+	return header, nil
+}
+
+// encrypt data with the key of epoch, the legacy alg produces the AesEncrypt format
+func SealEnvelope(data, key []byte, alg CipherAlg, epoch uint32) ([]byte, error) {
+	if alg == CIPHER_ALG_LEGACY {
+		return AesEncrypt(data, key)
+	}
+	aead, err := newAEAD(alg, key)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, ENVELOPE_HEADER_SIZE+aead.NonceSize(), ENVELOPE_HEADER_SIZE+aead.NonceSize()+len(data)+aead.Overhead())
+	copy(out, envelopeMagic[:])
+	out[2] = ENVELOPE_VERSION
+	out[3] = byte(alg)
+	binary.BigEndian.PutUint32(out[4:8], epoch)
+	nonce := out[ENVELOPE_HEADER_SIZE:]
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(out, nonce, data, out[:ENVELOPE_HEADER_SIZE]), nil
+}
+
+// decrypt an envelope, or legacy ciphertext with the key of epoch 0
+func OpenEnvelope(data []byte, keyring CipherKeyring) ([]byte, error) {
+	if !IsEnvelope(data) {
+		return openLegacy(data, keyring)
+	}
+	plaintext, err := openEnvelope(data, keyring)
+	if err == nil {
+		return plaintext, nil
+	}
+	// legacy ciphertext starts with a random nonce, which may look like the magic
+	if legacy, lerr := openLegacy(data, keyring); lerr == nil {
+		return legacy, nil
+	}
+	return nil, err
+}
+
+func openEnvelope(data []byte, keyring CipherKeyring) ([]byte, error) {
+	header, err := ParseEnvelopeHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keyring(header.Epoch)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(header.Alg, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < ENVELOPE_HEADER_SIZE+aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("cipher envelope too short")
+	}
+	nonce := data[ENVELOPE_HEADER_SIZE : ENVELOPE_HEADER_SIZE+aead.NonceSize()]
+	return aead.Open(nil, nonce, data[ENVELOPE_HEADER_SIZE+aead.NonceSize():], data[:ENVELOPE_HEADER_SIZE])
+}
+
+func openLegacy(data []byte, keyring CipherKeyring) ([]byte, error) {
+	key, err := keyring(0)
+	if err != nil {
+		return nil, err
+	}
+	return aesDecodeLegacy(data, key)
+}
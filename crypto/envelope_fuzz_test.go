@@ -0,0 +1,39 @@
+//go:build go1.18
+// +build go1.18
+
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+// an envelope with any byte changed is rejected, arbitrary input never opens
+func FuzzOpenEnvelope(f *testing.F) {
+	data := []byte("group data")
+	key, _ := testKeyring()(1)
+	for _, alg := range []CipherAlg{CIPHER_ALG_AES_GCM, CIPHER_ALG_XCHACHA20POLY1305} {
+		sealed, err := SealEnvelope(data, key, alg, 1)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(sealed, uint(0), byte(0x01))
+		f.Add(sealed, uint(ENVELOPE_HEADER_SIZE), byte(0x80))
+	}
+
+	f.Fuzz(func(t *testing.T, envelope []byte, pos uint, flip byte) {
+		opened, err := OpenEnvelope(envelope, testKeyring())
+		if err != nil || !IsEnvelope(envelope) || flip == 0 {
+			return
+		}
+		if !bytes.Equal(opened, data) {
+			// valid input from the fuzzer itself, only sealed data can be opened
+			t.Fatalf("opened data not sealed by the test: %q", opened)
+		}
+		tampered := append([]byte{}, envelope...)
+		tampered[pos%uint(len(tampered))] ^= flip
+		if opened, err := OpenEnvelope(tampered, testKeyring()); err == nil {
+			t.Fatalf("tampered envelope opened: %q", opened)
+		}
+	})
+}
@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testKeyring() CipherKeyring {
+	keys := map[uint32][]byte{
+		0: bytes.Repeat([]byte{0x01}, 32),
+		1: bytes.Repeat([]byte{0x02}, 32),
+		7: bytes.Repeat([]byte{0x03}, 32),
+	}
+	return func(epoch uint32) ([]byte, error) {
+		key, ok := keys[epoch]
+		if !ok {
+			return nil, ErrUnknownKeyEpoch
+		}
+		return key, nil
+	}
+}
+
+func sealTest(t *testing.T, data []byte, alg CipherAlg, epoch uint32) []byte {
+	key, err := testKeyring()(epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := SealEnvelope(data, key, alg, epoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sealed
+}
+
+// data is opened with the key of the epoch sealed in the header
+func TestOpenEnvelopeEpoch(t *testing.T) {
+	data := []byte("group data")
+	for _, alg := range []CipherAlg{CIPHER_ALG_AES_GCM, CIPHER_ALG_XCHACHA20POLY1305} {
+		for _, epoch := range []uint32{0, 1, 7} {
+			sealed := sealTest(t, data, alg, epoch)
+			header, err := ParseEnvelopeHeader(sealed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if header.Alg != alg || header.Epoch != epoch {
+				t.Fatalf("header %+v, want alg %s epoch %d", header, alg, epoch)
+			}
+			opened, err := OpenEnvelope(sealed, testKeyring())
+			if err != nil {
+				t.Fatalf("%s epoch %d: %s", alg, epoch, err)
+			}
+			if !bytes.Equal(opened, data) {
+				t.Fatalf("%s epoch %d: opened %q", alg, epoch, opened)
+			}
+		}
+	}
+
+	legacy := sealTest(t, data, CIPHER_ALG_LEGACY, 0)
+	if opened, err := OpenEnvelope(legacy, testKeyring()); err != nil || !bytes.Equal(opened, data) {
+		t.Fatalf("legacy: opened %q, err %v", opened, err)
+	}
+
+	unknown, err := SealEnvelope(data, bytes.Repeat([]byte{0x04}, 32), CIPHER_ALG_AES_GCM, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenEnvelope(unknown, testKeyring()); err != ErrUnknownKeyEpoch {
+		t.Fatalf("unknown epoch: err %v", err)
+	}
+}
+
+// a change to any byte of the header, the nonce or the ciphertext is rejected
+func TestOpenEnvelopeTampered(t *testing.T) {
+	data := []byte("group data")
+	for _, alg := range []CipherAlg{CIPHER_ALG_AES_GCM, CIPHER_ALG_XCHACHA20POLY1305} {
+		sealed := sealTest(t, data, alg, 1)
+		tests := []struct {
+			name   string
+			tamper func(b []byte) []byte
+		}{
+			{"version", func(b []byte) []byte { b[2]++; return b }},
+			{"alg", func(b []byte) []byte { b[3] ^= 0x03; return b }},
+			{"epoch to another key", func(b []byte) []byte { b[7] = 7; return b }},
+			{"epoch to the same key", func(b []byte) []byte { b[4] = 0x80; return b }},
+			{"nonce", func(b []byte) []byte { b[ENVELOPE_HEADER_SIZE] ^= 0x01; return b }},
+			{"ciphertext", func(b []byte) []byte { b[len(b)-len(data)-17] ^= 0x01; return b }},
+			{"tag", func(b []byte) []byte { b[len(b)-1] ^= 0x01; return b }},
+			{"truncated", func(b []byte) []byte { return b[:len(b)-1] }},
+			{"header only", func(b []byte) []byte { return b[:ENVELOPE_HEADER_SIZE] }},
+			{"appended", func(b []byte) []byte { return append(b, 0x00) }},
+		}
+		for _, test := range tests {
+			tampered := test.tamper(append([]byte{}, sealed...))
+			if opened, err := OpenEnvelope(tampered, testKeyring()); err == nil {
+				t.Errorf("%s %s: tampered envelope opened: %q", alg, test.name, opened)
+			}
+		}
+	}
+}
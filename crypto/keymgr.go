@@ -0,0 +1,183 @@
+// Package crypto provides the crypto utils to the program.
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	"filippo.io/age"
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+type KeyItem struct {
+	KeyName string
+	KeyType KeyType
+	Addr    string
+}
+
+func (kt KeyType) String() string {
+	switch kt {
+	case Encrypt:
+		return "encrypt"
+	case Sign:
+		return "sign"
+	}
+	return ""
+}
+
+func ParseKeyType(s string) (KeyType, error) {
+	switch s {
+	case "encrypt":
+		return Encrypt, nil
+	case "sign":
+		return Sign, nil
+	}
+	return 0, fmt.Errorf("unknown key type: %s", s)
+}
+
+// keys saved in the keystore dir, the address of sign keys comes from the sign key map
+func (ks *DirKeyStore) ListKeys() ([]*KeyItem, error) {
+	files, err := ioutil.ReadDir(ks.KeystorePath)
+	if err != nil {
+		return nil, err
+	}
+
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	keys := []*KeyItem{}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		for _, keytype := range []KeyType{Sign, Encrypt} {
+			if !strings.HasPrefix(f.Name(), keytype.Prefix()) {
+				continue
+			}
+			item := &KeyItem{KeyName: f.Name()[len(keytype.Prefix()):], KeyType: keytype}
+			if keytype == Sign {
+				item.Addr = ks.signkeymap[item.KeyName]
+			}
+			keys = append(keys, item)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].KeyName < keys[j].KeyName })
+	return keys, nil
+}
+
+// export the key encrypted under passphrase, sign keys are exported as ethereum keystore json,
+// encrypt keys as age identities encrypted with a scrypt recipient. Both are base64 encoded.
+func (ks *DirKeyStore) ExportKey(keyname string, keytype KeyType, passphrase string) (string, error) {
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase can't be empty")
+	}
+	key, err := ks.GetKeyFromUnlocked(keytype.NameString(keyname))
+	if err != nil {
+		return "", err
+	}
+
+	var exported []byte
+	switch k := key.(type) {
+	case *ethkeystore.Key:
+		exported, err = ethkeystore.EncryptKey(k, passphrase, ethkeystore.StandardScryptN, ethkeystore.StandardScryptP)
+		if err != nil {
+			return "", err
+		}
+	case *age.X25519Identity:
+		r, err := age.NewScryptRecipient(passphrase)
+		if err != nil {
+			return "", err
+		}
+		out := new(bytes.Buffer)
+		if err := AgeEncrypt([]age.Recipient{r}, strings.NewReader(k.String()), out); err != nil {
+			return "", err
+		}
+		exported = out.Bytes()
+	default:
+		return "", fmt.Errorf("The key %s can't be exported", keyname)
+	}
+	return base64.StdEncoding.EncodeToString(exported), nil
+}
+
+// import a key exported by ExportKey, it is saved with the keystore password
+func (ks *DirKeyStore) ImportExportedKey(keyname string, keytype KeyType, exported string, passphrase string) (string, error) {
+	data, err := base64.StdEncoding.DecodeString(exported)
+	if err != nil {
+		return "", err
+	}
+
+	name := keytype.NameString(keyname)
+	exist, err := ks.IfKeyExist(name)
+	if err != nil {
+		return "", err
+	}
+	if exist {
+		return "", fmt.Errorf("Key '%s' exists", name)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	switch keytype {
+	case Sign:
+		key, err := ethkeystore.DecryptKey(data, passphrase)
+		if err != nil {
+			return "", err
+		}
+		if err := ks.StoreSignKey(name, key, ks.password); err != nil {
+			return "", err
+		}
+		ks.unlocked[name] = key
+		return key.Address.String(), nil
+	case Encrypt:
+		key, err := AgeDecryptIdentityWithPassword(bytes.NewReader(data), nil, passphrase)
+		if err != nil {
+			return "", err
+		}
+		if err := ks.StoreEncryptKey(name, key, ks.password); err != nil {
+			return "", err
+		}
+		ks.unlocked[name] = key
+		return key.Recipient().String(), nil
+	}
+	return "", fmt.Errorf("unsupported key type")
+}
+
+func (ks *DirKeyStore) RenameKey(keyname string, newname string, keytype KeyType) error {
+	name, newName := keytype.NameString(keyname), keytype.NameString(newname)
+	exist, err := ks.IfKeyExist(newName)
+	if err != nil {
+		return err
+	}
+	if exist {
+		return fmt.Errorf("Key '%s' exists", newName)
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if err := os.Rename(JoinKeyStorePath(ks.KeystorePath, name), JoinKeyStorePath(ks.KeystorePath, newName)); err != nil {
+		return err
+	}
+	if key, ok := ks.unlocked[name]; ok {
+		ks.unlocked[newName] = key
+		delete(ks.unlocked, name)
+	}
+	return nil
+}
+
+func (ks *DirKeyStore) DeleteKey(keyname string, keytype KeyType) error {
+	name := keytype.NameString(keyname)
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if err := os.Remove(JoinKeyStorePath(ks.KeystorePath, name)); err != nil {
+		return err
+	}
+	if signk, ok := ks.unlocked[name].(*ethkeystore.Key); ok {
+		zeroSignKey(signk.PrivateKey)
+	}
+	delete(ks.unlocked, name)
+	return nil
+}
@@ -1,9 +1,27 @@
 // Package crypto provides the crypto utils to the program.
 package crypto
 
-func InitKeystore(KeyStoreName, KeyStoreDir string) (int, error) {
+import "fmt"
+
+const (
+	DIR_KEYSTORE    = "dir"
+	REMOTE_KEYSTORE = "remote"
+)
+
+// keystoretype selects the keystore, the remote keystore connects to the signer listening on signeraddr
+func InitKeystore(KeyStoreType, KeyStoreName, KeyStoreDir, SignerAddr string) (int, error) {
 	signkeycount := 0
 	var err error
-	ks, signkeycount, err = InitDirKeyStore(KeyStoreName, KeyStoreDir)
+	switch KeyStoreType {
+	case "", DIR_KEYSTORE:
+		ks, signkeycount, err = InitDirKeyStore(KeyStoreName, KeyStoreDir)
+	case REMOTE_KEYSTORE:
+		if SignerAddr == "" {
+			return 0, fmt.Errorf("signer address is required by the remote keystore")
+		}
+		ks, signkeycount, err = InitRemoteKeyStore(KeyStoreName, SignerAddr)
+	default:
+		return 0, fmt.Errorf("unsupported keystore type: %s", KeyStoreType)
+	}
 	return signkeycount, err
-}
\ No newline at end of file
+}
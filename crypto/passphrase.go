@@ -33,11 +33,7 @@ func PassphrasePromptForEncryption() (string, error) {
 	}
 	p := string(pass)
 	if p == "" {
-		var words []string
-		for i := 0; i < 10; i++ {
-			words = append(words, randomWord())
-		}
-		p = strings.Join(words, "-")
+		p = GeneratePassphrase()
 		// TODO: consider printing this to the terminal, instead of stderr.
 		fmt.Fprintf(os.Stderr, "Using the autogenerated passphrase %q.\n", p)
 	} else {
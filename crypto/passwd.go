@@ -0,0 +1,270 @@
+// Package crypto provides the crypto utils to the program.
+package crypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"filippo.io/age"
+	ethkeystore "github.com/ethereum/go-ethereum/accounts/keystore"
+)
+
+// key files re-encrypted under the new password are staged in <keystore>/.passwd/new, the new
+// password files are staged next to them as <passwordfile>.passwd. Once every keystore and password
+// file is staged, one commit file is written to the .passwd dir of the first keystore, the .passwd
+// dir of every keystore refers to it. Staged files are moved in place only after the commit file
+// exists, an interrupted change is finished if the commit file exists, or discarded if not.
+const PASSWD_DIR = ".passwd"
+const PASSWD_COMMIT_FILE = "commit"
+const PASSWD_COMMIT_REF_FILE = "commit_ref"
+const PASSWD_STAGE_SUFFIX = ".passwd"
+
+// keystore dirs and password files changed by a password change, the first keystore holds the commit file
+type passwdCommit struct {
+	Keystores     []string
+	PasswordFiles []string
+}
+
+func (ks *DirKeyStore) passwdDir(sub ...string) string {
+	return filepath.Join(append([]string{ks.KeystorePath, PASSWD_DIR}, sub...)...)
+}
+
+// names of all key files in the keystore dir
+func (ks *DirKeyStore) keyFiles() ([]string, error) {
+	files, err := ioutil.ReadDir(ks.KeystorePath)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		if strings.HasPrefix(f.Name(), Sign.Prefix()) || strings.HasPrefix(f.Name(), Encrypt.Prefix()) {
+			names = append(names, f.Name())
+		}
+	}
+	return names, nil
+}
+
+func reencryptKeyFile(name string, content []byte, oldpassword, newpassword string) ([]byte, error) {
+	if strings.HasPrefix(name, Sign.Prefix()) {
+		key, err := ethkeystore.DecryptKey(content, oldpassword)
+		if err != nil {
+			return nil, fmt.Errorf("key %s can't be unlocked, err:%s", name, err)
+		}
+		defer zeroSignKey(key.PrivateKey)
+		return ethkeystore.EncryptKey(key, newpassword, ethkeystore.StandardScryptN, ethkeystore.StandardScryptP)
+	}
+
+	key, err := AgeDecryptIdentityWithPassword(bytes.NewReader(content), nil, oldpassword)
+	if err != nil {
+		return nil, fmt.Errorf("key %s can't be unlocked, err:%s", name, err)
+	}
+	r, err := age.NewScryptRecipient(newpassword)
+	if err != nil {
+		return nil, err
+	}
+	out := new(bytes.Buffer)
+	if err := AgeEncrypt([]age.Recipient{r}, strings.NewReader(key.String()), out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// write every key file encrypted under newpassword to the staging dir, with the path of the commit file
+func (ks *DirKeyStore) stagePassword(oldpassword, newpassword string, commitpath string) error {
+	names, err := ks.keyFiles()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(ks.passwdDir("new"), 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(ks.passwdDir(PASSWD_COMMIT_REF_FILE), []byte(commitpath), 0600); err != nil {
+		return err
+	}
+	for _, name := range names {
+		content, err := ioutil.ReadFile(JoinKeyStorePath(ks.KeystorePath, name))
+		if err != nil {
+			return err
+		}
+		newcontent, err := reencryptKeyFile(name, content, oldpassword, newpassword)
+		if err != nil {
+			return err
+		}
+		if err := ioutil.WriteFile(ks.passwdDir("new", name), newcontent, 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write the commit file to a temp file renamed in place, the password change is committed by the rename
+func writePasswdCommit(commitpath string, commit *passwdCommit) error {
+	content, err := json.Marshal(commit)
+	if err != nil {
+		return err
+	}
+	tmppath := commitpath + ".tmp"
+	f, err := os.OpenFile(tmppath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmppath, commitpath)
+}
+
+// move the staged key files of the keystore dir in place and remove the staging dir, each rename is atomic
+func finishKeystorePassword(keydir string) error {
+	newdir := filepath.Join(keydir, PASSWD_DIR, "new")
+	files, err := ioutil.ReadDir(newdir)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, f := range files {
+		if err := os.Rename(filepath.Join(newdir, f.Name()), JoinKeyStorePath(keydir, f.Name())); err != nil {
+			return err
+		}
+	}
+	return os.RemoveAll(filepath.Join(keydir, PASSWD_DIR))
+}
+
+// finish a committed password change: the staged files of all keystores and password files are moved in place.
+// The first keystore is finished last, its staging dir holds the commit file
+func finishPassword(commitpath string) error {
+	content, err := ioutil.ReadFile(commitpath)
+	if err != nil {
+		return err
+	}
+	commit := &passwdCommit{}
+	if err := json.Unmarshal(content, commit); err != nil {
+		return err
+	}
+	if len(commit.Keystores) == 0 {
+		return fmt.Errorf("invalid password commit file %s", commitpath)
+	}
+	for _, keydir := range commit.Keystores[1:] {
+		if err := finishKeystorePassword(keydir); err != nil {
+			return err
+		}
+	}
+	for _, path := range commit.PasswordFiles {
+		if err := os.Rename(path+PASSWD_STAGE_SUFFIX, path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return finishKeystorePassword(commit.Keystores[0])
+}
+
+// finish or discard an unfinished password change of the keystore dir: if the commit file it refers to
+// exists, every keystore and password file of the change is finished, the new password works.
+// Otherwise no key file was replaced, the staged files are removed and the old password works.
+func recoverPassword(keydir string) error {
+	passwddir := filepath.Join(keydir, PASSWD_DIR)
+	if _, err := os.Stat(passwddir); os.IsNotExist(err) {
+		return nil
+	}
+	ref, err := ioutil.ReadFile(filepath.Join(passwddir, PASSWD_COMMIT_REF_FILE))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(ref) > 0 {
+		if _, err := os.Stat(string(ref)); err == nil {
+			return finishPassword(string(ref))
+		}
+	}
+	return os.RemoveAll(passwddir)
+}
+
+// re-encrypt all key files of the keystores under newpassword and replace the password files with it.
+// Every keystore and password file is staged before one commit file is written, nothing is replaced
+// before. If the change is interrupted, it is finished or discarded when a keystore is opened again.
+func ChangeKeystorePassword(keystores []*DirKeyStore, passwordfiles []string, oldpassword, newpassword string) error {
+	if newpassword == "" {
+		return errors.New("password can't be empty")
+	}
+	if len(keystores) == 0 {
+		return errors.New("no keystore to change")
+	}
+	for _, ks := range keystores {
+		ks.mu.Lock()
+		defer ks.mu.Unlock()
+		if ks.password != "" && ks.password != oldpassword {
+			return errors.New("wrong password")
+		}
+	}
+	for _, ks := range keystores {
+		if _, err := os.Stat(ks.passwdDir()); err == nil {
+			return fmt.Errorf("password change of keystore %s is in progress", ks.Name)
+		}
+	}
+
+	commit := &passwdCommit{}
+	for _, ks := range keystores {
+		commit.Keystores = append(commit.Keystores, ks.KeystorePath)
+	}
+	for _, path := range passwordfiles {
+		if path != "" {
+			commit.PasswordFiles = append(commit.PasswordFiles, path)
+		}
+	}
+	commitpath := keystores[0].passwdDir(PASSWD_COMMIT_FILE)
+
+	discard := func(err error) error {
+		for _, ks := range keystores {
+			if rerr := os.RemoveAll(ks.passwdDir()); rerr != nil {
+				cryptolog.Errorf("remove staged keys of keystore %s failed: %s", ks.Name, rerr)
+			}
+		}
+		for _, path := range commit.PasswordFiles {
+			os.Remove(path + PASSWD_STAGE_SUFFIX)
+		}
+		return err
+	}
+	for _, ks := range keystores {
+		if err := ks.stagePassword(oldpassword, newpassword, commitpath); err != nil {
+			return discard(err)
+		}
+	}
+	for _, path := range commit.PasswordFiles {
+		os.Remove(path + PASSWD_STAGE_SUFFIX)
+		if err := WritePasswordFile(path+PASSWD_STAGE_SUFFIX, newpassword); err != nil {
+			return discard(err)
+		}
+	}
+	if err := writePasswdCommit(commitpath, commit); err != nil {
+		os.Remove(commitpath + ".tmp")
+		return discard(err)
+	}
+
+	//committed, an error from here is finished when the keystore is opened again
+	if err := finishPassword(commitpath); err != nil {
+		return fmt.Errorf("password changed, finish it by restarting the node: %s", err)
+	}
+	for _, ks := range keystores {
+		ks.password = newpassword
+	}
+	return nil
+}
+
+// change the password of the keystore, the password file is replaced in the same change if not empty
+func (ks *DirKeyStore) ChangePassword(oldpassword, newpassword string, passwordfile string) error {
+	return ChangeKeystorePassword([]*DirKeyStore{ks}, []string{passwordfile}, oldpassword, newpassword)
+}
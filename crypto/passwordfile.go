@@ -0,0 +1,78 @@
+// Package crypto provides the crypto utils to the program.
+package crypto
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// name of the systemd credential holding the keystore password, e.g.: LoadCredential=chestnut-password:/etc/chestnut/password
+const PASSWORD_CREDENTIAL_NAME = "chestnut-password"
+
+// read the password from the first line of a file, the trailing newline is not part of the password
+func LoadPasswordFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		cryptolog.Warningf("password file %s is accessible by other users, mode %s", path, info.Mode().Perm())
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	password := strings.TrimRight(strings.SplitN(string(content), "\n", 2)[0], "\r")
+	if password == "" {
+		return "", fmt.Errorf("password file %s is empty", path)
+	}
+	return password, nil
+}
+
+// the password passed by systemd with LoadCredential or SetCredential, ok is false if there is no such credential
+func LoadCredentialPassword() (password string, ok bool, err error) {
+	dir := os.Getenv("CREDENTIALS_DIRECTORY")
+	if dir == "" {
+		return "", false, nil
+	}
+	path := filepath.Join(dir, PASSWORD_CREDENTIAL_NAME)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return "", false, nil
+	}
+	password, err = LoadPasswordFile(path)
+	return password, err == nil, err
+}
+
+// a passphrase of 10 random words
+func GeneratePassphrase() string {
+	var words []string
+	for i := 0; i < 10; i++ {
+		words = append(words, randomWord())
+	}
+	return strings.Join(words, "-")
+}
+
+// write the password to a new file only readable by the owner, an existing file is never overwritten
+func WritePasswordFile(path string, password string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(password + "\n"); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
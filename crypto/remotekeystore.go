@@ -0,0 +1,179 @@
+// Package crypto provides the crypto utils to the program.
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/rpc"
+	"sync"
+	"time"
+
+	ethcrypto "github.com/ethereum/go-ethereum/crypto"
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
+	peer "github.com/libp2p/go-libp2p-core/peer"
+)
+
+const REMOTE_SIGNER_TIMEOUT = 10 * time.Second
+
+// RemoteKeyStore delegates every operation needing a private key to a signer
+// process listening on a local unix socket, no private key is held by the node.
+type RemoteKeyStore struct {
+	Name       string
+	SocketPath string
+	client     *rpc.Client
+	mu         sync.Mutex
+}
+
+func InitRemoteKeyStore(name, socketpath string) (*RemoteKeyStore, int, error) {
+	ks := &RemoteKeyStore{Name: name, SocketPath: socketpath}
+	reply := &SignerReply{}
+	if err := ks.call("SignKeyCount", &SignerArgs{}, reply); err != nil {
+		return nil, 0, err
+	}
+	return ks, reply.Count, nil
+}
+
+func (ks *RemoteKeyStore) dial() (*rpc.Client, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.client == nil {
+		client, err := rpc.Dial("unix", ks.SocketPath)
+		if err != nil {
+			return nil, fmt.Errorf("connect to signer %s failed: %s", ks.SocketPath, err)
+		}
+		ks.client = client
+	}
+	return ks.client, nil
+}
+
+func (ks *RemoteKeyStore) reset(client *rpc.Client) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.client == client {
+		ks.client.Close()
+		ks.client = nil
+	}
+}
+
+// call the signer, the connection is dialed again once if the signer was restarted
+func (ks *RemoteKeyStore) call(method string, args *SignerArgs, reply *SignerReply) error {
+	var err error
+	for i := 0; i < 2; i++ {
+		var client *rpc.Client
+		client, err = ks.dial()
+		if err != nil {
+			return err
+		}
+		call := client.Go(SIGNER_SERVICE_NAME+"."+method, args, reply, make(chan *rpc.Call, 1))
+		select {
+		case <-call.Done:
+			err = call.Error
+		case <-time.After(REMOTE_SIGNER_TIMEOUT):
+			ks.reset(client)
+			return fmt.Errorf("signer %s timeout", method)
+		}
+		if !errors.Is(err, rpc.ErrShutdown) {
+			return err
+		}
+		ks.reset(client)
+	}
+	return err
+}
+
+// only the sign key map is sent, the signer unlocks its keys with its own password
+func (ks *RemoteKeyStore) Unlock(signkeymap map[string]string, password string) error {
+	return ks.call("Unlock", &SignerArgs{SignKeyMap: signkeymap}, &SignerReply{})
+}
+
+func (ks *RemoteKeyStore) Lock() error {
+	return ks.call("Lock", &SignerArgs{}, &SignerReply{})
+}
+
+func (ks *RemoteKeyStore) NewKey(keyname string, keytype KeyType, password string) (string, error) {
+	return ks.NewKeyWithDefaultPassword(keyname, keytype)
+}
+
+func (ks *RemoteKeyStore) NewKeyWithDefaultPassword(keyname string, keytype KeyType) (string, error) {
+	reply := &SignerReply{}
+	err := ks.call("NewKey", &SignerArgs{KeyName: keyname, KeyType: keytype}, reply)
+	return reply.Value, err
+}
+
+func (ks *RemoteKeyStore) Import(keyname string, encodedkey string, keytype KeyType, password string) (string, error) {
+	reply := &SignerReply{}
+	err := ks.call("Import", &SignerArgs{KeyName: keyname, KeyType: keytype, EncodedKey: encodedkey}, reply)
+	return reply.Value, err
+}
+
+func (ks *RemoteKeyStore) Sign(data []byte, privKey p2pcrypto.PrivKey) ([]byte, error) {
+	return privKey.Sign(data)
+}
+
+func (ks *RemoteKeyStore) VerifySign(data, sig []byte, pubKey p2pcrypto.PubKey) (bool, error) {
+	return pubKey.Verify(data, sig)
+}
+
+func (ks *RemoteKeyStore) SignByKeyName(keyname string, data []byte, opts ...string) ([]byte, error) {
+	reply := &SignerReply{}
+	err := ks.call("SignByKeyName", &SignerArgs{KeyName: keyname, Data: data, Opts: opts}, reply)
+	return reply.Data, err
+}
+
+func (ks *RemoteKeyStore) VerifySignByKeyName(keyname string, data []byte, sig []byte, opts ...string) (bool, error) {
+	reply := &SignerReply{}
+	err := ks.call("VerifySignByKeyName", &SignerArgs{KeyName: keyname, Data: data, Sig: sig, Opts: opts}, reply)
+	return reply.Valid, err
+}
+
+// encryption only needs the public keys of the recipients
+func (ks *RemoteKeyStore) EncryptTo(to []string, data []byte) ([]byte, error) {
+	return (&DirKeyStore{}).EncryptTo(to, data)
+}
+
+func (ks *RemoteKeyStore) Decrypt(keyname string, data []byte) ([]byte, error) {
+	reply := &SignerReply{}
+	err := ks.call("Decrypt", &SignerArgs{KeyName: keyname, Data: data}, reply)
+	return reply.Data, err
+}
+
+func (ks *RemoteKeyStore) GetEncodedPubkey(keyname string, keytype KeyType) (string, error) {
+	reply := &SignerReply{}
+	err := ks.call("GetEncodedPubkey", &SignerArgs{KeyName: keyname, KeyType: keytype}, reply)
+	return reply.Value, err
+}
+
+func (ks *RemoteKeyStore) GetPeerInfo(keyname string) (peerid peer.ID, ethaddr string, err error) {
+	hexkey, err := ks.GetEncodedPubkey(keyname, Sign)
+	if err != nil {
+		return "", "", err
+	}
+	pubkeybytes, err := hex.DecodeString(hexkey)
+	if err != nil {
+		return "", "", err
+	}
+	ethpubkey, err := ethcrypto.UnmarshalPubkey(pubkeybytes)
+	if err != nil {
+		return "", "", err
+	}
+	pub, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
+	if err != nil {
+		return "", "", err
+	}
+	peerid, err = peer.IDFromPublicKey(pub)
+	if err != nil {
+		return "", "", err
+	}
+	return peerid, ethcrypto.PubkeyToAddress(*ethpubkey).Hex(), nil
+}
+
+func (ks *RemoteKeyStore) Close() error {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+	if ks.client == nil {
+		return nil
+	}
+	err := ks.client.Close()
+	ks.client = nil
+	return err
+}
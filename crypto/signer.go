@@ -0,0 +1,146 @@
+// Package crypto provides the crypto utils to the program.
+package crypto
+
+import (
+	"fmt"
+	"net"
+	"net/rpc"
+	"os"
+)
+
+const SIGNER_SERVICE_NAME = "Signer"
+
+type SignerArgs struct {
+	KeyName    string
+	KeyType    KeyType
+	EncodedKey string
+	Data       []byte
+	Sig        []byte
+	Opts       []string
+	SignKeyMap map[string]string
+}
+
+type SignerReply struct {
+	Value string
+	Data  []byte
+	Valid bool
+	Count int
+}
+
+// SignerService serves the keys of a DirKeyStore to a RemoteKeyStore,
+// the keystore is unlocked with the password of the signer process.
+type SignerService struct {
+	ks       *DirKeyStore
+	password string
+}
+
+func NewSignerService(ks *DirKeyStore, password string) *SignerService {
+	return &SignerService{ks: ks, password: password}
+}
+
+// listen on the unix socket and serve the signer until the listener is closed
+func ServeSigner(service *SignerService, socketpath string) (net.Listener, error) {
+	server := rpc.NewServer()
+	if err := server.RegisterName(SIGNER_SERVICE_NAME, service); err != nil {
+		return nil, err
+	}
+
+	if err := os.Remove(socketpath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	l, err := net.Listen("unix", socketpath)
+	if err != nil {
+		return nil, err
+	}
+	// only the owner of the signer process is allowed to connect
+	if err := os.Chmod(socketpath, 0600); err != nil {
+		l.Close()
+		return nil, err
+	}
+	go server.Accept(l)
+	return l, nil
+}
+
+func (s *SignerService) SignKeyCount(args *SignerArgs, reply *SignerReply) error {
+	_, count, err := InitDirKeyStore(s.ks.Name, s.ks.KeystorePath)
+	reply.Count = count
+	return err
+}
+
+func (s *SignerService) Unlock(args *SignerArgs, reply *SignerReply) error {
+	signkeymap := make(map[string]string)
+	s.ks.mu.RLock()
+	for k, v := range s.ks.signkeymap {
+		signkeymap[k] = v
+	}
+	s.ks.mu.RUnlock()
+	for k, v := range args.SignKeyMap {
+		signkeymap[k] = v
+	}
+	return s.ks.Unlock(signkeymap, s.password)
+}
+
+func (s *SignerService) Lock(args *SignerArgs, reply *SignerReply) error {
+	return s.ks.Lock()
+}
+
+func (s *SignerService) NewKey(args *SignerArgs, reply *SignerReply) error {
+	addr, err := s.ks.NewKey(args.KeyName, args.KeyType, s.password)
+	if err != nil {
+		return err
+	}
+	s.setSignKeyAddr(args.KeyName, args.KeyType, addr)
+	reply.Value = addr
+	return nil
+}
+
+func (s *SignerService) Import(args *SignerArgs, reply *SignerReply) error {
+	addr, err := s.ks.Import(args.KeyName, args.EncodedKey, args.KeyType, s.password)
+	if err != nil {
+		return err
+	}
+	s.setSignKeyAddr(args.KeyName, args.KeyType, addr)
+	reply.Value = addr
+	return nil
+}
+
+// new sign keys can be loaded before the node sends its sign key map again
+func (s *SignerService) setSignKeyAddr(keyname string, keytype KeyType, addr string) {
+	if keytype != Sign {
+		return
+	}
+	s.ks.mu.Lock()
+	defer s.ks.mu.Unlock()
+	if s.ks.signkeymap == nil {
+		s.ks.signkeymap = make(map[string]string)
+	}
+	s.ks.signkeymap[keyname] = addr
+}
+
+func (s *SignerService) SignByKeyName(args *SignerArgs, reply *SignerReply) error {
+	sig, err := s.ks.SignByKeyName(args.KeyName, args.Data, args.Opts...)
+	reply.Data = sig
+	return err
+}
+
+func (s *SignerService) VerifySignByKeyName(args *SignerArgs, reply *SignerReply) error {
+	valid, err := s.ks.VerifySignByKeyName(args.KeyName, args.Data, args.Sig, args.Opts...)
+	reply.Valid = valid
+	return err
+}
+
+func (s *SignerService) Decrypt(args *SignerArgs, reply *SignerReply) error {
+	data, err := s.ks.Decrypt(args.KeyName, args.Data)
+	reply.Data = data
+	return err
+}
+
+func (s *SignerService) GetEncodedPubkey(args *SignerArgs, reply *SignerReply) error {
+	// load the key first, DirKeyStore only reports the pubkey of unlocked keys
+	if _, err := s.ks.GetKeyFromUnlocked(args.KeyType.NameString(args.KeyName)); err != nil {
+		return fmt.Errorf("key not exist :%s", args.KeyName)
+	}
+	pubkey, err := s.ks.GetEncodedPubkey(args.KeyName, args.KeyType)
+	reply.Value = pubkey
+	return err
+}
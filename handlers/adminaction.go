@@ -0,0 +1,124 @@
+// Package handlers provides handlers for the api package.
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	guuid "github.com/google/uuid"
+	"github.com/lixvyang/chestnut/chain"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+// sign the action with the key of an admin, keyname is the group key if empty.
+// A signature of the same admin is replaced.
+func signAdminAction(group *chain.Group, ks localcrypto.Keystore, keyname string, action *chestnutpb.AdminAction) (string, error) {
+	if keyname == "" {
+		keyname = group.Item.GroupId
+	}
+	pubkey, err := GetSignPubkey(keyname, ks)
+	if err != nil {
+		return "", err
+	}
+	if !chain.IsAdmin(group.Item.Admins, pubkey) {
+		return "", fmt.Errorf("key %s is not an admin of the group", keyname)
+	}
+	signature, err := ks.SignByKeyName(keyname, chain.AdminActionHash(action))
+	if err != nil {
+		return "", err
+	}
+
+	signs := []*chestnutpb.AdminSign{}
+	for _, sign := range action.Signs {
+		if sign.Pubkey != pubkey {
+			signs = append(signs, sign)
+		}
+	}
+	action.Signs = append(signs, &chestnutpb.AdminSign{Pubkey: pubkey, Sign: signature})
+	return pubkey, nil
+}
+
+// create a pending owner trx of an admin group signed by the proposer
+func ProposeAdminAction(group *chain.Group, ks localcrypto.Keystore, keyname string, trxType chestnutpb.TrxType, data []byte) (*chestnutpb.AdminAction, error) {
+	if !chain.IsAdminGroup(group.Item) {
+		return nil, errors.New("group is not owned by an admin set")
+	}
+	if !chain.IsOwnerTrx(trxType) {
+		return nil, fmt.Errorf("%s is not an owner trx", trxType)
+	}
+
+	action := &chestnutpb.AdminAction{
+		ActionId:  guuid.New().String(),
+		GroupId:   group.Item.GroupId,
+		Type:      trxType,
+		Data:      data,
+		TimeStamp: time.Now().UnixNano(),
+	}
+	proposer, err := signAdminAction(group, ks, keyname, action)
+	if err != nil {
+		return nil, err
+	}
+	action.Proposer = proposer
+	if err := group.SaveAdminAction(action); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// add the signature of the admin to an action proposed on any node.
+// Signatures of the local copy are merged, the signed content must be the same.
+func CosignAdminAction(group *chain.Group, ks localcrypto.Keystore, keyname string, action *chestnutpb.AdminAction) (*chestnutpb.AdminAction, error) {
+	if !chain.IsAdminGroup(group.Item) {
+		return nil, errors.New("group is not owned by an admin set")
+	}
+	if action.GroupId != group.Item.GroupId {
+		return nil, errors.New("action of another group")
+	}
+	if !chain.IsOwnerTrx(action.Type) {
+		return nil, fmt.Errorf("%s is not an owner trx", action.Type)
+	}
+	for _, sign := range action.Signs {
+		if err := chain.VerifyAdminSign(group.Item.Admins, action, sign); err != nil {
+			return nil, err
+		}
+	}
+
+	if saved, err := group.GetAdminAction(action.ActionId); err == nil {
+		if saved.Type != action.Type || !bytes.Equal(saved.Data, action.Data) {
+			return nil, fmt.Errorf("action %s differs from the saved one", action.ActionId)
+		}
+		signed := make(map[string]bool)
+		for _, sign := range action.Signs {
+			signed[sign.Pubkey] = true
+		}
+		for _, sign := range saved.Signs {
+			if !signed[sign.Pubkey] {
+				action.Signs = append(action.Signs, sign)
+			}
+		}
+	}
+
+	if _, err := signAdminAction(group, ks, keyname, action); err != nil {
+		return nil, err
+	}
+	if err := group.SaveAdminAction(action); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+// send the action signed by enough admins as an owner trx, the pending action is removed
+func SubmitAdminAction(group *chain.Group, actionId string) (string, error) {
+	action, err := group.GetAdminAction(actionId)
+	if err != nil {
+		return "", fmt.Errorf("action %s not found, err:%s", actionId, err)
+	}
+	trxId, err := group.SubmitAdminAction(action)
+	if err != nil {
+		return trxId, err
+	}
+	return trxId, group.RmAdminAction(actionId)
+}
@@ -15,7 +15,6 @@ import (
 	"github.com/lixvyang/chestnut/appdata"
 	chain "github.com/lixvyang/chestnut/chain"
 	localcrypto "github.com/lixvyang/chestnut/crypto"
-	"github.com/lixvyang/chestnut/nodectx"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 	"github.com/lixvyang/chestnut/utils/options"
 )
@@ -26,8 +25,20 @@ type CreateGroupParam struct {
 	ConsensusType  string `from:"consensus_type"  json:"consensus_type"  validate:"required,oneof=pos poa"`
 	EncryptionType string `from:"encryption_type" json:"encryption_type" validate:"required,oneof=public private"`
 	AppKey         string `from:"app_key"         json:"app_key"         validate:"required,max=20,min=4"`
+	CipherAlg      string `from:"cipher_alg"      json:"cipher_alg"      validate:"omitempty,oneof=legacy aes-gcm xchacha20-poly1305"`
+	AdminPubkeys   []string `from:"admin_pubkeys"   json:"admin_pubkeys"`
+	AdminThreshold int32    `from:"admin_threshold" json:"admin_threshold" validate:"gte=0"`
+	ProduceTimer    int64 `from:"produce_timer"     json:"produce_timer"     validate:"gte=0"` //ms, 0 for default
+	MergeTimer      int64 `from:"merge_timer"       json:"merge_timer"       validate:"gte=0"` //ms, 0 for default
+	TrxsTotalSize   int64 `from:"trxs_total_size"   json:"trxs_total_size"   validate:"gte=0"`
+	ObjectSizeLimit int64 `from:"object_size_limit" json:"object_size_limit" validate:"gte=0"`
+	TrxExpire       int64 `from:"trx_expire"        json:"trx_expire"        validate:"gte=0"` //s, 0 for default
+	LegacyBlockId   bool  `from:"legacy_block_id"   json:"legacy_block_id"` //uuid block ids for nodes not knowing hash ids
 }
 
+// new groups encrypt trx data with the cipher envelope, legacy is kept for nodes not knowing the envelope
+const DEFAULT_CIPHER_ALG = "aes-gcm"
+
 type GroupSeed struct {
 	GenesisBlock   *chestnutpb.Block `json:"genesis_block" validate:"required"`
 	GroupId        string          `json:"group_id" validate:"required"`
@@ -38,9 +49,11 @@ type GroupSeed struct {
 	CipherKey      string          `json:"cipher_key" validate:"required"`
 	AppKey         string          `json:"app_key" validate:"required"`
 	Signature      string          `json:"signature" validate:"required"`
+	CipherAlg      string          `json:"cipher_alg,omitempty"`
 }
 
-func CreateGroup(params *CreateGroupParam, nodeoptions *options.NodeOptions, appdb *appdata.AppDb) (*GroupSeed, error) {
+// create a group hosted by the tenant of groupmgr
+func CreateGroup(params *CreateGroupParam, nodeoptions *options.NodeOptions, appdb *appdata.AppDb, groupmgr *chain.GroupMgr) (*GroupSeed, error) {
 	validate := validator.New()
 	if err := validate.Struct(params); err != nil {
 		return nil, err
@@ -50,9 +63,21 @@ func CreateGroup(params *CreateGroupParam, nodeoptions *options.NodeOptions, app
 		return nil, fmt.Errorf("consensus type not supported")
 	}
 
+	// the config with defaults filled is kept in the genesis block, defaults of later versions don't change it
+	config := chain.WithDefaults(&chestnutpb.GroupConfig{
+		ProduceTimer:    params.ProduceTimer,
+		MergeTimer:      params.MergeTimer,
+		TrxsTotalSize:   params.TrxsTotalSize,
+		ObjectSizeLimit: params.ObjectSizeLimit,
+		TrxExpire:       params.TrxExpire,
+	})
+	if err := chain.CheckGroupConfig(config); err != nil {
+		return nil, err
+	}
+
 	groupid := guuid.New()
 
-	ks := nodectx.GetNodeCtx().Keystore
+	ks := groupmgr.Tenant().Keystore
 
 	// init sign key
 	hexkey, err := initSignKey(groupid.String(), ks, nodeoptions)
@@ -72,7 +97,23 @@ func CreateGroup(params *CreateGroupParam, nodeoptions *options.NodeOptions, app
 		return nil, errors.New("group key can't be decoded, err:" + err.Error())
 	}
 
-	genesisBlock, err := chain.CreateGeneisBlock(groupid.String(), p2ppubkey)
+	// the group key of the creator is always one of the admins
+	var admins *chestnutpb.AdminSet
+	if params.AdminThreshold > 0 {
+		admins = &chestnutpb.AdminSet{Pubkeys: []string{p2pcrypto.ConfigEncodeKey(groupSignPubkey)}, Threshold: params.AdminThreshold}
+		for _, pubkey := range params.AdminPubkeys {
+			if pubkey != admins.Pubkeys[0] {
+				admins.Pubkeys = append(admins.Pubkeys, pubkey)
+			}
+		}
+		if err := chain.CheckAdminSet(admins); err != nil {
+			return nil, err
+		}
+	} else if len(params.AdminPubkeys) > 0 {
+		return nil, errors.New("admin_threshold is required with admin_pubkeys")
+	}
+
+	genesisBlock, err := chain.CreateGeneisBlock(groupid.String(), p2ppubkey, admins, config, params.LegacyBlockId, ks)
 	if err != nil {
 		return nil, err
 	}
@@ -105,24 +146,23 @@ func CreateGroup(params *CreateGroupParam, nodeoptions *options.NodeOptions, app
 	}
 
 	item.CipherKey = hex.EncodeToString(cipherKey)
+	item.Admins = admins
+	item.CipherAlg = params.CipherAlg
+	if item.CipherAlg == "" {
+		item.CipherAlg = DEFAULT_CIPHER_ALG
+	}
 	item.AppKey = params.AppKey
 	item.HighestHeight = 0
 	item.HighestBlockId = genesisBlock.BlockId
 	item.LastUpdate = time.Now().UnixNano()
 	item.GenesisBlock = genesisBlock
 
-	var group *chain.Group
-	group = &chain.Group{}
-
 	// create group
-	err = group.CreateGrp(item)
+	_, err = groupmgr.Create(item)
 	if err != nil {
 		return nil, err
 	}
 
-	groupmgr := chain.GetGroupMgr()
-	groupmgr.Groups[group.Item.GroupId] = group
-
 	// create group result
 	encodedCipherKey := hex.EncodeToString(cipherKey)
 
@@ -136,10 +176,11 @@ func CreateGroup(params *CreateGroupParam, nodeoptions *options.NodeOptions, app
 		CipherKey:      encodedCipherKey,
 		AppKey:         params.AppKey,
 		Signature:      "", // updated by GenerateGroupSeedSignature
+		CipherAlg:      item.CipherAlg,
 	}
 
 	// generate signature
-	if err := GenerateGroupSeedSignature(createGrpResult); err != nil {
+	if err := GenerateGroupSeedSignature(createGrpResult, ks); err != nil {
 		return nil, err
 	}
 
@@ -155,7 +196,7 @@ func CreateGroup(params *CreateGroupParam, nodeoptions *options.NodeOptions, app
 
 
 
-func GenerateGroupSeedSignature(result *GroupSeed) error {
+func GenerateGroupSeedSignature(result *GroupSeed, ks localcrypto.Keystore) error {
 	genesisBlockBytes, err := json.Marshal(result.GenesisBlock)
 	if err != nil {
 		e := fmt.Errorf("Marshal genesis block failed with msg: %s", err)
@@ -183,9 +224,10 @@ func GenerateGroupSeedSignature(result *GroupSeed) error {
 	buffer.Write([]byte(result.EncryptionType))
 	buffer.Write([]byte(result.AppKey))
 	buffer.Write(cipherKey)
+	// seeds of legacy groups have no cipher alg, their signature is unchanged
+	buffer.Write([]byte(result.CipherAlg))
 
 	hash := localcrypto.Hash(buffer.Bytes())
-	ks := nodectx.GetNodeCtx().Keystore
 	signature, err := ks.SignByKeyName(result.GroupId, hash)
 	if err != nil {
 		e := fmt.Errorf("ks.SignByKeyName failed: %s", err)
@@ -208,6 +250,7 @@ func ToPbGroupSeed(s GroupSeed) chestnutpb.GroupSeed {
 		CipherKey:      s.CipherKey,
 		AppKey:         s.AppKey,
 		Signature:      s.Signature,
+		CipherAlg:      s.CipherAlg,
 	}
 }
 
@@ -224,5 +267,6 @@ func FromPbGroupSeed(s *chestnutpb.GroupSeed) GroupSeed {
 		CipherKey:      s.CipherKey,
 		AppKey:         s.AppKey,
 		Signature:      s.Signature,
+		CipherAlg:      s.CipherAlg,
 	}
 }
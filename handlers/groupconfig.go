@@ -0,0 +1,77 @@
+// Package handlers provides handlers for the api package.
+package handlers
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lixvyang/chestnut/chain"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// change the config of a group, fields not set keep the current value. The change takes effect
+// from the block at effectiveHeight, CONFIG_DELAY_BLOCKS after the current height if 0. The owner sends the
+// config trx, in a group owned by admins the change is proposed for the admins to cosign.
+func UpdGroupConfig(group *chain.Group, ks localcrypto.Keystore, config *chestnutpb.GroupConfig, effectiveHeight int64, memo string) (*chestnutpb.ConfigItem, string, *chestnutpb.AdminAction, error) {
+	isAdminGroup := chain.IsAdminGroup(group.Item)
+	if !isAdminGroup && group.Item.OwnerPubKey != group.Item.UserSignPubkey {
+		return nil, "", nil, errors.New("Only group owner can change the group config")
+	}
+
+	current := group.GetConfig()
+	if config.ProduceTimer == 0 {
+		config.ProduceTimer = current.ProduceTimer
+	}
+	if config.MergeTimer == 0 {
+		config.MergeTimer = current.MergeTimer
+	}
+	if config.TrxsTotalSize == 0 {
+		config.TrxsTotalSize = current.TrxsTotalSize
+	}
+	if config.ObjectSizeLimit == 0 {
+		config.ObjectSizeLimit = current.ObjectSizeLimit
+	}
+	if config.TrxExpire == 0 {
+		config.TrxExpire = current.TrxExpire
+	}
+	if err := chain.CheckGroupConfig(config); err != nil {
+		return nil, "", nil, err
+	}
+
+	height, _ := group.ChainHead()
+	if effectiveHeight == 0 {
+		effectiveHeight = height + chain.CONFIG_DELAY_BLOCKS
+	} else if effectiveHeight <= height+1 {
+		return nil, "", nil, fmt.Errorf("effective height must be above the next block height %d", height+1)
+	}
+
+	item := &chestnutpb.ConfigItem{
+		GroupId:          group.Item.GroupId,
+		Config:           config,
+		EffectiveHeight:  effectiveHeight,
+		GroupOwnerPubkey: group.Item.OwnerPubKey,
+		Memo:             memo,
+		TimeStamp:        time.Now().UnixNano(),
+	}
+
+	if isAdminGroup {
+		data, err := proto.Marshal(item)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		action, err := ProposeAdminAction(group, ks, "", chestnutpb.TrxType_CONFIG, data)
+		return item, "", action, err
+	}
+
+	signature, err := ks.SignByKeyName(group.Item.GroupId, chain.ConfigHash(item))
+	if err != nil {
+		return nil, "", nil, err
+	}
+	item.GroupOwnerSign = hex.EncodeToString(signature)
+	trxId, err := group.UpdConfig(item)
+	return item, trxId, nil, err
+}
@@ -0,0 +1,154 @@
+// Package handlers provides handlers for the api package.
+package handlers
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	guuid "github.com/google/uuid"
+	"github.com/lixvyang/chestnut/appdata"
+	"github.com/lixvyang/chestnut/chain"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// key of the invitee of a bound invitation if no key name is given, the node key
+const INVITEE_KEY_NAME = "default"
+
+// mint an invitation of a group created by the node, the invitation is sent on chain and
+// returned with the token wrapping the seed. expire 0 for never, maxUse 0 for no limit.
+func CreateInvite(group *chain.Group, ks localcrypto.Keystore, appdb *appdata.AppDb, expire int64, maxUse int32, inviteePubkey string) (*chestnutpb.InviteItem, string, string, error) {
+	if group.Item.OwnerPubKey != group.Item.UserSignPubkey {
+		return nil, "", "", errors.New("Only group owner can create invitations")
+	}
+	seed, err := appdb.GetGroupSeed(group.Item.GroupId)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("seed of group %s not found, err:%s", group.Item.GroupId, err)
+	}
+
+	item := &chestnutpb.InviteItem{
+		InviteId:      guuid.New().String(),
+		GroupId:       group.Item.GroupId,
+		Expire:        expire,
+		MaxUse:        maxUse,
+		InviteePubkey: inviteePubkey,
+		OwnerPubkey:   group.Item.OwnerPubKey,
+		TimeStamp:     time.Now().UnixNano(),
+		Action:        chestnutpb.ActionType_ADD,
+	}
+	signature, err := ks.SignByKeyName(group.Item.GroupId, chain.InviteHash(item))
+	if err != nil {
+		return nil, "", "", err
+	}
+	item.OwnerSign = hex.EncodeToString(signature)
+
+	tokenbytes, err := proto.Marshal(&chestnutpb.InviteToken{Invite: item, Seed: seed})
+	if err != nil {
+		return nil, "", "", err
+	}
+	trxId, err := group.UpdInvite(item)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return item, base64.RawURLEncoding.EncodeToString(tokenbytes), trxId, nil
+}
+
+func RevokeInvite(group *chain.Group, ks localcrypto.Keystore, inviteId string) (string, error) {
+	if group.Item.OwnerPubKey != group.Item.UserSignPubkey {
+		return "", errors.New("Only group owner can revoke invitations")
+	}
+	invite, err := group.GetInvite(inviteId)
+	if err != nil {
+		return "", err
+	}
+	if invite == nil {
+		return "", fmt.Errorf("invitation %s not found", inviteId)
+	}
+
+	item := &chestnutpb.InviteItem{
+		InviteId:      invite.InviteId,
+		GroupId:       invite.GroupId,
+		Expire:        invite.Expire,
+		MaxUse:        invite.MaxUse,
+		InviteePubkey: invite.InviteePubkey,
+		OwnerPubkey:   group.Item.OwnerPubKey,
+		TimeStamp:     time.Now().UnixNano(),
+		Action:        chestnutpb.ActionType_REMOVE,
+	}
+	signature, err := ks.SignByKeyName(group.Item.GroupId, chain.InviteHash(item))
+	if err != nil {
+		return "", err
+	}
+	item.OwnerSign = hex.EncodeToString(signature)
+	return group.UpdInvite(item)
+}
+
+// decode an invitation token, the invitation must be signed by the owner of the seed and not expired
+func OpenInvite(token string) (*chestnutpb.InviteItem, *GroupSeed, error) {
+	tokenbytes, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invitation can't be decoded, err:%s", err)
+	}
+	inviteToken := &chestnutpb.InviteToken{}
+	if err := proto.Unmarshal(tokenbytes, inviteToken); err != nil {
+		return nil, nil, fmt.Errorf("invitation can't be decoded, err:%s", err)
+	}
+	if inviteToken.Invite == nil || inviteToken.Seed == nil {
+		return nil, nil, errors.New("invitation can't be decoded")
+	}
+	invite := inviteToken.Invite
+	if invite.GroupId != inviteToken.Seed.GroupId {
+		return nil, nil, errors.New("invitation of another group")
+	}
+	if err := chain.VerifyInvite(invite, inviteToken.Seed.OwnerPubkey); err != nil {
+		return nil, nil, err
+	}
+	if err := chain.CheckInvite(invite, time.Now().UnixNano()); err != nil {
+		return nil, nil, err
+	}
+	seed := FromPbGroupSeed(inviteToken.Seed)
+	return invite, &seed, nil
+}
+
+// the invitee key of a bound invitation, the node key if keyname is empty
+func CheckInvitee(invite *chestnutpb.InviteItem, ks localcrypto.Keystore, keyname string) error {
+	if invite.InviteePubkey == "" {
+		return nil
+	}
+	if keyname == "" {
+		keyname = INVITEE_KEY_NAME
+	}
+	pubkey, err := GetSignPubkey(keyname, ks)
+	if err != nil {
+		return err
+	}
+	if pubkey != invite.InviteePubkey {
+		return fmt.Errorf("invitation is bound to another invitee")
+	}
+	return nil
+}
+
+// send the redemption of the joined group on chain
+func RedeemInvite(group *chain.Group, ks localcrypto.Keystore, keyname string, invite *chestnutpb.InviteItem) (string, error) {
+	item := &chestnutpb.RedeemItem{
+		InviteId:       invite.InviteId,
+		GroupId:        group.Item.GroupId,
+		RedeemerPubkey: group.Item.UserSignPubkey,
+		TimeStamp:      time.Now().UnixNano(),
+	}
+	if invite.InviteePubkey != "" {
+		if keyname == "" {
+			keyname = INVITEE_KEY_NAME
+		}
+		signature, err := ks.SignByKeyName(keyname, chain.RedeemHash(item))
+		if err != nil {
+			return "", err
+		}
+		item.InviteeSign = hex.EncodeToString(signature)
+	}
+	return group.RedeemInvite(item)
+}
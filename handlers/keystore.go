@@ -0,0 +1,161 @@
+// Package handlers provides handlers for the api package.
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	logging "github.com/ipfs/go-log/v2"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"github.com/lixvyang/chestnut/storage"
+	"github.com/lixvyang/chestnut/utils/options"
+	"google.golang.org/protobuf/proto"
+)
+
+var keystore_log = logging.Logger("keystore")
+
+// keys of the node itself, they are not bound to a group
+var nodeKeyNames = map[string]bool{"default": true, "network": true}
+
+type KeyOrphans struct {
+	OrphanKeys   []*localcrypto.KeyItem // key files of groups not hosted by the node
+	MissingKeys  []string               // sign key map entries without a sign key file
+	UnmappedKeys []string               // sign key files without an address in the sign key map
+}
+
+func IsNodeKey(keyname string) bool {
+	return nodeKeyNames[keyname]
+}
+
+// import a key exported by ExportKey, the address of a sign key is saved to the sign key map
+func ImportKey(ks *localcrypto.DirKeyStore, nodeoptions *options.NodeOptions, keyname string, keytype localcrypto.KeyType, exported string, passphrase string) (string, error) {
+	addr, err := ks.ImportExportedKey(keyname, keytype, exported, passphrase)
+	if err != nil {
+		return "", err
+	}
+	if keytype == localcrypto.Sign {
+		if err := nodeoptions.SetSignKeyMap(keyname, addr); err != nil {
+			return "", fmt.Errorf("save key map %s err: %s", addr, err.Error())
+		}
+	}
+	return addr, nil
+}
+
+func RenameKey(ks *localcrypto.DirKeyStore, nodeoptions *options.NodeOptions, keyname string, newname string, keytype localcrypto.KeyType) error {
+	if IsNodeKey(keyname) || IsNodeKey(newname) {
+		return errors.New("node keys can't be renamed")
+	}
+	if err := ks.RenameKey(keyname, newname, keytype); err != nil {
+		return err
+	}
+	if keytype == localcrypto.Sign {
+		return nodeoptions.RenameSignKeyMap(keyname, newname)
+	}
+	return nil
+}
+
+// delete the key file and its sign key map entry, keys of hosted groups are kept
+func DeleteKey(ks *localcrypto.DirKeyStore, nodeoptions *options.NodeOptions, keyname string, keytype localcrypto.KeyType, groupIds []string) error {
+	if IsNodeKey(keyname) {
+		return errors.New("node keys can't be deleted")
+	}
+	for _, groupId := range groupIds {
+		if groupId == keyname {
+			return fmt.Errorf("key %s is used by group %s, leave the group first", keyname, groupId)
+		}
+	}
+	if err := ks.DeleteKey(keyname, keytype); err != nil {
+		return err
+	}
+	if keytype == localcrypto.Sign {
+		return nodeoptions.DelSignKeyMap(keyname)
+	}
+	return nil
+}
+
+// find the keys of groups the keystore owner doesn't host and the sign key map entries out of sync with the key files.
+// Keystores sharing a sign key map are checked together.
+func CheckKeyOrphans(keystores []*localcrypto.DirKeyStore, signkeymap map[string]string, groupIds []string) (*KeyOrphans, error) {
+	groups := make(map[string]bool)
+	for _, groupId := range groupIds {
+		groups[groupId] = true
+	}
+
+	orphans := &KeyOrphans{OrphanKeys: []*localcrypto.KeyItem{}, MissingKeys: []string{}, UnmappedKeys: []string{}}
+	signkeys := make(map[string]bool)
+	for _, ks := range keystores {
+		keys, err := ks.ListKeys()
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range keys {
+			if key.KeyType == localcrypto.Sign {
+				signkeys[key.KeyName] = true
+				if _, ok := signkeymap[key.KeyName]; !ok {
+					orphans.UnmappedKeys = append(orphans.UnmappedKeys, key.KeyName)
+				}
+			}
+			if !IsNodeKey(key.KeyName) && !groups[key.KeyName] {
+				orphans.OrphanKeys = append(orphans.OrphanKeys, key)
+			}
+		}
+	}
+
+	for keyname := range signkeymap {
+		if !signkeys[keyname] {
+			orphans.MissingKeys = append(orphans.MissingKeys, keyname)
+		}
+	}
+	sort.Strings(orphans.MissingKeys)
+	return orphans, nil
+}
+
+// ids of the groups saved by a tenant, "" for the node itself. Groups not loaded at startup are included
+func SavedGroupIds(dbMgr *storage.DbMgr, tenant string) ([]string, error) {
+	prefix := []string{}
+	if tenant != "" {
+		prefix = append(prefix, tenant)
+	}
+	groupItemsBytes, err := dbMgr.GetGroupsBytes(prefix...)
+	if err != nil {
+		return nil, err
+	}
+	groupIds := []string{}
+	for _, b := range groupItemsBytes {
+		item := &chestnutpb.GroupItem{}
+		if err := proto.Unmarshal(b, item); err != nil {
+			keystore_log.Errorf("can't read saved group: %s", err)
+			continue
+		}
+		groupIds = append(groupIds, item.GroupId)
+	}
+	return groupIds, nil
+}
+
+// every group saved in the db, loaded or not, keyed by the node name the chain data of
+// the group is saved under: nodename for the groups of the node, the tenant name for tenant groups.
+// A group item that can't be read is logged and skipped, like GroupMgr.Start does
+func SavedGroupItems(dbMgr *storage.DbMgr, nodename string) (map[string][]*chestnutpb.GroupItem, error) {
+	groupsBytes, err := dbMgr.GetAllGroupsBytes()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string][]*chestnutpb.GroupItem)
+	for tenant, items := range groupsBytes {
+		name := tenant
+		if name == "" {
+			name = nodename
+		}
+		for _, b := range items {
+			item := &chestnutpb.GroupItem{}
+			if err := proto.Unmarshal(b, item); err != nil {
+				keystore_log.Errorf("can't read saved group of %s: %s", name, err)
+				continue
+			}
+			result[name] = append(result[name], item)
+		}
+	}
+	return result, nil
+}
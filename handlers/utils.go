@@ -3,9 +3,11 @@ package handlers
 
 import (
 	"strings"
+	"encoding/hex"
 	"errors"
 	"fmt"
 
+	p2pcrypto "github.com/libp2p/go-libp2p-core/crypto"
 	localcrypto "github.com/lixvyang/chestnut/crypto"
 	"github.com/lixvyang/chestnut/utils/options"
 )
@@ -41,3 +43,24 @@ func initEncodeKey(groupId string, bks localcrypto.Keystore) (string, error) {
 	}
 	return userEncryptKey, nil
 }
+
+// the sign pubkey of a key, encoded like the group owner pubkey
+func GetSignPubkey(keyname string, ks localcrypto.Keystore) (string, error) {
+	hexkey, err := ks.GetEncodedPubkey(keyname, localcrypto.Sign)
+	if err != nil {
+		return "", err
+	}
+	pubkeybytes, err := hex.DecodeString(hexkey)
+	if err != nil {
+		return "", err
+	}
+	p2ppubkey, err := p2pcrypto.UnmarshalSecp256k1PublicKey(pubkeybytes)
+	if err != nil {
+		return "", err
+	}
+	serializedpub, err := p2pcrypto.MarshalPublicKey(p2ppubkey)
+	if err != nil {
+		return "", err
+	}
+	return p2pcrypto.ConfigEncodeKey(serializedpub), nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/handlers"
+	"github.com/lixvyang/chestnut/utils/cli"
+	"github.com/lixvyang/chestnut/utils/options"
+)
+
+const keysUsage = `Usage: chestnut [options] keys <command>
+
+Manage the keys of the keystore dir, the node must not be running.
+With -tenants <name> the keys of the tenant are managed instead of the keys of the node.
+  list                                  list the keys
+  export <key_name> <sign|encrypt>      export a key encrypted under a new passphrase
+  import <key_name> <sign|encrypt> <key> import an exported key
+  rename <key_name> <new_name> <sign|encrypt>
+  delete <key_name> <sign|encrypt>      delete the key of a group the node left
+  passwd                                re-encrypt all keys of the keystore under a new password
+  orphans                               list keys of groups not saved and sign key map entries out of sync
+`
+
+// keysRet runs the keys command on the keystore of the node, or of the tenant given by -tenants
+func keysRet(config cli.Config, args []string) int {
+	if len(args) == 0 {
+		fmt.Print(keysUsage)
+		return 2
+	}
+	if len(config.Tenants) > 1 {
+		fmt.Println("keys of one tenant can be managed at a time")
+		return 2
+	}
+
+	nodeoptions, err := options.InitNodeOptions(config.ConfigDir, config.PeerName)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	ks, _, err := localcrypto.InitDirKeyStore(config.KeyStoreName, config.KeyStoreDir)
+	if err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	needpassword := args[0] != "list" && args[0] != "orphans"
+	password := ""
+	if needpassword || len(config.Tenants) > 0 {
+		var code int
+		password, code, err = loadPassword(config, false)
+		if err != nil {
+			fmt.Println(err)
+			return code
+		}
+	}
+	ks.Unlock(nodeoptions.SignKeyMap, password)
+
+	tenant := ""
+	passwordfile := config.PasswordFile
+	if os.Getenv("CHESTNUT_PASSWORD") != "" {
+		passwordfile = ""
+	}
+	if len(config.Tenants) == 1 {
+		tenant = config.Tenants[0]
+		ks, nodeoptions, password, err = openTenantKeystore(config, tenant, nodeoptions, password)
+		if err != nil {
+			fmt.Println(err)
+			return 1
+		}
+		passwordfile = tenantPasswordFile(config, tenant)
+		if os.Getenv(tenantPasswordEnv(tenant)) != "" {
+			passwordfile = ""
+		}
+	}
+
+	if err := runKeysCommand(config, tenant, nodeoptions, ks, password, passwordfile, args); err != nil {
+		fmt.Println(err)
+		return 1
+	}
+	return 0
+}
+
+func runKeysCommand(config cli.Config, tenant string, nodeoptions *options.NodeOptions, ks *localcrypto.DirKeyStore, password string, passwordfile string, args []string) error {
+	keytypeArg := func(i int) (localcrypto.KeyType, error) {
+		if len(args) <= i {
+			return 0, fmt.Errorf("key type is required\n%s", keysUsage)
+		}
+		return localcrypto.ParseKeyType(args[i])
+	}
+
+	switch args[0] {
+	case "list":
+		keys, err := ks.ListKeys()
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			fmt.Printf("%-40s %-8s %s\n", key.KeyName, key.KeyType, key.Addr)
+		}
+	case "export":
+		keytype, err := keytypeArg(2)
+		if err != nil {
+			return err
+		}
+		fmt.Println("Passphrase of the exported key")
+		passphrase, err := localcrypto.PassphrasePromptForEncryption()
+		if err != nil {
+			return err
+		}
+		key, err := ks.ExportKey(args[1], keytype, passphrase)
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+	case "import":
+		keytype, err := keytypeArg(2)
+		if err != nil {
+			return err
+		}
+		if len(args) < 4 {
+			return fmt.Errorf("exported key is required\n%s", keysUsage)
+		}
+		fmt.Println("Passphrase of the exported key")
+		passphrase, err := localcrypto.PassphrasePromptForUnlock()
+		if err != nil {
+			return err
+		}
+		addr, err := handlers.ImportKey(ks, nodeoptions, args[1], keytype, args[3], passphrase)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("key %s imported: %s\n", args[1], addr)
+	case "rename":
+		keytype, err := keytypeArg(3)
+		if err != nil {
+			return err
+		}
+		if err := handlers.RenameKey(ks, nodeoptions, args[1], args[2], keytype); err != nil {
+			return err
+		}
+		fmt.Printf("key %s renamed to %s\n", args[1], args[2])
+	case "delete":
+		keytype, err := keytypeArg(2)
+		if err != nil {
+			return err
+		}
+		groupIds, err := keysSavedGroupIds(config, tenant)
+		if err != nil {
+			return err
+		}
+		if err := handlers.DeleteKey(ks, nodeoptions, args[1], keytype, groupIds); err != nil {
+			return err
+		}
+		fmt.Printf("key %s deleted\n", args[1])
+	case "passwd":
+		fmt.Println("New password")
+		newpassword, err := localcrypto.PassphrasePromptForEncryption()
+		if err != nil {
+			return err
+		}
+		if err := ks.ChangePassword(password, newpassword, passwordfile); err != nil {
+			return err
+		}
+		fmt.Println("password changed")
+	case "orphans":
+		groupIds, err := keysSavedGroupIds(config, tenant)
+		if err != nil {
+			return err
+		}
+		orphans, err := handlers.CheckKeyOrphans([]*localcrypto.DirKeyStore{ks}, nodeoptions.SignKeyMap, groupIds)
+		if err != nil {
+			return err
+		}
+		for _, key := range orphans.OrphanKeys {
+			fmt.Printf("orphan key:   %s %s\n", key.KeyName, key.KeyType)
+		}
+		for _, keyname := range orphans.MissingKeys {
+			fmt.Printf("missing key:  %s\n", keyname)
+		}
+		for _, keyname := range orphans.UnmappedKeys {
+			fmt.Printf("unmapped key: %s\n", keyname)
+		}
+	default:
+		return fmt.Errorf("unknown command %s\n%s", args[0], keysUsage)
+	}
+	return nil
+}
+
+func keysSavedGroupIds(config cli.Config, tenant string) ([]string, error) {
+	dbManager, err := createDb(config.DataDir+"/"+config.PeerName, config.DbEngine)
+	if err != nil {
+		return nil, err
+	}
+	defer dbManager.CloseDb()
+	return handlers.SavedGroupIds(dbManager, tenant)
+}
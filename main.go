@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/signal"
 	"path"
+	"path/filepath"
 	"strings"
 	"syscall"
 	"time"
@@ -21,6 +22,7 @@ import (
 	"github.com/lixvyang/chestnut/appdata"
 	"github.com/lixvyang/chestnut/chain"
 	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/handlers"
 	"github.com/lixvyang/chestnut/nodectx"
 	"github.com/lixvyang/chestnut/p2p"
 	appapi "github.com/lixvyang/chestnut/pkg/app/api"
@@ -30,6 +32,14 @@ import (
 )
 
 const DEFAULT_KEY_NAME = "default"
+const NETWORK_KEY_NAME = "network"
+
+// exit codes of the node
+const (
+	EXIT_PASSWORD_REQUIRED = 3 // no password is given and it can't be prompted
+	EXIT_PASSWORD_FILE     = 4 // the password file or credential can't be read or written
+	EXIT_UNLOCK_FAILED     = 5 // the keystore can't be unlocked with the password
+)
 
 var (
 	ReleaseVersion string
@@ -50,42 +60,247 @@ func checkLockError(err error) {
 	}
 }
 
-func createDb(path string) (*storage.DbMgr, error) {
-	var err error
-	groupDb := storage.CSBadger{}
-	dataDb := storage.CSBadger{}
-	err = groupDb.Init(path)
+// group items and chain data are saved in one storage at path, so a DbMgr transaction is atomic
+func createDb(path string, engine string) (*storage.DbMgr, error) {
+	db, err := storage.NewChestnutStorage(engine)
 	if err != nil {
 		return nil, err
 	}
-
-	err = dataDb.Init(path)
+	err = db.Init(path)
 	if err != nil {
 		return nil, err
 	}
 
-	manager := storage.DbMgr{&groupDb, &dataDb, nil, path}
+	manager := storage.DbMgr{GroupInfoDb: db, Db: db, Auth: nil, DataPath: path}
 	return &manager, nil
 }
 
-func createAppDb(path string) (*appdata.AppDb, error) {
-	var err error
-	db := storage.CSBadger{}
+func createAppDb(path string, engine string) (*appdata.AppDb, error) {
+	db, err := storage.NewChestnutStorage(engine)
+	if err != nil {
+		return nil, err
+	}
 	err = db.Init(path + "_appdb")
 	if err != nil {
 		return nil, err
 	}
 
 	app := appdata.NewAppDb()
-	app.Db = &db
+	app.Db = db
 	app.DataPath = path
 	return app, nil
 }
 
+// loadPassword gets the keystore password from CHESTNUT_PASSWORD, the password file,
+// the systemd credential or the terminal, in that order. When a new keystore is created
+// and the password file doesn't exist yet, a passphrase is generated and written to it.
+func loadPassword(config cli.Config, newkeystore bool) (string, int, error) {
+	if password := os.Getenv("CHESTNUT_PASSWORD"); password != "" {
+		return password, 0, nil
+	}
+
+	if config.PasswordFile != "" {
+		_, err := os.Stat(config.PasswordFile)
+		switch {
+		case err == nil:
+			password, err := localcrypto.LoadPasswordFile(config.PasswordFile)
+			if err != nil {
+				return "", EXIT_PASSWORD_FILE, err
+			}
+			return password, 0, nil
+		case os.IsNotExist(err) && newkeystore:
+			password := localcrypto.GeneratePassphrase()
+			if err := localcrypto.WritePasswordFile(config.PasswordFile, password); err != nil {
+				return "", EXIT_PASSWORD_FILE, err
+			}
+			mainlog.Infof("password of the new keystore is written to %s", config.PasswordFile)
+			return password, 0, nil
+		default:
+			return "", EXIT_PASSWORD_FILE, fmt.Errorf("read password file %s failed: %s", config.PasswordFile, err)
+		}
+	}
+
+	password, ok, err := localcrypto.LoadCredentialPassword()
+	if err != nil {
+		return "", EXIT_PASSWORD_FILE, err
+	}
+	if ok {
+		return password, 0, nil
+	}
+
+	if config.NonInteractive {
+		if newkeystore {
+			return "", EXIT_PASSWORD_REQUIRED, fmt.Errorf("no password is given, use -password-file to write a generated password for the new keystore")
+		}
+		return "", EXIT_PASSWORD_REQUIRED, fmt.Errorf("no password is given by CHESTNUT_PASSWORD, -password-file or the %s credential", localcrypto.PASSWORD_CREDENTIAL_NAME)
+	}
+
+	if !newkeystore {
+		password, err = localcrypto.PassphrasePromptForUnlock()
+		if err != nil {
+			return "", EXIT_PASSWORD_REQUIRED, err
+		}
+		return password, 0, nil
+	}
+	password, err = localcrypto.PassphrasePromptForEncryption()
+	if err != nil {
+		return "", EXIT_PASSWORD_REQUIRED, err
+	}
+	fmt.Println("Please keeping your password safe, We can't recover or reset your password.")
+	fmt.Println("Your password:", password)
+	fmt.Println("After saving the password, press any key to continue.")
+	os.Stdin.Read(make([]byte, 1))
+	return password, 0, nil
+}
+
+// send the sign key map to the signer and make sure it has the default sign key of the node
+func initRemoteKeystore(ks *localcrypto.RemoteKeyStore, nodeoptions *options.NodeOptions) error {
+	if err := ks.Unlock(nodeoptions.SignKeyMap, ""); err != nil {
+		return err
+	}
+	if _, err := ks.GetEncodedPubkey(DEFAULT_KEY_NAME, localcrypto.Sign); err == nil {
+		return nil
+	}
+	addr, err := ks.NewKeyWithDefaultPassword(DEFAULT_KEY_NAME, localcrypto.Sign)
+	if err != nil {
+		return err
+	}
+	mainlog.Infof("default sign key created by the signer, address: <%s>", addr)
+	return nodeoptions.SetSignKeyMap(DEFAULT_KEY_NAME, addr)
+}
+
+func tenantPasswordEnv(name string) string {
+	return "CHESTNUT_PASSWORD_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// password file of a tenant keystore, saved in the config dir
+func tenantPasswordFile(config cli.Config, name string) string {
+	return filepath.Join(config.ConfigDir, fmt.Sprintf("%s_%s_password", config.PeerName, name))
+}
+
+// loadTenantPassword gets the password of a tenant keystore from CHESTNUT_PASSWORD_<TENANT> or the tenant
+// password file. A passphrase is generated and written to the password file for a new tenant keystore.
+// Tenant keystores created before tenants had their own password are unlocked with the node password,
+// the password file is written when the password of the tenant is changed.
+func loadTenantPassword(config cli.Config, name string, newkeystore bool, nodepassword string) (string, error) {
+	if password := os.Getenv(tenantPasswordEnv(name)); password != "" {
+		return password, nil
+	}
+
+	passwordfile := tenantPasswordFile(config, name)
+	_, err := os.Stat(passwordfile)
+	switch {
+	case err == nil:
+		return localcrypto.LoadPasswordFile(passwordfile)
+	case os.IsNotExist(err) && newkeystore:
+		password := localcrypto.GeneratePassphrase()
+		if err := localcrypto.WritePasswordFile(passwordfile, password); err != nil {
+			return "", err
+		}
+		mainlog.Infof("password of the tenant <%s> keystore is written to %s", name, passwordfile)
+		return password, nil
+	case os.IsNotExist(err):
+		mainlog.Warningf("tenant <%s> has no password file, the keystore is unlocked with the node password", name)
+		return nodepassword, nil
+	default:
+		return "", fmt.Errorf("read password file %s failed: %s", passwordfile, err)
+	}
+}
+
+// openTenantKeystore opens and unlocks the keystore of a tenant under <keystoredir>/tenants/<name> with its own options
+// file <peername>_<name>_options.toml, the sign key map of the tenant is saved there. Sign keys of the tenant
+// mapped in the node options by earlier versions are copied to the tenant options.
+func openTenantKeystore(config cli.Config, name string, nodeoptions *options.NodeOptions, nodepassword string) (*localcrypto.DirKeyStore, *options.NodeOptions, string, error) {
+	if !nodectx.IsValidTenantName(name) {
+		return nil, nil, "", fmt.Errorf("invalid tenant name: %s", name)
+	}
+	ks, signkeycount, err := localcrypto.InitDirKeyStore(name, filepath.Join(config.KeyStoreDir, "tenants", name))
+	if err != nil {
+		return nil, nil, "", err
+	}
+	tenantoptions, err := options.LoadNodeOptions(config.ConfigDir, config.PeerName+"_"+name)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if len(tenantoptions.SignKeyMap) == 0 && signkeycount > 0 {
+		ks.Unlock(nodeoptions.SignKeyMap, "")
+		keys, err := ks.ListKeys()
+		if err != nil {
+			return nil, nil, "", err
+		}
+		for _, key := range keys {
+			if key.KeyType == localcrypto.Sign && key.Addr != "" {
+				if err := tenantoptions.SetSignKeyMap(key.KeyName, key.Addr); err != nil {
+					return nil, nil, "", err
+				}
+			}
+		}
+	}
+
+	password, err := loadTenantPassword(config, name, signkeycount == 0, nodepassword)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	if err := ks.Unlock(tenantoptions.SignKeyMap, password); err != nil {
+		return nil, nil, "", err
+	}
+	return ks, tenantoptions, password, nil
+}
+
+// every tenant has its own keystore, options and api token. When tenants are hosted, the node itself
+// also needs its api token, requests without a token are rejected.
+func initTenants(config cli.Config, nodeoptions *options.NodeOptions, dbManager *storage.DbMgr, password string) error {
+	if len(config.Tenants) == 0 {
+		return nil
+	}
+	if _, err := nodeoptions.GetTenantToken(nodectx.GetNodeCtx().Name); err != nil {
+		return err
+	}
+	for _, name := range config.Tenants {
+		ks, tenantoptions, _, err := openTenantKeystore(config, name, nodeoptions, password)
+		if err != nil {
+			return err
+		}
+		passwordfile := tenantPasswordFile(config, name)
+		if os.Getenv(tenantPasswordEnv(name)) != "" {
+			passwordfile = ""
+		}
+		tenant, err := nodectx.GetNodeCtx().AddTenant(name, ks, tenantoptions, passwordfile)
+		if err != nil {
+			return err
+		}
+		if _, err := nodeoptions.GetTenantToken(name); err != nil {
+			return err
+		}
+		groupmgr, err := chain.InitTenantGroupMgr(dbManager, tenant)
+		if err != nil {
+			return err
+		}
+		if err := startGroups(config, groupmgr, name); err != nil {
+			return err
+		}
+		mainlog.Infof("tenant <%s> started", name)
+	}
+	mainlog.Infof("api tokens of the node and the tenants are saved in the TenantTokens of %s_options.toml", config.PeerName)
+	return nil
+}
+
+// load the groups of the node (tenant "") or a tenant listed by -groups, all groups if not set
+func startGroups(config cli.Config, groupmgr *chain.GroupMgr, tenant string) error {
+	groupIds, all := config.GroupsToLoad(tenant)
+	if !all && len(groupIds) == 0 {
+		mainlog.Infof("no groups of <%s> listed to load", tenant)
+		return nil
+	}
+	return groupmgr.Start(groupIds...)
+}
+
 // mainRet is the main function for the program. It is called from main.
 func mainRet(config cli.Config) int {
 	signalch = make(chan os.Signal, 1)
 	ctx, cancel := context.WithCancel(context.Background())
+	appsyncs := make(map[string]*appdata.AppSync)
 	defer cancel()
 
 	peername := config.PeerName
@@ -99,44 +314,40 @@ func mainRet(config cli.Config) int {
 		mainlog.Fatalf(err.Error())
 	}
 
-	signkeycount, err := localcrypto.InitKeystore(config.KeyStoreName, config.KeyStoreDir)
+	signkeycount, err := localcrypto.InitKeystore(config.KeyStoreType, config.KeyStoreName, config.KeyStoreDir, config.SignerAddr)
 	ksi := localcrypto.GetKeystore()
 	if err != nil {
 		cancel()
 		mainlog.Fatalf(err.Error())
 	}
 
+	// the libp2p host needs the private key of the node, with the remote keystore
+	// only this network key is kept in the local keystore dir
+	netkeyname := DEFAULT_KEY_NAME
 	ks, ok := ksi.(*localcrypto.DirKeyStore)
 	if !ok {
-		cancel()
-		mainlog.Fatalf(err.Error())
+		netkeyname = NETWORK_KEY_NAME
+		ks, signkeycount, err = localcrypto.InitDirKeyStore(config.KeyStoreName, config.KeyStoreDir)
+		if err != nil {
+			cancel()
+			mainlog.Fatalf(err.Error())
+		}
 	}
 
-	password := os.Getenv("CHESTNUT_PASSWORD")
+	password, code, err := loadPassword(config, signkeycount == 0)
+	if err != nil {
+		cancel()
+		mainlog.Errorf(err.Error())
+		return code
+	}
 	if signkeycount > 0 {
-		if password == "" {
-			password, err = localcrypto.PassphrasePromptForUnlock()
-		}
 		err = ks.Unlock(nodeoptions.SignKeyMap, password)
 		if err != nil {
-			mainlog.Fatalf(err.Error())
+			mainlog.Errorf(err.Error())
 			cancel()
-			return 0
+			return EXIT_UNLOCK_FAILED
 		}
 	} else {
-		if password == "" {
-			password, err = localcrypto.PassphrasePromptForEncryption()
-			if err != nil {
-				mainlog.Fatalf(err.Error())
-				cancel()
-				return 0
-			}
-			fmt.Println("Please keeping your password safe, We can't recover or reset your password.")
-			fmt.Println("Your password:", password)
-			fmt.Println("After saving the password, press any key to continue.")
-			os.Stdin.Read(make([]byte, 1))
-		}
-
 		signkeyhexstr, err := localcrypto.LoadEncodeKeyFrom(config.ConfigDir, peername, "txt")
 		if err != nil {
 			cancel()
@@ -145,9 +356,9 @@ func mainRet(config cli.Config) int {
 
 		var addr string
 		if signkeyhexstr != "" {
-			addr, err = ks.Import(DEFAULT_KEY_NAME, signkeyhexstr, localcrypto.Sign, password)
+			addr, err = ks.Import(netkeyname, signkeyhexstr, localcrypto.Sign, password)
 		} else {
-			addr, err = ks.NewKey(DEFAULT_KEY_NAME, localcrypto.Sign, password)
+			addr, err = ks.NewKey(netkeyname, localcrypto.Sign, password)
 			if err != nil {
 				cancel()
 				mainlog.Errorf(err.Error())
@@ -161,7 +372,7 @@ func mainRet(config cli.Config) int {
 			return 0
 		}
 
-		err = nodeoptions.SetSignKeyMap(DEFAULT_KEY_NAME, addr)
+		err = nodeoptions.SetSignKeyMap(netkeyname, addr)
 		if err != nil {
 			cancel()
 			mainlog.Errorf(err.Error())
@@ -176,16 +387,16 @@ func mainRet(config cli.Config) int {
 
 		fmt.Printf("load signkey: %d press any key to continue...\n", signkeycount)
 	}
-	_, err = ks.GetKeyFromUnlocked(localcrypto.Sign.NameString(DEFAULT_KEY_NAME))
+	_, err = ks.GetKeyFromUnlocked(localcrypto.Sign.NameString(netkeyname))
 	signkeycount = ks.UnlockedKeyCount(localcrypto.Sign)
 	if signkeycount == 0 {
-		mainlog.Fatalf("load signkey error, exit... %s", err)
+		mainlog.Errorf("load signkey error, exit... %s", err)
 		cancel()
-		return 0
+		return EXIT_UNLOCK_FAILED
 	}
 
 	// Load default sign keys
-	key, err := ks.GetKeyFromUnlocked(localcrypto.Sign.NameString(DEFAULT_KEY_NAME))
+	key, err := ks.GetKeyFromUnlocked(localcrypto.Sign.NameString(netkeyname))
 
 	defaultkey, ok := key.(*ethkeystore.Key)
 	if !ok {
@@ -202,12 +413,20 @@ func mainRet(config cli.Config) int {
 		return 0
 	}
 
-	peerid, ethaddr, err := ks.GetPeerInfo(DEFAULT_KEY_NAME)
+	peerid, ethaddr, err := ks.GetPeerInfo(netkeyname)
 	if err != nil {
 		cancel()
 		mainlog.Fatalf(err.Error())
 	}
 
+	if remoteks, ok := ksi.(*localcrypto.RemoteKeyStore); ok {
+		err = initRemoteKeystore(remoteks, nodeoptions)
+		if err != nil {
+			cancel()
+			mainlog.Fatalf(err.Error())
+		}
+	}
+
 	mainlog.Infof("eth address: <%s>", ethaddr)
 
 	ds, err := dsbadger2.NewDatastore(path.Join(config.ConfigDir, fmt.Sprintf("%s-%s", peername, "peerstore")), &dsbadger2.DefaultOptions)
@@ -226,7 +445,11 @@ func mainRet(config cli.Config) int {
 		}
 		datapath := config.DataDir + "/" + config.PeerName
 		
-		dbManager, err := createDb(datapath)
+		dbManager, err := createDb(datapath, config.DbEngine)
+		if err != nil {
+			mainlog.Fatalf(err.Error())
+		}
+		err = dbManager.TryMigration()
 		if err != nil {
 			mainlog.Fatalf(err.Error())
 		}
@@ -238,7 +461,7 @@ func mainRet(config cli.Config) int {
 
 		mainlog.Infof("Host created, ID:<%s>, Address:<%s>", node.Host.ID(), node.Host.Addrs())
 		h := &api.Handler{Node: node, NodeCtx: nodectx.GetNodeCtx(), GitCommit: GitCommit}
-		go api.StartAPIServer(config, signalch, h, nil, node, nodeoptions, ks, ethaddr, true)
+		go api.StartAPIServer(config, signalch, h, nil, node, nodeoptions, ksi, ethaddr, true)
 	} else {
 		//normal node connections: low watermarks: 10  hi watermarks 200, grace 60s
 		connmanager, _ := connmgr.NewConnManager(10, 200, connmgr.WithGracePeriod(60 * time.Second), connmgr.WithEmergencyTrim(true))
@@ -258,23 +481,53 @@ func mainRet(config cli.Config) int {
 
 		go node.ConnectPeers(ctx, peerok, nodeoptions.MaxPeers, config)
 		datapath := config.DataDir + "/" + config.PeerName
-		dbManager, err := createDb(datapath)
+		dbManager, err := createDb(datapath, config.DbEngine)
+		if err != nil {
+			mainlog.Fatalf(err.Error())
+		}
+		err = dbManager.TryMigration()
 		if err != nil {
 			mainlog.Fatalf(err.Error())
 		}
-		dbManager.TryMigration(0)
 		nodectx.InitCtx(ctx, "default", node, dbManager, "pubsub", GitCommit)
 		nodectx.GetNodeCtx().Keystore = ksi
+		if os.Getenv("CHESTNUT_PASSWORD") == "" {
+			nodectx.GetNodeCtx().PasswordFile = config.PasswordFile
+		}
 		nodectx.GetNodeCtx().PublickKey = keys.PubKey
 		nodectx.GetNodeCtx().PeerId = peerid
 		groupmgr := chain.InitGroupMgr(nodectx.GetDbMgr())
+		node.Blob = p2p.NewBlobService(node.Host, dbManager, chain.IsGroupPeer, storage.CheckBlobManifest, chain.GroupNodeName)
 
-		err = groupmgr.SyncAllGroup()
+		err = startGroups(config, groupmgr, "")
+		if err != nil {
+			mainlog.Fatalf(err.Error())
+		}
+		err = initTenants(config, nodeoptions, dbManager, password)
 		if err != nil {
 			mainlog.Fatalf(err.Error())
 		}
+		chain.StartPruner(chain.PRUNE_INTERVAL)
+		chain.StartLiveness(chain.HEARTBEAT_INTERVAL)
 
-		appdb, err := createAppDb(datapath)
+		appdb, err := createAppDb(datapath, config.DbEngine)
+		if err != nil {
+			mainlog.Fatalf(err.Error())
+		}
+		err = appdb.TryMigration()
+		if err != nil {
+			mainlog.Fatalf(err.Error())
+		}
+		// rebuild with all saved groups, not only the groups loaded by -groups
+		storedgroups, err := handlers.SavedGroupItems(dbManager, nodectx.GetNodeCtx().Name)
+		if err != nil {
+			mainlog.Fatalf(err.Error())
+		}
+		keystores := map[string]localcrypto.Keystore{nodectx.GetNodeCtx().Name: nodectx.GetNodeCtx().Keystore}
+		for _, tenant := range nodectx.GetNodeCtx().ListTenants() {
+			keystores[tenant.Name] = tenant.Keystore
+		}
+		err = appdb.TryRebuild(dbManager, storedgroups, keystores)
 		if err != nil {
 			mainlog.Fatalf(err.Error())
 		}
@@ -296,10 +549,14 @@ func mainRet(config cli.Config) int {
 			apiaddress = fmt.Sprintf(apiaddress, config.APIListenAddresses)
 		}
 
-		appsync := appdata.NewAppSyncAgent(apiaddress, "default", appdb, dbManager)
-		appsync.Start(10)
+		for _, tenantmgr := range chain.ListGroupMgrs() {
+			name := tenantmgr.Tenant().Name
+			appsyncs[name] = appdata.NewAppSyncAgent(apiaddress, name, appdb, dbManager, tenantmgr)
+			appsyncs[name].Start(ctx)
+		}
 		apph := &appapi.Handler{
 			Appdb: appdb,
+			Appsyncs: appsyncs,
 			Chaindb: dbManager,
 			GitCommit: GitCommit,
 			Apiroot: apiaddress,
@@ -307,7 +564,7 @@ func mainRet(config cli.Config) int {
 			PeerName: config.PeerName,
 			NodeName: nodectx.GetNodeCtx().Name,
 		}
-		go api.StartAPIServer(config, signalch, h, apph, node, nodeoptions, ks, ethaddr, false)
+		go api.StartAPIServer(config, signalch, h, apph, node, nodeoptions, ksi, ethaddr, false)
 	}
 
 	//attach signal
@@ -316,6 +573,16 @@ func mainRet(config cli.Config) int {
 	signal.Stop(signalch)
 
 	if !config.IsBootstrap {
+		chain.GetPruner().Stop()
+		chain.GetLiveness().Stop()
+		for _, appsync := range appsyncs {
+			appsync.Stop()
+		}
+		for _, tenantmgr := range chain.ListGroupMgrs() {
+			if !tenantmgr.Tenant().IsDefault {
+				tenantmgr.Stop()
+			}
+		}
 		groupmgr := chain.GetGroupMgr()
 		groupmgr.Release()
 	}
@@ -348,6 +615,14 @@ func main()  {
 		logging.SetLogLevel("user", "debug")
 		logging.SetLogLevel("groupmgr", "debug")
 		logging.SetLogLevel("trxmgr", "debug")
+		logging.SetLogLevel("archive", "debug")
+		logging.SetLogLevel("migration", "debug")
+		logging.SetLogLevel("pruner", "debug")
+		logging.SetLogLevel("liveness", "debug")
+		logging.SetLogLevel("config", "debug")
+		logging.SetLogLevel("light", "debug")
+		logging.SetLogLevel("blob", "debug")
+		logging.SetLogLevel("appsync", "debug")
 	}
 
 	if *help {
@@ -358,6 +633,10 @@ func main()  {
 		return
 	}
 
+	if flag.Arg(0) == "keys" {
+		os.Exit(keysRet(config, flag.Args()[1:]))
+	}
+
 
 	os.Exit(mainRet(config))
 }
\ No newline at end of file
@@ -28,6 +28,7 @@ type NodeCtx struct {
 	Node *p2p.Node
 	PeerId peer.ID
 	Keystore localcrypto.Keystore
+	PasswordFile string // file the keystore password is read from, empty if not read from a file
 	PublickKey p2pcrypto.PubKey
 	Name string
 	Ctx context.Context
@@ -50,7 +51,7 @@ func GetDbMgr() *storage.DbMgr {
 }
 
 func InitCtx(ctx context.Context, name string, node *p2p.Node, db *storage.DbMgr, channeltype string, gitcommit string)  {
-	nodeCtx := &NodeCtx{}
+	nodeCtx = &NodeCtx{}
 	nodeCtx.Ctx = ctx
 	nodeCtx.Node = node
 	dbMgr = db
@@ -0,0 +1,79 @@
+// Package nodectx provides context for node.
+package nodectx
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/utils/options"
+)
+
+// key of the tenant name selected by the api token in the request context
+const TENANT_CONTEXT_KEY = "tenant"
+
+// Tenant is a user identity hosted by the node. Each tenant has its own keystore and options
+// (sign key map), and its data is saved with the tenant name as the node name prefix.
+// The default tenant is the identity of the node, with the NodeCtx name and keystore.
+type Tenant struct {
+	Name         string
+	Keystore     localcrypto.Keystore
+	Options      *options.NodeOptions
+	PasswordFile string // the keystore password is saved in the file, empty if not
+	IsDefault    bool
+}
+
+// tenant names are used as key prefixes, "_" is the prefix separator
+var tenantNameRe = regexp.MustCompile(`^[a-z0-9-]{1,32}$`)
+
+var (
+	tenants  = make(map[string]*Tenant)
+	tenantmu sync.RWMutex
+)
+
+func IsValidTenantName(name string) bool {
+	return tenantNameRe.MatchString(name)
+}
+
+// the default tenant, built from the node name and keystore
+func (nodeCtx *NodeCtx) DefaultTenant() *Tenant {
+	return &Tenant{Name: nodeCtx.Name, Keystore: nodeCtx.Keystore, Options: options.GetNodeOptions(), PasswordFile: nodeCtx.PasswordFile, IsDefault: true}
+}
+
+func (nodeCtx *NodeCtx) AddTenant(name string, ks localcrypto.Keystore, opts *options.NodeOptions, passwordfile string) (*Tenant, error) {
+	if !IsValidTenantName(name) || name == nodeCtx.Name {
+		return nil, fmt.Errorf("invalid tenant name: %s", name)
+	}
+	tenantmu.Lock()
+	defer tenantmu.Unlock()
+	if _, ok := tenants[name]; ok {
+		return nil, fmt.Errorf("tenant already exist: %s", name)
+	}
+	tenant := &Tenant{Name: name, Keystore: ks, Options: opts, PasswordFile: passwordfile}
+	tenants[name] = tenant
+	chainctx_log.Infof("tenant <%s> added", name)
+	return tenant, nil
+}
+
+// get a tenant by name, the default tenant if name is empty or the node name
+func (nodeCtx *NodeCtx) GetTenant(name string) (*Tenant, bool) {
+	if name == "" || name == nodeCtx.Name {
+		return nodeCtx.DefaultTenant(), true
+	}
+	tenantmu.RLock()
+	defer tenantmu.RUnlock()
+	tenant, ok := tenants[name]
+	return tenant, ok
+}
+
+// tenants except the default tenant
+func (nodeCtx *NodeCtx) ListTenants() []*Tenant {
+	tenantmu.RLock()
+	defer tenantmu.RUnlock()
+	result := []*Tenant{}
+	for _, tenant := range tenants {
+		result = append(result, tenant)
+	}
+	return result
+}
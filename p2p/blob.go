@@ -0,0 +1,245 @@
+// Package p2p provides p2p connectivity for chestnut.
+package p2p
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	logging "github.com/ipfs/go-log/v2"
+	"github.com/libp2p/go-libp2p-core/host"
+	"github.com/libp2p/go-libp2p-core/network"
+	"github.com/libp2p/go-libp2p-core/peer"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+var bloblog = logging.Logger("blob")
+
+const (
+	BlobID       = "/chestnut/blob/1.0.0"
+	blobTimeout  = time.Second * 60
+	blobMsgLimit = 1024 * 1024 //a chunk and the message header
+)
+
+// storage of blobs served and fetched by BlobService
+type BlobStore interface {
+	GetBlobManifest(blobId string, prefix ...string) (*chestnutpb.BlobManifest, error)
+	GetBlobChunk(chunkId string, prefix ...string) ([]byte, error)
+	HasBlobChunk(chunkId string, prefix ...string) (bool, error)
+	AddBlobChunk(chunkId string, data []byte, prefix ...string) error
+	AddBlobManifest(groupId string, manifest *chestnutpb.BlobManifest, prefix ...string) error
+	IsBlobRef(groupId string, blobId string, prefix ...string) (bool, error)
+	IsBlobChunkRef(groupId string, blobId string, chunkId string, prefix ...string) (bool, error)
+}
+
+// check a manifest received from a peer before its chunks are fetched
+type BlobCheck func(manifest *chestnutpb.BlobManifest) error
+
+// check if the peer can fetch blobs of the group
+type BlobACL func(groupId string, p peer.ID) bool
+
+// node name prefix of the blobs of a group, groups of tenants are saved with the tenant name
+type BlobNodeName func(groupId string) string
+
+type BlobService struct {
+	Host     host.Host
+	Store    BlobStore
+	ACL      BlobACL
+	Check    BlobCheck
+	nodename BlobNodeName
+}
+
+func NewBlobService(h host.Host, store BlobStore, acl BlobACL, check BlobCheck, nodename BlobNodeName) *BlobService {
+	bs := &BlobService{Host: h, Store: store, ACL: acl, Check: check, nodename: nodename}
+	h.SetStreamHandler(BlobID, bs.BlobHandler)
+	return bs
+}
+
+// serve requests on the stream one by one until the stream is closed
+func (bs *BlobService) BlobHandler(s network.Stream) {
+	defer s.Close()
+	r := bufio.NewReader(s)
+	for {
+		s.SetDeadline(time.Now().Add(blobTimeout))
+		req := &chestnutpb.BlobReq{}
+		if err := readBlobMsg(r, req); err != nil {
+			if err != io.EOF {
+				bloblog.Debugf("read blob request from %s err: %s", s.Conn().RemotePeer(), err)
+				s.Reset()
+			}
+			return
+		}
+		resp := bs.handleReq(s.Conn().RemotePeer(), req)
+		if err := writeBlobMsg(s, resp); err != nil {
+			bloblog.Debugf("write blob response to %s err: %s", s.Conn().RemotePeer(), err)
+			s.Reset()
+			return
+		}
+	}
+}
+
+func (bs *BlobService) handleReq(p peer.ID, req *chestnutpb.BlobReq) *chestnutpb.BlobResp {
+	resp := &chestnutpb.BlobResp{Type: req.Type, Id: req.Id}
+	if bs.ACL != nil && !bs.ACL(req.GroupId, p) {
+		resp.Error = "permission denied"
+		return resp
+	}
+
+	switch req.Type {
+	case chestnutpb.BlobReqType_BLOB_MANIFEST:
+		ref, err := bs.Store.IsBlobRef(req.GroupId, req.Id, bs.nodename(req.GroupId))
+		if err == nil && !ref {
+			err = errors.New("blob not found")
+		}
+		if err == nil {
+			resp.Manifest, err = bs.Store.GetBlobManifest(req.Id, bs.nodename(req.GroupId))
+		}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+	case chestnutpb.BlobReqType_BLOB_CHUNK:
+		ref, err := bs.Store.IsBlobChunkRef(req.GroupId, req.BlobId, req.Id, bs.nodename(req.GroupId))
+		if err == nil && !ref {
+			err = errors.New("chunk not found")
+		}
+		if err == nil {
+			resp.Data, err = bs.Store.GetBlobChunk(req.Id, bs.nodename(req.GroupId))
+		}
+		if err != nil {
+			resp.Error = err.Error()
+		}
+	default:
+		resp.Error = fmt.Sprintf("unknown request type %s", req.Type)
+	}
+	return resp
+}
+
+// Fetch a blob of the group from peers, chunks already saved are skipped.
+// The blob is complete and referenced by the group when Fetch returns nil.
+func (bs *BlobService) Fetch(ctx context.Context, groupId string, blobId string, peers []peer.ID) (*chestnutpb.BlobManifest, error) {
+	if manifest, err := bs.Store.GetBlobManifest(blobId, bs.nodename(groupId)); err == nil {
+		return manifest, bs.Store.AddBlobManifest(groupId, manifest, bs.nodename(groupId))
+	}
+
+	errs := []error{}
+	for _, p := range peers {
+		if p == bs.Host.ID() {
+			continue
+		}
+		manifest, err := bs.fetchFrom(ctx, p, groupId, blobId)
+		if err == nil {
+			return manifest, nil
+		}
+		bloblog.Debugf("<%s> fetch blob %s from %s err: %s", groupId, blobId, p, err)
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+	}
+	if len(errs) == 0 {
+		return nil, fmt.Errorf("no peer to fetch blob %s", blobId)
+	}
+	return nil, fmt.Errorf("fetch blob %s failed from %d peer(s), last err: %s", blobId, len(errs), errs[len(errs)-1])
+}
+
+func (bs *BlobService) fetchFrom(ctx context.Context, p peer.ID, groupId string, blobId string) (*chestnutpb.BlobManifest, error) {
+	s, err := bs.Host.NewStream(ctx, p, BlobID)
+	if err != nil {
+		return nil, err
+	}
+	defer s.Close()
+	r := bufio.NewReader(s)
+
+	request := func(reqType chestnutpb.BlobReqType, id string) (*chestnutpb.BlobResp, error) {
+		s.SetDeadline(time.Now().Add(blobTimeout))
+		if err := writeBlobMsg(s, &chestnutpb.BlobReq{Type: reqType, GroupId: groupId, Id: id, BlobId: blobId}); err != nil {
+			return nil, err
+		}
+		resp := &chestnutpb.BlobResp{}
+		if err := readBlobMsg(r, resp); err != nil {
+			return nil, err
+		}
+		if resp.Error != "" {
+			return nil, errors.New(resp.Error)
+		}
+		if resp.Type != reqType || resp.Id != id {
+			return nil, errors.New("unexpected response")
+		}
+		return resp, nil
+	}
+
+	resp, err := request(chestnutpb.BlobReqType_BLOB_MANIFEST, blobId)
+	if err != nil {
+		return nil, err
+	}
+	manifest := resp.Manifest
+	if manifest == nil || manifest.BlobId != blobId {
+		return nil, errors.New("invalid manifest")
+	}
+	//the chunk count of a peer manifest is bound by the blob size limit
+	if bs.Check != nil {
+		if err := bs.Check(manifest); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, chunkId := range manifest.Chunks {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		exist, err := bs.Store.HasBlobChunk(chunkId, bs.nodename(groupId))
+		if err != nil {
+			return nil, err
+		}
+		if exist {
+			continue
+		}
+		resp, err := request(chestnutpb.BlobReqType_BLOB_CHUNK, chunkId)
+		if err != nil {
+			return nil, err
+		}
+		//chunk is verified by its hash
+		if err := bs.Store.AddBlobChunk(chunkId, resp.Data, bs.nodename(groupId)); err != nil {
+			return nil, err
+		}
+	}
+
+	//the whole content is verified by the blob id
+	if err := bs.Store.AddBlobManifest(groupId, manifest, bs.nodename(groupId)); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// messages are length (uvarint) delimited protobuf
+func writeBlobMsg(w io.Writer, msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	buf := make([]byte, binary.MaxVarintLen64+len(data))
+	n := binary.PutUvarint(buf, uint64(len(data)))
+	n += copy(buf[n:], data)
+	_, err = w.Write(buf[:n])
+	return err
+}
+
+func readBlobMsg(r *bufio.Reader, msg proto.Message) error {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return err
+	}
+	if size > blobMsgLimit {
+		return fmt.Errorf("message size %d exceeds limit", size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return err
+	}
+	return proto.Unmarshal(data, msg)
+}
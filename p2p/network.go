@@ -31,6 +31,7 @@ type Node struct {
 	Ddht *dual.DHT
 	Info *NodeInfo
 	RoutingDiscovery *discovery.RoutingDiscovery
+	Blob *BlobService
 }
 
 func (node *Node) eventhandler(ctx context.Context) {
@@ -69,31 +69,43 @@ func (PackageType) EnumDescriptor() ([]byte, []int) {
 type TrxType int32
 
 const (
-	TrxType_POST               TrxType = 0 // post to group
-	TrxType_AUTH               TrxType = 1 // group auth update
-	TrxType_SCHEMA             TrxType = 2 // group schema
-	TrxType_PRODUCER           TrxType = 3 // update group producer
-	TrxType_ANNOUNCE           TrxType = 4 // self announce, producer or user)
-	TrxType_REQ_BLOCK_FORWARD  TrxType = 5 // request next block
-	TrxType_REQ_BLOCK_BACKWARD TrxType = 6 // request previous block
-	TrxType_REQ_BLOCK_RESP     TrxType = 7 // response request next block
-	TrxType_BLOCK_SYNCED       TrxType = 8 // block for producer to sync (old block)
-	TrxType_BLOCK_PRODUCED     TrxType = 9 // block for producer to merge (newly produced block)
+	TrxType_POST               TrxType = 0  // post to group
+	TrxType_AUTH               TrxType = 1  // group auth update
+	TrxType_SCHEMA             TrxType = 2  // group schema
+	TrxType_PRODUCER           TrxType = 3  // update group producer
+	TrxType_ANNOUNCE           TrxType = 4  // self announce, producer or user)
+	TrxType_REQ_BLOCK_FORWARD  TrxType = 5  // request next block
+	TrxType_REQ_BLOCK_BACKWARD TrxType = 6  // request previous block
+	TrxType_REQ_BLOCK_RESP     TrxType = 7  // response request next block
+	TrxType_BLOCK_SYNCED       TrxType = 8  // block for producer to sync (old block)
+	TrxType_BLOCK_PRODUCED     TrxType = 9  // block for producer to merge (newly produced block)
+	TrxType_INVITE             TrxType = 10 // group invitation minted or revoked by owner
+	TrxType_REDEEM             TrxType = 11 // group invitation redeemed by a joiner
+	TrxType_HEARTBEAT          TrxType = 12 // producer liveness, sent on the producer channel, not packed into blocks
+	TrxType_CONFIG             TrxType = 13 // group block production config update
+	TrxType_REQ_TRX            TrxType = 14 // light node request trx data stripped from its blocks
+	TrxType_REQ_TRX_RESP       TrxType = 15 // response of request trx data
 )
 
 // Enum value maps for TrxType.
 var (
 	TrxType_name = map[int32]string{
-		0: "POST",
-		1: "AUTH",
-		2: "SCHEMA",
-		3: "PRODUCER",
-		4: "ANNOUNCE",
-		5: "REQ_BLOCK_FORWARD",
-		6: "REQ_BLOCK_BACKWARD",
-		7: "REQ_BLOCK_RESP",
-		8: "BLOCK_SYNCED",
-		9: "BLOCK_PRODUCED",
+		0:  "POST",
+		1:  "AUTH",
+		2:  "SCHEMA",
+		3:  "PRODUCER",
+		4:  "ANNOUNCE",
+		5:  "REQ_BLOCK_FORWARD",
+		6:  "REQ_BLOCK_BACKWARD",
+		7:  "REQ_BLOCK_RESP",
+		8:  "BLOCK_SYNCED",
+		9:  "BLOCK_PRODUCED",
+		10: "INVITE",
+		11: "REDEEM",
+		12: "HEARTBEAT",
+		13: "CONFIG",
+		14: "REQ_TRX",
+		15: "REQ_TRX_RESP",
 	}
 	TrxType_value = map[string]int32{
 		"POST":               0,
@@ -106,6 +118,12 @@ var (
 		"REQ_BLOCK_RESP":     7,
 		"BLOCK_SYNCED":       8,
 		"BLOCK_PRODUCED":     9,
+		"INVITE":             10,
+		"REDEEM":             11,
+		"HEARTBEAT":          12,
+		"CONFIG":             13,
+		"REQ_TRX":            14,
+		"REQ_TRX_RESP":       15,
 	}
 )
 
@@ -323,6 +341,52 @@ func (ReqBlkResult) EnumDescriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{5}
 }
 
+type BlobReqType int32
+
+const (
+	BlobReqType_BLOB_MANIFEST BlobReqType = 0
+	BlobReqType_BLOB_CHUNK    BlobReqType = 1
+)
+
+// Enum value maps for BlobReqType.
+var (
+	BlobReqType_name = map[int32]string{
+		0: "BLOB_MANIFEST",
+		1: "BLOB_CHUNK",
+	}
+	BlobReqType_value = map[string]int32{
+		"BLOB_MANIFEST": 0,
+		"BLOB_CHUNK":    1,
+	}
+)
+
+func (x BlobReqType) Enum() *BlobReqType {
+	p := new(BlobReqType)
+	*p = x
+	return p
+}
+
+func (x BlobReqType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BlobReqType) Descriptor() protoreflect.EnumDescriptor {
+	return file_chain_proto_enumTypes[6].Descriptor()
+}
+
+func (BlobReqType) Type() protoreflect.EnumType {
+	return &file_chain_proto_enumTypes[6]
+}
+
+func (x BlobReqType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BlobReqType.Descriptor instead.
+func (BlobReqType) EnumDescriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{6}
+}
+
 type GroupEncryptType int32
 
 const (
@@ -353,11 +417,11 @@ func (x GroupEncryptType) String() string {
 }
 
 func (GroupEncryptType) Descriptor() protoreflect.EnumDescriptor {
-	return file_chain_proto_enumTypes[6].Descriptor()
+	return file_chain_proto_enumTypes[7].Descriptor()
 }
 
 func (GroupEncryptType) Type() protoreflect.EnumType {
-	return &file_chain_proto_enumTypes[6]
+	return &file_chain_proto_enumTypes[7]
 }
 
 func (x GroupEncryptType) Number() protoreflect.EnumNumber {
@@ -366,7 +430,7 @@ func (x GroupEncryptType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use GroupEncryptType.Descriptor instead.
 func (GroupEncryptType) EnumDescriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{6}
+	return file_chain_proto_rawDescGZIP(), []int{7}
 }
 
 type GroupConsenseType int32
@@ -399,11 +463,11 @@ func (x GroupConsenseType) String() string {
 }
 
 func (GroupConsenseType) Descriptor() protoreflect.EnumDescriptor {
-	return file_chain_proto_enumTypes[7].Descriptor()
+	return file_chain_proto_enumTypes[8].Descriptor()
 }
 
 func (GroupConsenseType) Type() protoreflect.EnumType {
-	return &file_chain_proto_enumTypes[7]
+	return &file_chain_proto_enumTypes[8]
 }
 
 func (x GroupConsenseType) Number() protoreflect.EnumNumber {
@@ -412,7 +476,7 @@ func (x GroupConsenseType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use GroupConsenseType.Descriptor instead.
 func (GroupConsenseType) EnumDescriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{7}
+	return file_chain_proto_rawDescGZIP(), []int{8}
 }
 
 type RoleV0 int32
@@ -445,11 +509,11 @@ func (x RoleV0) String() string {
 }
 
 func (RoleV0) Descriptor() protoreflect.EnumDescriptor {
-	return file_chain_proto_enumTypes[8].Descriptor()
+	return file_chain_proto_enumTypes[9].Descriptor()
 }
 
 func (RoleV0) Type() protoreflect.EnumType {
-	return &file_chain_proto_enumTypes[8]
+	return &file_chain_proto_enumTypes[9]
 }
 
 func (x RoleV0) Number() protoreflect.EnumNumber {
@@ -458,7 +522,7 @@ func (x RoleV0) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use RoleV0.Descriptor instead.
 func (RoleV0) EnumDescriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{8}
+	return file_chain_proto_rawDescGZIP(), []int{9}
 }
 
 type Package struct {
@@ -521,17 +585,18 @@ type Trx struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TrxId        string  `protobuf:"bytes,1,opt,name=TrxId,proto3" json:"TrxId,omitempty"`
-	Type         TrxType `protobuf:"varint,2,opt,name=Type,proto3,enum=chestnut.pb.TrxType" json:"Type,omitempty"`
-	GroupId      string  `protobuf:"bytes,3,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	Data         []byte  `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
-	TimeStamp    int64   `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
-	Version      string  `protobuf:"bytes,6,opt,name=Version,proto3" json:"Version,omitempty"`
-	Expired      int64   `protobuf:"varint,7,opt,name=Expired,proto3" json:"Expired,omitempty"`
-	ResendCount  int64   `protobuf:"varint,8,opt,name=ResendCount,proto3" json:"ResendCount,omitempty"`
-	Nonce        int64   `protobuf:"varint,9,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
-	SenderPubkey string  `protobuf:"bytes,10,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
-	SenderSign   []byte  `protobuf:"bytes,11,opt,name=SenderSign,proto3" json:"SenderSign,omitempty"`
+	TrxId        string       `protobuf:"bytes,1,opt,name=TrxId,proto3" json:"TrxId,omitempty"`
+	Type         TrxType      `protobuf:"varint,2,opt,name=Type,proto3,enum=chestnut.pb.TrxType" json:"Type,omitempty"`
+	GroupId      string       `protobuf:"bytes,3,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Data         []byte       `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
+	TimeStamp    int64        `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	Version      string       `protobuf:"bytes,6,opt,name=Version,proto3" json:"Version,omitempty"`
+	Expired      int64        `protobuf:"varint,7,opt,name=Expired,proto3" json:"Expired,omitempty"`
+	ResendCount  int64        `protobuf:"varint,8,opt,name=ResendCount,proto3" json:"ResendCount,omitempty"`
+	Nonce        int64        `protobuf:"varint,9,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	SenderPubkey string       `protobuf:"bytes,10,opt,name=SenderPubkey,proto3" json:"SenderPubkey,omitempty"`
+	SenderSign   []byte       `protobuf:"bytes,11,opt,name=SenderSign,proto3" json:"SenderSign,omitempty"`
+	AdminSigns   []*AdminSign `protobuf:"bytes,12,rep,name=AdminSigns,proto3" json:"AdminSigns,omitempty"` //admin signatures of an owner trx, the TrxId is the admin action id
 }
 
 func (x *Trx) Reset() {
@@ -643,20 +708,31 @@ func (x *Trx) GetSenderSign() []byte {
 	return nil
 }
 
+func (x *Trx) GetAdminSigns() []*AdminSign {
+	if x != nil {
+		return x.AdminSigns
+	}
+	return nil
+}
+
 type Block struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	BlockId        string `protobuf:"bytes,1,opt,name=BlockId,proto3" json:"BlockId,omitempty"`
-	GroupId        string `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	PrevBlockId    string `protobuf:"bytes,3,opt,name=PrevBlockId,proto3" json:"PrevBlockId,omitempty"`
-	PreviousHash   []byte `protobuf:"bytes,4,opt,name=PreviousHash,proto3" json:"PreviousHash,omitempty"`
-	Trxs           []*Trx `protobuf:"bytes,5,rep,name=Trxs,proto3" json:"Trxs,omitempty"`
-	ProducerPubKey string `protobuf:"bytes,6,opt,name=ProducerPubKey,proto3" json:"ProducerPubKey,omitempty"`
-	Hash           []byte `protobuf:"bytes,7,opt,name=Hash,proto3" json:"Hash,omitempty"`
-	Signature      []byte `protobuf:"bytes,8,opt,name=Signature,proto3" json:"Signature,omitempty"`
-	TimeStamp      int64  `protobuf:"varint,9,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	BlockId        string       `protobuf:"bytes,1,opt,name=BlockId,proto3" json:"BlockId,omitempty"`
+	GroupId        string       `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	PrevBlockId    string       `protobuf:"bytes,3,opt,name=PrevBlockId,proto3" json:"PrevBlockId,omitempty"`
+	PreviousHash   []byte       `protobuf:"bytes,4,opt,name=PreviousHash,proto3" json:"PreviousHash,omitempty"`
+	Trxs           []*Trx       `protobuf:"bytes,5,rep,name=Trxs,proto3" json:"Trxs,omitempty"`
+	ProducerPubKey string       `protobuf:"bytes,6,opt,name=ProducerPubKey,proto3" json:"ProducerPubKey,omitempty"`
+	Hash           []byte       `protobuf:"bytes,7,opt,name=Hash,proto3" json:"Hash,omitempty"`
+	Signature      []byte       `protobuf:"bytes,8,opt,name=Signature,proto3" json:"Signature,omitempty"`
+	TimeStamp      int64        `protobuf:"varint,9,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	Admins         *AdminSet    `protobuf:"bytes,10,opt,name=Admins,proto3" json:"Admins,omitempty"`       //set in the genesis block of a group owned by admins
+	Config         *GroupConfig `protobuf:"bytes,11,opt,name=Config,proto3" json:"Config,omitempty"`       //set in the genesis block, changed by CONFIG trxs
+	TrxRoot        []byte       `protobuf:"bytes,12,opt,name=TrxRoot,proto3" json:"TrxRoot,omitempty"`     //Merkle root of the trxs, the hash covers the header only if set
+	TrxHashes      [][]byte     `protobuf:"bytes,13,rep,name=TrxHashes,proto3" json:"TrxHashes,omitempty"` //leaf hashes of the trx root, set if trx data is stripped for a light node
 }
 
 func (x *Block) Reset() {
@@ -754,6 +830,34 @@ func (x *Block) GetTimeStamp() int64 {
 	return 0
 }
 
+func (x *Block) GetAdmins() *AdminSet {
+	if x != nil {
+		return x.Admins
+	}
+	return nil
+}
+
+func (x *Block) GetConfig() *GroupConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Block) GetTrxRoot() []byte {
+	if x != nil {
+		return x.TrxRoot
+	}
+	return nil
+}
+
+func (x *Block) GetTrxHashes() [][]byte {
+	if x != nil {
+		return x.TrxHashes
+	}
+	return nil
+}
+
 type BlockDbChunk struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -764,6 +868,7 @@ type BlockDbChunk struct {
 	ParentBlockId string   `protobuf:"bytes,3,opt,name=ParentBlockId,proto3" json:"ParentBlockId,omitempty"`
 	SubBlockId    []string `protobuf:"bytes,4,rep,name=SubBlockId,proto3" json:"SubBlockId,omitempty"`
 	Height        int64    `protobuf:"varint,6,opt,name=Height,proto3" json:"Height,omitempty"`
+	Pruned        bool     `protobuf:"varint,7,opt,name=Pruned,proto3" json:"Pruned,omitempty"` //trx data of the block removed by retention policy
 }
 
 func (x *BlockDbChunk) Reset() {
@@ -833,6 +938,13 @@ func (x *BlockDbChunk) GetHeight() int64 {
 	return 0
 }
 
+func (x *BlockDbChunk) GetPruned() bool {
+	if x != nil {
+		return x.Pruned
+	}
+	return false
+}
+
 type ReqBlock struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -841,6 +953,7 @@ type ReqBlock struct {
 	BlockId string `protobuf:"bytes,1,opt,name=BlockId,proto3" json:"BlockId,omitempty"` //block id
 	GroupId string `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"` //group id
 	UserId  string `protobuf:"bytes,3,opt,name=UserId,proto3" json:"UserId,omitempty"`   //requester
+	Light   bool   `protobuf:"varint,4,opt,name=Light,proto3" json:"Light,omitempty"`    //requester is a light node, send the stripped block
 }
 
 func (x *ReqBlock) Reset() {
@@ -896,6 +1009,13 @@ func (x *ReqBlock) GetUserId() string {
 	return ""
 }
 
+func (x *ReqBlock) GetLight() bool {
+	if x != nil {
+		return x.Light
+	}
+	return false
+}
+
 type BlockSynced struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -990,21 +1110,19 @@ func (x *BlockProduced) GetBlockItem() *Block {
 	return nil
 }
 
-type ReqBlockResp struct {
+type ReqTrx struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Result          ReqBlkResult `protobuf:"varint,1,opt,name=Result,proto3,enum=chestnut.pb.ReqBlkResult" json:"Result,omitempty"`
-	ProviderPubkey  string       `protobuf:"bytes,2,opt,name=ProviderPubkey,proto3" json:"ProviderPubkey,omitempty"`
-	RequesterPubkey string       `protobuf:"bytes,3,opt,name=RequesterPubkey,proto3" json:"RequesterPubkey,omitempty"`
-	GroupId         string       `protobuf:"bytes,4,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	BlockId         string       `protobuf:"bytes,5,opt,name=BlockId,proto3" json:"BlockId,omitempty"`
-	Block           []byte       `protobuf:"bytes,6,opt,name=Block,proto3" json:"Block,omitempty"`
+	GroupId string `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	BlockId string `protobuf:"bytes,2,opt,name=BlockId,proto3" json:"BlockId,omitempty"` //block the trx is packed in
+	TrxId   string `protobuf:"bytes,3,opt,name=TrxId,proto3" json:"TrxId,omitempty"`
+	UserId  string `protobuf:"bytes,4,opt,name=UserId,proto3" json:"UserId,omitempty"` //requester
 }
 
-func (x *ReqBlockResp) Reset() {
-	*x = ReqBlockResp{}
+func (x *ReqTrx) Reset() {
+	*x = ReqTrx{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1012,13 +1130,13 @@ func (x *ReqBlockResp) Reset() {
 	}
 }
 
-func (x *ReqBlockResp) String() string {
+func (x *ReqTrx) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReqBlockResp) ProtoMessage() {}
+func (*ReqTrx) ProtoMessage() {}
 
-func (x *ReqBlockResp) ProtoReflect() protoreflect.Message {
+func (x *ReqTrx) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1030,66 +1148,54 @@ func (x *ReqBlockResp) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReqBlockResp.ProtoReflect.Descriptor instead.
-func (*ReqBlockResp) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReqTrx.ProtoReflect.Descriptor instead.
+func (*ReqTrx) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ReqBlockResp) GetResult() ReqBlkResult {
-	if x != nil {
-		return x.Result
-	}
-	return ReqBlkResult_BLOCK_IN_TRX
-}
-
-func (x *ReqBlockResp) GetProviderPubkey() string {
+func (x *ReqTrx) GetGroupId() string {
 	if x != nil {
-		return x.ProviderPubkey
+		return x.GroupId
 	}
 	return ""
 }
 
-func (x *ReqBlockResp) GetRequesterPubkey() string {
+func (x *ReqTrx) GetBlockId() string {
 	if x != nil {
-		return x.RequesterPubkey
+		return x.BlockId
 	}
 	return ""
 }
 
-func (x *ReqBlockResp) GetGroupId() string {
+func (x *ReqTrx) GetTrxId() string {
 	if x != nil {
-		return x.GroupId
+		return x.TrxId
 	}
 	return ""
 }
 
-func (x *ReqBlockResp) GetBlockId() string {
+func (x *ReqTrx) GetUserId() string {
 	if x != nil {
-		return x.BlockId
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *ReqBlockResp) GetBlock() []byte {
-	if x != nil {
-		return x.Block
-	}
-	return nil
-}
-
-type PostItem struct {
+type ReqTrxResp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	TrxId           string `protobuf:"bytes,1,opt,name=TrxId,proto3" json:"TrxId,omitempty"`
-	PublisherPubkey string `protobuf:"bytes,2,opt,name=PublisherPubkey,proto3" json:"PublisherPubkey,omitempty"`
-	Content         []byte `protobuf:"bytes,3,opt,name=Content,proto3" json:"Content,omitempty"`
-	TimeStamp       int64  `protobuf:"varint,4,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	GroupId         string `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	BlockId         string `protobuf:"bytes,2,opt,name=BlockId,proto3" json:"BlockId,omitempty"`
+	TrxId           string `protobuf:"bytes,3,opt,name=TrxId,proto3" json:"TrxId,omitempty"`
+	ProviderPubkey  string `protobuf:"bytes,4,opt,name=ProviderPubkey,proto3" json:"ProviderPubkey,omitempty"`
+	RequesterPubkey string `protobuf:"bytes,5,opt,name=RequesterPubkey,proto3" json:"RequesterPubkey,omitempty"`
+	Trx             []byte `protobuf:"bytes,6,opt,name=Trx,proto3" json:"Trx,omitempty"`
 }
 
-func (x *PostItem) Reset() {
-	*x = PostItem{}
+func (x *ReqTrxResp) Reset() {
+	*x = ReqTrxResp{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1097,13 +1203,13 @@ func (x *PostItem) Reset() {
 	}
 }
 
-func (x *PostItem) String() string {
+func (x *ReqTrxResp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PostItem) ProtoMessage() {}
+func (*ReqTrxResp) ProtoMessage() {}
 
-func (x *PostItem) ProtoReflect() protoreflect.Message {
+func (x *ReqTrxResp) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1115,55 +1221,66 @@ func (x *PostItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PostItem.ProtoReflect.Descriptor instead.
-func (*PostItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReqTrxResp.ProtoReflect.Descriptor instead.
+func (*ReqTrxResp) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *PostItem) GetTrxId() string {
+func (x *ReqTrxResp) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *ReqTrxResp) GetBlockId() string {
+	if x != nil {
+		return x.BlockId
+	}
+	return ""
+}
+
+func (x *ReqTrxResp) GetTrxId() string {
 	if x != nil {
 		return x.TrxId
 	}
 	return ""
 }
 
-func (x *PostItem) GetPublisherPubkey() string {
+func (x *ReqTrxResp) GetProviderPubkey() string {
 	if x != nil {
-		return x.PublisherPubkey
+		return x.ProviderPubkey
 	}
 	return ""
 }
 
-func (x *PostItem) GetContent() []byte {
+func (x *ReqTrxResp) GetRequesterPubkey() string {
 	if x != nil {
-		return x.Content
+		return x.RequesterPubkey
 	}
-	return nil
+	return ""
 }
 
-func (x *PostItem) GetTimeStamp() int64 {
+func (x *ReqTrxResp) GetTrx() []byte {
 	if x != nil {
-		return x.TimeStamp
+		return x.Trx
 	}
-	return 0
+	return nil
 }
 
-type DenyUserItem struct {
+// light mode of a group, blocks are saved with the data of own trxs and trxs of Senders only
+type LightModeItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId          string `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	PeerId           string `protobuf:"bytes,2,opt,name=PeerId,proto3" json:"PeerId,omitempty"`
-	GroupOwnerPubkey string `protobuf:"bytes,3,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
-	GroupOwnerSign   string `protobuf:"bytes,4,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
-	TimeStamp        int64  `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
-	Action           string `protobuf:"bytes,6,opt,name=Action,proto3" json:"Action,omitempty"`
-	Memo             string `protobuf:"bytes,7,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	GroupId   string   `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Senders   []string `protobuf:"bytes,2,rep,name=Senders,proto3" json:"Senders,omitempty"` //followed senders
+	TimeStamp int64    `protobuf:"varint,3,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
 }
 
-func (x *DenyUserItem) Reset() {
-	*x = DenyUserItem{}
+func (x *LightModeItem) Reset() {
+	*x = LightModeItem{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1171,13 +1288,13 @@ func (x *DenyUserItem) Reset() {
 	}
 }
 
-func (x *DenyUserItem) String() string {
+func (x *LightModeItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DenyUserItem) ProtoMessage() {}
+func (*LightModeItem) ProtoMessage() {}
 
-func (x *DenyUserItem) ProtoReflect() protoreflect.Message {
+func (x *LightModeItem) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1189,77 +1306,47 @@ func (x *DenyUserItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DenyUserItem.ProtoReflect.Descriptor instead.
-func (*DenyUserItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use LightModeItem.ProtoReflect.Descriptor instead.
+func (*LightModeItem) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *DenyUserItem) GetGroupId() string {
+func (x *LightModeItem) GetGroupId() string {
 	if x != nil {
 		return x.GroupId
 	}
 	return ""
 }
 
-func (x *DenyUserItem) GetPeerId() string {
-	if x != nil {
-		return x.PeerId
-	}
-	return ""
-}
-
-func (x *DenyUserItem) GetGroupOwnerPubkey() string {
-	if x != nil {
-		return x.GroupOwnerPubkey
-	}
-	return ""
-}
-
-func (x *DenyUserItem) GetGroupOwnerSign() string {
+func (x *LightModeItem) GetSenders() []string {
 	if x != nil {
-		return x.GroupOwnerSign
+		return x.Senders
 	}
-	return ""
+	return nil
 }
 
-func (x *DenyUserItem) GetTimeStamp() int64 {
+func (x *LightModeItem) GetTimeStamp() int64 {
 	if x != nil {
 		return x.TimeStamp
 	}
 	return 0
 }
 
-func (x *DenyUserItem) GetAction() string {
-	if x != nil {
-		return x.Action
-	}
-	return ""
-}
-
-func (x *DenyUserItem) GetMemo() string {
-	if x != nil {
-		return x.Memo
-	}
-	return ""
-}
-
-type ProducerItem struct {
+type ReqBlockResp struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId          string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	ProducerPubkey   string     `protobuf:"bytes,2,opt,name=ProducerPubkey,proto3" json:"ProducerPubkey,omitempty"`
-	GroupOwnerPubkey string     `protobuf:"bytes,3,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
-	GroupOwnerSign   string     `protobuf:"bytes,4,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
-	TimeStamp        int64      `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
-	Action           ActionType `protobuf:"varint,6,opt,name=Action,proto3,enum=chestnut.pb.ActionType" json:"Action,omitempty"`
-	BlockProduced    int64      `protobuf:"varint,7,opt,name=BlockProduced,proto3" json:"BlockProduced,omitempty"`
-	Memo             string     `protobuf:"bytes,8,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	Result          ReqBlkResult `protobuf:"varint,1,opt,name=Result,proto3,enum=chestnut.pb.ReqBlkResult" json:"Result,omitempty"`
+	ProviderPubkey  string       `protobuf:"bytes,2,opt,name=ProviderPubkey,proto3" json:"ProviderPubkey,omitempty"`
+	RequesterPubkey string       `protobuf:"bytes,3,opt,name=RequesterPubkey,proto3" json:"RequesterPubkey,omitempty"`
+	GroupId         string       `protobuf:"bytes,4,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	BlockId         string       `protobuf:"bytes,5,opt,name=BlockId,proto3" json:"BlockId,omitempty"`
+	Block           []byte       `protobuf:"bytes,6,opt,name=Block,proto3" json:"Block,omitempty"`
 }
 
-func (x *ProducerItem) Reset() {
-	*x = ProducerItem{}
+func (x *ReqBlockResp) Reset() {
+	*x = ReqBlockResp{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1267,13 +1354,13 @@ func (x *ProducerItem) Reset() {
 	}
 }
 
-func (x *ProducerItem) String() string {
+func (x *ReqBlockResp) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ProducerItem) ProtoMessage() {}
+func (*ReqBlockResp) ProtoMessage() {}
 
-func (x *ProducerItem) ProtoReflect() protoreflect.Message {
+func (x *ReqBlockResp) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1285,87 +1372,66 @@ func (x *ProducerItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ProducerItem.ProtoReflect.Descriptor instead.
-func (*ProducerItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use ReqBlockResp.ProtoReflect.Descriptor instead.
+func (*ReqBlockResp) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *ProducerItem) GetGroupId() string {
+func (x *ReqBlockResp) GetResult() ReqBlkResult {
 	if x != nil {
-		return x.GroupId
+		return x.Result
 	}
-	return ""
+	return ReqBlkResult_BLOCK_IN_TRX
 }
 
-func (x *ProducerItem) GetProducerPubkey() string {
+func (x *ReqBlockResp) GetProviderPubkey() string {
 	if x != nil {
-		return x.ProducerPubkey
+		return x.ProviderPubkey
 	}
 	return ""
 }
 
-func (x *ProducerItem) GetGroupOwnerPubkey() string {
+func (x *ReqBlockResp) GetRequesterPubkey() string {
 	if x != nil {
-		return x.GroupOwnerPubkey
+		return x.RequesterPubkey
 	}
 	return ""
 }
 
-func (x *ProducerItem) GetGroupOwnerSign() string {
+func (x *ReqBlockResp) GetGroupId() string {
 	if x != nil {
-		return x.GroupOwnerSign
+		return x.GroupId
 	}
 	return ""
 }
 
-func (x *ProducerItem) GetTimeStamp() int64 {
+func (x *ReqBlockResp) GetBlockId() string {
 	if x != nil {
-		return x.TimeStamp
-	}
-	return 0
-}
-
-func (x *ProducerItem) GetAction() ActionType {
-	if x != nil {
-		return x.Action
-	}
-	return ActionType_ADD
-}
-
-func (x *ProducerItem) GetBlockProduced() int64 {
-	if x != nil {
-		return x.BlockProduced
+		return x.BlockId
 	}
-	return 0
+	return ""
 }
 
-func (x *ProducerItem) GetMemo() string {
+func (x *ReqBlockResp) GetBlock() []byte {
 	if x != nil {
-		return x.Memo
+		return x.Block
 	}
-	return ""
+	return nil
 }
 
-type AnnounceItem struct {
+type PostItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId            string       `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	SignPubkey         string       `protobuf:"bytes,2,opt,name=SignPubkey,proto3" json:"SignPubkey,omitempty"`
-	EncryptPubkey      string       `protobuf:"bytes,3,opt,name=EncryptPubkey,proto3" json:"EncryptPubkey,omitempty"`
-	AnnouncerSignature string       `protobuf:"bytes,4,opt,name=AnnouncerSignature,proto3" json:"AnnouncerSignature,omitempty"`
-	Type               AnnounceType `protobuf:"varint,5,opt,name=Type,proto3,enum=chestnut.pb.AnnounceType" json:"Type,omitempty"`
-	OwnerPubkey        string       `protobuf:"bytes,6,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
-	OwnerSignature     string       `protobuf:"bytes,7,opt,name=OwnerSignature,proto3" json:"OwnerSignature,omitempty"`
-	Result             ApproveType  `protobuf:"varint,8,opt,name=Result,proto3,enum=chestnut.pb.ApproveType" json:"Result,omitempty"`
-	TimeStamp          int64        `protobuf:"varint,9,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
-	Action             ActionType   `protobuf:"varint,10,opt,name=Action,proto3,enum=chestnut.pb.ActionType" json:"Action,omitempty"`
-	Memo               string       `protobuf:"bytes,11,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	TrxId           string `protobuf:"bytes,1,opt,name=TrxId,proto3" json:"TrxId,omitempty"`
+	PublisherPubkey string `protobuf:"bytes,2,opt,name=PublisherPubkey,proto3" json:"PublisherPubkey,omitempty"`
+	Content         []byte `protobuf:"bytes,3,opt,name=Content,proto3" json:"Content,omitempty"`
+	TimeStamp       int64  `protobuf:"varint,4,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
 }
 
-func (x *AnnounceItem) Reset() {
-	*x = AnnounceItem{}
+func (x *PostItem) Reset() {
+	*x = PostItem{}
 	if protoimpl.UnsafeEnabled {
 		mi := &file_chain_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1373,13 +1439,13 @@ func (x *AnnounceItem) Reset() {
 	}
 }
 
-func (x *AnnounceItem) String() string {
+func (x *PostItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AnnounceItem) ProtoMessage() {}
+func (*PostItem) ProtoMessage() {}
 
-func (x *AnnounceItem) ProtoReflect() protoreflect.Message {
+func (x *PostItem) ProtoReflect() protoreflect.Message {
 	mi := &file_chain_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -1391,119 +1457,148 @@ func (x *AnnounceItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AnnounceItem.ProtoReflect.Descriptor instead.
-func (*AnnounceItem) Descriptor() ([]byte, []int) {
+// Deprecated: Use PostItem.ProtoReflect.Descriptor instead.
+func (*PostItem) Descriptor() ([]byte, []int) {
 	return file_chain_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *AnnounceItem) GetGroupId() string {
+func (x *PostItem) GetTrxId() string {
 	if x != nil {
-		return x.GroupId
+		return x.TrxId
 	}
 	return ""
 }
 
-func (x *AnnounceItem) GetSignPubkey() string {
+func (x *PostItem) GetPublisherPubkey() string {
 	if x != nil {
-		return x.SignPubkey
+		return x.PublisherPubkey
 	}
 	return ""
 }
 
-func (x *AnnounceItem) GetEncryptPubkey() string {
+func (x *PostItem) GetContent() []byte {
 	if x != nil {
-		return x.EncryptPubkey
+		return x.Content
 	}
-	return ""
+	return nil
 }
 
-func (x *AnnounceItem) GetAnnouncerSignature() string {
+func (x *PostItem) GetTimeStamp() int64 {
 	if x != nil {
-		return x.AnnouncerSignature
+		return x.TimeStamp
 	}
-	return ""
+	return 0
 }
 
-func (x *AnnounceItem) GetType() AnnounceType {
-	if x != nil {
-		return x.Type
+type RetentionItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId     string `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	KeepDays    int64  `protobuf:"varint,2,opt,name=KeepDays,proto3" json:"KeepDays,omitempty"`       //0 means no limit
+	KeepBlocks  int64  `protobuf:"varint,3,opt,name=KeepBlocks,proto3" json:"KeepBlocks,omitempty"`   //0 means no limit
+	HeadersOnly bool   `protobuf:"varint,4,opt,name=HeadersOnly,proto3" json:"HeadersOnly,omitempty"` //keep block headers only, trx data removed after applied
+	TimeStamp   int64  `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+}
+
+func (x *RetentionItem) Reset() {
+	*x = RetentionItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return AnnounceType_AS_USER
 }
 
-func (x *AnnounceItem) GetOwnerPubkey() string {
-	if x != nil {
-		return x.OwnerPubkey
+func (x *RetentionItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetentionItem) ProtoMessage() {}
+
+func (x *RetentionItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return ""
+	return mi.MessageOf(x)
 }
 
-func (x *AnnounceItem) GetOwnerSignature() string {
+// Deprecated: Use RetentionItem.ProtoReflect.Descriptor instead.
+func (*RetentionItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *RetentionItem) GetGroupId() string {
 	if x != nil {
-		return x.OwnerSignature
+		return x.GroupId
 	}
 	return ""
 }
 
-func (x *AnnounceItem) GetResult() ApproveType {
+func (x *RetentionItem) GetKeepDays() int64 {
 	if x != nil {
-		return x.Result
+		return x.KeepDays
 	}
-	return ApproveType_ANNOUNCED
+	return 0
 }
 
-func (x *AnnounceItem) GetTimeStamp() int64 {
+func (x *RetentionItem) GetKeepBlocks() int64 {
 	if x != nil {
-		return x.TimeStamp
+		return x.KeepBlocks
 	}
 	return 0
 }
 
-func (x *AnnounceItem) GetAction() ActionType {
+func (x *RetentionItem) GetHeadersOnly() bool {
 	if x != nil {
-		return x.Action
+		return x.HeadersOnly
 	}
-	return ActionType_ADD
+	return false
 }
 
-func (x *AnnounceItem) GetMemo() string {
+func (x *RetentionItem) GetTimeStamp() int64 {
 	if x != nil {
-		return x.Memo
+		return x.TimeStamp
 	}
-	return ""
+	return 0
 }
 
-type SchemaItem struct {
+// 0 for the default value of the node
+type GroupConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId          string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	GroupOwnerPubkey string     `protobuf:"bytes,2,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
-	GroupOwnerSign   string     `protobuf:"bytes,3,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
-	Type             string     `protobuf:"bytes,4,opt,name=Type,proto3" json:"Type,omitempty"`
-	Rule             string     `protobuf:"bytes,5,opt,name=Rule,proto3" json:"Rule,omitempty"`
-	TimeStamp        int64      `protobuf:"varint,6,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
-	Action           ActionType `protobuf:"varint,7,opt,name=Action,proto3,enum=chestnut.pb.ActionType" json:"Action,omitempty"`
+	ProduceTimer    int64 `protobuf:"varint,1,opt,name=ProduceTimer,proto3" json:"ProduceTimer,omitempty"`       //milliseconds to wait for trxs before producing a block
+	MergeTimer      int64 `protobuf:"varint,2,opt,name=MergeTimer,proto3" json:"MergeTimer,omitempty"`           //milliseconds to wait for blocks of other producers before merging
+	TrxsTotalSize   int64 `protobuf:"varint,3,opt,name=TrxsTotalSize,proto3" json:"TrxsTotalSize,omitempty"`     //max bytes of trxs packed into a block
+	ObjectSizeLimit int64 `protobuf:"varint,4,opt,name=ObjectSizeLimit,proto3" json:"ObjectSizeLimit,omitempty"` //max bytes of a posted object
+	TrxExpire       int64 `protobuf:"varint,5,opt,name=TrxExpire,proto3" json:"TrxExpire,omitempty"`             //seconds before a trx expires
 }
 
-func (x *SchemaItem) Reset() {
-	*x = SchemaItem{}
+func (x *GroupConfig) Reset() {
+	*x = GroupConfig{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[12]
+		mi := &file_chain_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *SchemaItem) String() string {
+func (x *GroupConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SchemaItem) ProtoMessage() {}
+func (*GroupConfig) ProtoMessage() {}
 
-func (x *SchemaItem) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[12]
+func (x *GroupConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1514,97 +1609,77 @@ func (x *SchemaItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SchemaItem.ProtoReflect.Descriptor instead.
-func (*SchemaItem) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{12}
-}
-
-func (x *SchemaItem) GetGroupId() string {
-	if x != nil {
-		return x.GroupId
-	}
-	return ""
-}
-
-func (x *SchemaItem) GetGroupOwnerPubkey() string {
-	if x != nil {
-		return x.GroupOwnerPubkey
-	}
-	return ""
+// Deprecated: Use GroupConfig.ProtoReflect.Descriptor instead.
+func (*GroupConfig) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *SchemaItem) GetGroupOwnerSign() string {
+func (x *GroupConfig) GetProduceTimer() int64 {
 	if x != nil {
-		return x.GroupOwnerSign
+		return x.ProduceTimer
 	}
-	return ""
+	return 0
 }
 
-func (x *SchemaItem) GetType() string {
+func (x *GroupConfig) GetMergeTimer() int64 {
 	if x != nil {
-		return x.Type
+		return x.MergeTimer
 	}
-	return ""
+	return 0
 }
 
-func (x *SchemaItem) GetRule() string {
+func (x *GroupConfig) GetTrxsTotalSize() int64 {
 	if x != nil {
-		return x.Rule
+		return x.TrxsTotalSize
 	}
-	return ""
+	return 0
 }
 
-func (x *SchemaItem) GetTimeStamp() int64 {
+func (x *GroupConfig) GetObjectSizeLimit() int64 {
 	if x != nil {
-		return x.TimeStamp
+		return x.ObjectSizeLimit
 	}
 	return 0
 }
 
-func (x *SchemaItem) GetAction() ActionType {
+func (x *GroupConfig) GetTrxExpire() int64 {
 	if x != nil {
-		return x.Action
+		return x.TrxExpire
 	}
-	return ActionType_ADD
+	return 0
 }
 
-type GroupItem struct {
+type ConfigItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId           string            `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	GroupName         string            `protobuf:"bytes,2,opt,name=GroupName,proto3" json:"GroupName,omitempty"`
-	OwnerPubKey       string            `protobuf:"bytes,3,opt,name=OwnerPubKey,proto3" json:"OwnerPubKey,omitempty"`
-	UserSignPubkey    string            `protobuf:"bytes,4,opt,name=UserSignPubkey,proto3" json:"UserSignPubkey,omitempty"`
-	UserEncryptPubkey string            `protobuf:"bytes,5,opt,name=UserEncryptPubkey,proto3" json:"UserEncryptPubkey,omitempty"`
-	LastUpdate        int64             `protobuf:"varint,6,opt,name=LastUpdate,proto3" json:"LastUpdate,omitempty"`
-	HighestHeight     int64             `protobuf:"varint,7,opt,name=HighestHeight,proto3" json:"HighestHeight,omitempty"`
-	HighestBlockId    string            `protobuf:"bytes,8,opt,name=HighestBlockId,proto3" json:"HighestBlockId,omitempty"`
-	GenesisBlock      *Block            `protobuf:"bytes,9,opt,name=GenesisBlock,proto3" json:"GenesisBlock,omitempty"`
-	EncryptType       GroupEncryptType  `protobuf:"varint,10,opt,name=EncryptType,proto3,enum=chestnut.pb.GroupEncryptType" json:"EncryptType,omitempty"`
-	ConsenseType      GroupConsenseType `protobuf:"varint,11,opt,name=ConsenseType,proto3,enum=chestnut.pb.GroupConsenseType" json:"ConsenseType,omitempty"`
-	CipherKey         string            `protobuf:"bytes,12,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
-	AppKey            string            `protobuf:"bytes,13,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
+	GroupId          string       `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Config           *GroupConfig `protobuf:"bytes,2,opt,name=Config,proto3" json:"Config,omitempty"`
+	EffectiveHeight  int64        `protobuf:"varint,3,opt,name=EffectiveHeight,proto3" json:"EffectiveHeight,omitempty"` //config applies to the blocks from the height, above the block packing the config
+	GroupOwnerPubkey string       `protobuf:"bytes,4,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
+	GroupOwnerSign   string       `protobuf:"bytes,5,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
+	Memo             string       `protobuf:"bytes,6,opt,name=Memo,proto3" json:"Memo,omitempty"`
+	TimeStamp        int64        `protobuf:"varint,7,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
 }
 
-func (x *GroupItem) Reset() {
-	*x = GroupItem{}
+func (x *ConfigItem) Reset() {
+	*x = ConfigItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[13]
+		mi := &file_chain_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupItem) String() string {
+func (x *ConfigItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupItem) ProtoMessage() {}
+func (*ConfigItem) ProtoMessage() {}
 
-func (x *GroupItem) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[13]
+func (x *ConfigItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1615,140 +1690,166 @@ func (x *GroupItem) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupItem.ProtoReflect.Descriptor instead.
-func (*GroupItem) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ConfigItem.ProtoReflect.Descriptor instead.
+func (*ConfigItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *GroupItem) GetGroupId() string {
+func (x *ConfigItem) GetGroupId() string {
 	if x != nil {
 		return x.GroupId
 	}
 	return ""
 }
 
-func (x *GroupItem) GetGroupName() string {
+func (x *ConfigItem) GetConfig() *GroupConfig {
 	if x != nil {
-		return x.GroupName
+		return x.Config
 	}
-	return ""
+	return nil
 }
 
-func (x *GroupItem) GetOwnerPubKey() string {
+func (x *ConfigItem) GetEffectiveHeight() int64 {
 	if x != nil {
-		return x.OwnerPubKey
+		return x.EffectiveHeight
 	}
-	return ""
+	return 0
 }
 
-func (x *GroupItem) GetUserSignPubkey() string {
+func (x *ConfigItem) GetGroupOwnerPubkey() string {
 	if x != nil {
-		return x.UserSignPubkey
+		return x.GroupOwnerPubkey
 	}
 	return ""
 }
 
-func (x *GroupItem) GetUserEncryptPubkey() string {
+func (x *ConfigItem) GetGroupOwnerSign() string {
 	if x != nil {
-		return x.UserEncryptPubkey
+		return x.GroupOwnerSign
 	}
 	return ""
 }
 
-func (x *GroupItem) GetLastUpdate() int64 {
+func (x *ConfigItem) GetMemo() string {
 	if x != nil {
-		return x.LastUpdate
+		return x.Memo
 	}
-	return 0
+	return ""
 }
 
-func (x *GroupItem) GetHighestHeight() int64 {
+func (x *ConfigItem) GetTimeStamp() int64 {
 	if x != nil {
-		return x.HighestHeight
+		return x.TimeStamp
 	}
 	return 0
 }
 
-func (x *GroupItem) GetHighestBlockId() string {
-	if x != nil {
-		return x.HighestBlockId
-	}
-	return ""
+type HeartbeatItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId        string `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	ProducerPubkey string `protobuf:"bytes,2,opt,name=ProducerPubkey,proto3" json:"ProducerPubkey,omitempty"`
+	HighestHeight  int64  `protobuf:"varint,3,opt,name=HighestHeight,proto3" json:"HighestHeight,omitempty"`
+	HighestBlockId string `protobuf:"bytes,4,opt,name=HighestBlockId,proto3" json:"HighestBlockId,omitempty"`
+	TimeStamp      int64  `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
 }
 
-func (x *GroupItem) GetGenesisBlock() *Block {
-	if x != nil {
-		return x.GenesisBlock
+func (x *HeartbeatItem) Reset() {
+	*x = HeartbeatItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
 	}
-	return nil
 }
 
-func (x *GroupItem) GetEncryptType() GroupEncryptType {
-	if x != nil {
-		return x.EncryptType
-	}
-	return GroupEncryptType_PUBLIC
+func (x *HeartbeatItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *GroupItem) GetConsenseType() GroupConsenseType {
-	if x != nil {
-		return x.ConsenseType
-	}
-	return GroupConsenseType_POA
+func (*HeartbeatItem) ProtoMessage() {}
+
+func (x *HeartbeatItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
 }
 
-func (x *GroupItem) GetCipherKey() string {
+// Deprecated: Use HeartbeatItem.ProtoReflect.Descriptor instead.
+func (*HeartbeatItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *HeartbeatItem) GetGroupId() string {
 	if x != nil {
-		return x.CipherKey
+		return x.GroupId
 	}
 	return ""
 }
 
-func (x *GroupItem) GetAppKey() string {
+func (x *HeartbeatItem) GetProducerPubkey() string {
 	if x != nil {
-		return x.AppKey
+		return x.ProducerPubkey
 	}
 	return ""
 }
 
-type GroupItemV0 struct {
+func (x *HeartbeatItem) GetHighestHeight() int64 {
+	if x != nil {
+		return x.HighestHeight
+	}
+	return 0
+}
+
+func (x *HeartbeatItem) GetHighestBlockId() string {
+	if x != nil {
+		return x.HighestBlockId
+	}
+	return ""
+}
+
+func (x *HeartbeatItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+type DemotionPolicyItem struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	GroupId           string            `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
-	GroupName         string            `protobuf:"bytes,2,opt,name=GroupName,proto3" json:"GroupName,omitempty"`
-	OwnerPubKey       string            `protobuf:"bytes,3,opt,name=OwnerPubKey,proto3" json:"OwnerPubKey,omitempty"`
-	UserSignPubkey    string            `protobuf:"bytes,4,opt,name=UserSignPubkey,proto3" json:"UserSignPubkey,omitempty"`
-	UserEncryptPubkey string            `protobuf:"bytes,5,opt,name=UserEncryptPubkey,proto3" json:"UserEncryptPubkey,omitempty"`
-	UserRole          RoleV0            `protobuf:"varint,6,opt,name=UserRole,proto3,enum=chestnut.pb.RoleV0" json:"UserRole,omitempty"`
-	LastUpdate        int64             `protobuf:"varint,7,opt,name=LastUpdate,proto3" json:"LastUpdate,omitempty"`
-	HighestHeight     int64             `protobuf:"varint,8,opt,name=HighestHeight,proto3" json:"HighestHeight,omitempty"`
-	HighestBlockId    string            `protobuf:"bytes,9,opt,name=HighestBlockId,proto3" json:"HighestBlockId,omitempty"`
-	GenesisBlock      *Block            `protobuf:"bytes,10,opt,name=GenesisBlock,proto3" json:"GenesisBlock,omitempty"`
-	EncryptType       GroupEncryptType  `protobuf:"varint,11,opt,name=EncryptType,proto3,enum=chestnut.pb.GroupEncryptType" json:"EncryptType,omitempty"`
-	ConsenseType      GroupConsenseType `protobuf:"varint,12,opt,name=ConsenseType,proto3,enum=chestnut.pb.GroupConsenseType" json:"ConsenseType,omitempty"`
-	CipherKey         string            `protobuf:"bytes,13,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
-	AppKey            string            `protobuf:"bytes,14,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
+	GroupId         string `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	MaxMissedRounds int64  `protobuf:"varint,2,opt,name=MaxMissedRounds,proto3" json:"MaxMissedRounds,omitempty"` //propose removing a producer missed the rounds in a row, 0 disables
+	TimeStamp       int64  `protobuf:"varint,3,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
 }
 
-func (x *GroupItemV0) Reset() {
-	*x = GroupItemV0{}
+func (x *DemotionPolicyItem) Reset() {
+	*x = DemotionPolicyItem{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[14]
+		mi := &file_chain_proto_msgTypes[16]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *GroupItemV0) String() string {
+func (x *DemotionPolicyItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GroupItemV0) ProtoMessage() {}
+func (*DemotionPolicyItem) ProtoMessage() {}
 
-func (x *GroupItemV0) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[14]
+func (x *DemotionPolicyItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[16]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1759,137 +1860,1526 @@ func (x *GroupItemV0) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GroupItemV0.ProtoReflect.Descriptor instead.
-func (*GroupItemV0) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use DemotionPolicyItem.ProtoReflect.Descriptor instead.
+func (*DemotionPolicyItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *GroupItemV0) GetGroupId() string {
+func (x *DemotionPolicyItem) GetGroupId() string {
 	if x != nil {
 		return x.GroupId
 	}
 	return ""
 }
 
-func (x *GroupItemV0) GetGroupName() string {
+func (x *DemotionPolicyItem) GetMaxMissedRounds() int64 {
 	if x != nil {
-		return x.GroupName
+		return x.MaxMissedRounds
 	}
-	return ""
+	return 0
 }
 
-func (x *GroupItemV0) GetOwnerPubKey() string {
+func (x *DemotionPolicyItem) GetTimeStamp() int64 {
 	if x != nil {
-		return x.OwnerPubKey
+		return x.TimeStamp
+	}
+	return 0
+}
+
+type BlobManifest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	BlobId    string   `protobuf:"bytes,1,opt,name=BlobId,proto3" json:"BlobId,omitempty"` //sha256 of the whole content, hex encoded
+	Size      int64    `protobuf:"varint,2,opt,name=Size,proto3" json:"Size,omitempty"`
+	MediaType string   `protobuf:"bytes,3,opt,name=MediaType,proto3" json:"MediaType,omitempty"`
+	Name      string   `protobuf:"bytes,4,opt,name=Name,proto3" json:"Name,omitempty"`
+	Chunks    []string `protobuf:"bytes,5,rep,name=Chunks,proto3" json:"Chunks,omitempty"` //sha256 of each chunk in order, hex encoded
+	TimeStamp int64    `protobuf:"varint,6,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+}
+
+func (x *BlobManifest) Reset() {
+	*x = BlobManifest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlobManifest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobManifest) ProtoMessage() {}
+
+func (x *BlobManifest) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobManifest.ProtoReflect.Descriptor instead.
+func (*BlobManifest) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BlobManifest) GetBlobId() string {
+	if x != nil {
+		return x.BlobId
 	}
 	return ""
 }
 
-func (x *GroupItemV0) GetUserSignPubkey() string {
+func (x *BlobManifest) GetSize() int64 {
 	if x != nil {
-		return x.UserSignPubkey
+		return x.Size
+	}
+	return 0
+}
+
+func (x *BlobManifest) GetMediaType() string {
+	if x != nil {
+		return x.MediaType
 	}
 	return ""
 }
 
-func (x *GroupItemV0) GetUserEncryptPubkey() string {
+func (x *BlobManifest) GetName() string {
 	if x != nil {
-		return x.UserEncryptPubkey
+		return x.Name
 	}
 	return ""
 }
 
-func (x *GroupItemV0) GetUserRole() RoleV0 {
+func (x *BlobManifest) GetChunks() []string {
 	if x != nil {
-		return x.UserRole
+		return x.Chunks
 	}
-	return RoleV0_GROUP_PRODUCER
+	return nil
 }
 
-func (x *GroupItemV0) GetLastUpdate() int64 {
+func (x *BlobManifest) GetTimeStamp() int64 {
 	if x != nil {
-		return x.LastUpdate
+		return x.TimeStamp
 	}
 	return 0
 }
 
-func (x *GroupItemV0) GetHighestHeight() int64 {
+type BlobReq struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type    BlobReqType `protobuf:"varint,1,opt,name=Type,proto3,enum=chestnut.pb.BlobReqType" json:"Type,omitempty"`
+	GroupId string      `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Id      string      `protobuf:"bytes,3,opt,name=Id,proto3" json:"Id,omitempty"`         //blob id for BLOB_MANIFEST, chunk id for BLOB_CHUNK
+	BlobId  string      `protobuf:"bytes,4,opt,name=BlobId,proto3" json:"BlobId,omitempty"` //blob of the chunk for BLOB_CHUNK, referenced by the group
+}
+
+func (x *BlobReq) Reset() {
+	*x = BlobReq{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlobReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobReq) ProtoMessage() {}
+
+func (x *BlobReq) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobReq.ProtoReflect.Descriptor instead.
+func (*BlobReq) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *BlobReq) GetType() BlobReqType {
 	if x != nil {
-		return x.HighestHeight
+		return x.Type
 	}
-	return 0
+	return BlobReqType_BLOB_MANIFEST
 }
 
-func (x *GroupItemV0) GetHighestBlockId() string {
+func (x *BlobReq) GetGroupId() string {
 	if x != nil {
-		return x.HighestBlockId
+		return x.GroupId
 	}
 	return ""
 }
 
-func (x *GroupItemV0) GetGenesisBlock() *Block {
+func (x *BlobReq) GetId() string {
 	if x != nil {
-		return x.GenesisBlock
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BlobReq) GetBlobId() string {
+	if x != nil {
+		return x.BlobId
+	}
+	return ""
+}
+
+type BlobResp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type     BlobReqType   `protobuf:"varint,1,opt,name=Type,proto3,enum=chestnut.pb.BlobReqType" json:"Type,omitempty"`
+	Id       string        `protobuf:"bytes,2,opt,name=Id,proto3" json:"Id,omitempty"`
+	Error    string        `protobuf:"bytes,3,opt,name=Error,proto3" json:"Error,omitempty"`
+	Manifest *BlobManifest `protobuf:"bytes,4,opt,name=Manifest,proto3" json:"Manifest,omitempty"`
+	Data     []byte        `protobuf:"bytes,5,opt,name=Data,proto3" json:"Data,omitempty"`
+}
+
+func (x *BlobResp) Reset() {
+	*x = BlobResp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BlobResp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BlobResp) ProtoMessage() {}
+
+func (x *BlobResp) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BlobResp.ProtoReflect.Descriptor instead.
+func (*BlobResp) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *BlobResp) GetType() BlobReqType {
+	if x != nil {
+		return x.Type
+	}
+	return BlobReqType_BLOB_MANIFEST
+}
+
+func (x *BlobResp) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *BlobResp) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BlobResp) GetManifest() *BlobManifest {
+	if x != nil {
+		return x.Manifest
 	}
 	return nil
 }
 
-func (x *GroupItemV0) GetEncryptType() GroupEncryptType {
+func (x *BlobResp) GetData() []byte {
 	if x != nil {
-		return x.EncryptType
+		return x.Data
+	}
+	return nil
+}
+
+type DenyUserItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId          string `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	PeerId           string `protobuf:"bytes,2,opt,name=PeerId,proto3" json:"PeerId,omitempty"`
+	GroupOwnerPubkey string `protobuf:"bytes,3,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
+	GroupOwnerSign   string `protobuf:"bytes,4,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
+	TimeStamp        int64  `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	Action           string `protobuf:"bytes,6,opt,name=Action,proto3" json:"Action,omitempty"`
+	Memo             string `protobuf:"bytes,7,opt,name=Memo,proto3" json:"Memo,omitempty"`
+}
+
+func (x *DenyUserItem) Reset() {
+	*x = DenyUserItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DenyUserItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DenyUserItem) ProtoMessage() {}
+
+func (x *DenyUserItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DenyUserItem.ProtoReflect.Descriptor instead.
+func (*DenyUserItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *DenyUserItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *DenyUserItem) GetPeerId() string {
+	if x != nil {
+		return x.PeerId
+	}
+	return ""
+}
+
+func (x *DenyUserItem) GetGroupOwnerPubkey() string {
+	if x != nil {
+		return x.GroupOwnerPubkey
+	}
+	return ""
+}
+
+func (x *DenyUserItem) GetGroupOwnerSign() string {
+	if x != nil {
+		return x.GroupOwnerSign
+	}
+	return ""
+}
+
+func (x *DenyUserItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *DenyUserItem) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *DenyUserItem) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+type ProducerItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId          string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	ProducerPubkey   string     `protobuf:"bytes,2,opt,name=ProducerPubkey,proto3" json:"ProducerPubkey,omitempty"`
+	GroupOwnerPubkey string     `protobuf:"bytes,3,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
+	GroupOwnerSign   string     `protobuf:"bytes,4,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
+	TimeStamp        int64      `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	Action           ActionType `protobuf:"varint,6,opt,name=Action,proto3,enum=chestnut.pb.ActionType" json:"Action,omitempty"`
+	BlockProduced    int64      `protobuf:"varint,7,opt,name=BlockProduced,proto3" json:"BlockProduced,omitempty"`
+	Memo             string     `protobuf:"bytes,8,opt,name=Memo,proto3" json:"Memo,omitempty"`
+}
+
+func (x *ProducerItem) Reset() {
+	*x = ProducerItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProducerItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProducerItem) ProtoMessage() {}
+
+func (x *ProducerItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProducerItem.ProtoReflect.Descriptor instead.
+func (*ProducerItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ProducerItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *ProducerItem) GetProducerPubkey() string {
+	if x != nil {
+		return x.ProducerPubkey
+	}
+	return ""
+}
+
+func (x *ProducerItem) GetGroupOwnerPubkey() string {
+	if x != nil {
+		return x.GroupOwnerPubkey
+	}
+	return ""
+}
+
+func (x *ProducerItem) GetGroupOwnerSign() string {
+	if x != nil {
+		return x.GroupOwnerSign
+	}
+	return ""
+}
+
+func (x *ProducerItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *ProducerItem) GetAction() ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return ActionType_ADD
+}
+
+func (x *ProducerItem) GetBlockProduced() int64 {
+	if x != nil {
+		return x.BlockProduced
+	}
+	return 0
+}
+
+func (x *ProducerItem) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+type AnnounceItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId            string       `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	SignPubkey         string       `protobuf:"bytes,2,opt,name=SignPubkey,proto3" json:"SignPubkey,omitempty"`
+	EncryptPubkey      string       `protobuf:"bytes,3,opt,name=EncryptPubkey,proto3" json:"EncryptPubkey,omitempty"`
+	AnnouncerSignature string       `protobuf:"bytes,4,opt,name=AnnouncerSignature,proto3" json:"AnnouncerSignature,omitempty"`
+	Type               AnnounceType `protobuf:"varint,5,opt,name=Type,proto3,enum=chestnut.pb.AnnounceType" json:"Type,omitempty"`
+	OwnerPubkey        string       `protobuf:"bytes,6,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
+	OwnerSignature     string       `protobuf:"bytes,7,opt,name=OwnerSignature,proto3" json:"OwnerSignature,omitempty"`
+	Result             ApproveType  `protobuf:"varint,8,opt,name=Result,proto3,enum=chestnut.pb.ApproveType" json:"Result,omitempty"`
+	TimeStamp          int64        `protobuf:"varint,9,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	Action             ActionType   `protobuf:"varint,10,opt,name=Action,proto3,enum=chestnut.pb.ActionType" json:"Action,omitempty"`
+	Memo               string       `protobuf:"bytes,11,opt,name=Memo,proto3" json:"Memo,omitempty"`
+}
+
+func (x *AnnounceItem) Reset() {
+	*x = AnnounceItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AnnounceItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnnounceItem) ProtoMessage() {}
+
+func (x *AnnounceItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnnounceItem.ProtoReflect.Descriptor instead.
+func (*AnnounceItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AnnounceItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetSignPubkey() string {
+	if x != nil {
+		return x.SignPubkey
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetEncryptPubkey() string {
+	if x != nil {
+		return x.EncryptPubkey
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetAnnouncerSignature() string {
+	if x != nil {
+		return x.AnnouncerSignature
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetType() AnnounceType {
+	if x != nil {
+		return x.Type
+	}
+	return AnnounceType_AS_USER
+}
+
+func (x *AnnounceItem) GetOwnerPubkey() string {
+	if x != nil {
+		return x.OwnerPubkey
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetOwnerSignature() string {
+	if x != nil {
+		return x.OwnerSignature
+	}
+	return ""
+}
+
+func (x *AnnounceItem) GetResult() ApproveType {
+	if x != nil {
+		return x.Result
+	}
+	return ApproveType_ANNOUNCED
+}
+
+func (x *AnnounceItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *AnnounceItem) GetAction() ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return ActionType_ADD
+}
+
+func (x *AnnounceItem) GetMemo() string {
+	if x != nil {
+		return x.Memo
+	}
+	return ""
+}
+
+type SchemaItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId          string     `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	GroupOwnerPubkey string     `protobuf:"bytes,2,opt,name=GroupOwnerPubkey,proto3" json:"GroupOwnerPubkey,omitempty"`
+	GroupOwnerSign   string     `protobuf:"bytes,3,opt,name=GroupOwnerSign,proto3" json:"GroupOwnerSign,omitempty"`
+	Type             string     `protobuf:"bytes,4,opt,name=Type,proto3" json:"Type,omitempty"`
+	Rule             string     `protobuf:"bytes,5,opt,name=Rule,proto3" json:"Rule,omitempty"`
+	TimeStamp        int64      `protobuf:"varint,6,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	Action           ActionType `protobuf:"varint,7,opt,name=Action,proto3,enum=chestnut.pb.ActionType" json:"Action,omitempty"`
+}
+
+func (x *SchemaItem) Reset() {
+	*x = SchemaItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *SchemaItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaItem) ProtoMessage() {}
+
+func (x *SchemaItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SchemaItem.ProtoReflect.Descriptor instead.
+func (*SchemaItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SchemaItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *SchemaItem) GetGroupOwnerPubkey() string {
+	if x != nil {
+		return x.GroupOwnerPubkey
+	}
+	return ""
+}
+
+func (x *SchemaItem) GetGroupOwnerSign() string {
+	if x != nil {
+		return x.GroupOwnerSign
+	}
+	return ""
+}
+
+func (x *SchemaItem) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SchemaItem) GetRule() string {
+	if x != nil {
+		return x.Rule
+	}
+	return ""
+}
+
+func (x *SchemaItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *SchemaItem) GetAction() ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return ActionType_ADD
+}
+
+type GroupItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId           string            `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	GroupName         string            `protobuf:"bytes,2,opt,name=GroupName,proto3" json:"GroupName,omitempty"`
+	OwnerPubKey       string            `protobuf:"bytes,3,opt,name=OwnerPubKey,proto3" json:"OwnerPubKey,omitempty"`
+	UserSignPubkey    string            `protobuf:"bytes,4,opt,name=UserSignPubkey,proto3" json:"UserSignPubkey,omitempty"`
+	UserEncryptPubkey string            `protobuf:"bytes,5,opt,name=UserEncryptPubkey,proto3" json:"UserEncryptPubkey,omitempty"`
+	LastUpdate        int64             `protobuf:"varint,6,opt,name=LastUpdate,proto3" json:"LastUpdate,omitempty"`
+	HighestHeight     int64             `protobuf:"varint,7,opt,name=HighestHeight,proto3" json:"HighestHeight,omitempty"`
+	HighestBlockId    string            `protobuf:"bytes,8,opt,name=HighestBlockId,proto3" json:"HighestBlockId,omitempty"`
+	GenesisBlock      *Block            `protobuf:"bytes,9,opt,name=GenesisBlock,proto3" json:"GenesisBlock,omitempty"`
+	EncryptType       GroupEncryptType  `protobuf:"varint,10,opt,name=EncryptType,proto3,enum=chestnut.pb.GroupEncryptType" json:"EncryptType,omitempty"`
+	ConsenseType      GroupConsenseType `protobuf:"varint,11,opt,name=ConsenseType,proto3,enum=chestnut.pb.GroupConsenseType" json:"ConsenseType,omitempty"`
+	CipherKey         string            `protobuf:"bytes,12,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
+	AppKey            string            `protobuf:"bytes,13,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
+	CipherAlg         string            `protobuf:"bytes,14,opt,name=CipherAlg,proto3" json:"CipherAlg,omitempty"`
+	Admins            *AdminSet         `protobuf:"bytes,15,opt,name=Admins,proto3" json:"Admins,omitempty"`
+	CipherKeyEpoch    uint32            `protobuf:"varint,16,opt,name=CipherKeyEpoch,proto3" json:"CipherKeyEpoch,omitempty"`                                                                                           //key epoch of CipherKey, sealed in the cipher envelope
+	EpochCipherKeys   map[uint32]string `protobuf:"bytes,17,rep,name=EpochCipherKeys,proto3" json:"EpochCipherKeys,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` //cipher keys of earlier epochs, to open data sealed before
+}
+
+func (x *GroupItem) Reset() {
+	*x = GroupItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupItem) ProtoMessage() {}
+
+func (x *GroupItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupItem.ProtoReflect.Descriptor instead.
+func (*GroupItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GroupItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GroupItem) GetGroupName() string {
+	if x != nil {
+		return x.GroupName
+	}
+	return ""
+}
+
+func (x *GroupItem) GetOwnerPubKey() string {
+	if x != nil {
+		return x.OwnerPubKey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetUserSignPubkey() string {
+	if x != nil {
+		return x.UserSignPubkey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetUserEncryptPubkey() string {
+	if x != nil {
+		return x.UserEncryptPubkey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetLastUpdate() int64 {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return 0
+}
+
+func (x *GroupItem) GetHighestHeight() int64 {
+	if x != nil {
+		return x.HighestHeight
+	}
+	return 0
+}
+
+func (x *GroupItem) GetHighestBlockId() string {
+	if x != nil {
+		return x.HighestBlockId
+	}
+	return ""
+}
+
+func (x *GroupItem) GetGenesisBlock() *Block {
+	if x != nil {
+		return x.GenesisBlock
+	}
+	return nil
+}
+
+func (x *GroupItem) GetEncryptType() GroupEncryptType {
+	if x != nil {
+		return x.EncryptType
+	}
+	return GroupEncryptType_PUBLIC
+}
+
+func (x *GroupItem) GetConsenseType() GroupConsenseType {
+	if x != nil {
+		return x.ConsenseType
+	}
+	return GroupConsenseType_POA
+}
+
+func (x *GroupItem) GetCipherKey() string {
+	if x != nil {
+		return x.CipherKey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetAppKey() string {
+	if x != nil {
+		return x.AppKey
+	}
+	return ""
+}
+
+func (x *GroupItem) GetCipherAlg() string {
+	if x != nil {
+		return x.CipherAlg
+	}
+	return ""
+}
+
+func (x *GroupItem) GetAdmins() *AdminSet {
+	if x != nil {
+		return x.Admins
+	}
+	return nil
+}
+
+func (x *GroupItem) GetCipherKeyEpoch() uint32 {
+	if x != nil {
+		return x.CipherKeyEpoch
+	}
+	return 0
+}
+
+func (x *GroupItem) GetEpochCipherKeys() map[uint32]string {
+	if x != nil {
+		return x.EpochCipherKeys
+	}
+	return nil
+}
+
+type GroupItemV0 struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupId           string            `protobuf:"bytes,1,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	GroupName         string            `protobuf:"bytes,2,opt,name=GroupName,proto3" json:"GroupName,omitempty"`
+	OwnerPubKey       string            `protobuf:"bytes,3,opt,name=OwnerPubKey,proto3" json:"OwnerPubKey,omitempty"`
+	UserSignPubkey    string            `protobuf:"bytes,4,opt,name=UserSignPubkey,proto3" json:"UserSignPubkey,omitempty"`
+	UserEncryptPubkey string            `protobuf:"bytes,5,opt,name=UserEncryptPubkey,proto3" json:"UserEncryptPubkey,omitempty"`
+	UserRole          RoleV0            `protobuf:"varint,6,opt,name=UserRole,proto3,enum=chestnut.pb.RoleV0" json:"UserRole,omitempty"`
+	LastUpdate        int64             `protobuf:"varint,7,opt,name=LastUpdate,proto3" json:"LastUpdate,omitempty"`
+	HighestHeight     int64             `protobuf:"varint,8,opt,name=HighestHeight,proto3" json:"HighestHeight,omitempty"`
+	HighestBlockId    string            `protobuf:"bytes,9,opt,name=HighestBlockId,proto3" json:"HighestBlockId,omitempty"`
+	GenesisBlock      *Block            `protobuf:"bytes,10,opt,name=GenesisBlock,proto3" json:"GenesisBlock,omitempty"`
+	EncryptType       GroupEncryptType  `protobuf:"varint,11,opt,name=EncryptType,proto3,enum=chestnut.pb.GroupEncryptType" json:"EncryptType,omitempty"`
+	ConsenseType      GroupConsenseType `protobuf:"varint,12,opt,name=ConsenseType,proto3,enum=chestnut.pb.GroupConsenseType" json:"ConsenseType,omitempty"`
+	CipherKey         string            `protobuf:"bytes,13,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
+	AppKey            string            `protobuf:"bytes,14,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
+}
+
+func (x *GroupItemV0) Reset() {
+	*x = GroupItemV0{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GroupItemV0) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GroupItemV0) ProtoMessage() {}
+
+func (x *GroupItemV0) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GroupItemV0.ProtoReflect.Descriptor instead.
+func (*GroupItemV0) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GroupItemV0) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *GroupItemV0) GetGroupName() string {
+	if x != nil {
+		return x.GroupName
+	}
+	return ""
+}
+
+func (x *GroupItemV0) GetOwnerPubKey() string {
+	if x != nil {
+		return x.OwnerPubKey
+	}
+	return ""
+}
+
+func (x *GroupItemV0) GetUserSignPubkey() string {
+	if x != nil {
+		return x.UserSignPubkey
+	}
+	return ""
+}
+
+func (x *GroupItemV0) GetUserEncryptPubkey() string {
+	if x != nil {
+		return x.UserEncryptPubkey
+	}
+	return ""
+}
+
+func (x *GroupItemV0) GetUserRole() RoleV0 {
+	if x != nil {
+		return x.UserRole
+	}
+	return RoleV0_GROUP_PRODUCER
+}
+
+func (x *GroupItemV0) GetLastUpdate() int64 {
+	if x != nil {
+		return x.LastUpdate
+	}
+	return 0
+}
+
+func (x *GroupItemV0) GetHighestHeight() int64 {
+	if x != nil {
+		return x.HighestHeight
+	}
+	return 0
+}
+
+func (x *GroupItemV0) GetHighestBlockId() string {
+	if x != nil {
+		return x.HighestBlockId
+	}
+	return ""
+}
+
+func (x *GroupItemV0) GetGenesisBlock() *Block {
+	if x != nil {
+		return x.GenesisBlock
+	}
+	return nil
+}
+
+func (x *GroupItemV0) GetEncryptType() GroupEncryptType {
+	if x != nil {
+		return x.EncryptType
+	}
+	return GroupEncryptType_PUBLIC
+}
+
+func (x *GroupItemV0) GetConsenseType() GroupConsenseType {
+	if x != nil {
+		return x.ConsenseType
+	}
+	return GroupConsenseType_POA
+}
+
+func (x *GroupItemV0) GetCipherKey() string {
+	if x != nil {
+		return x.CipherKey
+	}
+	return ""
+}
+
+func (x *GroupItemV0) GetAppKey() string {
+	if x != nil {
+		return x.AppKey
+	}
+	return ""
+}
+
+type PSPing struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Seqnum    int32  `protobuf:"varint,1,opt,name=Seqnum,proto3" json:"Seqnum,omitempty"`
+	IsResp    bool   `protobuf:"varint,2,opt,name=IsResp,proto3" json:"IsResp,omitempty"`
+	TimeStamp int64  `protobuf:"varint,3,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	Payload   []byte `protobuf:"bytes,4,opt,name=Payload,proto3" json:"Payload,omitempty"`
+}
+
+func (x *PSPing) Reset() {
+	*x = PSPing{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PSPing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PSPing) ProtoMessage() {}
+
+func (x *PSPing) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PSPing.ProtoReflect.Descriptor instead.
+func (*PSPing) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *PSPing) GetSeqnum() int32 {
+	if x != nil {
+		return x.Seqnum
+	}
+	return 0
+}
+
+func (x *PSPing) GetIsResp() bool {
+	if x != nil {
+		return x.IsResp
+	}
+	return false
+}
+
+func (x *PSPing) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *PSPing) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// M-of-N admins, owner trxs are valid with the signatures of at least Threshold admins
+type AdminSet struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pubkeys   []string `protobuf:"bytes,1,rep,name=Pubkeys,proto3" json:"Pubkeys,omitempty"`
+	Threshold int32    `protobuf:"varint,2,opt,name=Threshold,proto3" json:"Threshold,omitempty"`
+}
+
+func (x *AdminSet) Reset() {
+	*x = AdminSet{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminSet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminSet) ProtoMessage() {}
+
+func (x *AdminSet) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminSet.ProtoReflect.Descriptor instead.
+func (*AdminSet) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AdminSet) GetPubkeys() []string {
+	if x != nil {
+		return x.Pubkeys
+	}
+	return nil
+}
+
+func (x *AdminSet) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+type AdminSign struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Pubkey string `protobuf:"bytes,1,opt,name=Pubkey,proto3" json:"Pubkey,omitempty"`
+	Sign   []byte `protobuf:"bytes,2,opt,name=Sign,proto3" json:"Sign,omitempty"`
+}
+
+func (x *AdminSign) Reset() {
+	*x = AdminSign{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminSign) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminSign) ProtoMessage() {}
+
+func (x *AdminSign) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminSign.ProtoReflect.Descriptor instead.
+func (*AdminSign) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *AdminSign) GetPubkey() string {
+	if x != nil {
+		return x.Pubkey
+	}
+	return ""
+}
+
+func (x *AdminSign) GetSign() []byte {
+	if x != nil {
+		return x.Sign
+	}
+	return nil
+}
+
+// owner trx waiting for the signatures of the admins
+type AdminAction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ActionId  string       `protobuf:"bytes,1,opt,name=ActionId,proto3" json:"ActionId,omitempty"`
+	GroupId   string       `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Type      TrxType      `protobuf:"varint,3,opt,name=Type,proto3,enum=chestnut.pb.TrxType" json:"Type,omitempty"`
+	Data      []byte       `protobuf:"bytes,4,opt,name=Data,proto3" json:"Data,omitempty"`
+	Signs     []*AdminSign `protobuf:"bytes,5,rep,name=Signs,proto3" json:"Signs,omitempty"`
+	Proposer  string       `protobuf:"bytes,6,opt,name=Proposer,proto3" json:"Proposer,omitempty"`
+	TimeStamp int64        `protobuf:"varint,7,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+}
+
+func (x *AdminAction) Reset() {
+	*x = AdminAction{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *AdminAction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminAction) ProtoMessage() {}
+
+func (x *AdminAction) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminAction.ProtoReflect.Descriptor instead.
+func (*AdminAction) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *AdminAction) GetActionId() string {
+	if x != nil {
+		return x.ActionId
+	}
+	return ""
+}
+
+func (x *AdminAction) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *AdminAction) GetType() TrxType {
+	if x != nil {
+		return x.Type
+	}
+	return TrxType_POST
+}
+
+func (x *AdminAction) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *AdminAction) GetSigns() []*AdminSign {
+	if x != nil {
+		return x.Signs
+	}
+	return nil
+}
+
+func (x *AdminAction) GetProposer() string {
+	if x != nil {
+		return x.Proposer
+	}
+	return ""
+}
+
+func (x *AdminAction) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+type InviteItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InviteId      string     `protobuf:"bytes,1,opt,name=InviteId,proto3" json:"InviteId,omitempty"`
+	GroupId       string     `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	Expire        int64      `protobuf:"varint,3,opt,name=Expire,proto3" json:"Expire,omitempty"`              //unix nano, 0 for never
+	MaxUse        int32      `protobuf:"varint,4,opt,name=MaxUse,proto3" json:"MaxUse,omitempty"`              //0 for no limit
+	InviteePubkey string     `protobuf:"bytes,5,opt,name=InviteePubkey,proto3" json:"InviteePubkey,omitempty"` //only the holder of the key can redeem, empty for anyone
+	OwnerPubkey   string     `protobuf:"bytes,6,opt,name=OwnerPubkey,proto3" json:"OwnerPubkey,omitempty"`
+	OwnerSign     string     `protobuf:"bytes,7,opt,name=OwnerSign,proto3" json:"OwnerSign,omitempty"`
+	TimeStamp     int64      `protobuf:"varint,8,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+	Action        ActionType `protobuf:"varint,9,opt,name=Action,proto3,enum=chestnut.pb.ActionType" json:"Action,omitempty"` //REMOVE revokes the invitation
+	Used          int32      `protobuf:"varint,10,opt,name=Used,proto3" json:"Used,omitempty"`                                //redemptions applied, not signed
+	Revoked       bool       `protobuf:"varint,11,opt,name=Revoked,proto3" json:"Revoked,omitempty"`                          //not signed
+}
+
+func (x *InviteItem) Reset() {
+	*x = InviteItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[30]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InviteItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InviteItem) ProtoMessage() {}
+
+func (x *InviteItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[30]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InviteItem.ProtoReflect.Descriptor instead.
+func (*InviteItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *InviteItem) GetInviteId() string {
+	if x != nil {
+		return x.InviteId
+	}
+	return ""
+}
+
+func (x *InviteItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
+	}
+	return ""
+}
+
+func (x *InviteItem) GetExpire() int64 {
+	if x != nil {
+		return x.Expire
+	}
+	return 0
+}
+
+func (x *InviteItem) GetMaxUse() int32 {
+	if x != nil {
+		return x.MaxUse
+	}
+	return 0
+}
+
+func (x *InviteItem) GetInviteePubkey() string {
+	if x != nil {
+		return x.InviteePubkey
+	}
+	return ""
+}
+
+func (x *InviteItem) GetOwnerPubkey() string {
+	if x != nil {
+		return x.OwnerPubkey
+	}
+	return ""
+}
+
+func (x *InviteItem) GetOwnerSign() string {
+	if x != nil {
+		return x.OwnerSign
+	}
+	return ""
+}
+
+func (x *InviteItem) GetTimeStamp() int64 {
+	if x != nil {
+		return x.TimeStamp
+	}
+	return 0
+}
+
+func (x *InviteItem) GetAction() ActionType {
+	if x != nil {
+		return x.Action
+	}
+	return ActionType_ADD
+}
+
+func (x *InviteItem) GetUsed() int32 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *InviteItem) GetRevoked() bool {
+	if x != nil {
+		return x.Revoked
+	}
+	return false
+}
+
+type RedeemItem struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	InviteId       string `protobuf:"bytes,1,opt,name=InviteId,proto3" json:"InviteId,omitempty"`
+	GroupId        string `protobuf:"bytes,2,opt,name=GroupId,proto3" json:"GroupId,omitempty"`
+	RedeemerPubkey string `protobuf:"bytes,3,opt,name=RedeemerPubkey,proto3" json:"RedeemerPubkey,omitempty"` //group sign pubkey of the joiner
+	InviteeSign    string `protobuf:"bytes,4,opt,name=InviteeSign,proto3" json:"InviteeSign,omitempty"`       //signed by the invitee key of a bound invitation
+	TimeStamp      int64  `protobuf:"varint,5,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
+}
+
+func (x *RedeemItem) Reset() {
+	*x = RedeemItem{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_chain_proto_msgTypes[31]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RedeemItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RedeemItem) ProtoMessage() {}
+
+func (x *RedeemItem) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[31]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RedeemItem.ProtoReflect.Descriptor instead.
+func (*RedeemItem) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *RedeemItem) GetInviteId() string {
+	if x != nil {
+		return x.InviteId
+	}
+	return ""
+}
+
+func (x *RedeemItem) GetGroupId() string {
+	if x != nil {
+		return x.GroupId
 	}
-	return GroupEncryptType_PUBLIC
+	return ""
 }
 
-func (x *GroupItemV0) GetConsenseType() GroupConsenseType {
+func (x *RedeemItem) GetRedeemerPubkey() string {
 	if x != nil {
-		return x.ConsenseType
+		return x.RedeemerPubkey
 	}
-	return GroupConsenseType_POA
+	return ""
 }
 
-func (x *GroupItemV0) GetCipherKey() string {
+func (x *RedeemItem) GetInviteeSign() string {
 	if x != nil {
-		return x.CipherKey
+		return x.InviteeSign
 	}
 	return ""
 }
 
-func (x *GroupItemV0) GetAppKey() string {
+func (x *RedeemItem) GetTimeStamp() int64 {
 	if x != nil {
-		return x.AppKey
+		return x.TimeStamp
 	}
-	return ""
+	return 0
 }
 
-type PSPing struct {
+// invitation passed to the joiner, wraps the seed
+type InviteToken struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Seqnum    int32  `protobuf:"varint,1,opt,name=Seqnum,proto3" json:"Seqnum,omitempty"`
-	IsResp    bool   `protobuf:"varint,2,opt,name=IsResp,proto3" json:"IsResp,omitempty"`
-	TimeStamp int64  `protobuf:"varint,3,opt,name=TimeStamp,proto3" json:"TimeStamp,omitempty"`
-	Payload   []byte `protobuf:"bytes,4,opt,name=Payload,proto3" json:"Payload,omitempty"`
+	Invite *InviteItem `protobuf:"bytes,1,opt,name=Invite,proto3" json:"Invite,omitempty"`
+	Seed   *GroupSeed  `protobuf:"bytes,2,opt,name=Seed,proto3" json:"Seed,omitempty"`
 }
 
-func (x *PSPing) Reset() {
-	*x = PSPing{}
+func (x *InviteToken) Reset() {
+	*x = InviteToken{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[15]
+		mi := &file_chain_proto_msgTypes[32]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *PSPing) String() string {
+func (x *InviteToken) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PSPing) ProtoMessage() {}
+func (*InviteToken) ProtoMessage() {}
 
-func (x *PSPing) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[15]
+func (x *InviteToken) ProtoReflect() protoreflect.Message {
+	mi := &file_chain_proto_msgTypes[32]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1900,35 +3390,21 @@ func (x *PSPing) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PSPing.ProtoReflect.Descriptor instead.
-func (*PSPing) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{15}
-}
-
-func (x *PSPing) GetSeqnum() int32 {
-	if x != nil {
-		return x.Seqnum
-	}
-	return 0
-}
-
-func (x *PSPing) GetIsResp() bool {
-	if x != nil {
-		return x.IsResp
-	}
-	return false
+// Deprecated: Use InviteToken.ProtoReflect.Descriptor instead.
+func (*InviteToken) Descriptor() ([]byte, []int) {
+	return file_chain_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *PSPing) GetTimeStamp() int64 {
+func (x *InviteToken) GetInvite() *InviteItem {
 	if x != nil {
-		return x.TimeStamp
+		return x.Invite
 	}
-	return 0
+	return nil
 }
 
-func (x *PSPing) GetPayload() []byte {
+func (x *InviteToken) GetSeed() *GroupSeed {
 	if x != nil {
-		return x.Payload
+		return x.Seed
 	}
 	return nil
 }
@@ -1947,12 +3423,13 @@ type GroupSeed struct {
 	CipherKey      string `protobuf:"bytes,7,opt,name=CipherKey,proto3" json:"CipherKey,omitempty"`
 	AppKey         string `protobuf:"bytes,8,opt,name=AppKey,proto3" json:"AppKey,omitempty"`
 	Signature      string `protobuf:"bytes,9,opt,name=Signature,proto3" json:"Signature,omitempty"`
+	CipherAlg      string `protobuf:"bytes,10,opt,name=CipherAlg,proto3" json:"CipherAlg,omitempty"`
 }
 
 func (x *GroupSeed) Reset() {
 	*x = GroupSeed{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_chain_proto_msgTypes[16]
+		mi := &file_chain_proto_msgTypes[33]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -1965,7 +3442,7 @@ func (x *GroupSeed) String() string {
 func (*GroupSeed) ProtoMessage() {}
 
 func (x *GroupSeed) ProtoReflect() protoreflect.Message {
-	mi := &file_chain_proto_msgTypes[16]
+	mi := &file_chain_proto_msgTypes[33]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1978,7 +3455,7 @@ func (x *GroupSeed) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GroupSeed.ProtoReflect.Descriptor instead.
 func (*GroupSeed) Descriptor() ([]byte, []int) {
-	return file_chain_proto_rawDescGZIP(), []int{16}
+	return file_chain_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *GroupSeed) GetGenesisBlock() *Block {
@@ -2044,6 +3521,13 @@ func (x *GroupSeed) GetSignature() string {
 	return ""
 }
 
+func (x *GroupSeed) GetCipherAlg() string {
+	if x != nil {
+		return x.CipherAlg
+	}
+	return ""
+}
+
 var File_chain_proto protoreflect.FileDescriptor
 
 var file_chain_proto_rawDesc = []byte{
@@ -2053,7 +3537,7 @@ var file_chain_proto_rawDesc = []byte{
 	0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70,
 	0x62, 0x2e, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74,
 	0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x22, 0xc1, 0x02, 0x0a, 0x03, 0x54, 0x72, 0x78, 0x12,
+	0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x22, 0xf9, 0x02, 0x0a, 0x03, 0x54, 0x72, 0x78, 0x12,
 	0x14, 0x0a, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
 	0x54, 0x72, 0x78, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
 	0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70,
@@ -2073,281 +3557,498 @@ var file_chain_proto_rawDesc = []byte{
 	0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x53,
 	0x65, 0x6e, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x53,
 	0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0c, 0x52,
-	0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x22, 0x9f, 0x02, 0x0a, 0x05,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12,
-	0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x72, 0x65,
-	0x76, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
-	0x50, 0x72, 0x65, 0x76, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x50,
-	0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x48, 0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0c, 0x52, 0x0c, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x48, 0x61, 0x73, 0x68, 0x12,
-	0x24, 0x0a, 0x04, 0x54, 0x72, 0x78, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e,
-	0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x52,
-	0x04, 0x54, 0x72, 0x78, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65,
-	0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x50,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a,
-	0x04, 0x48, 0x61, 0x73, 0x68, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x48, 0x61, 0x73,
-	0x68, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12,
-	0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xb8, 0x01,
-	0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x62, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x18,
-	0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x49, 0x74, 0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68,
-	0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
-	0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x24, 0x0a, 0x0d, 0x50, 0x61,
-	0x72, 0x65, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0d, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64,
-	0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x75, 0x62, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x04,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x53, 0x75, 0x62, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64,
-	0x12, 0x16, 0x0a, 0x06, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03,
-	0x52, 0x06, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x22, 0x56, 0x0a, 0x08, 0x52, 0x65, 0x71, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x18,
-	0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x55, 0x73, 0x65, 0x72,
-	0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64,
-	0x22, 0x3f, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x79, 0x6e, 0x63, 0x65, 0x64, 0x12,
-	0x30, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62,
-	0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65,
-	0x6d, 0x22, 0x41, 0x0a, 0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63,
-	0x65, 0x64, 0x12, 0x30, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74,
-	0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
-	0x49, 0x74, 0x65, 0x6d, 0x22, 0xdd, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x52, 0x65, 0x73, 0x70, 0x12, 0x31, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74,
-	0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
-	0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x76,
-	0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
-	0x12, 0x28, 0x0a, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x14,
-	0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x42,
-	0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x82, 0x01, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x74, 0x49, 0x74, 0x65,
-	0x6d, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69,
-	0x73, 0x68, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
-	0x79, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x0c, 0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x54,
-	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
-	0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xde, 0x01, 0x0a, 0x0c, 0x44, 0x65,
-	0x6e, 0x79, 0x55, 0x73, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x10,
+	0x0a, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x36, 0x0a, 0x0a, 0x41,
+	0x64, 0x6d, 0x69, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x52, 0x0a, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x53, 0x69,
+	0x67, 0x6e, 0x73, 0x22, 0xb8, 0x03, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a,
+	0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x64, 0x12, 0x20, 0x0a, 0x0b, 0x50, 0x72, 0x65, 0x76, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x50, 0x72, 0x65, 0x76, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0c, 0x50, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x48,
+	0x61, 0x73, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0c, 0x50, 0x72, 0x65, 0x76, 0x69,
+	0x6f, 0x75, 0x73, 0x48, 0x61, 0x73, 0x68, 0x12, 0x24, 0x0a, 0x04, 0x54, 0x72, 0x78, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74,
+	0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x52, 0x04, 0x54, 0x72, 0x78, 0x73, 0x12, 0x26, 0x0a,
+	0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50,
+	0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x48, 0x61, 0x73, 0x68, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x48, 0x61, 0x73, 0x68, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x53, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65,
+	0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2d, 0x0a, 0x06, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74,
+	0x2e, 0x70, 0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x53, 0x65, 0x74, 0x52, 0x06, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x73, 0x12, 0x30, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x0b,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e,
+	0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x18, 0x0a, 0x07, 0x54, 0x72, 0x78, 0x52, 0x6f, 0x6f,
+	0x74, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x54, 0x72, 0x78, 0x52, 0x6f, 0x6f, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x54, 0x72, 0x78, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x18, 0x0d, 0x20,
+	0x03, 0x28, 0x0c, 0x52, 0x09, 0x54, 0x72, 0x78, 0x48, 0x61, 0x73, 0x68, 0x65, 0x73, 0x22, 0xd0,
+	0x01, 0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x62, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12,
+	0x18, 0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x30, 0x0a, 0x09, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63,
+	0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x52, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x24, 0x0a, 0x0d, 0x50,
+	0x61, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x50, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
+	0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x75, 0x62, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x53, 0x75, 0x62, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x06, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x72, 0x75,
+	0x6e, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x50, 0x72, 0x75, 0x6e, 0x65,
+	0x64, 0x22, 0x72, 0x0a, 0x08, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a,
+	0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x64, 0x12, 0x16, 0x0a, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x05, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x4a,
+	0x04, 0x08, 0x05, 0x10, 0x06, 0x22, 0x3f, 0x0a, 0x0b, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x53, 0x79,
+	0x6e, 0x63, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65,
+	0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e,
+	0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x09, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x22, 0x41, 0x0a, 0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x64, 0x12, 0x30, 0x0a, 0x09, 0x42, 0x6c, 0x6f, 0x63, 0x6b,
+	0x49, 0x74, 0x65, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68, 0x65,
+	0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x09,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x74, 0x65, 0x6d, 0x22, 0x6a, 0x0a, 0x06, 0x52, 0x65, 0x71,
+	0x54, 0x72, 0x78, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64, 0x12, 0x16, 0x0a,
+	0x06, 0x55, 0x73, 0x65, 0x72, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x55,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0xba, 0x01, 0x0a, 0x0a, 0x52, 0x65, 0x71, 0x54, 0x72, 0x78,
+	0x52, 0x65, 0x73, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x78, 0x49,
+	0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64, 0x12, 0x26,
+	0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x12, 0x10, 0x0a, 0x03, 0x54, 0x72, 0x78, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x03, 0x54,
+	0x72, 0x78, 0x22, 0x61, 0x0a, 0x0d, 0x4c, 0x69, 0x67, 0x68, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x49,
+	0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x18, 0x0a,
+	0x07, 0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07,
+	0x53, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65,
+	0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xdd, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x12, 0x31, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75,
+	0x74, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f,
+	0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x12, 0x28, 0x0a, 0x0f, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75,
+	0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12,
+	0x14, 0x0a, 0x05, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x22, 0x82, 0x01, 0x0a, 0x08, 0x50, 0x6f, 0x73, 0x74, 0x49, 0x74,
+	0x65, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x54, 0x72, 0x78, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x50, 0x75, 0x62, 0x6c,
+	0x69, 0x73, 0x68, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0f, 0x50, 0x75, 0x62, 0x6c, 0x69, 0x73, 0x68, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x07, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x1c, 0x0a, 0x09,
+	0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xa5, 0x01, 0x0a, 0x0d, 0x52,
+	0x65, 0x74, 0x65, 0x6e, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07,
+	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x4b, 0x65, 0x65, 0x70, 0x44, 0x61,
+	0x79, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x4b, 0x65, 0x65, 0x70, 0x44, 0x61,
+	0x79, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x4b, 0x65, 0x65, 0x70, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x4b, 0x65, 0x65, 0x70, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x4f, 0x6e, 0x6c,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73,
+	0x4f, 0x6e, 0x6c, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d,
+	0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61,
+	0x6d, 0x70, 0x22, 0xbf, 0x01, 0x0a, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x22, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x54, 0x69, 0x6d,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0c, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x1e, 0x0a, 0x0a, 0x4d, 0x65, 0x72, 0x67, 0x65, 0x54,
+	0x69, 0x6d, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x4d, 0x65, 0x72, 0x67,
+	0x65, 0x54, 0x69, 0x6d, 0x65, 0x72, 0x12, 0x24, 0x0a, 0x0d, 0x54, 0x72, 0x78, 0x73, 0x54, 0x6f,
+	0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x54,
+	0x72, 0x78, 0x73, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x28, 0x0a, 0x0f,
+	0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x69, 0x7a, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x4f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x69, 0x7a,
+	0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x72, 0x78, 0x45, 0x78, 0x70,
+	0x69, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x72, 0x78, 0x45, 0x78,
+	0x70, 0x69, 0x72, 0x65, 0x22, 0x88, 0x02, 0x0a, 0x0a, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x49,
+	0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x30, 0x0a,
+	0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12,
+	0x28, 0x0a, 0x0f, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x48, 0x65, 0x69, 0x67,
+	0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74,
+	0x69, 0x76, 0x65, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x2a, 0x0a, 0x10, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77,
+	0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d,
+	0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22,
+	0xbd, 0x01, 0x0a, 0x0d, 0x48, 0x65, 0x61, 0x72, 0x74, 0x62, 0x65, 0x61, 0x74, 0x49, 0x74, 0x65,
+	0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x50,
+	0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x48, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x48, 0x69, 0x67, 0x68,
+	0x65, 0x73, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x48, 0x69, 0x67,
+	0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49,
+	0x64, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22,
+	0x76, 0x0a, 0x12, 0x44, 0x65, 0x6d, 0x6f, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x6f, 0x6c, 0x69, 0x63,
+	0x79, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12,
+	0x28, 0x0a, 0x0f, 0x4d, 0x61, 0x78, 0x4d, 0x69, 0x73, 0x73, 0x65, 0x64, 0x52, 0x6f, 0x75, 0x6e,
+	0x64, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0f, 0x4d, 0x61, 0x78, 0x4d, 0x69, 0x73,
+	0x73, 0x65, 0x64, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d,
+	0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0xa2, 0x01, 0x0a, 0x0c, 0x42, 0x6c, 0x6f, 0x62,
+	0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x42, 0x6c, 0x6f, 0x62,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x42, 0x6c, 0x6f, 0x62, 0x49, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x7a, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x54, 0x79, 0x70,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x4d, 0x65, 0x64, 0x69, 0x61, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x12, 0x1c,
+	0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x79, 0x0a, 0x07,
+	0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x12, 0x2c, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74,
+	0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12,
+	0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12,
+	0x16, 0x0a, 0x06, 0x42, 0x6c, 0x6f, 0x62, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x42, 0x6c, 0x6f, 0x62, 0x49, 0x64, 0x22, 0xa9, 0x01, 0x0a, 0x08, 0x42, 0x6c, 0x6f, 0x62,
+	0x52, 0x65, 0x73, 0x70, 0x12, 0x2c, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62,
+	0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02,
+	0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x35, 0x0a, 0x08, 0x4d, 0x61, 0x6e, 0x69,
+	0x66, 0x65, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x65,
+	0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x62, 0x4d, 0x61, 0x6e,
+	0x69, 0x66, 0x65, 0x73, 0x74, 0x52, 0x08, 0x4d, 0x61, 0x6e, 0x69, 0x66, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x44,
+	0x61, 0x74, 0x61, 0x22, 0xde, 0x01, 0x0a, 0x0c, 0x44, 0x65, 0x6e, 0x79, 0x55, 0x73, 0x65, 0x72,
+	0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f,
+	0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x53, 0x69, 0x67, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54,
+	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x4d, 0x65, 0x6d, 0x6f, 0x22, 0xad, 0x02, 0x0a, 0x0c, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65,
+	0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12,
+	0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65,
+	0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65,
+	0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x54,
+	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2f, 0x0a, 0x06, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x65, 0x73,
+	0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x4d, 0x65, 0x6d, 0x6f, 0x22, 0xac, 0x03, 0x0a, 0x0c, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63,
+	0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12,
+	0x1e, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12,
+	0x24, 0x0a, 0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2e, 0x0a, 0x12, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63,
+	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x12, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70,
+	0x62, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x30,
+	0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x18,
+	0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x70, 0x70,
+	0x72, 0x6f, 0x76, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x09, 0x20,
+	0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2f,
+	0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17,
+	0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d,
+	0x65, 0x6d, 0x6f, 0x22, 0xf1, 0x01, 0x0a, 0x0a, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x49, 0x74,
+	0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x10,
 	0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e,
 	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
 	0x52, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e,
-	0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20,
-	0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x16,
-	0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0xad, 0x02, 0x0a, 0x0c, 0x50,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65,
-	0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x50,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a,
-	0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
-	0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67,
-	0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12,
-	0x2f, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32,
-	0x17, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x24, 0x0a, 0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x65,
-	0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x50, 0x72,
-	0x6f, 0x64, 0x75, 0x63, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0xac, 0x03, 0x0a, 0x0c, 0x41,
-	0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x53, 0x69, 0x67, 0x6e, 0x50,
-	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
-	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x45, 0x6e,
-	0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2e, 0x0a, 0x12, 0x41,
-	0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63,
-	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x2d, 0x0a, 0x04, 0x54,
-	0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x63, 0x68, 0x65, 0x73,
-	0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77,
-	0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e,
-	0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x07,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x61,
-	0x74, 0x75, 0x72, 0x65, 0x12, 0x30, 0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x18, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e,
-	0x70, 0x62, 0x2e, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06,
-	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74,
-	0x61, 0x6d, 0x70, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53,
-	0x74, 0x61, 0x6d, 0x70, 0x12, 0x2f, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0a,
-	0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e,
-	0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x18, 0x0b, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x4d, 0x65, 0x6d, 0x6f, 0x22, 0xf1, 0x01, 0x0a, 0x0a, 0x53, 0x63,
-	0x68, 0x65, 0x6d, 0x61, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x49, 0x64, 0x12, 0x2a, 0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72,
-	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x26,
-	0x0a, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4f, 0x77, 0x6e,
-	0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x75,
-	0x6c, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1c,
-	0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x12, 0x12, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x52, 0x75, 0x6c, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x52, 0x75, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65,
+	0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d,
+	0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2f, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75,
+	0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xac, 0x06, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75,
+	0x70, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12,
+	0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a,
+	0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12,
+	0x26, 0x0a, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67,
+	0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45,
+	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64,
+	0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74,
+	0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x48, 0x69,
+	0x67, 0x68, 0x65, 0x73, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x48,
+	0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63,
+	0x6b, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68, 0x65, 0x73,
+	0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x47,
+	0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x3f, 0x0a, 0x0b, 0x45,
+	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x1d, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52,
+	0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x42, 0x0a, 0x0c,
+	0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62,
+	0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x18, 0x0c, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x16,
+	0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
+	0x41, 0x6c, 0x67, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x41, 0x6c, 0x67, 0x12, 0x2d, 0x0a, 0x06, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x73, 0x18, 0x0f,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e,
+	0x70, 0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x53, 0x65, 0x74, 0x52, 0x06, 0x41, 0x64, 0x6d,
+	0x69, 0x6e, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79,
+	0x45, 0x70, 0x6f, 0x63, 0x68, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x43, 0x69, 0x70,
+	0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x12, 0x55, 0x0a, 0x0f, 0x45,
+	0x70, 0x6f, 0x63, 0x68, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x73, 0x18, 0x11,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e,
+	0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x74, 0x65, 0x6d, 0x2e, 0x45, 0x70, 0x6f,
+	0x63, 0x68, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x52, 0x0f, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65,
+	0x79, 0x73, 0x1a, 0x42, 0x0a, 0x14, 0x45, 0x70, 0x6f, 0x63, 0x68, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x4b, 0x65, 0x79, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0xcf, 0x04, 0x0a, 0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70,
+	0x49, 0x74, 0x65, 0x6d, 0x56, 0x30, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
+	0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20,
+	0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79,
+	0x12, 0x26, 0x0a, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69,
+	0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x11, 0x55, 0x73, 0x65, 0x72,
+	0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
+	0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x08, 0x55, 0x73, 0x65, 0x72, 0x52, 0x6f,
+	0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74,
+	0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x6f, 0x6c, 0x65, 0x56, 0x30, 0x52, 0x08, 0x55,
+	0x73, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x55,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x4c, 0x61, 0x73,
+	0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x48, 0x69, 0x67, 0x68, 0x65,
+	0x73, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0d,
+	0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x26, 0x0a,
+	0x0e, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c,
+	0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73,
+	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68,
+	0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52,
+	0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x3f, 0x0a,
+	0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62,
+	0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x42,
+	0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e,
+	0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x22, 0x70, 0x0a, 0x06, 0x50, 0x53, 0x50, 0x69,
+	0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x71, 0x6e, 0x75, 0x6d, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x06, 0x53, 0x65, 0x71, 0x6e, 0x75, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x49, 0x73,
+	0x52, 0x65, 0x73, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x49, 0x73, 0x52, 0x65,
+	0x73, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70,
+	0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x42, 0x0a, 0x08, 0x41, 0x64,
+	0x6d, 0x69, 0x6e, 0x53, 0x65, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x73,
+	0x12, 0x1c, 0x0a, 0x09, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x09, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x22, 0x37,
+	0x0a, 0x09, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x50,
+	0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x75, 0x62,
+	0x6b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x04, 0x53, 0x69, 0x67, 0x6e, 0x22, 0xe9, 0x01, 0x0a, 0x0b, 0x41, 0x64, 0x6d, 0x69,
+	0x6e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x41, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x28, 0x0a,
+	0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x63, 0x68,
+	0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x54, 0x72, 0x78, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x44, 0x61, 0x74, 0x61, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x44, 0x61, 0x74, 0x61, 0x12, 0x2c, 0x0a, 0x05, 0x53,
+	0x69, 0x67, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x68, 0x65,
+	0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x53, 0x69,
+	0x67, 0x6e, 0x52, 0x05, 0x53, 0x69, 0x67, 0x6e, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x50, 0x72, 0x6f,
+	0x70, 0x6f, 0x73, 0x65, 0x72, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x50, 0x72, 0x6f,
+	0x70, 0x6f, 0x73, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61,
+	0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74,
+	0x61, 0x6d, 0x70, 0x22, 0xd5, 0x02, 0x0a, 0x0a, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x49, 0x74,
+	0x65, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x49, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x49, 0x64, 0x12, 0x18,
+	0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x45, 0x78, 0x70, 0x69,
+	0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x45, 0x78, 0x70, 0x69, 0x72, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x4d, 0x61, 0x78, 0x55, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x4d, 0x61, 0x78, 0x55, 0x73, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x49, 0x6e, 0x76, 0x69,
+	0x74, 0x65, 0x65, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x65, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x20,
+	0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
+	0x12, 0x1c, 0x0a, 0x09, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x07, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x1c,
+	0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x08, 0x20, 0x01, 0x28,
 	0x03, 0x52, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x2f, 0x0a, 0x06,
-	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63,
+	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x63,
 	0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f,
-	0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x9c, 0x04,
-	0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x18, 0x0a, 0x07, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61,
-	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e,
-	0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x4b,
-	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50,
-	0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67,
-	0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x55,
-	0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c, 0x0a,
-	0x11, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b,
-	0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6e,
-	0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x1e, 0x0a, 0x0a, 0x4c,
-	0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x0a, 0x4c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x0d, 0x48,
-	0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x0d, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68,
-	0x74, 0x12, 0x26, 0x0a, 0x0e, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x49, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x48, 0x69, 0x67, 0x68, 0x65,
-	0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x0c, 0x47, 0x65, 0x6e,
-	0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x12, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63,
-	0x6b, 0x12, 0x3f, 0x0a, 0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65,
-	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75,
-	0x74, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70,
-	0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x42, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79,
-	0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74,
-	0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6e, 0x73,
-	0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e,
-	0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
-	0x4b, 0x65, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65,
-	0x72, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x18, 0x0d,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x22, 0xcf, 0x04, 0x0a,
-	0x0b, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x74, 0x65, 0x6d, 0x56, 0x30, 0x12, 0x18, 0x0a, 0x07,
-	0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47,
-	0x72, 0x6f, 0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e,
-	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70,
-	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62,
-	0x4b, 0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72,
-	0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x26, 0x0a, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x53, 0x69,
-	0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
-	0x55, 0x73, 0x65, 0x72, 0x53, 0x69, 0x67, 0x6e, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2c,
-	0x0a, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62,
-	0x6b, 0x65, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x55, 0x73, 0x65, 0x72, 0x45,
-	0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x08,
-	0x55, 0x73, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13,
-	0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x52, 0x6f, 0x6c,
-	0x65, 0x56, 0x30, 0x52, 0x08, 0x55, 0x73, 0x65, 0x72, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1e, 0x0a,
-	0x0a, 0x4c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x0a, 0x4c, 0x61, 0x73, 0x74, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x12, 0x24, 0x0a,
-	0x0d, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x03, 0x52, 0x0d, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x48, 0x65, 0x69,
-	0x67, 0x68, 0x74, 0x12, 0x26, 0x0a, 0x0e, 0x48, 0x69, 0x67, 0x68, 0x65, 0x73, 0x74, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x49, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x48, 0x69, 0x67,
-	0x68, 0x65, 0x73, 0x74, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x0c, 0x47,
-	0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x0a, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e,
-	0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c,
-	0x6f, 0x63, 0x6b, 0x12, 0x3f, 0x0a, 0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79,
-	0x70, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1d, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74,
-	0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x45, 0x6e, 0x63, 0x72,
-	0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0b, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x42, 0x0a, 0x0c, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65,
-	0x54, 0x79, 0x70, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x63, 0x68, 0x65,
-	0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f,
-	0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x52, 0x0c, 0x43, 0x6f, 0x6e, 0x73,
-	0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68,
-	0x65, 0x72, 0x4b, 0x65, 0x79, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70,
-	0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79,
-	0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x22, 0x70,
-	0x0a, 0x06, 0x50, 0x53, 0x50, 0x69, 0x6e, 0x67, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x71, 0x6e,
-	0x75, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x53, 0x65, 0x71, 0x6e, 0x75, 0x6d,
-	0x12, 0x16, 0x0a, 0x06, 0x49, 0x73, 0x52, 0x65, 0x73, 0x70, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08,
-	0x52, 0x06, 0x49, 0x73, 0x52, 0x65, 0x73, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65,
-	0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54, 0x69, 0x6d,
-	0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61,
-	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x50, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
-	0x22, 0xbf, 0x02, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x65, 0x65, 0x64, 0x12, 0x36,
-	0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75, 0x74, 0x2e,
-	0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69,
-	0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
+	0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x55, 0x73, 0x65, 0x64, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x55, 0x73, 0x65,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x07, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x64, 0x22, 0xaa, 0x01, 0x0a, 0x0a,
+	0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x49, 0x74, 0x65, 0x6d, 0x12, 0x1a, 0x0a, 0x08, 0x49, 0x6e,
+	0x76, 0x69, 0x74, 0x65, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x49, 0x6e,
+	0x76, 0x69, 0x74, 0x65, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49,
 	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x49, 0x64,
-	0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20,
-	0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79,
-	0x12, 0x24, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x54, 0x79, 0x70,
-	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73,
-	0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
-	0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c,
-	0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x12, 0x16, 0x0a, 0x06,
-	0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x41, 0x70,
-	0x70, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
-	0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
-	0x72, 0x65, 0x2a, 0x21, 0x0a, 0x0b, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x52, 0x58, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x4c,
-	0x4f, 0x43, 0x4b, 0x10, 0x01, 0x2a, 0xae, 0x01, 0x0a, 0x07, 0x54, 0x72, 0x78, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x4f, 0x53, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x41,
-	0x55, 0x54, 0x48, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x43, 0x48, 0x45, 0x4d, 0x41, 0x10,
-	0x02, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x52, 0x4f, 0x44, 0x55, 0x43, 0x45, 0x52, 0x10, 0x03, 0x12,
-	0x0c, 0x0a, 0x08, 0x41, 0x4e, 0x4e, 0x4f, 0x55, 0x4e, 0x43, 0x45, 0x10, 0x04, 0x12, 0x15, 0x0a,
-	0x11, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x46, 0x4f, 0x52, 0x57, 0x41,
-	0x52, 0x44, 0x10, 0x05, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43,
-	0x4b, 0x5f, 0x42, 0x41, 0x43, 0x4b, 0x57, 0x41, 0x52, 0x44, 0x10, 0x06, 0x12, 0x12, 0x0a, 0x0e,
-	0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x10, 0x07,
-	0x12, 0x10, 0x0a, 0x0c, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x53, 0x59, 0x4e, 0x43, 0x45, 0x44,
-	0x10, 0x08, 0x12, 0x12, 0x0a, 0x0e, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x50, 0x52, 0x4f, 0x44,
-	0x55, 0x43, 0x45, 0x44, 0x10, 0x09, 0x2a, 0x2c, 0x0a, 0x0c, 0x41, 0x6e, 0x6e, 0x6f, 0x75, 0x6e,
-	0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x53, 0x5f, 0x55, 0x53, 0x45,
-	0x52, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x53, 0x5f, 0x50, 0x52, 0x4f, 0x44, 0x55, 0x43,
-	0x45, 0x52, 0x10, 0x01, 0x2a, 0x38, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x54,
-	0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x4e, 0x4e, 0x4f, 0x55, 0x4e, 0x43, 0x45, 0x44,
-	0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x50, 0x50, 0x52, 0x4f, 0x56, 0x45, 0x44, 0x10, 0x01,
-	0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x02, 0x2a, 0x21,
-	0x0a, 0x0a, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03,
-	0x41, 0x44, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x10,
-	0x01, 0x2a, 0x35, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6b, 0x52, 0x65, 0x73, 0x75, 0x6c,
-	0x74, 0x12, 0x10, 0x0a, 0x0c, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x49, 0x4e, 0x5f, 0x54, 0x52,
-	0x58, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x4e, 0x4f, 0x54,
-	0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x01, 0x2a, 0x2b, 0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75,
-	0x70, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06,
-	0x50, 0x55, 0x42, 0x4c, 0x49, 0x43, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52, 0x49, 0x56,
-	0x41, 0x54, 0x45, 0x10, 0x01, 0x2a, 0x25, 0x0a, 0x11, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f,
-	0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4f,
-	0x41, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4f, 0x53, 0x10, 0x01, 0x2a, 0x2c, 0x0a, 0x06,
-	0x52, 0x6f, 0x6c, 0x65, 0x56, 0x30, 0x12, 0x12, 0x0a, 0x0e, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f,
-	0x50, 0x52, 0x4f, 0x44, 0x55, 0x43, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x47, 0x52,
-	0x4f, 0x55, 0x50, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x10, 0x01, 0x42, 0x05, 0x5a, 0x03, 0x2f, 0x70,
-	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x26, 0x0a, 0x0e, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b,
+	0x65, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x52, 0x65, 0x64, 0x65, 0x65, 0x6d,
+	0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x49, 0x6e, 0x76, 0x69,
+	0x74, 0x65, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x49,
+	0x6e, 0x76, 0x69, 0x74, 0x65, 0x65, 0x53, 0x69, 0x67, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x69,
+	0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x54,
+	0x69, 0x6d, 0x65, 0x53, 0x74, 0x61, 0x6d, 0x70, 0x22, 0x6a, 0x0a, 0x0b, 0x49, 0x6e, 0x76, 0x69,
+	0x74, 0x65, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x2f, 0x0a, 0x06, 0x49, 0x6e, 0x76, 0x69, 0x74,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e,
+	0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x49, 0x74, 0x65, 0x6d,
+	0x52, 0x06, 0x49, 0x6e, 0x76, 0x69, 0x74, 0x65, 0x12, 0x2a, 0x0a, 0x04, 0x53, 0x65, 0x65, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74, 0x6e, 0x75,
+	0x74, 0x2e, 0x70, 0x62, 0x2e, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x65, 0x65, 0x64, 0x52, 0x04,
+	0x53, 0x65, 0x65, 0x64, 0x22, 0xdd, 0x02, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x53, 0x65,
+	0x65, 0x64, 0x12, 0x36, 0x0a, 0x0c, 0x47, 0x65, 0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f,
+	0x63, 0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63, 0x68, 0x65, 0x73, 0x74,
+	0x6e, 0x75, 0x74, 0x2e, 0x70, 0x62, 0x2e, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x52, 0x0c, 0x47, 0x65,
+	0x6e, 0x65, 0x73, 0x69, 0x73, 0x42, 0x6c, 0x6f, 0x63, 0x6b, 0x12, 0x18, 0x0a, 0x07, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x49, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x47, 0x72, 0x6f,
+	0x75, 0x70, 0x49, 0x64, 0x12, 0x1c, 0x0a, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75, 0x62, 0x6b, 0x65,
+	0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x4f, 0x77, 0x6e, 0x65, 0x72, 0x50, 0x75,
+	0x62, 0x6b, 0x65, 0x79, 0x12, 0x24, 0x0a, 0x0d, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x75,
+	0x73, 0x54, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x43, 0x6f, 0x6e,
+	0x73, 0x65, 0x6e, 0x73, 0x75, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x45, 0x6e,
+	0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0e, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72, 0x4b, 0x65, 0x79,
+	0x12, 0x16, 0x0a, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x41, 0x70, 0x70, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x53, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x53, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65, 0x72,
+	0x41, 0x6c, 0x67, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x43, 0x69, 0x70, 0x68, 0x65,
+	0x72, 0x41, 0x6c, 0x67, 0x2a, 0x21, 0x0a, 0x0b, 0x50, 0x61, 0x63, 0x6b, 0x61, 0x67, 0x65, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x54, 0x52, 0x58, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05,
+	0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x10, 0x01, 0x2a, 0x80, 0x02, 0x0a, 0x07, 0x54, 0x72, 0x78, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x50, 0x4f, 0x53, 0x54, 0x10, 0x00, 0x12, 0x08, 0x0a,
+	0x04, 0x41, 0x55, 0x54, 0x48, 0x10, 0x01, 0x12, 0x0a, 0x0a, 0x06, 0x53, 0x43, 0x48, 0x45, 0x4d,
+	0x41, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x52, 0x4f, 0x44, 0x55, 0x43, 0x45, 0x52, 0x10,
+	0x03, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x4e, 0x4e, 0x4f, 0x55, 0x4e, 0x43, 0x45, 0x10, 0x04, 0x12,
+	0x15, 0x0a, 0x11, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x46, 0x4f, 0x52,
+	0x57, 0x41, 0x52, 0x44, 0x10, 0x05, 0x12, 0x16, 0x0a, 0x12, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c,
+	0x4f, 0x43, 0x4b, 0x5f, 0x42, 0x41, 0x43, 0x4b, 0x57, 0x41, 0x52, 0x44, 0x10, 0x06, 0x12, 0x12,
+	0x0a, 0x0e, 0x52, 0x45, 0x51, 0x5f, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x52, 0x45, 0x53, 0x50,
+	0x10, 0x07, 0x12, 0x10, 0x0a, 0x0c, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x53, 0x59, 0x4e, 0x43,
+	0x45, 0x44, 0x10, 0x08, 0x12, 0x12, 0x0a, 0x0e, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x50, 0x52,
+	0x4f, 0x44, 0x55, 0x43, 0x45, 0x44, 0x10, 0x09, 0x12, 0x0a, 0x0a, 0x06, 0x49, 0x4e, 0x56, 0x49,
+	0x54, 0x45, 0x10, 0x0a, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x44, 0x45, 0x45, 0x4d, 0x10, 0x0b,
+	0x12, 0x0d, 0x0a, 0x09, 0x48, 0x45, 0x41, 0x52, 0x54, 0x42, 0x45, 0x41, 0x54, 0x10, 0x0c, 0x12,
+	0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x4e, 0x46, 0x49, 0x47, 0x10, 0x0d, 0x12, 0x0b, 0x0a, 0x07, 0x52,
+	0x45, 0x51, 0x5f, 0x54, 0x52, 0x58, 0x10, 0x0e, 0x12, 0x10, 0x0a, 0x0c, 0x52, 0x45, 0x51, 0x5f,
+	0x54, 0x52, 0x58, 0x5f, 0x52, 0x45, 0x53, 0x50, 0x10, 0x0f, 0x2a, 0x2c, 0x0a, 0x0c, 0x41, 0x6e,
+	0x6e, 0x6f, 0x75, 0x6e, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x53,
+	0x5f, 0x55, 0x53, 0x45, 0x52, 0x10, 0x00, 0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x53, 0x5f, 0x50, 0x52,
+	0x4f, 0x44, 0x55, 0x43, 0x45, 0x52, 0x10, 0x01, 0x2a, 0x38, 0x0a, 0x0b, 0x41, 0x70, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0d, 0x0a, 0x09, 0x41, 0x4e, 0x4e, 0x4f, 0x55,
+	0x4e, 0x43, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x50, 0x50, 0x52, 0x4f, 0x56,
+	0x45, 0x44, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x4a, 0x45, 0x43, 0x54, 0x45, 0x44,
+	0x10, 0x02, 0x2a, 0x21, 0x0a, 0x0a, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x07, 0x0a, 0x03, 0x41, 0x44, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4d,
+	0x4f, 0x56, 0x45, 0x10, 0x01, 0x2a, 0x35, 0x0a, 0x0c, 0x52, 0x65, 0x71, 0x42, 0x6c, 0x6b, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x10, 0x0a, 0x0c, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x5f, 0x49,
+	0x4e, 0x5f, 0x54, 0x52, 0x58, 0x10, 0x00, 0x12, 0x13, 0x0a, 0x0f, 0x42, 0x4c, 0x4f, 0x43, 0x4b,
+	0x5f, 0x4e, 0x4f, 0x54, 0x5f, 0x46, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x01, 0x2a, 0x30, 0x0a, 0x0b,
+	0x42, 0x6c, 0x6f, 0x62, 0x52, 0x65, 0x71, 0x54, 0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x42,
+	0x4c, 0x4f, 0x42, 0x5f, 0x4d, 0x41, 0x4e, 0x49, 0x46, 0x45, 0x53, 0x54, 0x10, 0x00, 0x12, 0x0e,
+	0x0a, 0x0a, 0x42, 0x4c, 0x4f, 0x42, 0x5f, 0x43, 0x48, 0x55, 0x4e, 0x4b, 0x10, 0x01, 0x2a, 0x2b,
+	0x0a, 0x10, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x54, 0x79,
+	0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x55, 0x42, 0x4c, 0x49, 0x43, 0x10, 0x00, 0x12, 0x0b,
+	0x0a, 0x07, 0x50, 0x52, 0x49, 0x56, 0x41, 0x54, 0x45, 0x10, 0x01, 0x2a, 0x25, 0x0a, 0x11, 0x47,
+	0x72, 0x6f, 0x75, 0x70, 0x43, 0x6f, 0x6e, 0x73, 0x65, 0x6e, 0x73, 0x65, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x07, 0x0a, 0x03, 0x50, 0x4f, 0x41, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x50, 0x4f, 0x53,
+	0x10, 0x01, 0x2a, 0x2c, 0x0a, 0x06, 0x52, 0x6f, 0x6c, 0x65, 0x56, 0x30, 0x12, 0x12, 0x0a, 0x0e,
+	0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x50, 0x52, 0x4f, 0x44, 0x55, 0x43, 0x45, 0x52, 0x10, 0x00,
+	0x12, 0x0e, 0x0a, 0x0a, 0x47, 0x52, 0x4f, 0x55, 0x50, 0x5f, 0x55, 0x53, 0x45, 0x52, 0x10, 0x01,
+	0x42, 0x05, 0x5a, 0x03, 0x2f, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2362,62 +4063,95 @@ func file_chain_proto_rawDescGZIP() []byte {
 	return file_chain_proto_rawDescData
 }
 
-var file_chain_proto_enumTypes = make([]protoimpl.EnumInfo, 9)
-var file_chain_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_chain_proto_enumTypes = make([]protoimpl.EnumInfo, 10)
+var file_chain_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
 var file_chain_proto_goTypes = []interface{}{
-	(PackageType)(0),       // 0: chestnut.pb.PackageType
-	(TrxType)(0),           // 1: chestnut.pb.TrxType
-	(AnnounceType)(0),      // 2: chestnut.pb.AnnounceType
-	(ApproveType)(0),       // 3: chestnut.pb.ApproveType
-	(ActionType)(0),        // 4: chestnut.pb.ActionType
-	(ReqBlkResult)(0),      // 5: chestnut.pb.ReqBlkResult
-	(GroupEncryptType)(0),  // 6: chestnut.pb.GroupEncryptType
-	(GroupConsenseType)(0), // 7: chestnut.pb.GroupConsenseType
-	(RoleV0)(0),            // 8: chestnut.pb.RoleV0
-	(*Package)(nil),        // 9: chestnut.pb.Package
-	(*Trx)(nil),            // 10: chestnut.pb.Trx
-	(*Block)(nil),          // 11: chestnut.pb.Block
-	(*BlockDbChunk)(nil),   // 12: chestnut.pb.BlockDbChunk
-	(*ReqBlock)(nil),       // 13: chestnut.pb.ReqBlock
-	(*BlockSynced)(nil),    // 14: chestnut.pb.BlockSynced
-	(*BlockProduced)(nil),  // 15: chestnut.pb.BlockProduced
-	(*ReqBlockResp)(nil),   // 16: chestnut.pb.ReqBlockResp
-	(*PostItem)(nil),       // 17: chestnut.pb.PostItem
-	(*DenyUserItem)(nil),   // 18: chestnut.pb.DenyUserItem
-	(*ProducerItem)(nil),   // 19: chestnut.pb.ProducerItem
-	(*AnnounceItem)(nil),   // 20: chestnut.pb.AnnounceItem
-	(*SchemaItem)(nil),     // 21: chestnut.pb.SchemaItem
-	(*GroupItem)(nil),      // 22: chestnut.pb.GroupItem
-	(*GroupItemV0)(nil),    // 23: chestnut.pb.GroupItemV0
-	(*PSPing)(nil),         // 24: chestnut.pb.PSPing
-	(*GroupSeed)(nil),      // 25: chestnut.pb.GroupSeed
+	(PackageType)(0),           // 0: chestnut.pb.PackageType
+	(TrxType)(0),               // 1: chestnut.pb.TrxType
+	(AnnounceType)(0),          // 2: chestnut.pb.AnnounceType
+	(ApproveType)(0),           // 3: chestnut.pb.ApproveType
+	(ActionType)(0),            // 4: chestnut.pb.ActionType
+	(ReqBlkResult)(0),          // 5: chestnut.pb.ReqBlkResult
+	(BlobReqType)(0),           // 6: chestnut.pb.BlobReqType
+	(GroupEncryptType)(0),      // 7: chestnut.pb.GroupEncryptType
+	(GroupConsenseType)(0),     // 8: chestnut.pb.GroupConsenseType
+	(RoleV0)(0),                // 9: chestnut.pb.RoleV0
+	(*Package)(nil),            // 10: chestnut.pb.Package
+	(*Trx)(nil),                // 11: chestnut.pb.Trx
+	(*Block)(nil),              // 12: chestnut.pb.Block
+	(*BlockDbChunk)(nil),       // 13: chestnut.pb.BlockDbChunk
+	(*ReqBlock)(nil),           // 14: chestnut.pb.ReqBlock
+	(*BlockSynced)(nil),        // 15: chestnut.pb.BlockSynced
+	(*BlockProduced)(nil),      // 16: chestnut.pb.BlockProduced
+	(*ReqTrx)(nil),             // 17: chestnut.pb.ReqTrx
+	(*ReqTrxResp)(nil),         // 18: chestnut.pb.ReqTrxResp
+	(*LightModeItem)(nil),      // 19: chestnut.pb.LightModeItem
+	(*ReqBlockResp)(nil),       // 20: chestnut.pb.ReqBlockResp
+	(*PostItem)(nil),           // 21: chestnut.pb.PostItem
+	(*RetentionItem)(nil),      // 22: chestnut.pb.RetentionItem
+	(*GroupConfig)(nil),        // 23: chestnut.pb.GroupConfig
+	(*ConfigItem)(nil),         // 24: chestnut.pb.ConfigItem
+	(*HeartbeatItem)(nil),      // 25: chestnut.pb.HeartbeatItem
+	(*DemotionPolicyItem)(nil), // 26: chestnut.pb.DemotionPolicyItem
+	(*BlobManifest)(nil),       // 27: chestnut.pb.BlobManifest
+	(*BlobReq)(nil),            // 28: chestnut.pb.BlobReq
+	(*BlobResp)(nil),           // 29: chestnut.pb.BlobResp
+	(*DenyUserItem)(nil),       // 30: chestnut.pb.DenyUserItem
+	(*ProducerItem)(nil),       // 31: chestnut.pb.ProducerItem
+	(*AnnounceItem)(nil),       // 32: chestnut.pb.AnnounceItem
+	(*SchemaItem)(nil),         // 33: chestnut.pb.SchemaItem
+	(*GroupItem)(nil),          // 34: chestnut.pb.GroupItem
+	(*GroupItemV0)(nil),        // 35: chestnut.pb.GroupItemV0
+	(*PSPing)(nil),             // 36: chestnut.pb.PSPing
+	(*AdminSet)(nil),           // 37: chestnut.pb.AdminSet
+	(*AdminSign)(nil),          // 38: chestnut.pb.AdminSign
+	(*AdminAction)(nil),        // 39: chestnut.pb.AdminAction
+	(*InviteItem)(nil),         // 40: chestnut.pb.InviteItem
+	(*RedeemItem)(nil),         // 41: chestnut.pb.RedeemItem
+	(*InviteToken)(nil),        // 42: chestnut.pb.InviteToken
+	(*GroupSeed)(nil),          // 43: chestnut.pb.GroupSeed
+	nil,                        // 44: chestnut.pb.GroupItem.EpochCipherKeysEntry
 }
 var file_chain_proto_depIdxs = []int32{
 	0,  // 0: chestnut.pb.Package.type:type_name -> chestnut.pb.PackageType
 	1,  // 1: chestnut.pb.Trx.Type:type_name -> chestnut.pb.TrxType
-	10, // 2: chestnut.pb.Block.Trxs:type_name -> chestnut.pb.Trx
-	11, // 3: chestnut.pb.BlockDbChunk.BlockItem:type_name -> chestnut.pb.Block
-	11, // 4: chestnut.pb.BlockSynced.BlockItem:type_name -> chestnut.pb.Block
-	11, // 5: chestnut.pb.BlockProduced.BlockItem:type_name -> chestnut.pb.Block
-	5,  // 6: chestnut.pb.ReqBlockResp.Result:type_name -> chestnut.pb.ReqBlkResult
-	4,  // 7: chestnut.pb.ProducerItem.Action:type_name -> chestnut.pb.ActionType
-	2,  // 8: chestnut.pb.AnnounceItem.Type:type_name -> chestnut.pb.AnnounceType
-	3,  // 9: chestnut.pb.AnnounceItem.Result:type_name -> chestnut.pb.ApproveType
-	4,  // 10: chestnut.pb.AnnounceItem.Action:type_name -> chestnut.pb.ActionType
-	4,  // 11: chestnut.pb.SchemaItem.Action:type_name -> chestnut.pb.ActionType
-	11, // 12: chestnut.pb.GroupItem.GenesisBlock:type_name -> chestnut.pb.Block
-	6,  // 13: chestnut.pb.GroupItem.EncryptType:type_name -> chestnut.pb.GroupEncryptType
-	7,  // 14: chestnut.pb.GroupItem.ConsenseType:type_name -> chestnut.pb.GroupConsenseType
-	8,  // 15: chestnut.pb.GroupItemV0.UserRole:type_name -> chestnut.pb.RoleV0
-	11, // 16: chestnut.pb.GroupItemV0.GenesisBlock:type_name -> chestnut.pb.Block
-	6,  // 17: chestnut.pb.GroupItemV0.EncryptType:type_name -> chestnut.pb.GroupEncryptType
-	7,  // 18: chestnut.pb.GroupItemV0.ConsenseType:type_name -> chestnut.pb.GroupConsenseType
-	11, // 19: chestnut.pb.GroupSeed.GenesisBlock:type_name -> chestnut.pb.Block
-	20, // [20:20] is the sub-list for method output_type
-	20, // [20:20] is the sub-list for method input_type
-	20, // [20:20] is the sub-list for extension type_name
-	20, // [20:20] is the sub-list for extension extendee
-	0,  // [0:20] is the sub-list for field type_name
+	38, // 2: chestnut.pb.Trx.AdminSigns:type_name -> chestnut.pb.AdminSign
+	11, // 3: chestnut.pb.Block.Trxs:type_name -> chestnut.pb.Trx
+	37, // 4: chestnut.pb.Block.Admins:type_name -> chestnut.pb.AdminSet
+	23, // 5: chestnut.pb.Block.Config:type_name -> chestnut.pb.GroupConfig
+	12, // 6: chestnut.pb.BlockDbChunk.BlockItem:type_name -> chestnut.pb.Block
+	12, // 7: chestnut.pb.BlockSynced.BlockItem:type_name -> chestnut.pb.Block
+	12, // 8: chestnut.pb.BlockProduced.BlockItem:type_name -> chestnut.pb.Block
+	5,  // 9: chestnut.pb.ReqBlockResp.Result:type_name -> chestnut.pb.ReqBlkResult
+	23, // 10: chestnut.pb.ConfigItem.Config:type_name -> chestnut.pb.GroupConfig
+	6,  // 11: chestnut.pb.BlobReq.Type:type_name -> chestnut.pb.BlobReqType
+	6,  // 12: chestnut.pb.BlobResp.Type:type_name -> chestnut.pb.BlobReqType
+	27, // 13: chestnut.pb.BlobResp.Manifest:type_name -> chestnut.pb.BlobManifest
+	4,  // 14: chestnut.pb.ProducerItem.Action:type_name -> chestnut.pb.ActionType
+	2,  // 15: chestnut.pb.AnnounceItem.Type:type_name -> chestnut.pb.AnnounceType
+	3,  // 16: chestnut.pb.AnnounceItem.Result:type_name -> chestnut.pb.ApproveType
+	4,  // 17: chestnut.pb.AnnounceItem.Action:type_name -> chestnut.pb.ActionType
+	4,  // 18: chestnut.pb.SchemaItem.Action:type_name -> chestnut.pb.ActionType
+	12, // 19: chestnut.pb.GroupItem.GenesisBlock:type_name -> chestnut.pb.Block
+	7,  // 20: chestnut.pb.GroupItem.EncryptType:type_name -> chestnut.pb.GroupEncryptType
+	8,  // 21: chestnut.pb.GroupItem.ConsenseType:type_name -> chestnut.pb.GroupConsenseType
+	37, // 22: chestnut.pb.GroupItem.Admins:type_name -> chestnut.pb.AdminSet
+	44, // 23: chestnut.pb.GroupItem.EpochCipherKeys:type_name -> chestnut.pb.GroupItem.EpochCipherKeysEntry
+	9,  // 24: chestnut.pb.GroupItemV0.UserRole:type_name -> chestnut.pb.RoleV0
+	12, // 25: chestnut.pb.GroupItemV0.GenesisBlock:type_name -> chestnut.pb.Block
+	7,  // 26: chestnut.pb.GroupItemV0.EncryptType:type_name -> chestnut.pb.GroupEncryptType
+	8,  // 27: chestnut.pb.GroupItemV0.ConsenseType:type_name -> chestnut.pb.GroupConsenseType
+	1,  // 28: chestnut.pb.AdminAction.Type:type_name -> chestnut.pb.TrxType
+	38, // 29: chestnut.pb.AdminAction.Signs:type_name -> chestnut.pb.AdminSign
+	4,  // 30: chestnut.pb.InviteItem.Action:type_name -> chestnut.pb.ActionType
+	40, // 31: chestnut.pb.InviteToken.Invite:type_name -> chestnut.pb.InviteItem
+	43, // 32: chestnut.pb.InviteToken.Seed:type_name -> chestnut.pb.GroupSeed
+	12, // 33: chestnut.pb.GroupSeed.GenesisBlock:type_name -> chestnut.pb.Block
+	34, // [34:34] is the sub-list for method output_type
+	34, // [34:34] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_chain_proto_init() }
@@ -2511,7 +4245,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ReqBlockResp); i {
+			switch v := v.(*ReqTrx); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2523,7 +4257,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PostItem); i {
+			switch v := v.(*ReqTrxResp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2535,7 +4269,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*DenyUserItem); i {
+			switch v := v.(*LightModeItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2547,7 +4281,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProducerItem); i {
+			switch v := v.(*ReqBlockResp); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2559,7 +4293,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*AnnounceItem); i {
+			switch v := v.(*PostItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2571,7 +4305,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*SchemaItem); i {
+			switch v := v.(*RetentionItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2583,7 +4317,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupItem); i {
+			switch v := v.(*GroupConfig); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2595,7 +4329,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GroupItemV0); i {
+			switch v := v.(*ConfigItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2607,7 +4341,7 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*PSPing); i {
+			switch v := v.(*HeartbeatItem); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -2619,6 +4353,210 @@ func file_chain_proto_init() {
 			}
 		}
 		file_chain_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DemotionPolicyItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobManifest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobReq); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BlobResp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DenyUserItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProducerItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AnnounceItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*SchemaItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GroupItemV0); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PSPing); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminSet); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminSign); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*AdminAction); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[30].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InviteItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[31].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RedeemItem); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[32].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InviteToken); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_chain_proto_msgTypes[33].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GroupSeed); i {
 			case 0:
 				return &v.state
@@ -2636,8 +4574,8 @@ func file_chain_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_chain_proto_rawDesc,
-			NumEnums:      9,
-			NumMessages:   17,
+			NumEnums:      10,
+			NumMessages:   35,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
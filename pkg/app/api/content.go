@@ -2,12 +2,12 @@
 package api
 
 import (
-	"encoding/hex"
+	"fmt"
 	"net/http"
 	"strconv"
 
 	"github.com/labstack/echo/v4"
-	"github.com/lixvyang/chestnut/chain"
+	"github.com/lixvyang/chestnut/appdata"
 	localcrypto "github.com/lixvyang/chestnut/crypto"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
 	"google.golang.org/protobuf/proto"
@@ -19,6 +19,8 @@ type GroupContentObjectItem struct {
 	Content   proto.Message
 	TypeUrl   string
 	TimeStamp int64
+	Updated   int64 `json:",omitempty"` //timestamp of the last edit by the author
+	Deleted   bool  `json:",omitempty"`
 }
 
 type SenderList struct {
@@ -51,7 +53,7 @@ func (h *Handler) ContentByPeers(c echo.Context) (err error) {
 		return c.JSON(http.StatusBadRequest, output)
 	}
 
-	groupmgr := chain.GetGroupMgr()
+	groupmgr := h.groupMgr(c)
 	groupitem, err := groupmgr.GetGroupItem(groupid)
 	if err != nil {
 		output[ERROR_INFO] = err.Error()
@@ -59,41 +61,43 @@ func (h *Handler) ContentByPeers(c echo.Context) (err error) {
 	}
 	ctnobjList := []*GroupContentObjectItem{}
 	for _, trxid := range trxids {
-		trx, err := h.Chaindb.GetTrx(trxid, h.NodeName)
+		ctnobjitem, err := h.getContentItem(groupitem, trxid, h.nodeName(c), h.keystore(c))
 		if err != nil {
 			c.Logger().Errorf("GetTrx Err: %s", err)
 			continue
 		}
+		ctnobjList = append(ctnobjList, ctnobjitem)
+	}
+	return c.JSON(http.StatusOK, ctnobjList)
+}
 
-		//decrypt trx data
-		if trx.Type == chestnutpb.TrxType_POST && groupitem.EncryptType == chestnutpb.GroupEncryptType_PRIVATE {
-			//for post, private group, encrypted by pgp for all announced group user
-			ks := localcrypto.GetKeystore()
-			decryptData, err := ks.Decrypt(groupitem.UserEncryptPubkey, trx.Data)
-			if err != nil {
-				return err
-			}
-			trx.Data = decryptData
-		} else {
-			//decode trx data
-			ciperKey, err := hex.DecodeString(groupitem.CipherKey)
-			if err != nil {
-				return err
-			}
+// load a post with the edit/delete overlay of its author applied
+func (h *Handler) getContentItem(groupitem *chestnutpb.GroupItem, trxid string, nodename string, ks localcrypto.Keystore) (*GroupContentObjectItem, error) {
+	trx, err := h.Chaindb.GetTrx(trxid, nodename)
+	if err != nil {
+		return nil, err
+	}
+	if trx.GroupId != groupitem.GroupId {
+		return nil, fmt.Errorf("trx %s not in group %s", trxid, groupitem.GroupId)
+	}
 
-			decryptData, err := localcrypto.AesDecode(trx.Data, ciperKey)
-			if err != nil {
-				return err
-			}
-			trx.Data = decryptData
-		}
+	ctnobjitem := &GroupContentObjectItem{TrxId: trx.TrxId, Publisher: trx.SenderPubkey, TimeStamp: trx.TimeStamp}
+	ctnobj, typeurl, err := appdata.DecodeTrxContent(groupitem, trx, ks)
+	if err != nil {
+		return nil, err
+	}
+	ctnobjitem.Content = ctnobj
+	ctnobjitem.TypeUrl = typeurl
 
-		ctnobj, typeurl, errum := chestnutpb.BytesToMessage(trx.TrxId, trx.Data)
-		if errum != nil {
-			c.Logger().Errorf("Unmarshal trx.Data %s Err: %s", trx.TrxId, errum)
-		}
-		ctnobjitem := &GroupContentObjectItem{TrxId: trx.TrxId, Publisher: trx.SenderPubkey, Content: ctnobj, TimeStamp: trx.TimeStamp, TypeUrl: typeurl}
-		ctnobjList = append(ctnobjList, ctnobjitem)
+	overlay, err := h.Appdb.GetPostOverlay(groupitem.GroupId, trxid)
+	if err != nil {
+		return nil, err
 	}
-	return c.JSON(http.StatusOK, ctnobjList)
+	if obj, ok := ctnobj.(*chestnutpb.Object); ok && overlay != nil {
+		obj.Content = overlay.Content
+		obj.Name = overlay.Name
+		ctnobjitem.Updated = overlay.TimeStamp
+		ctnobjitem.Deleted = overlay.Deleted
+	}
+	return ctnobjitem, nil
 }
@@ -10,10 +10,11 @@ import (
 type Handler struct {
 	Ctx       context.Context
 	Appdb     *appdata.AppDb
+	Appsyncs  map[string]*appdata.AppSync // AppSync of each tenant, by tenant name
 	Chaindb   *storage.DbMgr
 	Apiroot   string
 	GitCommit string
 	ConfigDir string
 	PeerName  string
-	NodeName  string
+	NodeName  string // name of the default tenant
 }
@@ -0,0 +1,88 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+type SearchResultItem struct {
+	*GroupContentObjectItem
+	Score float64
+}
+
+type SearchResults struct {
+	Total   int
+	Offset  int
+	Results []*SearchResultItem
+}
+
+type RebuildSearchResult struct {
+	GroupId string `json:"group_id"`
+	Docs    int64  `json:"docs"`
+}
+
+// full-text search of group posts, ranked by relevance
+func (h *Handler) SearchContent(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	query := c.QueryParam("q")
+	if query == "" {
+		output[ERROR_INFO] = "q can't be nil."
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	num, _ := strconv.Atoi(c.QueryParam("num"))
+	if num <= 0 {
+		num = 20
+	}
+	offset, _ := strconv.Atoi(c.QueryParam("offset"))
+	if offset < 0 {
+		offset = 0
+	}
+
+	groupmgr := h.groupMgr(c)
+	groupitem, err := groupmgr.GetGroupItem(groupid)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	results, total, err := h.Appdb.Search(groupid, query, offset, num)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	searchResults := &SearchResults{Total: total, Offset: offset, Results: []*SearchResultItem{}}
+	for _, result := range results {
+		ctnobjitem, err := h.getContentItem(groupitem, result.TrxId, h.nodeName(c), h.keystore(c))
+		if err != nil {
+			c.Logger().Errorf("GetTrx Err: %s", err)
+			continue
+		}
+		searchResults.Results = append(searchResults.Results, &SearchResultItem{GroupContentObjectItem: ctnobjitem, Score: result.Score})
+	}
+	return c.JSON(http.StatusOK, searchResults)
+}
+
+// rebuild the search index of group from the chain store
+func (h *Handler) RebuildSearchIndex(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+
+	groupmgr := h.groupMgr(c)
+	groupitem, err := groupmgr.GetGroupItem(groupid)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	docs, err := h.Appdb.RebuildSearchIndex(groupitem, h.Chaindb, h.nodeName(c), h.keystore(c))
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, &RebuildSearchResult{GroupId: groupid, Docs: docs})
+}
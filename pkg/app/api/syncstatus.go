@@ -0,0 +1,37 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/appdata"
+)
+
+// index status of all groups, Lag is the number of blocks not indexed yet
+func (h *Handler) GetSyncStatus(c echo.Context) (err error) {
+	output := make(map[string]string)
+	appsync := h.appSync(c)
+	if appsync == nil {
+		output[ERROR_INFO] = "appsync is not running"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, map[string][]*appdata.GroupSyncStatus{"groups": appsync.GetSyncStatus()})
+}
+
+// index status of a group
+func (h *Handler) GetGroupSyncStatus(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	appsync := h.appSync(c)
+	if appsync == nil {
+		output[ERROR_INFO] = "appsync is not running"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	status, ok := appsync.GetGroupSyncStatus(groupid)
+	if !ok {
+		output[ERROR_INFO] = "group " + groupid + " not found"
+		return c.JSON(http.StatusBadRequest, output)
+	}
+	return c.JSON(http.StatusOK, status)
+}
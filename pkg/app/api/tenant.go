@@ -0,0 +1,39 @@
+package api
+
+import (
+	"github.com/labstack/echo/v4"
+	"github.com/lixvyang/chestnut/appdata"
+	"github.com/lixvyang/chestnut/chain"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	"github.com/lixvyang/chestnut/nodectx"
+)
+
+// the GroupMgr of the tenant selected by the request token
+func (h *Handler) groupMgr(c echo.Context) *chain.GroupMgr {
+	name, _ := c.Get(nodectx.TENANT_CONTEXT_KEY).(string)
+	if groupmgr, ok := chain.GetTenantGroupMgr(name); ok {
+		return groupmgr
+	}
+	return chain.GetGroupMgr()
+}
+
+// chain data of the tenant is saved with the tenant name as the node name
+func (h *Handler) nodeName(c echo.Context) string {
+	if groupmgr := h.groupMgr(c); groupmgr != nil {
+		return groupmgr.Tenant().Name
+	}
+	return h.NodeName
+}
+
+// keystore of the tenant, private posts of its groups are decrypted by it
+func (h *Handler) keystore(c echo.Context) localcrypto.Keystore {
+	if groupmgr := h.groupMgr(c); groupmgr != nil {
+		return groupmgr.Tenant().Keystore
+	}
+	return nil
+}
+
+// the AppSync indexing the groups of the tenant, nil if not running
+func (h *Handler) appSync(c echo.Context) *appdata.AppSync {
+	return h.Appsyncs[h.nodeName(c)]
+}
@@ -0,0 +1,85 @@
+// Package api provides API for chestnut.
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	localcrypto "github.com/lixvyang/chestnut/crypto"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+)
+
+type ThreadItem struct {
+	*GroupContentObjectItem
+	Reactions  map[string]int64
+	ReplyCount int64
+}
+
+type PostThread struct {
+	Post    *ThreadItem
+	Replies []*ThreadItem
+}
+
+// get a post with its replies and reaction summary
+func (h *Handler) ContentThread(c echo.Context) (err error) {
+	output := make(map[string]string)
+	groupid := c.Param("group_id")
+	trxid := c.Param("trx_id")
+	num, _ := strconv.Atoi(c.QueryParam("num"))
+	starttrx := c.QueryParam("starttrx")
+	if num == 0 {
+		num = 20
+	}
+
+	reverse := false
+	if c.QueryParam("reverse") == "true" {
+		reverse = true
+	}
+
+	groupmgr := h.groupMgr(c)
+	groupitem, err := groupmgr.GetGroupItem(groupid)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	post, err := h.getThreadItem(groupitem, trxid, h.nodeName(c), h.keystore(c))
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	replyids, err := h.Appdb.GetReplies(groupid, trxid, starttrx, num, reverse)
+	if err != nil {
+		output[ERROR_INFO] = err.Error()
+		return c.JSON(http.StatusBadRequest, output)
+	}
+
+	thread := &PostThread{Post: post, Replies: []*ThreadItem{}}
+	for _, replyid := range replyids {
+		reply, err := h.getThreadItem(groupitem, replyid, h.nodeName(c), h.keystore(c))
+		if err != nil {
+			c.Logger().Errorf("GetTrx Err: %s", err)
+			continue
+		}
+		thread.Replies = append(thread.Replies, reply)
+	}
+	return c.JSON(http.StatusOK, thread)
+}
+
+func (h *Handler) getThreadItem(groupitem *chestnutpb.GroupItem, trxid string, nodename string, ks localcrypto.Keystore) (*ThreadItem, error) {
+	ctnobjitem, err := h.getContentItem(groupitem, trxid, nodename, ks)
+	if err != nil {
+		return nil, err
+	}
+	reactions, err := h.Appdb.GetReactions(groupitem.GroupId, trxid)
+	if err != nil {
+		return nil, err
+	}
+	replycount, err := h.Appdb.GetReplyCount(groupitem.GroupId, trxid)
+	if err != nil {
+		return nil, err
+	}
+	return &ThreadItem{GroupContentObjectItem: ctnobjitem, Reactions: reactions, ReplyCount: replycount}, nil
+}
@@ -3,6 +3,7 @@ package pubsubconn
 
 import (
 	"context"
+	"fmt"
 
 	logging "github.com/ipfs/go-log/v2"
 	pubsub "github.com/libp2p/go-libp2p-pubsub"
@@ -49,17 +50,37 @@ func (psconn *P2pPubSubConn) JoinChannel(cId string, chain Chain) error {
 	} else {
 		channel_log.Infof("Subscribe <%s> done", cId)
 	}
-	go psconn.handleGroupChannel()
+	go psconn.handleGroupChannel(psconn.Subscription)
+	return nil
+}
+
+// unsubscribe and close the topic, the channel can be joined again
+func (psconn *P2pPubSubConn) LeaveChannel() error {
+	if psconn.Subscription != nil {
+		psconn.Subscription.Cancel()
+		psconn.Subscription = nil
+	}
+	if psconn.Topic != nil {
+		if err := psconn.Topic.Close(); err != nil {
+			channel_log.Warningf("Close <%s> failed: %s", psconn.Cid, err)
+			return err
+		}
+		psconn.Topic = nil
+	}
+	channel_log.Infof("Leave <%s> done", psconn.Cid)
 	return nil
 }
 
 func (psconn *P2pPubSubConn) Publish(data []byte) error {
+	if psconn.Topic == nil {
+		return fmt.Errorf("channel <%s> not joined", psconn.Cid)
+	}
 	return psconn.Topic.Publish(psconn.Ctx, data)
 }
 
-func (psconn *P2pPubSubConn) handleGroupChannel() error {
+func (psconn *P2pPubSubConn) handleGroupChannel(sub *pubsub.Subscription) error {
 	for {
-		msg, err := psconn.Subscription.Next(psconn.Ctx)
+		msg, err := sub.Next(psconn.Ctx)
 		if err == nil {
 			var pkg chestnutpb.Package
 			err = proto.Unmarshal(msg.Data, &pkg)
@@ -12,5 +12,6 @@ type Chain interface {
 
 type PubSubConn interface {
 	JoinChannel(cId string, chain Chain) error
+	LeaveChannel() error
 	Publish(data []byte) error
 }
\ No newline at end of file
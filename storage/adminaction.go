@@ -0,0 +1,57 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const ADM_PREFIX = "adm" //pending admin action
+
+func (dbMgr *DbMgr) SaveAdminAction(action *chestnutpb.AdminAction, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + ADM_PREFIX + "_" + action.GroupId + "_" + action.ActionId
+	value, err := proto.Marshal(action)
+	if err != nil {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), value)
+}
+
+func (dbMgr *DbMgr) GetAdminAction(groupId, actionId string, prefix ...string) (*chestnutpb.AdminAction, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + ADM_PREFIX + "_" + groupId + "_" + actionId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	action := &chestnutpb.AdminAction{}
+	if err := proto.Unmarshal(value, action); err != nil {
+		return nil, err
+	}
+	return action, nil
+}
+
+func (dbMgr *DbMgr) GetAdminActions(groupId string, prefix ...string) ([]*chestnutpb.AdminAction, error) {
+	var actions []*chestnutpb.AdminAction
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + ADM_PREFIX + "_" + groupId + "_"
+	err := dbMgr.Db.PrefixForeach([]byte(key), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		action := &chestnutpb.AdminAction{}
+		if perr := proto.Unmarshal(v, action); perr != nil {
+			return perr
+		}
+		actions = append(actions, action)
+		return nil
+	})
+	return actions, err
+}
+
+func (dbMgr *DbMgr) RmAdminAction(groupId, actionId string, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + ADM_PREFIX + "_" + groupId + "_" + actionId
+	return dbMgr.Db.Delete([]byte(key))
+}
@@ -21,6 +21,12 @@ type CSBadger struct {
 	db *badger.DB
 }
 
+// CSBadgerTxn is a read-write badger transaction
+type CSBadgerTxn struct {
+	txn *badger.Txn
+	done bool
+}
+
 func (s *CSBadger) Init(path string) error {
 	var err error
 	s.db, err = badger.Open(badger.DefaultOptions(path).WithValueLogFileSize(DefaultLogFileSize).WithMemTableSize(DefaultMemTableSize).WithValueLogMaxEntries(DefaultMaxEntries).WithBlockCacheSize(DefaultBlockCacheSize).WithCompression(DefaultCompressionType).WithLoggingLevel(badger.ERROR))
@@ -52,15 +58,9 @@ func (s *CSBadger) Delete(key []byte) error {
 func (s *CSBadger) Get(key []byte) ([]byte, error) {
 	var val []byte
 	err := s.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get(key)
-		if err != nil {
-			return err
-		}
-		val, err = item.ValueCopy(nil)
-		if err != nil {
-			return err
-		}
-		return nil
+		var err error
+		val, err = txnGet(txn, key)
+		return err
 	})
 	return val, err
 }
@@ -69,14 +69,7 @@ func (s *CSBadger) IsExist(key []byte) (bool, error) {
 	var ret bool
 
 	err := s.db.View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = 1
-		opts.PrefetchValues = false
-		it := txn.NewIterator(opts)
-		defer it.Close()
-
-		it.Seek(key)
-		ret = it.ValidForPrefix(key)
+		ret = txnIsExist(txn, key)
 		return nil
 	})
 	if err == nil {
@@ -85,83 +78,211 @@ func (s *CSBadger) IsExist(key []byte) (bool, error) {
 	return false, err
 }
 
-
-
 func (s *CSBadger) PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error {
-	err := s.db.View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = DefaultPrefetchSize
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
-			item := it.Item()
-			key := item.KeyCopy(nil)
-			val, err := item.ValueCopy(nil)
-			if err != nil {
-				return err
-			}
-			ferr := fn(key, val, nil)
-			if ferr != nil {
-				return ferr
-			}
-		}
-		return nil
+	return s.db.View(func(txn *badger.Txn) error {
+		return txnPrefixForeach(txn, prefix, fn)
 	})
-	return err
 }
 
 func (s *CSBadger) PrefixForeachKey(prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) error {
-	err := s.db.View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = 20
-		opts.PrefetchValues = false
-		opts.Reverse = reverse
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		for it.Seek(prefix); it.ValidForPrefix(valid); it.Next() {
-			item := it.Item()
-			key := item.KeyCopy(nil)
-			ferr := fn(key, nil)
-			if ferr != nil {
-				return ferr
-			}
-		}
-		return nil
+	return s.db.View(func(txn *badger.Txn) error {
+		return txnPrefixForeachKey(txn, prefix, valid, reverse, fn)
 	})
-	return err
 }
 
 func (s *CSBadger) Foreach(fn func([]byte, []byte, error) error) error {
-	err := s.db.View(func(txn *badger.Txn) error {
-		opts := badger.DefaultIteratorOptions
-		opts.PrefetchSize = DefaultPrefetchSize
-		it := txn.NewIterator(opts)
-		defer it.Close()
-		for it.Rewind(); it.Valid(); it.Next() {
-			item := it.Item()
-			key := item.KeyCopy(nil)
-			val, err := item.ValueCopy(nil)
-			if err != nil {
-				return err
-			}
-			ferr := fn(key, val, nil)
-			if ferr != nil {
-				return ferr
-			}
-		}
-		return nil
+	return s.db.View(func(txn *badger.Txn) error {
+		return txnForeach(txn, fn)
 	})
-	return err
 }
 
 func (s *CSBadger) BatchWrite(keys [][]byte, values [][]byte) error {
+	txn := s.db.NewTransaction(true)
+	defer txn.Discard()
+
+	if err := txnBatchWrite(txn, keys, values); err != nil {
+		return err
+	}
+	return txn.Commit()
+
+}
+
+func (s *CSBadger) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
+	return s.db.GetSequence(key, bandwidth)
+}
+
+// Run value log GC until nothing to rewrite, returns the value log bytes reclaimed
+func (s *CSBadger) RunGC() (int64, error) {
+	_, before := s.db.Size()
+	for {
+		err := s.db.RunValueLogGC(0.5)
+		if err == badger.ErrNoRewrite || err == badger.ErrRejected {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	_, after := s.db.Size()
+	if after > before {
+		return 0, nil
+	}
+	return before - after, nil
+}
+
+func (s *CSBadger) BeginTxn() (ChestnutTxn, error) {
+	return &CSBadgerTxn{txn: s.db.NewTransaction(true)}, nil
+}
+
+func (t *CSBadgerTxn) Init(path string) error {
+	return errors.New("can not init a transaction")
+}
+
+// Close a transaction equals to rollback
+func (t *CSBadgerTxn) Close() error {
+	t.Rollback()
+	return nil
+}
+
+func (t *CSBadgerTxn) Set(key []byte, val []byte) error {
+	return t.txn.SetEntry(badger.NewEntry(key, val))
+}
+
+func (t *CSBadgerTxn) Delete(key []byte) error {
+	return t.txn.Delete(key)
+}
+
+func (t *CSBadgerTxn) Get(key []byte) ([]byte, error) {
+	return txnGet(t.txn, key)
+}
+
+func (t *CSBadgerTxn) IsExist(key []byte) (bool, error) {
+	return txnIsExist(t.txn, key), nil
+}
+
+// only one iterator can be active in a read-write transaction, fn must not iterate the same transaction
+func (t *CSBadgerTxn) PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error {
+	return txnPrefixForeach(t.txn, prefix, fn)
+}
+
+func (t *CSBadgerTxn) PrefixForeachKey(prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) error {
+	return txnPrefixForeachKey(t.txn, prefix, valid, reverse, fn)
+}
+
+func (t *CSBadgerTxn) Foreach(fn func([]byte, []byte, error) error) error {
+	return txnForeach(t.txn, fn)
+}
+
+func (t *CSBadgerTxn) BatchWrite(keys [][]byte, values [][]byte) error {
+	return txnBatchWrite(t.txn, keys, values)
+}
+
+func (t *CSBadgerTxn) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
+	return nil, errors.New("sequence is not supported in transaction")
+}
+
+func (t *CSBadgerTxn) BeginTxn() (ChestnutTxn, error) {
+	return nil, errors.New("nested transaction is not supported")
+}
+
+func (t *CSBadgerTxn) Commit() error {
+	if t.done {
+		return errors.New("transaction already committed or rolled back")
+	}
+	t.done = true
+	return t.txn.Commit()
+}
+
+func (t *CSBadgerTxn) Rollback() {
+	if t.done {
+		return
+	}
+	t.done = true
+	t.txn.Discard()
+}
+
+func txnGet(txn *badger.Txn, key []byte) ([]byte, error) {
+	item, err := txn.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	return item.ValueCopy(nil)
+}
+
+func txnIsExist(txn *badger.Txn, key []byte) bool {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchSize = 1
+	opts.PrefetchValues = false
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	it.Seek(key)
+	return it.ValidForPrefix(key)
+}
+
+func txnPrefixForeach(txn *badger.Txn, prefix []byte, fn func([]byte, []byte, error) error) error {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchSize = DefaultPrefetchSize
+	it := txn.NewIterator(opts)
+	defer it.Close()
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		item := it.Item()
+		key := item.KeyCopy(nil)
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		ferr := fn(key, val, nil)
+		if ferr != nil {
+			return ferr
+		}
+	}
+	return nil
+}
+
+func txnPrefixForeachKey(txn *badger.Txn, prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) error {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchSize = 20
+	opts.PrefetchValues = false
+	opts.Reverse = reverse
+	it := txn.NewIterator(opts)
+	defer it.Close()
+	for it.Seek(prefix); it.ValidForPrefix(valid); it.Next() {
+		item := it.Item()
+		key := item.KeyCopy(nil)
+		ferr := fn(key, nil)
+		if ferr != nil {
+			return ferr
+		}
+	}
+	return nil
+}
+
+func txnForeach(txn *badger.Txn, fn func([]byte, []byte, error) error) error {
+	opts := badger.DefaultIteratorOptions
+	opts.PrefetchSize = DefaultPrefetchSize
+	it := txn.NewIterator(opts)
+	defer it.Close()
+	for it.Rewind(); it.Valid(); it.Next() {
+		item := it.Item()
+		key := item.KeyCopy(nil)
+		val, err := item.ValueCopy(nil)
+		if err != nil {
+			return err
+		}
+		ferr := fn(key, val, nil)
+		if ferr != nil {
+			return ferr
+		}
+	}
+	return nil
+}
+
+func txnBatchWrite(txn *badger.Txn, keys [][]byte, values [][]byte) error {
 	if len(keys) != len(values) {
 		return errors.New("keys' and values' length should be equal")
 	}
 
-	txn := s.db.NewTransaction(true)
-	defer txn.Discard()
-
 	for i, k := range keys {
 		v := values[i]
 		e := badger.NewEntry(k, v)
@@ -170,10 +291,5 @@ func (s *CSBadger) BatchWrite(keys [][]byte, values [][]byte) error {
 			return err
 		}
 	}
-	return txn.Commit()
-
-}
-
-func (s *CSBadger) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
-	return s.db.GetSequence(key, bandwidth)
+	return nil
 }
@@ -0,0 +1,239 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const BLB_PREFIX = "blb" //blob chunk
+const BMF_PREFIX = "bmf" //blob manifest
+const BRF_PREFIX = "brf" //blob referenced by group
+
+const BLOB_CHUNK_SIZE = 256 * 1024        //256Kb
+const BLOB_SIZE_LIMIT = 100 * 1024 * 1024 //100Mb
+
+// blobs are referenced by url from Object.attachments and Image.url
+const BLOB_URL_PREFIX = "blob:sha256:"
+
+func BlobUrl(blobId string) string {
+	return BLOB_URL_PREFIX + blobId
+}
+
+// get the blob id of a blob url, empty if url is not a blob url
+func ParseBlobUrl(url string) string {
+	if !strings.HasPrefix(url, BLOB_URL_PREFIX) {
+		return ""
+	}
+	blobId := url[len(BLOB_URL_PREFIX):]
+	if !IsBlobId(blobId) {
+		return ""
+	}
+	return blobId
+}
+
+func IsBlobId(id string) bool {
+	b, err := hex.DecodeString(id)
+	return err == nil && len(b) == sha256.Size
+}
+
+func blobHash(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+// Save content read from r as chunks and reference it from the group.
+// Chunks are content addressed, the same chunk is saved only once.
+func (dbMgr *DbMgr) AddBlob(groupId string, name string, mediaType string, r io.Reader, prefix ...string) (*chestnutpb.BlobManifest, error) {
+	manifest := &chestnutpb.BlobManifest{Name: name, MediaType: mediaType, TimeStamp: time.Now().UnixNano()}
+	hash := sha256.New()
+	buf := make([]byte, BLOB_CHUNK_SIZE)
+	for {
+		n, err := io.ReadFull(r, buf)
+		if n > 0 {
+			manifest.Size += int64(n)
+			if manifest.Size > BLOB_SIZE_LIMIT {
+				return nil, fmt.Errorf("blob size exceeds limit %d", BLOB_SIZE_LIMIT)
+			}
+			hash.Write(buf[:n])
+			chunkId := blobHash(buf[:n])
+			if err := dbMgr.AddBlobChunk(chunkId, buf[:n], prefix...); err != nil {
+				return nil, err
+			}
+			manifest.Chunks = append(manifest.Chunks, chunkId)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	if manifest.Size == 0 {
+		return nil, errors.New("empty blob")
+	}
+	manifest.BlobId = hex.EncodeToString(hash.Sum(nil))
+
+	if err := dbMgr.AddBlobManifest(groupId, manifest, prefix...); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// save a chunk, chunkId must be the sha256 of data
+func (dbMgr *DbMgr) AddBlobChunk(chunkId string, data []byte, prefix ...string) error {
+	if blobHash(data) != chunkId {
+		return fmt.Errorf("chunk %s hash mismatch", chunkId)
+	}
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + BLB_PREFIX + "_" + chunkId
+	exist, err := dbMgr.Db.IsExist([]byte(key))
+	if err != nil || exist {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), data)
+}
+
+func (dbMgr *DbMgr) GetBlobChunk(chunkId string, prefix ...string) ([]byte, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + BLB_PREFIX + "_" + chunkId
+	return dbMgr.Db.Get([]byte(key))
+}
+
+func (dbMgr *DbMgr) HasBlobChunk(chunkId string, prefix ...string) (bool, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + BLB_PREFIX + "_" + chunkId
+	return dbMgr.Db.IsExist([]byte(key))
+}
+
+// Save the manifest after all chunks are saved and reference the blob from the group.
+// The content of the chunks is verified against the blob id.
+func (dbMgr *DbMgr) AddBlobManifest(groupId string, manifest *chestnutpb.BlobManifest, prefix ...string) error {
+	if err := CheckBlobManifest(manifest); err != nil {
+		return err
+	}
+
+	hash := sha256.New()
+	var size int64
+	for _, chunkId := range manifest.Chunks {
+		data, err := dbMgr.GetBlobChunk(chunkId, prefix...)
+		if err != nil {
+			return fmt.Errorf("get chunk %s of blob %s err: %s", chunkId, manifest.BlobId, err)
+		}
+		hash.Write(data)
+		size += int64(len(data))
+	}
+	if hex.EncodeToString(hash.Sum(nil)) != manifest.BlobId || size != manifest.Size {
+		return fmt.Errorf("blob %s content mismatch", manifest.BlobId)
+	}
+
+	value, err := proto.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	nodeprefix := getPrefix(prefix...)
+	keys := [][]byte{[]byte(nodeprefix + BMF_PREFIX + "_" + manifest.BlobId), []byte(nodeprefix + BRF_PREFIX + "_" + groupId + "_" + manifest.BlobId)}
+	values := [][]byte{value, []byte(fmt.Sprint(manifest.TimeStamp))}
+	return dbMgr.Db.BatchWrite(keys, values)
+}
+
+// check a manifest before its chunks are fetched, chunks are BLOB_CHUNK_SIZE except the last one
+func CheckBlobManifest(manifest *chestnutpb.BlobManifest) error {
+	if !IsBlobId(manifest.BlobId) {
+		return fmt.Errorf("invalid blob id %s", manifest.BlobId)
+	}
+	if manifest.Size <= 0 || manifest.Size > BLOB_SIZE_LIMIT {
+		return fmt.Errorf("blob size must be between 1 and %d", BLOB_SIZE_LIMIT)
+	}
+	if chunks := (manifest.Size + BLOB_CHUNK_SIZE - 1) / BLOB_CHUNK_SIZE; int64(len(manifest.Chunks)) != chunks {
+		return fmt.Errorf("blob %s of size %d must have %d chunks", manifest.BlobId, manifest.Size, chunks)
+	}
+	return nil
+}
+
+// get the manifest of a complete blob, ErrKeyNotFound if the blob is not saved (or not complete)
+func (dbMgr *DbMgr) GetBlobManifest(blobId string, prefix ...string) (*chestnutpb.BlobManifest, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + BMF_PREFIX + "_" + blobId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err != nil {
+		return nil, err
+	}
+	manifest := &chestnutpb.BlobManifest{}
+	if err := proto.Unmarshal(value, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+func (dbMgr *DbMgr) AddBlobRef(groupId string, blobId string, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + BRF_PREFIX + "_" + groupId + "_" + blobId
+	return dbMgr.Db.Set([]byte(key), []byte(fmt.Sprint(time.Now().UnixNano())))
+}
+
+// blobs are only served to the groups referencing them
+func (dbMgr *DbMgr) IsBlobRef(groupId string, blobId string, prefix ...string) (bool, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + BRF_PREFIX + "_" + groupId + "_" + blobId
+	_, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// chunks are only served to the groups referencing a blob of the chunk
+func (dbMgr *DbMgr) IsBlobChunkRef(groupId string, blobId string, chunkId string, prefix ...string) (bool, error) {
+	ref, err := dbMgr.IsBlobRef(groupId, blobId, prefix...)
+	if err != nil || !ref {
+		return false, err
+	}
+	manifest, err := dbMgr.GetBlobManifest(blobId, prefix...)
+	if err != nil {
+		return false, err
+	}
+	for _, id := range manifest.Chunks {
+		if id == chunkId {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// write the content of a complete blob to w
+func (dbMgr *DbMgr) ReadBlob(blobId string, w io.Writer, prefix ...string) error {
+	manifest, err := dbMgr.GetBlobManifest(blobId, prefix...)
+	if err != nil {
+		return err
+	}
+	for _, chunkId := range manifest.Chunks {
+		data, err := dbMgr.GetBlobChunk(chunkId, prefix...)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// check the blob exists and is referenced by the group, used to validate posts with attachments
+func (dbMgr *DbMgr) CheckBlob(groupId string, blobId string, prefix ...string) error {
+	ref, err := dbMgr.IsBlobRef(groupId, blobId, prefix...)
+	if err != nil {
+		return err
+	}
+	if !ref {
+		return errors.New("blob " + blobId + " not found in group " + groupId)
+	}
+	return nil
+}
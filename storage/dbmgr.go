@@ -2,8 +2,12 @@
 package storage
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	logging "github.com/ipfs/go-log/v2"
 	chestnutpb "github.com/lixvyang/chestnut/pb"
@@ -21,55 +25,235 @@ const PRD_PREFIX = "prd" //producer
 const ANN_PREFIX = "ann" //announce
 const SMA_PREFIX = "sma" //schema
 const CHD_PREFIX = "chd" //cached
+const IMP_PREFIX = "imp" //import progress
+const GTX_PREFIX = "gtx" //group trx index
+const STX_PREFIX = "stx" //sender trx index
+const GBK_PREFIX = "gbk" //group block index
+const BHT_PREFIX = "bht" //block height index
+const BHS_PREFIX = "bhs" //block hash index
+const PAU_PREFIX = "pau" //paused group
+const GRPITEM_PREFIX = "grpitem" //group item
 
 type DbMgr struct {
 	GroupInfoDb ChestnutStorage
 	Db	ChestnutStorage
 	Auth ChestnutStorage
 	DataPath string
+
+	//set when the DbMgr is created by BeginTxn
+	txns []ChestnutTxn
+	onCommit []func()
 }
 
 func (dbMgr *DbMgr) CloseDb() {
-	dbMgr.GroupInfoDb.Close()
+	if dbMgr.GroupInfoDb != dbMgr.Db {
+		dbMgr.GroupInfoDb.Close()
+	}
 	dbMgr.Db.Close()
 	dbmgr_log.Infof("ChainCtx Db Closed")
 }
 
-func (dbMgr *DbMgr) TryMigration(nodeDataVer int)  {
-	if nodeDataVer == 0 {
-		dbmgr_log.Info("Migration v0")
-		groupItemsBytes, err := dbMgr.GetGroupsBytes()
-		if err != nil {
-			for _, b := range groupItemsBytes {
-				var item *chestnutpb.GroupItem
-				item = &chestnutpb.GroupItem{}
-				proto.Unmarshal(b, item)
-				if item.CipherKey == "" {
-					itemv0 := &chestnutpb.GroupItemV0{}
-					proto.Unmarshal(b, itemv0)
-					if itemv0.CipherKey != "" { //ok
-						item.LastUpdate = itemv0.LastUpdate
-						item.HighestHeight = itemv0.HighestHeight
-						item.HighestBlockId = itemv0.HighestBlockId
-						item.GenesisBlock = itemv0.GenesisBlock
-						item.EncryptType = itemv0.EncryptType
-						item.ConsenseType = itemv0.ConsenseType
-						item.CipherKey = itemv0.CipherKey
-						item.AppKey = itemv0.AppKey
-						//add group to db
-						value, err := proto.Marshal(item)
-						if err == nil {
-							dbMgr.GroupInfoDb.Set([]byte(item.GroupId), value)
-							dbmgr_log.Infof("db migration v0 for group %s", item.GroupId)
-						}
-					}
-				}
-			}
+// Begin a transaction, all DbMgr methods called on the returned DbMgr read and write in the
+// transaction until Commit or Rollback. GroupInfoDb and Db must be the same storage, so the
+// group items and the chain data are committed atomically.
+func (dbMgr *DbMgr) BeginTxn() (*DbMgr, error) {
+	if dbMgr.txns != nil {
+		return nil, errors.New("nested transaction is not supported")
+	}
+	if dbMgr.GroupInfoDb != dbMgr.Db {
+		return nil, errors.New("transaction needs GroupInfoDb and Db in one storage")
+	}
+
+	dbTxn, err := dbMgr.Db.BeginTxn()
+	if err != nil {
+		return nil, err
+	}
+	txnMgr := &DbMgr{Db: dbTxn, GroupInfoDb: dbTxn, Auth: dbMgr.Auth, DataPath: dbMgr.DataPath}
+	txnMgr.txns = []ChestnutTxn{dbTxn}
+	return txnMgr, nil
+}
+
+// Register fn to be called after the transaction committed, fn is called immediately if dbMgr is not a transaction
+func (dbMgr *DbMgr) OnCommit(fn func()) {
+	if dbMgr.txns == nil {
+		fn()
+		return
+	}
+	dbMgr.onCommit = append(dbMgr.onCommit, fn)
+}
+
+func (dbMgr *DbMgr) Commit() error {
+	if dbMgr.txns == nil {
+		return errors.New("not a transaction")
+	}
+
+	for _, txn := range dbMgr.txns {
+		if err := txn.Commit(); err != nil {
+			return err
+		}
+	}
+
+	for _, fn := range dbMgr.onCommit {
+		fn()
+	}
+	dbMgr.onCommit = nil
+	return nil
+}
+
+// Discard all writes in the transaction, it is safe to call Rollback after Commit
+func (dbMgr *DbMgr) Rollback() {
+	for _, txn := range dbMgr.txns {
+		txn.Rollback()
+	}
+	dbMgr.onCommit = nil
+}
+
+func init() {
+	RegisterMigration(GROUPINFO_DB, "convert GroupItemV0 to GroupItem", migrateGroupItemV0)
+	RegisterMigration(CHAIN_DB, "build trx and block indexes", migrateBuildIndexes)
+	RegisterMigration(CHAIN_DB, "build block hash index", migrateBuildHashIndex)
+	RegisterMigration(GROUPINFO_DB, "move group items under the group item prefix", migrateGroupItemKeys)
+}
+
+// Run pending schema migrations of GroupInfoDb and Db
+func (dbMgr *DbMgr) TryMigration() error {
+	if err := Migrate(GROUPINFO_DB, dbMgr.GroupInfoDb); err != nil {
+		return err
+	}
+	return Migrate(CHAIN_DB, dbMgr.Db)
+}
+
+// Group items were saved by group id, and by <tenant>_<groupid> for the groups of a tenant, beside
+// the chain data in the same storage. They are moved under GRPITEM_PREFIX to list them by prefix.
+func migrateGroupItemKeys(k []byte, v []byte) ([][]byte, [][]byte, error) {
+	key := string(k)
+	tenant, groupId := "", key
+	switch strings.Count(key, "_") {
+	case 0:
+	case 1:
+		idx := strings.Index(key, "_")
+		tenant, groupId = key[:idx], key[idx+1:]
+		if tenant == GRPITEM_PREFIX {
+			return nil, nil, nil
+		}
+	default:
+		return nil, nil, nil
+	}
+
+	item := &chestnutpb.GroupItem{}
+	if err := proto.Unmarshal(v, item); err != nil || item.GroupId != groupId || item.CipherKey == "" {
+		dbmgr_log.Warnf("db migration, unknown group item %s", key)
+		return nil, nil, nil
+	}
+	var prefix []string
+	if tenant != "" {
+		prefix = append(prefix, tenant)
+	}
+	return [][]byte{[]byte(groupItemKey(groupId, prefix...)), k}, [][]byte{v, nil}, nil
+}
+
+func migrateGroupItemV0(k []byte, v []byte) ([][]byte, [][]byte, error) {
+	//group items of v0 are saved by group id, chain data and tenant groups have "_" in the key
+	if strings.Contains(string(k), "_") {
+		return nil, nil, nil
+	}
+
+	item := &chestnutpb.GroupItem{}
+	if err := proto.Unmarshal(v, item); err == nil && item.CipherKey != "" {
+		return nil, nil, nil
+	}
+
+	itemv0 := &chestnutpb.GroupItemV0{}
+	if err := proto.Unmarshal(v, itemv0); err != nil || itemv0.CipherKey == "" {
+		dbmgr_log.Warnf("db migration v0, unknown group item %s", string(k))
+		return nil, nil, nil
+	}
+
+	item.GroupId = itemv0.GroupId
+	item.GroupName = itemv0.GroupName
+	item.OwnerPubKey = itemv0.OwnerPubKey
+	item.UserSignPubkey = itemv0.UserSignPubkey
+	item.UserEncryptPubkey = itemv0.UserEncryptPubkey
+	item.LastUpdate = itemv0.LastUpdate
+	item.HighestHeight = itemv0.HighestHeight
+	item.HighestBlockId = itemv0.HighestBlockId
+	item.GenesisBlock = itemv0.GenesisBlock
+	item.EncryptType = itemv0.EncryptType
+	item.ConsenseType = itemv0.ConsenseType
+	item.CipherKey = itemv0.CipherKey
+	item.AppKey = itemv0.AppKey
+	value, err := proto.Marshal(item)
+	if err != nil {
+		return nil, nil, err
+	}
+	dbmgr_log.Infof("db migration v0 for group %s", item.GroupId)
+	return [][]byte{[]byte(item.GroupId)}, [][]byte{value}, nil
+}
+
+func migrateBuildIndexes(k []byte, v []byte) ([][]byte, [][]byte, error) {
+	var keys, values [][]byte
+	key := string(k)
+
+	if nodeprefix, id, ok := splitDataKey(key, CHD_PREFIX+"_"+BLK_PREFIX+"_"); ok {
+		chunk := &chestnutpb.BlockDbChunk{}
+		if err := proto.Unmarshal(v, chunk); err != nil || chunk.BlockId != id || chunk.BlockItem == nil {
+			return nil, nil, nil
+		}
+		for _, idxKey := range blockIndexKeys(chunk, true, nodeprefix) {
+			keys = append(keys, []byte(idxKey))
+			values = append(values, []byte(chunk.BlockId))
+		}
+	} else if nodeprefix, id, ok := splitDataKey(key, BLK_PREFIX+"_"); ok {
+		chunk := &chestnutpb.BlockDbChunk{}
+		if err := proto.Unmarshal(v, chunk); err != nil || chunk.BlockId != id || chunk.BlockItem == nil {
+			return nil, nil, nil
+		}
+		for _, idxKey := range blockIndexKeys(chunk, false, nodeprefix) {
+			keys = append(keys, []byte(idxKey))
+			values = append(values, []byte(chunk.BlockId))
+		}
+	} else if nodeprefix, id, ok := splitDataKey(key, TRX_PREFIX+"_"); ok {
+		trx := &chestnutpb.Trx{}
+		if err := proto.Unmarshal(v, trx); err != nil || trx.TrxId != id {
+			return nil, nil, nil
+		}
+		for _, idxKey := range trxIndexKeys(trx, nodeprefix) {
+			keys = append(keys, []byte(idxKey))
+			values = append(values, []byte(trx.TrxId))
 		}
 	}
+	return keys, values, nil
 }
 
-// save trx
+func migrateBuildHashIndex(k []byte, v []byte) ([][]byte, [][]byte, error) {
+	key := string(k)
+	if _, _, ok := splitDataKey(key, CHD_PREFIX+"_"+BLK_PREFIX+"_"); ok {
+		return nil, nil, nil
+	}
+	if nodeprefix, id, ok := splitDataKey(key, BLK_PREFIX+"_"); ok {
+		chunk := &chestnutpb.BlockDbChunk{}
+		if err := proto.Unmarshal(v, chunk); err != nil || chunk.BlockId != id || chunk.BlockItem == nil {
+			return nil, nil, nil
+		}
+		return [][]byte{[]byte(blockHashKey(chunk.BlockItem.GroupId, chunk.BlockItem.Hash, nodeprefix))}, [][]byte{[]byte(chunk.BlockId)}, nil
+	}
+	return nil, nil, nil
+}
+
+// split key nodeprefix + dataPrefix + id, nodeprefix is empty or nodename + "_"
+func splitDataKey(key string, dataPrefix string) (string, string, bool) {
+	idx := strings.Index(key, dataPrefix)
+	if idx < 0 {
+		return "", "", false
+	}
+	nodeprefix := key[:idx]
+	if nodeprefix != "" && strings.Index(nodeprefix, "_") != len(nodeprefix)-1 {
+		return "", "", false
+	}
+	return nodeprefix, key[idx+len(dataPrefix):], true
+}
+
+// save trx and the group/sender indexes of trx
 func (dbMgr *DbMgr) AddTrx(trx *chestnutpb.Trx, prefix ...string) error {
 	nodeprefix := getPrefix(prefix...)
 	key := nodeprefix + TRX_PREFIX + "_" + trx.TrxId
@@ -77,17 +261,157 @@ func (dbMgr *DbMgr) AddTrx(trx *chestnutpb.Trx, prefix ...string) error {
 	if err != nil {
 		return err
 	}
-	return dbMgr.Db.Set([]byte(key), value)
+
+	keys := [][]byte{[]byte(key)}
+	values := [][]byte{value}
+	for _, idxKey := range trxIndexKeys(trx, nodeprefix) {
+		keys = append(keys, []byte(idxKey))
+		values = append(values, []byte(trx.TrxId))
+	}
+	return dbMgr.Db.BatchWrite(keys, values)
 }
 
 // UNUSED
 // Remove Trx
 func (dbMgr *DbMgr) RmTrx(trxId string, prefix ...string) error {
 	nodeprefix := getPrefix(prefix...)
+	trx, err := dbMgr.GetTrx(trxId, prefix...)
+	if err != nil {
+		return err
+	}
+	for _, idxKey := range trxIndexKeys(trx, nodeprefix) {
+		if err := dbMgr.Db.Delete([]byte(idxKey)); err != nil {
+			return err
+		}
+	}
 	key := nodeprefix + TRX_PREFIX + "_" + trxId
 	return dbMgr.Db.Delete([]byte(key))
 }
 
+// index keys of trx, value of the index is trx id
+// group index: GTX_PREFIX_groupId_timestamp_trxId
+// sender index: STX_PREFIX_groupId_sender_timestamp_trxId
+func trxIndexKeys(trx *chestnutpb.Trx, nodeprefix string) []string {
+	ts := fmt.Sprintf("%019d", trx.TimeStamp)
+	return []string{
+		nodeprefix + GTX_PREFIX + "_" + trx.GroupId + "_" + ts + "_" + trx.TrxId,
+		nodeprefix + STX_PREFIX + "_" + trx.GroupId + "_" + trx.SenderPubkey + "_" + ts + "_" + trx.TrxId,
+	}
+}
+
+// index keys of block, value of the index is block id
+// group index: GBK_PREFIX_groupId_blockId (CHD_PREFIX_GBK_PREFIX_groupId_blockId for cached block)
+// height index: BHT_PREFIX_groupId_height_blockId, not for cached block
+// hash index: BHS_PREFIX_groupId_hash, not for cached block
+func blockIndexKeys(chunk *chestnutpb.BlockDbChunk, cached bool, nodeprefix string) []string {
+	groupId := chunk.BlockItem.GroupId
+	if cached {
+		return []string{nodeprefix + CHD_PREFIX + "_" + GBK_PREFIX + "_" + groupId + "_" + chunk.BlockId}
+	}
+	return []string{
+		nodeprefix + GBK_PREFIX + "_" + groupId + "_" + chunk.BlockId,
+		nodeprefix + BHT_PREFIX + "_" + groupId + "_" + fmt.Sprintf("%019d", chunk.Height) + "_" + chunk.BlockId,
+		blockHashKey(groupId, chunk.BlockItem.Hash, nodeprefix),
+	}
+}
+
+func blockHashKey(groupId string, hash []byte, nodeprefix string) string {
+	return nodeprefix + BHS_PREFIX + "_" + groupId + "_" + hex.EncodeToString(hash)
+}
+
+// get trxs of group (of sender if sender is not empty) by the timestamp order
+func (dbMgr *DbMgr) GetTrxsByGroup(groupId string, sender string, num int, reverse bool, prefix ...string) ([]*chestnutpb.Trx, error) {
+	trxIds, err := dbMgr.GetTrxIdsByGroup(groupId, sender, num, reverse, prefix...)
+	if err != nil {
+		return nil, err
+	}
+
+	var trxs []*chestnutpb.Trx
+	for _, trxId := range trxIds {
+		trx, err := dbMgr.GetTrx(trxId, prefix...)
+		if err != nil {
+			return nil, err
+		}
+		trxs = append(trxs, trx)
+	}
+	return trxs, nil
+}
+
+// get trx ids of group (or of sender in group if sender is not empty) ordered by timestamp, num <= 0 means all
+func (dbMgr *DbMgr) GetTrxIdsByGroup(groupId string, sender string, num int, reverse bool, prefix ...string) ([]string, error) {
+	nodeprefix := getPrefix(prefix...)
+	var key string
+	if sender == "" {
+		key = nodeprefix + GTX_PREFIX + "_" + groupId + "_"
+	} else {
+		key = nodeprefix + STX_PREFIX + "_" + groupId + "_" + sender + "_"
+	}
+
+	seek := []byte(key)
+	if reverse {
+		seek = append(seek, 0xff)
+	}
+
+	var trxIds []string
+	err := dbMgr.Db.PrefixForeachKey(seek, []byte(key), reverse, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		if num > 0 && len(trxIds) >= num {
+			return errors.New("OK")
+		}
+		keystr := string(k)
+		trxIds = append(trxIds, keystr[strings.LastIndex(keystr, "_")+1:])
+		return nil
+	})
+	if err != nil && err.Error() != "OK" {
+		return nil, err
+	}
+	return trxIds, nil
+}
+
+// get block of group by the hex encoded block hash, blocks of legacy chains have ids other than the hash
+func (dbMgr *DbMgr) GetBlockByHash(groupId string, hash string, prefix ...string) (*chestnutpb.Block, error) {
+	nodeprefix := getPrefix(prefix...)
+	hashbytes, err := hex.DecodeString(hash)
+	if err != nil {
+		return nil, err
+	}
+	blockId, err := dbMgr.Db.Get([]byte(blockHashKey(groupId, hashbytes, nodeprefix)))
+	if err != nil {
+		return nil, err
+	}
+	return dbMgr.GetBlock(string(blockId), false, prefix...)
+}
+
+// get all blocks of group at height, there may be more than one block at the same height before the chain is trimmed
+func (dbMgr *DbMgr) GetBlocksByHeight(groupId string, height int64, prefix ...string) ([]*chestnutpb.Block, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + BHT_PREFIX + "_" + groupId + "_" + fmt.Sprintf("%019d", height) + "_"
+
+	var blockIds []string
+	err := dbMgr.Db.PrefixForeachKey([]byte(key), []byte(key), false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		blockIds = append(blockIds, string(k[len(key):]))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var blocks []*chestnutpb.Block
+	for _, blockId := range blockIds {
+		block, err := dbMgr.GetBlock(blockId, false, prefix...)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, nil
+}
+
 // get trx
 func (dbMgr *DbMgr) GetTrx(trxId string, prefix ...string) (*chestnutpb.Trx, error) {
 	nodeprefix := getPrefix(prefix...)
@@ -126,7 +450,14 @@ func (dbMgr *DbMgr) AddGensisBlock(gensisBlock *chestnutpb.Block, prefix ...stri
 	if err != nil {
 		return err
 	}
-	return dbMgr.Db.Set([]byte(key), value)
+
+	keys := [][]byte{[]byte(key)}
+	values := [][]byte{value}
+	for _, idxKey := range blockIndexKeys(&chunk, false, nodePrefix) {
+		keys = append(keys, []byte(idxKey))
+		values = append(values, []byte(chunk.BlockId))
+	}
+	return dbMgr.Db.BatchWrite(keys, values)
 }
 
 // check if block existed
@@ -174,7 +505,7 @@ func (dbMgr *DbMgr) AddBlock(newBlock *chestnutpb.Block, cached bool, prefix ...
 
 		// update parent chunk
 		pChunk.SubBlockId = append(pChunk.SubBlockId, newBlock.BlockId)
-		err = dbMgr.saveBlockChunk(pChunk, cached, prefix...)
+		err = dbMgr.saveBlockChunk(pChunk, cached, nil, prefix...)
 		if err != nil {
 			return err
 		}
@@ -182,11 +513,11 @@ func (dbMgr *DbMgr) AddBlock(newBlock *chestnutpb.Block, cached bool, prefix ...
 		chunk.Height = pChunk.Height + 1
 		chunk.ParentBlockId = pChunk.BlockId
 	}
-	// save chunk
-	return dbMgr.saveBlockChunk(chunk, cached, prefix...)
+	// save chunk and indexes
+	return dbMgr.saveBlockChunk(chunk, cached, blockIndexKeys(chunk, cached, getPrefix(prefix...)), prefix...)
 }
 
-//remove block
+//remove block and indexes of block
 func (dbMgr *DbMgr) RmBlock(blockId string, cached bool, prefix ...string) error {
 	nodeprefix := getPrefix(prefix...)
 	var key string
@@ -196,6 +527,15 @@ func (dbMgr *DbMgr) RmBlock(blockId string, cached bool, prefix ...string) error
 		key = nodeprefix + BLK_PREFIX + "_" + blockId
 	}
 
+	chunk, err := dbMgr.getBlockChunk(blockId, cached, prefix...)
+	if err == nil {
+		for _, idxKey := range blockIndexKeys(chunk, cached, nodeprefix) {
+			if err := dbMgr.Db.Delete([]byte(idxKey)); err != nil {
+				return err
+			}
+		}
+	}
+
 	return dbMgr.Db.Delete([]byte(key))
 }
 
@@ -208,10 +548,20 @@ func (dbMgr *DbMgr) GetBlock(blockId string, cached bool, prefix ...string) (*ch
 	return pChunk.BlockItem, nil
 }
 
+// max size of the blocks gathered from cache for one transaction. A badger transaction is limited to
+// 15% of the memtable, the gathered blocks are written again with their trxs and the chunks of the parents
+var MAX_GATHER_BYTES = int(DefaultMemTableSize * 15 / 100 / 4)
+
+var errGatherFull = errors.New("gathered blocks reach the limit")
+
+// gather newBlock and the cached blocks connected to it, parents before children.
+// Blocks are gathered until their size reaches MAX_GATHER_BYTES, newBlock is always gathered,
+// the rest are found by GetCachedChildBlocks.
 func (dbMgr *DbMgr) GatherBlocksFromCache(newBlock *chestnutpb.Block, cached bool, prefix ...string) ([]*chestnutpb.Block, error) {
 	nodeprefix := getPrefix(prefix...)
 	var blocks []*chestnutpb.Block
 	blocks = append(blocks, newBlock)
+	size := proto.Size(newBlock)
 	pointer1 := 0 // point to head
 	pointer2 := 0 // point to tail
 
@@ -229,11 +579,19 @@ func (dbMgr *DbMgr) GatherBlocksFromCache(newBlock *chestnutpb.Block, cached boo
 			}
 
 			if chunk.BlockItem.PrevBlockId == blocks[pointer1].BlockId {
+				blockSize := proto.Size(chunk.BlockItem)
+				if size+blockSize > MAX_GATHER_BYTES {
+					return errGatherFull
+				}
 				blocks = append(blocks, chunk.BlockItem)
+				size += blockSize
 				pointer2++
 			}
 			return nil
 		})
+		if err == errGatherFull {
+			break
+		}
 		if err != nil {
 			return blocks, err
 		}
@@ -247,6 +605,33 @@ func (dbMgr *DbMgr) GatherBlocksFromCache(newBlock *chestnutpb.Block, cached boo
 	return blocks, nil
 }
 
+// cached blocks with the parent in blocks, excluding blocks. They are left in cache
+// when the gathered blocks reach MAX_GATHER_BYTES
+func (dbMgr *DbMgr) GetCachedChildBlocks(blocks []*chestnutpb.Block, prefix ...string) ([]*chestnutpb.Block, error) {
+	nodeprefix := getPrefix(prefix...)
+	blockIds := make(map[string]bool)
+	for _, block := range blocks {
+		blockIds[block.BlockId] = true
+	}
+
+	var children []*chestnutpb.Block
+	pre := nodeprefix + CHD_PREFIX + "_" + BLK_PREFIX + "_"
+	err := dbMgr.Db.PrefixForeach([]byte(pre), func(k, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		chunk := chestnutpb.BlockDbChunk{}
+		if err := proto.Unmarshal(v, &chunk); err != nil {
+			return err
+		}
+		if blockIds[chunk.BlockItem.PrevBlockId] && !blockIds[chunk.BlockItem.BlockId] {
+			children = append(children, chunk.BlockItem)
+		}
+		return nil
+	})
+	return children, err
+}
+
 func (dbMgr *DbMgr) GetBlockHeight(blockId string, prefix ...string) (int64, error) {
 	pChunk, err := dbMgr.getBlockChunk(blockId, false, prefix...)
 	if err != nil {
@@ -302,8 +687,8 @@ func (dbMgr *DbMgr) getBlockChunk(blockId string, cached bool, prefix ...string)
 	return &pChunk, err
 }
 
-// save block chunk
-func (dbMgr *DbMgr) saveBlockChunk(chunk *chestnutpb.BlockDbChunk, cached bool, prefix ...string) error {
+// save block chunk, with index keys of the chunk if any
+func (dbMgr *DbMgr) saveBlockChunk(chunk *chestnutpb.BlockDbChunk, cached bool, indexKeys []string, prefix ...string) error {
 	nodeprefix := getPrefix(prefix...)
 	var key string
 	if cached {
@@ -316,13 +701,26 @@ func (dbMgr *DbMgr) saveBlockChunk(chunk *chestnutpb.BlockDbChunk, cached bool,
 	if err != nil {
 		return err
 	}
-	return dbMgr.Db.Set([]byte(key), value)
+
+	keys := [][]byte{[]byte(key)}
+	values := [][]byte{value}
+	for _, idxKey := range indexKeys {
+		keys = append(keys, []byte(idxKey))
+		values = append(values, []byte(chunk.BlockId))
+	}
+	return dbMgr.Db.BatchWrite(keys, values)
 }
 
-// add group
-func (dbMgr *DbMgr) AddGroup(groupItem *chestnutpb.GroupItem) error {
+// key of a group item, groups of tenants are saved with the tenant name prefix
+func groupItemKey(groupId string, prefix ...string) string {
+	return getPrefix(prefix...) + GRPITEM_PREFIX + "_" + groupId
+}
+
+// add group, groups of tenants are saved with the tenant name prefix
+func (dbMgr *DbMgr) AddGroup(groupItem *chestnutpb.GroupItem, prefix ...string) error {
+	key := groupItemKey(groupItem.GroupId, prefix...)
 	//check if group exist
-	exist, err := dbMgr.GroupInfoDb.IsExist([]byte(groupItem.GroupId))
+	exist, err := dbMgr.GroupInfoDb.IsExist([]byte(key))
 	if exist {
 		return errors.New("Group with same GroupId existed")
 	}
@@ -332,20 +730,27 @@ func (dbMgr *DbMgr) AddGroup(groupItem *chestnutpb.GroupItem) error {
 	if err != nil {
 		return err
 	}
-	return dbMgr.GroupInfoDb.Set([]byte(groupItem.GroupId), value)
+	return dbMgr.GroupInfoDb.Set([]byte(key), value)
 }
 
-func (dbMgr *DbMgr) UpdGroup(groupItem *chestnutpb.GroupItem) error {
+func (dbMgr *DbMgr) UpdGroup(groupItem *chestnutpb.GroupItem, prefix ...string) error {
+	key := groupItemKey(groupItem.GroupId, prefix...)
 	value, err := proto.Marshal(groupItem)
 	if err != nil {
 		return err
 	}
-	return dbMgr.GroupInfoDb.Set([]byte(groupItem.GroupId), value)
+	return dbMgr.GroupInfoDb.Set([]byte(key), value)
 }
 
-func (dbMgr *DbMgr) RmGroup(item *chestnutpb.GroupItem) error {
+// saved group item, ErrKeyNotFound if the group is not saved
+func (dbMgr *DbMgr) GetGroupBytes(groupId string, prefix ...string) ([]byte, error) {
+	return dbMgr.GroupInfoDb.Get([]byte(groupItemKey(groupId, prefix...)))
+}
+
+func (dbMgr *DbMgr) RmGroup(item *chestnutpb.GroupItem, prefix ...string) error {
+	key := groupItemKey(item.GroupId, prefix...)
 	// check if group exist
-	exist, err := dbMgr.GroupInfoDb.IsExist([]byte(item.GroupId))
+	exist, err := dbMgr.GroupInfoDb.IsExist([]byte(key))
 	if ! exist {
 		if err != nil {
 			return err
@@ -353,7 +758,7 @@ func (dbMgr *DbMgr) RmGroup(item *chestnutpb.GroupItem) error {
 		return errors.New("Group Not Fount")
 	}
 	// delete group
-	return dbMgr.GroupInfoDb.Delete([]byte(item.GroupId))
+	return dbMgr.GroupInfoDb.Delete([]byte(key))
 }
 
 func (dbMgr *DbMgr) RemoveGroupData(item *chestnutpb.GroupItem, prefix ...string) error {
@@ -380,6 +785,35 @@ func (dbMgr *DbMgr) RemoveGroupData(item *chestnutpb.GroupItem, prefix ...string
 	key = nodeprefix + SMA_PREFIX + "_" + item.GroupId
 	keys = append(keys, key)
 
+	//group import progress
+	key = nodeprefix + IMP_PREFIX + "_" + item.GroupId
+	keys = append(keys, key)
+
+	//group retention policy and prune progress
+	key = nodeprefix + RTN_PREFIX + "_" + item.GroupId
+	keys = append(keys, key)
+	key = nodeprefix + PRN_PREFIX + "_" + item.GroupId
+	keys = append(keys, key)
+
+	//producer demotion policy
+	key = nodeprefix + DMP_PREFIX + "_" + item.GroupId
+	keys = append(keys, key)
+
+	//group config changes
+	key = nodeprefix + CFG_PREFIX + "_" + item.GroupId
+	keys = append(keys, key)
+
+	//light mode and trxs saved without data
+	key = nodeprefix + LGT_PREFIX + "_" + item.GroupId
+	keys = append(keys, key)
+
+	key = nodeprefix + LTX_PREFIX + "_" + item.GroupId
+	keys = append(keys, key)
+
+	//blob references of the group, chunks may be shared by other groups and are kept
+	key = nodeprefix + BRF_PREFIX + "_" + item.GroupId
+	keys = append(keys, key)
+
 	//remove all
 	for _, key_prefix := range keys {
 		err := dbMgr.Db.PrefixForeachKey([]byte(key_prefix), []byte(key_prefix), false, func(k []byte, err error) error {
@@ -396,31 +830,22 @@ func (dbMgr *DbMgr) RemoveGroupData(item *chestnutpb.GroupItem, prefix ...string
 		}
 	}
 
-	keys = nil
-	//remove all cached block
-	key = nodeprefix + BLK_PREFIX + "_"
-	keys = append(keys, key)
-	key = nodeprefix + CHD_PREFIX + "_" + BLK_PREFIX + "_"
-	keys = append(keys, key)
-
-	for _, key_prefix := range keys {
-		err := dbMgr.Db.PrefixForeach([]byte(key_prefix), func(k []byte, v []byte, err error) error {
+	//remove all blocks, cached blocks and trxs by the group indexes
+	dataPrefixes := map[string]string{
+		nodeprefix + GBK_PREFIX + "_" + item.GroupId + "_":                    nodeprefix + BLK_PREFIX + "_",
+		nodeprefix + CHD_PREFIX + "_" + GBK_PREFIX + "_" + item.GroupId + "_": nodeprefix + CHD_PREFIX + "_" + BLK_PREFIX + "_",
+		nodeprefix + GTX_PREFIX + "_" + item.GroupId + "_":                    nodeprefix + TRX_PREFIX + "_",
+	}
+	for idxPrefix, dataPrefix := range dataPrefixes {
+		err := dbMgr.Db.PrefixForeach([]byte(idxPrefix), func(k []byte, v []byte, err error) error {
 			if err != nil {
 				return err
 			}
-
-			blockChunk := chestnutpb.BlockDbChunk{}
-			perr := proto.Unmarshal(v, &blockChunk)
-			if perr != nil {
-				return perr
-			}
-
-			if blockChunk.BlockItem.GroupId == item.GroupId {
-				dbmgr_log.Debugf("Remove key %s", string(k))
-				return dbMgr.Db.Delete(k)
+			dbmgr_log.Debugf("Remove key %s", dataPrefix+string(v))
+			if err := dbMgr.Db.Delete([]byte(dataPrefix + string(v))); err != nil {
+				return err
 			}
-
-			return nil
+			return dbMgr.Db.Delete(k)
 		})
 
 		if err != nil {
@@ -428,50 +853,86 @@ func (dbMgr *DbMgr) RemoveGroupData(item *chestnutpb.GroupItem, prefix ...string
 		}
 	}
 
-	//remove all trx
-	key = nodeprefix + TRX_PREFIX + "_"
-	err := dbMgr.Db.PrefixForeach([]byte(key), func(k []byte, v []byte, err error) error {
+	//remove the other indexes
+	keys = []string{
+		nodeprefix + STX_PREFIX + "_" + item.GroupId + "_",
+		nodeprefix + BHT_PREFIX + "_" + item.GroupId + "_",
+		nodeprefix + BHS_PREFIX + "_" + item.GroupId + "_",
+	}
+	for _, key_prefix := range keys {
+		err := dbMgr.Db.PrefixForeachKey([]byte(key_prefix), []byte(key_prefix), false, func(k []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			return dbMgr.Db.Delete(k)
+		})
+
 		if err != nil {
 			return err
 		}
-
-		trx := chestnutpb.Trx{}
-		perr := proto.Unmarshal(v, &trx)
-
-		if perr != nil {
-			return perr
-		}
-
-		if trx.GroupId == item.GroupId {
-			dbmgr_log.Debugf("Remove key %s", string(k))
-			return dbMgr.Db.Delete(k)
-		}
-
-		return nil
-	})
-
-	if err != nil {
-		return err
 	}
 
 	return nil
 }
 
-// Get group list
-func (dbMgr *DbMgr) GetGroupsBytes() ([][]byte, error) {
+// Get group list, the groups of a tenant if prefix is given
+func (dbMgr *DbMgr) GetGroupsBytes(prefix ...string) ([][]byte, error) {
 	var groupItemList [][]byte
-	// test only, show db contents
-	err := dbMgr.GroupInfoDb.Foreach(func(k, v []byte, err error) error {
+	key := groupItemKey("", prefix...)
+	err := dbMgr.GroupInfoDb.PrefixForeach([]byte(key), func(k, v []byte, err error) error {
 		if err != nil {
 			return err
 		}
-
 		groupItemList = append(groupItemList, v)
 		return nil
 	})
 	return groupItemList, err
 }
 
+// groups of the node and of all tenants saved in the db, keyed by the tenant name, "" for the groups of the node
+func (dbMgr *DbMgr) GetAllGroupsBytes() (map[string][][]byte, error) {
+	result := make(map[string][][]byte)
+	itemPrefix := groupItemKey("")
+	err := dbMgr.GroupInfoDb.PrefixForeachKey([]byte{}, []byte{}, false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		//group items are saved as <tenant>_grpitem_<groupid>, or grpitem_<groupid> for the node
+		key := string(k)
+		tenant := ""
+		if !strings.HasPrefix(key, itemPrefix) {
+			idx := strings.Index(key, "_")
+			if idx < 0 || !strings.HasPrefix(key[idx+1:], itemPrefix) {
+				return nil
+			}
+			tenant = key[:idx]
+		}
+		v, err := dbMgr.GroupInfoDb.Get(k)
+		if err != nil {
+			return err
+		}
+		result[tenant] = append(result[tenant], v)
+		return nil
+	})
+	return result, err
+}
+
+// paused groups don't join the group channels when loaded
+func (dbMgr *DbMgr) SetGroupPaused(groupId string, paused bool, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + PAU_PREFIX + "_" + groupId
+	if !paused {
+		return dbMgr.Db.Delete([]byte(key))
+	}
+	return dbMgr.Db.Set([]byte(key), []byte(fmt.Sprint(time.Now().UnixNano())))
+}
+
+func (dbMgr *DbMgr) IsGroupPaused(groupId string, prefix ...string) (bool, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + PAU_PREFIX + "_" + groupId
+	return dbMgr.Db.IsExist([]byte(key))
+}
+
 // add post
 func (dbMgr *DbMgr) AddPost(trx *chestnutpb.Trx, prefix ...string) error {
 	nodeprefix := getPrefix(prefix...)
@@ -854,6 +1315,45 @@ func (dbMgr *DbMgr) GetSchemaByGroup(groupId, schemaType string, prefix ...strin
 	return &schema, err
 }
 
+// save how many blocks of the archive have been imported for the group, with the id of the archive
+func (dbMgr *DbMgr) UpdImportProgress(groupId string, archiveId string, count int64, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + IMP_PREFIX + "_" + groupId
+	return dbMgr.Db.Set([]byte(key), []byte(fmt.Sprintf("%s %d", archiveId, count)))
+}
+
+// get how many blocks of the archive have been imported for the group, 0 if no import of the
+// archive is in progress
+func (dbMgr *DbMgr) GetImportProgress(groupId string, archiveId string, prefix ...string) (int64, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + IMP_PREFIX + "_" + groupId
+
+	exist, err := dbMgr.Db.IsExist([]byte(key))
+	if err != nil {
+		return 0, err
+	}
+	if !exist {
+		return 0, nil
+	}
+
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err != nil {
+		return 0, err
+	}
+	//progress saved without the archive id or of another archive
+	fields := strings.Fields(string(value))
+	if len(fields) != 2 || fields[0] != archiveId {
+		return 0, nil
+	}
+	return strconv.ParseInt(fields[1], 10, 64)
+}
+
+func (dbMgr *DbMgr) RmImportProgress(groupId string, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + IMP_PREFIX + "_" + groupId
+	return dbMgr.Db.Delete([]byte(key))
+}
+
 func getPrefix(prefix ...string) string {
 	nodeprefix := ""
 	if len(prefix) == 1 {
@@ -0,0 +1,37 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const DMP_PREFIX = "dmp" //producer demotion policy
+
+func (dbMgr *DbMgr) UpdDemotionPolicy(item *chestnutpb.DemotionPolicyItem, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + DMP_PREFIX + "_" + item.GroupId
+	value, err := proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), value)
+}
+
+// get demotion policy of group, nil if no policy set (producers are never demoted)
+func (dbMgr *DbMgr) GetDemotionPolicy(groupId string, prefix ...string) (*chestnutpb.DemotionPolicyItem, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + DMP_PREFIX + "_" + groupId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	item := &chestnutpb.DemotionPolicyItem{}
+	if err := proto.Unmarshal(value, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
@@ -0,0 +1,57 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	"fmt"
+
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const CFG_PREFIX = "cfg" //group config changes, by effective height
+
+func (dbMgr *DbMgr) SaveGroupConfig(item *chestnutpb.ConfigItem, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + CFG_PREFIX + "_" + item.GroupId + "_" + fmt.Sprintf("%020d", item.EffectiveHeight)
+	value, err := proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), value)
+}
+
+// the config change effective at the height, nil if no change is effective yet
+func (dbMgr *DbMgr) GetGroupConfig(groupId string, height int64, prefix ...string) (*chestnutpb.ConfigItem, error) {
+	items, err := dbMgr.GetGroupConfigs(groupId, prefix...)
+	if err != nil {
+		return nil, err
+	}
+	var effective *chestnutpb.ConfigItem
+	for _, item := range items {
+		if item.EffectiveHeight > height {
+			continue
+		}
+		if effective == nil || item.EffectiveHeight > effective.EffectiveHeight {
+			effective = item
+		}
+	}
+	return effective, nil
+}
+
+func (dbMgr *DbMgr) GetGroupConfigs(groupId string, prefix ...string) ([]*chestnutpb.ConfigItem, error) {
+	var items []*chestnutpb.ConfigItem
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + CFG_PREFIX + "_" + groupId + "_"
+	err := dbMgr.Db.PrefixForeach([]byte(key), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		item := &chestnutpb.ConfigItem{}
+		if perr := proto.Unmarshal(v, item); perr != nil {
+			return perr
+		}
+		items = append(items, item)
+		return nil
+	})
+	return items, err
+}
@@ -0,0 +1,109 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const INV_PREFIX = "inv" //group invitation
+const RDM_PREFIX = "rdm" //invitation redeemed by a group user
+
+func (dbMgr *DbMgr) SaveInvite(item *chestnutpb.InviteItem, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + INV_PREFIX + "_" + item.GroupId + "_" + item.InviteId
+	value, err := proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), value)
+}
+
+// nil if the invitation is not on chain
+func (dbMgr *DbMgr) GetInvite(groupId, inviteId string, prefix ...string) (*chestnutpb.InviteItem, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + INV_PREFIX + "_" + groupId + "_" + inviteId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	item := &chestnutpb.InviteItem{}
+	if err := proto.Unmarshal(value, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (dbMgr *DbMgr) GetInvites(groupId string, prefix ...string) ([]*chestnutpb.InviteItem, error) {
+	var invites []*chestnutpb.InviteItem
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + INV_PREFIX + "_" + groupId + "_"
+	err := dbMgr.Db.PrefixForeach([]byte(key), func(k []byte, v []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		item := &chestnutpb.InviteItem{}
+		if perr := proto.Unmarshal(v, item); perr != nil {
+			return perr
+		}
+		invites = append(invites, item)
+		return nil
+	})
+	return invites, err
+}
+
+func (dbMgr *DbMgr) SaveRedeem(item *chestnutpb.RedeemItem, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + RDM_PREFIX + "_" + item.GroupId + "_" + item.RedeemerPubkey
+	value, err := proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), value)
+}
+
+func (dbMgr *DbMgr) IsRedeemed(groupId, redeemerPubkey string, prefix ...string) (bool, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + RDM_PREFIX + "_" + groupId + "_" + redeemerPubkey
+	return dbMgr.Db.IsExist([]byte(key))
+}
+
+// result of an announce, nil error if the announce doesn't exist
+func (dbMgr *DbMgr) UpdateAnnounceResult(groupId string, announceType chestnutpb.AnnounceType, signPubkey string, result chestnutpb.ApproveType, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + ANN_PREFIX + "_" + groupId + "_" + announceType.String() + "_" + signPubkey
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	item := &chestnutpb.AnnounceItem{}
+	if err := proto.Unmarshal(value, item); err != nil {
+		return err
+	}
+	item.Result = result
+	value, err = proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), value)
+}
+
+func (dbMgr *DbMgr) GetAnnounceResult(groupId string, announceType chestnutpb.AnnounceType, signPubkey string, prefix ...string) (chestnutpb.ApproveType, bool, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + ANN_PREFIX + "_" + groupId + "_" + announceType.String() + "_" + signPubkey
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return chestnutpb.ApproveType_ANNOUNCED, false, nil
+	} else if err != nil {
+		return chestnutpb.ApproveType_ANNOUNCED, false, err
+	}
+	item := &chestnutpb.AnnounceItem{}
+	if err := proto.Unmarshal(value, item); err != nil {
+		return chestnutpb.ApproveType_ANNOUNCED, false, err
+	}
+	return item.Result, true, nil
+}
@@ -0,0 +1,84 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const LGT_PREFIX = "lgt" //light mode of group
+const LTX_PREFIX = "ltx" //trxs saved without data by a light node, to the block packed in
+
+func (dbMgr *DbMgr) UpdLightMode(item *chestnutpb.LightModeItem, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + LGT_PREFIX + "_" + item.GroupId
+	value, err := proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), value)
+}
+
+// get light mode of group, nil if the group is synced with full blocks
+func (dbMgr *DbMgr) GetLightMode(groupId string, prefix ...string) (*chestnutpb.LightModeItem, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + LGT_PREFIX + "_" + groupId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	item := &chestnutpb.LightModeItem{}
+	if err := proto.Unmarshal(value, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (dbMgr *DbMgr) RmLightMode(groupId string, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + LGT_PREFIX + "_" + groupId
+	return dbMgr.Db.Delete([]byte(key))
+}
+
+func (dbMgr *DbMgr) AddLightTrx(groupId string, trxId string, blockId string, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + LTX_PREFIX + "_" + groupId + "_" + trxId
+	return dbMgr.Db.Set([]byte(key), []byte(blockId))
+}
+
+// block of a trx saved without data, "" if the trx data is saved
+func (dbMgr *DbMgr) GetLightTrxBlock(groupId string, trxId string, prefix ...string) (string, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + LTX_PREFIX + "_" + groupId + "_" + trxId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return "", nil
+	} else if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func (dbMgr *DbMgr) RmLightTrx(groupId string, trxId string, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + LTX_PREFIX + "_" + groupId + "_" + trxId
+	return dbMgr.Db.Delete([]byte(key))
+}
+
+// number of trxs saved without data
+func (dbMgr *DbMgr) CountLightTrxs(groupId string, prefix ...string) (int64, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + LTX_PREFIX + "_" + groupId + "_"
+	var count int64
+	err := dbMgr.Db.PrefixForeachKey([]byte(key), []byte(key), false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
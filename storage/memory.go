@@ -0,0 +1,296 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CSMemory is a in-memory storage, all data are lost after Close, for test only
+type CSMemory struct {
+	mu   sync.RWMutex
+	data map[string][]byte
+	keys []string //sorted
+}
+
+// CSMemoryTxn buffers writes until Commit, reads see the buffered writes
+type CSMemoryTxn struct {
+	db     *CSMemory
+	writes map[string][]byte //nil value means deleted
+	done   bool
+}
+
+func (s *CSMemory) Init(path string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = make(map[string][]byte)
+	s.keys = nil
+	return nil
+}
+
+func (s *CSMemory) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data = nil
+	s.keys = nil
+	return nil
+}
+
+func (s *CSMemory) Set(key []byte, val []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.set(string(key), copyBytes(val))
+	return nil
+}
+
+func (s *CSMemory) Delete(key []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.delete(string(key))
+	return nil
+}
+
+func (s *CSMemory) Get(key []byte) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	val, ok := s.data[string(key)]
+	if !ok {
+		return nil, ErrKeyNotFound
+	}
+	return copyBytes(val), nil
+}
+
+func (s *CSMemory) IsExist(key []byte) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.prefixKeys(string(key))) > 0, nil
+}
+
+func (s *CSMemory) PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error {
+	return s.PrefixForeachKey(prefix, prefix, false, func(k []byte, err error) error {
+		val, err := s.Get(k)
+		if err == ErrKeyNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		return fn(k, val, nil)
+	})
+}
+
+func (s *CSMemory) PrefixForeachKey(prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) error {
+	s.mu.RLock()
+	keys := seekKeys(s.prefixKeys(string(valid)), string(prefix), reverse)
+	s.mu.RUnlock()
+
+	for _, k := range keys {
+		if err := fn([]byte(k), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *CSMemory) Foreach(fn func([]byte, []byte, error) error) error {
+	return s.PrefixForeach([]byte{}, fn)
+}
+
+func (s *CSMemory) BatchWrite(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("keys' and values' length should be equal")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, k := range keys {
+		s.set(string(k), copyBytes(values[i]))
+	}
+	return nil
+}
+
+func (s *CSMemory) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
+	return newLeaseSequence(s, key, bandwidth)
+}
+
+func (s *CSMemory) BeginTxn() (ChestnutTxn, error) {
+	return &CSMemoryTxn{db: s, writes: make(map[string][]byte)}, nil
+}
+
+// must be called with lock held
+func (s *CSMemory) set(key string, val []byte) {
+	if _, ok := s.data[key]; !ok {
+		i := sort.SearchStrings(s.keys, key)
+		s.keys = append(s.keys, "")
+		copy(s.keys[i+1:], s.keys[i:])
+		s.keys[i] = key
+	}
+	s.data[key] = val
+}
+
+// must be called with lock held
+func (s *CSMemory) delete(key string) {
+	if _, ok := s.data[key]; !ok {
+		return
+	}
+	delete(s.data, key)
+	i := sort.SearchStrings(s.keys, key)
+	s.keys = append(s.keys[:i], s.keys[i+1:]...)
+}
+
+// sorted keys with prefix, must be called with lock held
+func (s *CSMemory) prefixKeys(prefix string) []string {
+	start := sort.SearchStrings(s.keys, prefix)
+	end := start
+	for end < len(s.keys) && strings.HasPrefix(s.keys[end], prefix) {
+		end++
+	}
+	result := make([]string, end-start)
+	copy(result, s.keys[start:end])
+	return result
+}
+
+func (t *CSMemoryTxn) Init(path string) error {
+	return errors.New("can not init a transaction")
+}
+
+// Close a transaction equals to rollback
+func (t *CSMemoryTxn) Close() error {
+	t.Rollback()
+	return nil
+}
+
+func (t *CSMemoryTxn) Set(key []byte, val []byte) error {
+	t.writes[string(key)] = copyBytes(val)
+	return nil
+}
+
+func (t *CSMemoryTxn) Delete(key []byte) error {
+	t.writes[string(key)] = nil
+	return nil
+}
+
+func (t *CSMemoryTxn) Get(key []byte) ([]byte, error) {
+	if val, ok := t.writes[string(key)]; ok {
+		if val == nil {
+			return nil, ErrKeyNotFound
+		}
+		return copyBytes(val), nil
+	}
+	return t.db.Get(key)
+}
+
+func (t *CSMemoryTxn) IsExist(key []byte) (bool, error) {
+	return len(t.prefixKeys(string(key))) > 0, nil
+}
+
+func (t *CSMemoryTxn) PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error {
+	return t.PrefixForeachKey(prefix, prefix, false, func(k []byte, err error) error {
+		val, err := t.Get(k)
+		if err != nil {
+			return err
+		}
+		return fn(k, val, nil)
+	})
+}
+
+func (t *CSMemoryTxn) PrefixForeachKey(prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) error {
+	for _, k := range seekKeys(t.prefixKeys(string(valid)), string(prefix), reverse) {
+		if err := fn([]byte(k), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *CSMemoryTxn) Foreach(fn func([]byte, []byte, error) error) error {
+	return t.PrefixForeach([]byte{}, fn)
+}
+
+func (t *CSMemoryTxn) BatchWrite(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("keys' and values' length should be equal")
+	}
+	for i, k := range keys {
+		t.writes[string(k)] = copyBytes(values[i])
+	}
+	return nil
+}
+
+func (t *CSMemoryTxn) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
+	return nil, errors.New("sequence is not supported in transaction")
+}
+
+func (t *CSMemoryTxn) BeginTxn() (ChestnutTxn, error) {
+	return nil, errors.New("nested transaction is not supported")
+}
+
+func (t *CSMemoryTxn) Commit() error {
+	if t.done {
+		return errors.New("transaction already committed or rolled back")
+	}
+	t.done = true
+
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	for k, v := range t.writes {
+		if v == nil {
+			t.db.delete(k)
+		} else {
+			t.db.set(k, v)
+		}
+	}
+	return nil
+}
+
+func (t *CSMemoryTxn) Rollback() {
+	t.done = true
+	t.writes = make(map[string][]byte)
+}
+
+// sorted keys with prefix in db and buffered writes
+func (t *CSMemoryTxn) prefixKeys(prefix string) []string {
+	t.db.mu.RLock()
+	keys := t.db.prefixKeys(prefix)
+	t.db.mu.RUnlock()
+
+	keyset := make(map[string]bool)
+	for _, k := range keys {
+		keyset[k] = true
+	}
+	for k, v := range t.writes {
+		if strings.HasPrefix(k, prefix) {
+			keyset[k] = v != nil
+		}
+	}
+
+	result := []string{}
+	for k, exist := range keyset {
+		if exist {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// keys in iteration order, start from the first key >= seek, or the last key <= seek if reverse
+func seekKeys(sorted []string, seek string, reverse bool) []string {
+	if !reverse {
+		return sorted[sort.SearchStrings(sorted, seek):]
+	}
+
+	end := sort.Search(len(sorted), func(i int) bool { return sorted[i] > seek })
+	result := make([]string, 0, end)
+	for i := end - 1; i >= 0; i-- {
+		result = append(result, sorted[i])
+	}
+	return result
+}
+
+func copyBytes(b []byte) []byte {
+	result := make([]byte, len(b))
+	copy(result, b)
+	return result
+}
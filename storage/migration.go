@@ -0,0 +1,220 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	logging "github.com/ipfs/go-log/v2"
+)
+
+var migration_log = logging.Logger("migration")
+
+// names of the dbs with a schema version
+const (
+	GROUPINFO_DB = "groupinfo"
+	CHAIN_DB     = "chain"
+	APP_DB       = "app"
+)
+
+const DATAVER_PREFIX = "dbver_" //schema version of a db, key is DATAVER_PREFIX + db name
+
+// max keys scanned, and max keys written, in one chunk of a migration
+const MIGRATION_CHUNK_SIZE = 1000
+
+var errMigrationChunkFull = errors.New("migration chunk is full")
+
+// MigrateKeyFunc returns the keys/values should be written for a key/value of the db, a nil value
+// removes the key. The db is walked in key order and written in chunks, each chunk is written
+// together with the key it ends at, so an interrupted migration resumes from the last chunk written.
+type MigrateKeyFunc func(key []byte, value []byte) (keys [][]byte, values [][]byte, err error)
+
+type Migration struct {
+	Version    int
+	Name       string
+	MigrateKey MigrateKeyFunc
+}
+
+// ordered migrations of each db, the schema version of a db is the number of migrations applied
+var migrations = make(map[string][]*Migration)
+
+// Register a migration for db, the version of the migration is the next version of the db.
+// Migrations must be registered in order and never be removed or reordered.
+func RegisterMigration(dbname string, name string, fn MigrateKeyFunc) {
+	m := &Migration{Version: len(migrations[dbname]) + 1, Name: name, MigrateKey: fn}
+	migrations[dbname] = append(migrations[dbname], m)
+}
+
+// The schema version this binary supports for db
+func SchemaVersion(dbname string) int {
+	return len(migrations[dbname])
+}
+
+func IsDataVersionKey(key []byte) bool {
+	return len(key) >= len(DATAVER_PREFIX) && string(key[:len(DATAVER_PREFIX)]) == DATAVER_PREFIX
+}
+
+func dataVersionKey(dbname string) []byte {
+	return []byte(DATAVER_PREFIX + dbname)
+}
+
+// Get the schema version saved in db, a db without version (created by old binary) is version 0
+func GetDataVersion(dbname string, db ChestnutStorage) (int, error) {
+	ver, _, err := getMigrationProgress(dbname, db)
+	return ver, err
+}
+
+// The schema version and the key the running migration has walked to, the version key is
+// "<version>" when no migration is running, or "<version>:<hex key>" during a chunked migration
+func getMigrationProgress(dbname string, db ChestnutStorage) (int, []byte, error) {
+	key := dataVersionKey(dbname)
+	exist, err := db.IsExist(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !exist {
+		return 0, nil, nil
+	}
+
+	value, err := db.Get(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	parts := strings.SplitN(string(value), ":", 2)
+	ver, err := strconv.Atoi(parts[0])
+	if err != nil || len(parts) == 1 {
+		return ver, nil, err
+	}
+	progress, err := hex.DecodeString(parts[1])
+	return ver, progress, err
+}
+
+func migrationProgressValue(ver int, progress []byte) []byte {
+	if progress == nil {
+		return []byte(strconv.Itoa(ver))
+	}
+	return []byte(strconv.Itoa(ver) + ":" + hex.EncodeToString(progress))
+}
+
+// Run all pending migrations of db in order.
+// Each migration is committed in chunks together with the migration progress, an interrupted
+// migration will be run again from the last committed chunk on next start.
+// Returns error if the db was written by a newer binary.
+func Migrate(dbname string, db ChestnutStorage) error {
+	ver, progress, err := getMigrationProgress(dbname, db)
+	if err != nil {
+		return err
+	}
+
+	target := SchemaVersion(dbname)
+	if ver > target {
+		return fmt.Errorf("%s db schema version %d is newer than supported version %d, please upgrade", dbname, ver, target)
+	}
+
+	if ver == target {
+		migration_log.Debugf("<%s> db schema version %d, no migration needed", dbname, ver)
+		return setDataVersion(dbname, db, ver)
+	}
+
+	migration_log.Infof("<%s> db schema version %d, migrate to %d", dbname, ver, target)
+	for _, m := range migrations[dbname][ver:] {
+		migration_log.Infof("<%s> migration %d/%d: %s", dbname, m.Version, target, m.Name)
+		if err := migrateKeys(dbname, db, m, progress); err != nil {
+			return fmt.Errorf("%s db migration %d (%s) failed: %s", dbname, m.Version, m.Name, err)
+		}
+		progress = nil
+	}
+	return nil
+}
+
+// walk the keys after progress and write the migrated keys in chunks, each chunk is written with
+// the last key walked. The version of the migration is saved with the last chunk.
+func migrateKeys(dbname string, db ChestnutStorage, m *Migration, progress []byte) error {
+	if progress != nil {
+		migration_log.Infof("<%s> migration %d resumed after key %s", dbname, m.Version, string(progress))
+	}
+	total := 0
+	for {
+		var keys, values [][]byte
+		var last []byte
+		scanned := 0
+		err := db.PrefixForeachKey(progress, []byte{}, false, func(k []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			if progress != nil && bytes.Equal(k, progress) {
+				return nil
+			}
+			if scanned >= MIGRATION_CHUNK_SIZE || len(keys) >= MIGRATION_CHUNK_SIZE {
+				return errMigrationChunkFull
+			}
+			scanned++
+			last = k
+			if IsDataVersionKey(k) {
+				return nil
+			}
+			v, err := db.Get(k)
+			if err == ErrKeyNotFound {
+				return nil
+			} else if err != nil {
+				return err
+			}
+			ks, vs, err := m.MigrateKey(k, v)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, ks...)
+			values = append(values, vs...)
+			return nil
+		})
+		if err != nil && err != errMigrationChunkFull {
+			return err
+		}
+
+		done := err == nil
+		total += len(keys)
+		keys = append(keys, dataVersionKey(dbname))
+		if done {
+			values = append(values, migrationProgressValue(m.Version, nil))
+		} else {
+			values = append(values, migrationProgressValue(m.Version-1, last))
+		}
+		if err := writeMigrationChunk(db, keys, values); err != nil {
+			return fmt.Errorf("commit failed: %s", err)
+		}
+		if done {
+			migration_log.Infof("<%s> migration %d done, %d keys updated", dbname, m.Version, total)
+			return nil
+		}
+		migration_log.Debugf("<%s> migration %d, %d keys updated", dbname, m.Version, total)
+		progress = last
+	}
+}
+
+// write a chunk of a migration in one transaction, keys with a nil value are removed
+func writeMigrationChunk(db ChestnutStorage, keys [][]byte, values [][]byte) error {
+	txn, err := db.BeginTxn()
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+	for i, k := range keys {
+		if values[i] == nil {
+			err = txn.Delete(k)
+		} else {
+			err = txn.Set(k, values[i])
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return txn.Commit()
+}
+
+func setDataVersion(dbname string, db ChestnutStorage, ver int) error {
+	return db.Set(dataVersionKey(dbname), []byte(strconv.Itoa(ver)))
+}
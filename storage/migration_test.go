@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// a chunked migration interrupted after some chunks resumes from the progress saved with the version
+func TestMigrateKeysResume(t *testing.T) {
+	const dbname = "test_resume"
+	calls := 0
+	failAt := MIGRATION_CHUNK_SIZE*2 + 10
+	RegisterMigration(dbname, "copy src keys", func(k []byte, v []byte) ([][]byte, [][]byte, error) {
+		if !strings.HasPrefix(string(k), "src_") {
+			return nil, nil, nil
+		}
+		calls++
+		if calls == failAt {
+			return nil, nil, errors.New("interrupted")
+		}
+		return [][]byte{[]byte("dst_" + string(k[4:]))}, [][]byte{v}, nil
+	})
+	defer delete(migrations, dbname)
+
+	db := &CSMemory{}
+	db.Init("")
+	total := MIGRATION_CHUNK_SIZE*3 + 5
+	for i := 0; i < total; i++ {
+		setKeys(t, db, fmt.Sprintf("src_%05d", i))
+	}
+
+	if err := Migrate(dbname, db); err == nil {
+		t.Fatal("interrupted migration should fail")
+	}
+	ver, progress, err := getMigrationProgress(dbname, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ver != 0 || progress == nil {
+		t.Fatalf("progress after interruption: version %d, key %q", ver, progress)
+	}
+	written := len(prefixKeys(t, db, "dst_", "dst_", false))
+	if written != MIGRATION_CHUNK_SIZE*2 {
+		t.Fatalf("%d keys written before interruption, want %d", written, MIGRATION_CHUNK_SIZE*2)
+	}
+
+	calls = 0
+	if err := Migrate(dbname, db); err != nil {
+		t.Fatal(err)
+	}
+	// only the keys after the saved progress are walked again
+	if calls != total-MIGRATION_CHUNK_SIZE*2 {
+		t.Fatalf("%d keys migrated after resume, want %d", calls, total-MIGRATION_CHUNK_SIZE*2)
+	}
+	if written := len(prefixKeys(t, db, "dst_", "dst_", false)); written != total {
+		t.Fatalf("%d keys written, want %d", written, total)
+	}
+	ver, progress, err = getMigrationProgress(dbname, db)
+	if err != nil || ver != 1 || progress != nil {
+		t.Fatalf("progress after migration: version %d, key %q, err %v", ver, progress, err)
+	}
+	if val, _ := db.Get([]byte("dst_00042")); string(val) != "v_src_00042" {
+		t.Fatalf("migrated value %q", val)
+	}
+}
@@ -0,0 +1,229 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	"errors"
+	"io"
+
+	"github.com/cockroachdb/pebble"
+)
+
+type CSPebble struct {
+	db *pebble.DB
+}
+
+// CSPebbleTxn is a indexed pebble batch, reads see the writes of the batch
+type CSPebbleTxn struct {
+	batch *pebble.Batch
+	done  bool
+}
+
+// read methods shared by pebble.DB and indexed pebble.Batch
+type pebbleReader interface {
+	Get(key []byte) ([]byte, io.Closer, error)
+	NewIter(o *pebble.IterOptions) *pebble.Iterator
+}
+
+func (s *CSPebble) Init(path string) error {
+	var err error
+	s.db, err = pebble.Open(path, &pebble.Options{})
+	return err
+}
+
+func (s *CSPebble) Close() error {
+	return s.db.Close()
+}
+
+func (s *CSPebble) Set(key []byte, val []byte) error {
+	return s.db.Set(key, val, pebble.Sync)
+}
+
+func (s *CSPebble) Delete(key []byte) error {
+	return s.db.Delete(key, pebble.Sync)
+}
+
+func (s *CSPebble) Get(key []byte) ([]byte, error) {
+	return pebbleGet(s.db, key)
+}
+
+func (s *CSPebble) IsExist(key []byte) (bool, error) {
+	return pebbleIsExist(s.db, key)
+}
+
+func (s *CSPebble) PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error {
+	return pebblePrefixForeach(s.db, prefix, fn)
+}
+
+func (s *CSPebble) PrefixForeachKey(prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) error {
+	return pebblePrefixForeachKey(s.db, prefix, valid, reverse, fn)
+}
+
+func (s *CSPebble) Foreach(fn func([]byte, []byte, error) error) error {
+	return pebblePrefixForeach(s.db, []byte{}, fn)
+}
+
+func (s *CSPebble) BatchWrite(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("keys' and values' length should be equal")
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+	for i, k := range keys {
+		if err := batch.Set(k, values[i], nil); err != nil {
+			return err
+		}
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+func (s *CSPebble) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
+	return newLeaseSequence(s, key, bandwidth)
+}
+
+func (s *CSPebble) BeginTxn() (ChestnutTxn, error) {
+	return &CSPebbleTxn{batch: s.db.NewIndexedBatch()}, nil
+}
+
+func (t *CSPebbleTxn) Init(path string) error {
+	return errors.New("can not init a transaction")
+}
+
+// Close a transaction equals to rollback
+func (t *CSPebbleTxn) Close() error {
+	t.Rollback()
+	return nil
+}
+
+func (t *CSPebbleTxn) Set(key []byte, val []byte) error {
+	return t.batch.Set(key, val, nil)
+}
+
+func (t *CSPebbleTxn) Delete(key []byte) error {
+	return t.batch.Delete(key, nil)
+}
+
+func (t *CSPebbleTxn) Get(key []byte) ([]byte, error) {
+	return pebbleGet(t.batch, key)
+}
+
+func (t *CSPebbleTxn) IsExist(key []byte) (bool, error) {
+	return pebbleIsExist(t.batch, key)
+}
+
+func (t *CSPebbleTxn) PrefixForeach(prefix []byte, fn func([]byte, []byte, error) error) error {
+	return pebblePrefixForeach(t.batch, prefix, fn)
+}
+
+func (t *CSPebbleTxn) PrefixForeachKey(prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) error {
+	return pebblePrefixForeachKey(t.batch, prefix, valid, reverse, fn)
+}
+
+func (t *CSPebbleTxn) Foreach(fn func([]byte, []byte, error) error) error {
+	return pebblePrefixForeach(t.batch, []byte{}, fn)
+}
+
+func (t *CSPebbleTxn) BatchWrite(keys [][]byte, values [][]byte) error {
+	if len(keys) != len(values) {
+		return errors.New("keys' and values' length should be equal")
+	}
+	for i, k := range keys {
+		if err := t.batch.Set(k, values[i], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *CSPebbleTxn) GetSequence(key []byte, bandwidth uint64) (Sequence, error) {
+	return nil, errors.New("sequence is not supported in transaction")
+}
+
+func (t *CSPebbleTxn) BeginTxn() (ChestnutTxn, error) {
+	return nil, errors.New("nested transaction is not supported")
+}
+
+func (t *CSPebbleTxn) Commit() error {
+	if t.done {
+		return errors.New("transaction already committed or rolled back")
+	}
+	t.done = true
+	defer t.batch.Close()
+	return t.batch.Commit(pebble.Sync)
+}
+
+func (t *CSPebbleTxn) Rollback() {
+	if t.done {
+		return
+	}
+	t.done = true
+	t.batch.Close()
+}
+
+func pebbleGet(r pebbleReader, key []byte) ([]byte, error) {
+	val, closer, err := r.Get(key)
+	if err == pebble.ErrNotFound {
+		return nil, ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	return copyBytes(val), nil
+}
+
+func pebbleIsExist(r pebbleReader, key []byte) (bool, error) {
+	it := r.NewIter(&pebble.IterOptions{LowerBound: key, UpperBound: prefixUpperBound(key)})
+	defer it.Close()
+	return it.First(), nil
+}
+
+func pebblePrefixForeach(r pebbleReader, prefix []byte, fn func([]byte, []byte, error) error) error {
+	it := r.NewIter(&pebble.IterOptions{LowerBound: prefix, UpperBound: prefixUpperBound(prefix)})
+	defer it.Close()
+	for it.First(); it.Valid(); it.Next() {
+		ferr := fn(copyBytes(it.Key()), copyBytes(it.Value()), nil)
+		if ferr != nil {
+			return ferr
+		}
+	}
+	return nil
+}
+
+// same as badger: seek to the first key >= prefix (or the last key <= prefix if reverse), iterate keys with prefix valid
+func pebblePrefixForeachKey(r pebbleReader, prefix []byte, valid []byte, reverse bool, fn func([]byte, error) error) error {
+	it := r.NewIter(&pebble.IterOptions{LowerBound: valid, UpperBound: prefixUpperBound(valid)})
+	defer it.Close()
+
+	var ok bool
+	if reverse {
+		//the key right after prefix is prefix + 0x00, so SeekLT finds the last key <= prefix
+		ok = it.SeekLT(append(copyBytes(prefix), 0x00))
+	} else {
+		ok = it.SeekGE(prefix)
+	}
+	for ok {
+		ferr := fn(copyBytes(it.Key()), nil)
+		if ferr != nil {
+			return ferr
+		}
+		if reverse {
+			ok = it.Prev()
+		} else {
+			ok = it.Next()
+		}
+	}
+	return nil
+}
+
+// the smallest key greater than all keys with prefix, nil means no upper bound
+func prefixUpperBound(prefix []byte) []byte {
+	end := copyBytes(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
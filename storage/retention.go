@@ -0,0 +1,263 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+const RTN_PREFIX = "rtn" //retention policy
+const PRN_PREFIX = "prn" //prune progress, the highest pruned height
+
+// blocks near the chain head are never pruned, a fork may still be merged there and
+// syncing peers ask the producers for the latest blocks
+const PRUNE_MIN_KEEP_BLOCKS int64 = 100
+
+// strips the trx data of a block to prune, the block hash and signature must stay verifiable
+type BlockStripFunc func(block *chestnutpb.Block) (*chestnutpb.Block, error)
+
+// storage supports garbage collection of deleted data, e.g. badger value log GC
+type GCStorage interface {
+	RunGC() (int64, error)
+}
+
+func (dbMgr *DbMgr) UpdRetention(item *chestnutpb.RetentionItem, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + RTN_PREFIX + "_" + item.GroupId
+	value, err := proto.Marshal(item)
+	if err != nil {
+		return err
+	}
+	return dbMgr.Db.Set([]byte(key), value)
+}
+
+// get retention policy of group, nil if no policy set (keep everything)
+func (dbMgr *DbMgr) GetRetention(groupId string, prefix ...string) (*chestnutpb.RetentionItem, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + RTN_PREFIX + "_" + groupId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	item := &chestnutpb.RetentionItem{}
+	if err := proto.Unmarshal(value, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+func (dbMgr *DbMgr) RmRetention(groupId string, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + RTN_PREFIX + "_" + groupId
+	return dbMgr.Db.Delete([]byte(key))
+}
+
+func (dbMgr *DbMgr) IsBlockPruned(blockId string, prefix ...string) (bool, error) {
+	chunk, err := dbMgr.getBlockChunk(blockId, false, prefix...)
+	if err != nil {
+		return false, err
+	}
+	return chunk.Pruned, nil
+}
+
+// Remove trx data and post bodies of all blocks not kept by the retention policy.
+// Block headers, hashes, signatures and trx ids are kept so the chain is still verifiable,
+// the trx data of a block is removed by strip. The last PRUNE_MIN_KEEP_BLOCKS blocks are kept.
+// Blocks are pruned by height, each block in its own transaction.
+// Returns the number of pruned blocks, the bytes removed and the ids of the POST trxs pruned.
+func (dbMgr *DbMgr) PruneGroup(item *chestnutpb.GroupItem, policy *chestnutpb.RetentionItem, strip BlockStripFunc, prefix ...string) (int64, int64, []string, error) {
+	if policy == nil || (policy.KeepDays <= 0 && policy.KeepBlocks <= 0 && !policy.HeadersOnly) {
+		return 0, 0, nil, nil
+	}
+
+	nodeprefix := getPrefix(prefix...)
+	pruned, err := dbMgr.getPruneHeight(item.GroupId, prefix...)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	maxHeight := item.HighestHeight
+	if policy.KeepBlocks > 0 && !policy.HeadersOnly {
+		maxHeight = item.HighestHeight - policy.KeepBlocks
+	}
+	if maxHeight > item.HighestHeight-PRUNE_MIN_KEEP_BLOCKS {
+		maxHeight = item.HighestHeight - PRUNE_MIN_KEEP_BLOCKS
+	}
+	var cutoff int64
+	if policy.KeepDays > 0 && !policy.HeadersOnly {
+		cutoff = time.Now().Add(-time.Duration(policy.KeepDays) * 24 * time.Hour).UnixNano()
+	}
+
+	//genesis block has no trx, start from height 1
+	if pruned < 0 {
+		pruned = 0
+	}
+
+	key := nodeprefix + BHT_PREFIX + "_" + item.GroupId + "_"
+	seek := key + fmt.Sprintf("%019d", pruned+1)
+	var blockIds []string
+	var heights []int64
+	err = dbMgr.Db.PrefixForeachKey([]byte(seek), []byte(key), false, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		keystr := string(k[len(key):])
+		height, err := strconv.ParseInt(keystr[:19], 10, 64)
+		if err != nil {
+			return err
+		}
+		if height > maxHeight {
+			return errors.New("OK")
+		}
+		blockIds = append(blockIds, keystr[20:])
+		heights = append(heights, height)
+		return nil
+	})
+	if err != nil && err.Error() != "OK" {
+		return 0, 0, nil, err
+	}
+
+	var blocks, reclaimed int64
+	var trxIds []string
+	for i, blockId := range blockIds {
+		//all blocks at the same height are pruned before the height is saved
+		lastOfHeight := i == len(blockIds)-1 || heights[i+1] != heights[i]
+
+		txn, err := dbMgr.BeginTxn()
+		if err != nil {
+			return blocks, reclaimed, trxIds, err
+		}
+		size, posts, done, err := txn.pruneBlock(blockId, cutoff, strip, prefix...)
+		if err == nil && !done {
+			//block is newer than the cutoff, blocks after it are kept too
+			txn.Rollback()
+			break
+		}
+		if err == nil && lastOfHeight {
+			err = txn.updPruneHeight(item.GroupId, heights[i], prefix...)
+		}
+		if err == nil {
+			err = txn.Commit()
+		}
+		if err != nil {
+			txn.Rollback()
+			return blocks, reclaimed, trxIds, err
+		}
+		if size > 0 {
+			blocks++
+			reclaimed += size
+		}
+		trxIds = append(trxIds, posts...)
+	}
+
+	return blocks, reclaimed, trxIds, nil
+}
+
+// prune a block, done is false if the block is newer than cutoff (if cutoff is set).
+// The block is marked as pruned only if strip removed its trx data, a block whose hash covers
+// the trx data keeps it. Returns the bytes removed and the ids of the POST trxs pruned.
+func (dbMgr *DbMgr) pruneBlock(blockId string, cutoff int64, strip BlockStripFunc, prefix ...string) (int64, []string, bool, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + BLK_PREFIX + "_" + blockId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err != nil {
+		return 0, nil, false, err
+	}
+	chunk := &chestnutpb.BlockDbChunk{}
+	if err := proto.Unmarshal(value, chunk); err != nil {
+		return 0, nil, false, err
+	}
+	if chunk.Pruned {
+		return 0, nil, true, nil
+	}
+	if cutoff > 0 && chunk.BlockItem.TimeStamp > cutoff {
+		return 0, nil, false, nil
+	}
+
+	var reclaimed int64
+	var posts []string
+	for _, trx := range chunk.BlockItem.Trxs {
+		if trx.Type == chestnutpb.TrxType_POST {
+			posts = append(posts, trx.TrxId)
+			postKey := []byte(nodeprefix + GRP_PREFIX + "_" + CNT_PREFIX + "_" + trx.GroupId + "_" + fmt.Sprint(trx.TimeStamp) + "_" + trx.TrxId)
+			if post, err := dbMgr.Db.Get(postKey); err == nil {
+				reclaimed += int64(len(post))
+				if err := dbMgr.Db.Delete(postKey); err != nil {
+					return 0, nil, false, err
+				}
+			}
+		}
+
+		trxKey := []byte(nodeprefix + TRX_PREFIX + "_" + trx.TrxId)
+		if trxBytes, err := dbMgr.Db.Get(trxKey); err == nil {
+			savedTrx := &chestnutpb.Trx{}
+			if err := proto.Unmarshal(trxBytes, savedTrx); err != nil {
+				return 0, nil, false, err
+			}
+			reclaimed += int64(len(savedTrx.Data))
+			savedTrx.Data = nil
+			if err := dbMgr.AddTrx(savedTrx, prefix...); err != nil {
+				return 0, nil, false, err
+			}
+		}
+	}
+
+	stripped, err := strip(chunk.BlockItem)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	if stripped != chunk.BlockItem {
+		chunk.BlockItem = stripped
+		chunk.Pruned = true
+	}
+	newValue, err := proto.Marshal(chunk)
+	if err != nil {
+		return 0, nil, false, err
+	}
+	reclaimed += int64(len(value) - len(newValue))
+	return reclaimed, posts, true, dbMgr.Db.Set([]byte(key), newValue)
+}
+
+func (dbMgr *DbMgr) getPruneHeight(groupId string, prefix ...string) (int64, error) {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + PRN_PREFIX + "_" + groupId
+	value, err := dbMgr.Db.Get([]byte(key))
+	if err == ErrKeyNotFound {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(value), 10, 64)
+}
+
+func (dbMgr *DbMgr) updPruneHeight(groupId string, height int64, prefix ...string) error {
+	nodeprefix := getPrefix(prefix...)
+	key := nodeprefix + PRN_PREFIX + "_" + groupId
+	return dbMgr.Db.Set([]byte(key), []byte(fmt.Sprint(height)))
+}
+
+// run garbage collection of all storages support it, returns bytes reclaimed
+func (dbMgr *DbMgr) RunGC() (int64, error) {
+	var reclaimed int64
+	for _, db := range []ChestnutStorage{dbMgr.Db, dbMgr.GroupInfoDb} {
+		if gcdb, ok := db.(GCStorage); ok {
+			size, err := gcdb.RunGC()
+			if err != nil {
+				return reclaimed, err
+			}
+			reclaimed += size
+		}
+		if dbMgr.GroupInfoDb == dbMgr.Db {
+			break
+		}
+	}
+	return reclaimed, nil
+}
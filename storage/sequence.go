@@ -0,0 +1,78 @@
+// Package storage provides storage for chestnut.
+package storage
+
+import (
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	badger "github.com/dgraph-io/badger/v3"
+)
+
+// returned by Get of all backends when the key does not exist
+var ErrKeyNotFound = badger.ErrKeyNotFound
+
+// Sequence for backends without native sequence support, works like the badger sequence:
+// a range of bandwidth ids is leased and saved to key, ids not used are returned by Release.
+type leaseSequence struct {
+	sync.Mutex
+	db        ChestnutStorage
+	key       []byte
+	next      uint64
+	leased    uint64
+	bandwidth uint64
+}
+
+func newLeaseSequence(db ChestnutStorage, key []byte, bandwidth uint64) (*leaseSequence, error) {
+	if bandwidth == 0 {
+		return nil, errors.New("bandwidth must be greater than zero")
+	}
+	seq := &leaseSequence{db: db, key: key, bandwidth: bandwidth}
+	err := seq.updateLease()
+	return seq, err
+}
+
+func (seq *leaseSequence) updateLease() error {
+	value, err := seq.db.Get(seq.key)
+	if err == ErrKeyNotFound {
+		seq.next = 0
+	} else if err != nil {
+		return err
+	} else {
+		seq.next = binary.BigEndian.Uint64(value)
+	}
+
+	lease := seq.next + seq.bandwidth
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, lease)
+	if err := seq.db.Set(seq.key, buf); err != nil {
+		return err
+	}
+	seq.leased = lease
+	return nil
+}
+
+func (seq *leaseSequence) Next() (uint64, error) {
+	seq.Lock()
+	defer seq.Unlock()
+	if seq.next >= seq.leased {
+		if err := seq.updateLease(); err != nil {
+			return 0, err
+		}
+	}
+	val := seq.next
+	seq.next++
+	return val, nil
+}
+
+func (seq *leaseSequence) Release() error {
+	seq.Lock()
+	defer seq.Unlock()
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, seq.next)
+	if err := seq.db.Set(seq.key, buf); err != nil {
+		return err
+	}
+	seq.leased = seq.next
+	return nil
+}
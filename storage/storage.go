@@ -1,6 +1,15 @@
 // Package storage provides storage for chestnut.
 package storage
 
+import "fmt"
+
+// storage engines
+const (
+	BADGER_ENGINE = "badger"
+	PEBBLE_ENGINE = "pebble"
+	MEMORY_ENGINE = "memory"
+)
+
 type ChestnutStorage interface {
 	Init(path string) error
 	Close() error
@@ -15,6 +24,30 @@ type ChestnutStorage interface {
 	// For appdb, atomic batch write
 	BatchWrite(keys [][]byte, values [][]byte) error
 	GetSequence([]byte, uint64)(Sequence, error)
+
+	// Begin a read-write transaction, reads in the transaction see its own writes
+	BeginTxn() (ChestnutTxn, error)
+}
+
+// All writes of a transaction are committed atomically or discarded by Rollback
+type ChestnutTxn interface {
+	ChestnutStorage
+	Commit() error
+	Rollback()
+}
+
+// Create a storage of engine, Init must be called before use
+func NewChestnutStorage(engine string) (ChestnutStorage, error) {
+	switch engine {
+	case BADGER_ENGINE, "":
+		return &CSBadger{}, nil
+	case PEBBLE_ENGINE:
+		return &CSPebble{}, nil
+	case MEMORY_ENGINE:
+		return &CSMemory{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported storage engine %s", engine)
+	}
 }
 
 type Sequence interface {
@@ -0,0 +1,554 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v3"
+	chestnutpb "github.com/lixvyang/chestnut/pb"
+	"google.golang.org/protobuf/proto"
+)
+
+// every storage engine must pass the same behaviour tests, the chain and app dbs run on all of them
+func forEachEngine(t *testing.T, fn func(t *testing.T, db ChestnutStorage)) {
+	for _, engine := range []string{BADGER_ENGINE, PEBBLE_ENGINE, MEMORY_ENGINE} {
+		t.Run(engine, func(t *testing.T) {
+			db, err := NewChestnutStorage(engine)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := db.Init(t.TempDir()); err != nil {
+				t.Fatal(err)
+			}
+			defer db.Close()
+			fn(t, db)
+		})
+	}
+}
+
+func setKeys(t *testing.T, db ChestnutStorage, keys ...string) {
+	for _, k := range keys {
+		if err := db.Set([]byte(k), []byte("v_"+k)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func prefixKeys(t *testing.T, db ChestnutStorage, seek string, valid string, reverse bool) []string {
+	result := []string{}
+	err := db.PrefixForeachKey([]byte(seek), []byte(valid), reverse, func(k []byte, err error) error {
+		if err != nil {
+			return err
+		}
+		result = append(result, string(k))
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func checkKeys(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("got keys %v, want %v", got, want)
+	}
+}
+
+func TestStorageGetSetDelete(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		if _, err := db.Get([]byte("a")); err != ErrKeyNotFound {
+			t.Fatalf("get missing key: %v, want ErrKeyNotFound", err)
+		}
+		setKeys(t, db, "a")
+		val, err := db.Get([]byte("a"))
+		if err != nil || !bytes.Equal(val, []byte("v_a")) {
+			t.Fatalf("get a: %q %v", val, err)
+		}
+		if err := db.Set([]byte("a"), []byte("new")); err != nil {
+			t.Fatal(err)
+		}
+		if val, _ := db.Get([]byte("a")); !bytes.Equal(val, []byte("new")) {
+			t.Fatalf("overwrite a: %q", val)
+		}
+		if err := db.Delete([]byte("a")); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Get([]byte("a")); err != ErrKeyNotFound {
+			t.Fatalf("get deleted key: %v, want ErrKeyNotFound", err)
+		}
+		if err := db.Delete([]byte("a")); err != nil {
+			t.Fatalf("delete missing key: %v", err)
+		}
+	})
+}
+
+// IsExist is a prefix match, callers check key prefixes with it
+func TestStorageIsExist(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		setKeys(t, db, "blk_1")
+		for key, want := range map[string]bool{"blk_1": true, "blk_": true, "blk_2": false, "trx": false} {
+			exist, err := db.IsExist([]byte(key))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exist != want {
+				t.Fatalf("IsExist(%s) = %v, want %v", key, exist, want)
+			}
+		}
+	})
+}
+
+func TestStoragePrefixForeach(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		setKeys(t, db, "a_2", "a_1", "a_3", "b_1", "a")
+		values := map[string]string{}
+		err := db.PrefixForeach([]byte("a_"), func(k []byte, v []byte, err error) error {
+			if err != nil {
+				return err
+			}
+			values[string(k)] = string(v)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(values) != 3 || values["a_1"] != "v_a_1" || values["a_3"] != "v_a_3" {
+			t.Fatalf("prefix a_: %v", values)
+		}
+
+		all := []string{}
+		err = db.Foreach(func(k []byte, v []byte, err error) error {
+			all = append(all, string(k))
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkKeys(t, all, "a", "a_1", "a_2", "a_3", "b_1")
+
+		// an error returned by fn stops the iteration
+		stop := fmt.Errorf("stop")
+		count := 0
+		err = db.Foreach(func(k []byte, v []byte, err error) error {
+			count++
+			return stop
+		})
+		if err != stop || count != 1 {
+			t.Fatalf("stop iteration: %v after %d keys", err, count)
+		}
+	})
+}
+
+// seek to the first key >= seek, or the last key <= seek if reverse, and iterate keys with prefix valid
+func TestStoragePrefixForeachKey(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		setKeys(t, db, "g_1", "g_2", "g_3", "h_1", "f_9")
+		checkKeys(t, prefixKeys(t, db, "g_", "g_", false), "g_1", "g_2", "g_3")
+		checkKeys(t, prefixKeys(t, db, "g_2", "g_", false), "g_2", "g_3")
+		checkKeys(t, prefixKeys(t, db, "g_\xff", "g_", true), "g_3", "g_2", "g_1")
+		checkKeys(t, prefixKeys(t, db, "g_2", "g_", true), "g_2", "g_1")
+		checkKeys(t, prefixKeys(t, db, "x_", "x_", false))
+		checkKeys(t, prefixKeys(t, db, "x_\xff", "x_", true))
+	})
+}
+
+func TestStorageBatchWrite(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		keys := [][]byte{[]byte("k1"), []byte("k2")}
+		if err := db.BatchWrite(keys, [][]byte{[]byte("v1")}); err == nil {
+			t.Fatal("batch write with mismatched keys and values should fail")
+		}
+		if _, err := db.Get([]byte("k1")); err != ErrKeyNotFound {
+			t.Fatalf("failed batch write is visible: %v", err)
+		}
+		if err := db.BatchWrite(keys, [][]byte{[]byte("v1"), []byte("v2")}); err != nil {
+			t.Fatal(err)
+		}
+		checkKeys(t, prefixKeys(t, db, "k", "k", false), "k1", "k2")
+	})
+}
+
+func TestStorageSequence(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		seq, err := db.GetSequence([]byte("seq"), 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		first, err := seq.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		second, err := seq.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second != first+1 {
+			t.Fatalf("sequence %d after %d", second, first)
+		}
+		if err := seq.Release(); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+func TestStorageTxn(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		setKeys(t, db, "t_1", "t_2")
+
+		txn, err := db.BeginTxn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.Set([]byte("t_3"), []byte("v_t_3")); err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.Delete([]byte("t_1")); err != nil {
+			t.Fatal(err)
+		}
+		// reads in the transaction see its own writes, others don't until commit
+		if val, err := txn.Get([]byte("t_3")); err != nil || string(val) != "v_t_3" {
+			t.Fatalf("txn get own write: %q %v", val, err)
+		}
+		if _, err := txn.Get([]byte("t_1")); err != ErrKeyNotFound {
+			t.Fatalf("txn get own delete: %v", err)
+		}
+		checkKeys(t, prefixKeys(t, txn, "t_", "t_", false), "t_2", "t_3")
+		checkKeys(t, prefixKeys(t, db, "t_", "t_", false), "t_1", "t_2")
+
+		if err := txn.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		checkKeys(t, prefixKeys(t, db, "t_", "t_", false), "t_2", "t_3")
+		if err := txn.Commit(); err == nil {
+			t.Fatal("commit twice should fail")
+		}
+		txn.Rollback()
+
+		txn, err = db.BeginTxn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.BatchWrite([][]byte{[]byte("t_4")}, [][]byte{[]byte("v")}); err != nil {
+			t.Fatal(err)
+		}
+		txn.Rollback()
+		checkKeys(t, prefixKeys(t, db, "t_", "t_", false), "t_2", "t_3")
+		if _, err := txn.BeginTxn(); err == nil {
+			t.Fatal("nested transaction should fail")
+		}
+	})
+}
+
+// group items and chain data share one storage, a DbMgr transaction commits both at once
+func TestDbMgrTxnSharedStorage(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		dbMgr := &DbMgr{GroupInfoDb: db, Db: db}
+		txn, err := dbMgr.BeginTxn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(txn.txns) != 1 {
+			t.Fatalf("shared storage opened %d transactions", len(txn.txns))
+		}
+		committed := false
+		txn.OnCommit(func() { committed = true })
+		if err := txn.GroupInfoDb.Set([]byte("group"), []byte("item")); err != nil {
+			t.Fatal(err)
+		}
+		if err := txn.Db.Set([]byte("blk_1"), []byte("block")); err != nil {
+			t.Fatal(err)
+		}
+		if committed {
+			t.Fatal("OnCommit called before commit")
+		}
+		if err := txn.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		if !committed {
+			t.Fatal("OnCommit not called after commit")
+		}
+		checkKeys(t, prefixKeys(t, db, "", "", false), "blk_1", "group")
+	})
+}
+
+// a long chain of large cached blocks is gathered in parts, each part is moved to the chain in one
+// badger transaction and the blocks left are found as children of the last part
+func TestGatherBlocksFromCacheLimit(t *testing.T) {
+	db := &CSBadger{}
+	if err := db.Init(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	dbMgr := &DbMgr{GroupInfoDb: db, Db: db}
+	if err := dbMgr.AddGensisBlock(&chestnutpb.Block{BlockId: "genesis", GroupId: "group"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var blocks []*chestnutpb.Block
+	prev := "genesis"
+	for i := 0; i < 20; i++ {
+		trx := &chestnutpb.Trx{TrxId: fmt.Sprintf("trx%03d", i), GroupId: "group", Data: make([]byte, 64<<10)}
+		block := &chestnutpb.Block{BlockId: fmt.Sprintf("block%03d", i), GroupId: "group", PrevBlockId: prev, Trxs: []*chestnutpb.Trx{trx}}
+		if err := dbMgr.AddBlock(block, true); err != nil {
+			t.Fatal(err)
+		}
+		blocks = append(blocks, block)
+		prev = block.BlockId
+	}
+
+	// all the blocks are too large for one transaction
+	txn, err := dbMgr.BeginTxn()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tooBig error
+	for _, block := range blocks {
+		if tooBig = txn.AddBlock(block, false); tooBig != nil {
+			break
+		}
+	}
+	txn.Rollback()
+	if tooBig != badger.ErrTxnTooBig {
+		t.Fatalf("all blocks added in one transaction: %v", tooBig)
+	}
+
+	next := blocks[0]
+	added, parts := 0, 0
+	for next != nil {
+		txn, err := dbMgr.BeginTxn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		gathered, err := txn.GatherBlocksFromCache(next, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(gathered) < 2 {
+			t.Fatalf("gathered %d blocks of %d", len(gathered), len(blocks)-added)
+		}
+		parts++
+		size := 0
+		for i, block := range gathered {
+			if block.BlockId != blocks[added+i].BlockId {
+				t.Fatalf("gathered block %d is %s, want %s", i, block.BlockId, blocks[added+i].BlockId)
+			}
+			size += proto.Size(block)
+			if err := txn.AddBlock(block, false); err != nil {
+				t.Fatal(err)
+			}
+			if err := txn.RmBlock(block.BlockId, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if size > MAX_GATHER_BYTES {
+			t.Fatalf("gathered %d bytes, limit %d", size, MAX_GATHER_BYTES)
+		}
+		if err := txn.Commit(); err != nil {
+			t.Fatal(err)
+		}
+		added += len(gathered)
+
+		children, err := dbMgr.GetCachedChildBlocks(gathered)
+		if err != nil {
+			t.Fatal(err)
+		}
+		next = nil
+		if added < len(blocks) {
+			if len(children) != 1 || children[0].BlockId != blocks[added].BlockId {
+				t.Fatalf("children of gathered blocks: %v", children)
+			}
+			next = children[0]
+		} else if len(children) != 0 {
+			t.Fatalf("children left after the last block: %v", children)
+		}
+	}
+
+	if parts < 2 {
+		t.Fatalf("blocks gathered in %d parts", parts)
+	}
+	for _, block := range blocks {
+		if ok, err := dbMgr.IsBlockExist(block.BlockId, false); err != nil || !ok {
+			t.Fatalf("block %s not in chain: %v", block.BlockId, err)
+		}
+		if ok, _ := dbMgr.IsBlockExist(block.BlockId, true); ok {
+			t.Fatalf("block %s left in cache", block.BlockId)
+		}
+	}
+}
+
+// a transaction over two storages can't be committed atomically
+func TestDbMgrTxnSeparateStorage(t *testing.T) {
+	groupDb, db := &CSMemory{}, &CSMemory{}
+	groupDb.Init("")
+	db.Init("")
+	dbMgr := &DbMgr{GroupInfoDb: groupDb, Db: db}
+	if _, err := dbMgr.BeginTxn(); err == nil {
+		t.Fatal("transaction over separate storages should fail")
+	}
+}
+
+// blocks saved to the chain, e.g. imported from an archive, can be found by hash, the hash index
+// of blocks saved by old versions is built by the migration
+func TestBlockHashIndex(t *testing.T) {
+	db := &CSMemory{}
+	db.Init("")
+	dbMgr := &DbMgr{GroupInfoDb: db, Db: db}
+
+	genesis := &chestnutpb.Block{BlockId: "genesis", GroupId: "group", Hash: []byte{0x01}}
+	block := &chestnutpb.Block{BlockId: "block1", GroupId: "group", PrevBlockId: "genesis", Hash: []byte{0xab, 0xcd}}
+	if err := dbMgr.AddGensisBlock(genesis, "tenant"); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbMgr.AddBlock(block, false, "tenant"); err != nil {
+		t.Fatal(err)
+	}
+	found, err := dbMgr.GetBlockByHash("group", "abcd", "tenant")
+	if err != nil || found == nil || found.BlockId != "block1" {
+		t.Fatalf("get block by hash: %v %v", found, err)
+	}
+
+	hashKey := []byte(blockHashKey("group", block.Hash, "tenant_"))
+	if err := db.Delete(hashKey); err != nil {
+		t.Fatal(err)
+	}
+	err = db.PrefixForeach([]byte("tenant_"+BLK_PREFIX+"_"), func(k []byte, v []byte, err error) error {
+		keys, values, err := migrateBuildHashIndex(k, v)
+		if err != nil {
+			return err
+		}
+		return db.BatchWrite(keys, values)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if val, err := db.Get(hashKey); err != nil || string(val) != "block1" {
+		t.Fatalf("hash index built by migration: %q %v", val, err)
+	}
+}
+
+func newTestGroupItem(t *testing.T, groupId string) ([]byte, *chestnutpb.GroupItem) {
+	item := &chestnutpb.GroupItem{GroupId: groupId, GroupName: groupId, CipherKey: "cipherkey"}
+	value, err := proto.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return value, item
+}
+
+func checkGroupsBytes(t *testing.T, list [][]byte, groupIds ...string) {
+	got := []string{}
+	for _, v := range list {
+		item := &chestnutpb.GroupItem{}
+		if err := proto.Unmarshal(v, item); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, item.GroupId)
+	}
+	checkKeys(t, got, groupIds...)
+}
+
+// groups of the node and of a tenant are listed without the chain data saved in the same storage
+// after the node is restarted
+func TestGroupsReloadWithChainData(t *testing.T) {
+	for _, engine := range []string{BADGER_ENGINE, PEBBLE_ENGINE} {
+		t.Run(engine, func(t *testing.T) {
+			dir := t.TempDir()
+			open := func() *DbMgr {
+				db, err := NewChestnutStorage(engine)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := db.Init(dir); err != nil {
+					t.Fatal(err)
+				}
+				dbMgr := &DbMgr{GroupInfoDb: db, Db: db}
+				if err := dbMgr.TryMigration(); err != nil {
+					t.Fatal(err)
+				}
+				return dbMgr
+			}
+
+			dbMgr := open()
+			for _, g := range []struct{ groupId, tenant, nodename string }{
+				{"group1", "", "default"},
+				{"group2", "tenant", "tenant"},
+			} {
+				var prefix []string
+				if g.tenant != "" {
+					prefix = append(prefix, g.tenant)
+				}
+				_, item := newTestGroupItem(t, g.groupId)
+				if err := dbMgr.AddGroup(item, prefix...); err != nil {
+					t.Fatal(err)
+				}
+				genesis := &chestnutpb.Block{BlockId: g.groupId + "_genesis", GroupId: g.groupId}
+				block := &chestnutpb.Block{BlockId: g.groupId + "_1", GroupId: g.groupId, PrevBlockId: genesis.BlockId}
+				if err := dbMgr.AddGensisBlock(genesis, g.nodename); err != nil {
+					t.Fatal(err)
+				}
+				if err := dbMgr.AddBlock(block, false, g.nodename); err != nil {
+					t.Fatal(err)
+				}
+				if err := dbMgr.AddTrx(&chestnutpb.Trx{TrxId: g.groupId + "_trx", GroupId: g.groupId}, g.nodename); err != nil {
+					t.Fatal(err)
+				}
+			}
+			dbMgr.CloseDb()
+
+			dbMgr = open()
+			defer dbMgr.CloseDb()
+			nodeGroups, err := dbMgr.GetGroupsBytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGroupsBytes(t, nodeGroups, "group1")
+			tenantGroups, err := dbMgr.GetGroupsBytes("tenant")
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkGroupsBytes(t, tenantGroups, "group2")
+			all, err := dbMgr.GetAllGroupsBytes()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) != 2 {
+				t.Fatalf("groups of %d tenants listed, want 2", len(all))
+			}
+			checkGroupsBytes(t, all[""], "group1")
+			checkGroupsBytes(t, all["tenant"], "group2")
+		})
+	}
+}
+
+// group items saved by group id and <tenant>_<groupid> are moved under the group item prefix,
+// the chain data is not touched
+func TestMigrateGroupItemKeys(t *testing.T) {
+	forEachEngine(t, func(t *testing.T, db ChestnutStorage) {
+		dbMgr := &DbMgr{GroupInfoDb: db, Db: db}
+		value1, _ := newTestGroupItem(t, "group1")
+		value2, _ := newTestGroupItem(t, "group2")
+		if err := db.Set([]byte("group1"), value1); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Set([]byte("tenant_group2"), value2); err != nil {
+			t.Fatal(err)
+		}
+		setKeys(t, db, "default_blk_block1", "default_trx_trx1", "tenant_blk_block2")
+		if err := dbMgr.TryMigration(); err != nil {
+			t.Fatal(err)
+		}
+
+		checkKeys(t, prefixKeys(t, db, "", "", false),
+			"dbver_chain", "dbver_groupinfo", "default_blk_block1", "default_trx_trx1", "grpitem_group1",
+			"tenant_blk_block2", "tenant_grpitem_group2")
+		if v, err := dbMgr.GetGroupBytes("group1"); err != nil || !bytes.Equal(v, value1) {
+			t.Fatalf("node group after migration: %v", err)
+		}
+		if v, err := dbMgr.GetGroupBytes("group2", "tenant"); err != nil || !bytes.Equal(v, value2) {
+			t.Fatalf("tenant group after migration: %v", err)
+		}
+	})
+}
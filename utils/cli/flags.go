@@ -13,6 +13,7 @@ import (
 
 type addrList []maddr.Multiaddr
 type ipList []net.IP
+type groupList []string
 
 type Config struct {
 	RendezvousString    string
@@ -27,9 +28,16 @@ type Config struct {
 	IsDebug            bool
 	ConfigDir          string
 	DataDir            string
+	DbEngine           string
 	IsPing             bool
 	KeyStoreDir        string
 	KeyStoreName       string
+	KeyStoreType       string
+	SignerAddr         string
+	PasswordFile       string
+	NonInteractive     bool
+	LoadGroups         groupList
+	Tenants            groupList
 }
 
 var logger = logging.Logger("cli")
@@ -75,6 +83,37 @@ func (ips *ipList) Set(value string) error {
 	return nil
 }
 
+func (gl *groupList) String() string {
+	return strings.Join(*gl, ",")
+}
+
+func (gl *groupList) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*gl = append(*gl, v)
+		}
+	}
+	return nil
+}
+
+// Groups listed by -groups to load for the node (tenant "") or a tenant, all is true if -groups is not set.
+// Groups of a tenant are listed as <tenant>:<group_id>, the others are groups of the node.
+func (config Config) GroupsToLoad(tenant string) (groupIds []string, all bool) {
+	if len(config.LoadGroups) == 0 {
+		return nil, true
+	}
+	for _, v := range config.LoadGroups {
+		name, groupId := "", v
+		if idx := strings.Index(v, ":"); idx >= 0 {
+			name, groupId = v[:idx], v[idx+1:]
+		}
+		if name == tenant && groupId != "" {
+			groupIds = append(groupIds, groupId)
+		}
+	}
+	return groupIds, false
+}
+
 var Chestnut *Config
 
 func GetConfig() Config {
@@ -94,8 +133,15 @@ func ParseFlags() (Config, error) {
 	flag.StringVar(&config.PeerName, "peername", "peer", "peername")
 	flag.StringVar(&config.ConfigDir, "configdir", "./config/", "config and keys dir")
 	flag.StringVar(&config.DataDir, "datadir", "./data/", "config dir")
+	flag.StringVar(&config.DbEngine, "dbengine", "badger", "storage engine: badger, pebble or memory (test only, data lost on exit)")
 	flag.StringVar(&config.KeyStoreDir, "keystoredir", "./keystore/", "keystore dir")
 	flag.StringVar(&config.KeyStoreName, "keystorename", "defaultkeystore", "keystore name")
+	flag.StringVar(&config.KeyStoreType, "keystoretype", "dir", "keystore type, dir or remote. The remote keystore signs and decrypts by the signer, only the network key is kept in keystoredir")
+	flag.StringVar(&config.PasswordFile, "password-file", "", "read the keystore password from the file, a generated password is written to it when a new keystore is created")
+	flag.BoolVar(&config.NonInteractive, "noninteractive", false, "never prompt or wait on the terminal, exit with an error code if no password is given")
+	flag.StringVar(&config.SignerAddr, "signeraddr", "", "unix socket of the signer used by the remote keystore, e.g.: `/run/chestnut/signer.sock`")
+	flag.Var(&config.LoadGroups, "groups", "Load only the listed groups at startup, groups of a tenant as <tenant>:<group_id>, e.g.: `-groups <group_id>,alice:<group_id>`, all groups of the node and the tenants are loaded if not set")
+	flag.Var(&config.Tenants, "tenants", "Host the listed tenants besides the node itself, e.g.: `-tenants alice,bob`, each tenant has its own keystore and groups")
 	flag.StringVar(&config.JsonTracer, "jsontracer", "", "output tracer data to a json file")
 	flag.BoolVar(&config.IsBootstrap, "bootstrap", false, "run a bootstrap node")
 	flag.BoolVar(&config.IsPing, "ping", false, "ping peer")
@@ -22,7 +22,10 @@ type NodeOptions struct {
 	JWTToken         string
 	JWTKey           string
 	SignKeyMap       map[string]string
+	TenantTokens     map[string]string
 	mu               sync.RWMutex
+	configdir        string
+	peername         string
 }	
 
 var nodeoptions *NodeOptions
@@ -48,6 +51,12 @@ func InitNodeOptions(configdir, peername string) (*NodeOptions, error) {
 	return nodeoptions, err
 }
 
+// load the options saved in <configdir>/<peername>_options.toml without replacing the node options,
+// e.g. the options of a tenant
+func LoadNodeOptions(configdir, peername string) (*NodeOptions, error) {
+	return load(configdir, peername)
+}
+
 func load(dir, peername string) (*NodeOptions, error) {
 	v, err := initConfigfile(dir, peername)
 	if err != nil {
@@ -76,8 +85,11 @@ func load(dir, peername string) (*NodeOptions, error) {
 	}
 
 	options.SignKeyMap = v.GetStringMapString("SignKeyMap")
+	options.TenantTokens = v.GetStringMapString("TenantTokens")
 	options.JWTKey = v.GetString("JWTKey")
 	options.JWTToken = v.GetString("JWTToken")
+	options.configdir = dir
+	options.peername = peername
 	return options, nil
 }
 
@@ -118,7 +130,7 @@ func GetConfigDir() (string, error) {
 }
 
 func (opt *NodeOptions) WriteToConfig() error {
-	v, err := initConfigfile(nodeconfigdir, nodepeername)
+	v, err := initConfigfile(opt.configdir, opt.peername)
 	if err != nil {
 		return err
 	}
@@ -126,6 +138,7 @@ func (opt *NodeOptions) WriteToConfig() error {
 	v.Set("EnableNat", opt.EnableNat)
 	v.Set("EnableDevNetwork", opt.EnableDevNetwork)
 	v.Set("SignKeyMap", opt.SignKeyMap)
+	v.Set("TenantTokens", opt.TenantTokens)
 	v.Set("JWTKey", opt.JWTKey)
 	v.Set("JWTToken", opt.JWTToken)
 	return v.WriteConfig()
@@ -140,6 +153,43 @@ func (opt *NodeOptions) SetSignKeyMap(keyname, addr string) error {
 	return opt.WriteToConfig()
 }
 
+func (opt *NodeOptions) RenameSignKeyMap(keyname, newname string) error {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+	addr, ok := opt.SignKeyMap[keyname]
+	if !ok {
+		return nil
+	}
+	opt.SignKeyMap[newname] = addr
+	delete(opt.SignKeyMap, keyname)
+	return opt.WriteToConfig()
+}
+
+func (opt *NodeOptions) DelSignKeyMap(keyname string) error {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+	if _, ok := opt.SignKeyMap[keyname]; !ok {
+		return nil
+	}
+	delete(opt.SignKeyMap, keyname)
+	return opt.WriteToConfig()
+}
+
+// api token of a tenant, a random token is generated and saved if the tenant has none
+func (opt *NodeOptions) GetTenantToken(tenant string) (string, error) {
+	opt.mu.Lock()
+	defer opt.mu.Unlock()
+	if token, ok := opt.TenantTokens[tenant]; ok && token != "" {
+		return token, nil
+	}
+	if opt.TenantTokens == nil {
+		opt.TenantTokens = map[string]string{}
+	}
+	token := utils.GetRandomStr(JWTKeyLength)
+	opt.TenantTokens[tenant] = token
+	return token, opt.WriteToConfig()
+}
+
 func writeDefaultToconfig(v *viper.Viper) error {
 	v.Set("EnableNat", true)
 	v.Set("EnableDevNetwork", false)
@@ -149,5 +199,6 @@ func writeDefaultToconfig(v *viper.Viper) error {
 	v.Set("JWTKey", utils.GetRandomStr(JWTKeyLength))
 	v.Set("JWTToken", "")
 	v.Set("SignKeyMap", map[string]string{})
+	v.Set("TenantTokens", map[string]string{})
 	return v.SafeWriteConfig()
 }
\ No newline at end of file